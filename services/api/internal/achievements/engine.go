@@ -0,0 +1,84 @@
+package achievements
+
+import (
+	"context"
+	"fmt"
+)
+
+// Event is something a user did that may unlock achievements
+type Event struct {
+	Type   EventType
+	UserID int32
+}
+
+// Counter counts a metric for a user
+type Counter interface {
+	Count(ctx context.Context, userID int32, metric Metric) (int64, error)
+}
+
+// Store records unlocked achievements
+type Store interface {
+	// Unlocked returns the keys of the user's unlocked achievements
+	Unlocked(ctx context.Context, userID int32) (map[string]bool, error)
+	// Unlock records the achievement, returning false if it was already unlocked
+	Unlock(ctx context.Context, userID int32, key string) (bool, error)
+}
+
+// Engine evaluates rules against events
+type Engine struct {
+	rules   []Rule
+	counter Counter
+	store   Store
+}
+
+func NewEngine(rules []Rule, counter Counter, store Store) *Engine {
+	return &Engine{rules: rules, counter: counter, store: store}
+}
+
+// Evaluate checks every rule the event can trigger and returns the ones newly
+// unlocked. Each metric is counted at most once per event.
+func (e *Engine) Evaluate(ctx context.Context, event Event) ([]Rule, error) {
+	var candidates []Rule
+	for _, rule := range e.rules {
+		if rule.triggeredBy(event.Type) {
+			candidates = append(candidates, rule)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	unlocked, err := e.store.Unlocked(ctx, event.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching achievements: %w", err)
+	}
+
+	counts := make(map[Metric]int64)
+	var newlyUnlocked []Rule
+	for _, rule := range candidates {
+		if unlocked[rule.Key] {
+			continue
+		}
+
+		count, counted := counts[rule.Metric]
+		if !counted {
+			count, err = e.counter.Count(ctx, event.UserID, rule.Metric)
+			if err != nil {
+				return newlyUnlocked, fmt.Errorf("error counting %s: %w", rule.Metric, err)
+			}
+			counts[rule.Metric] = count
+		}
+		if count < rule.Threshold {
+			continue
+		}
+
+		created, err := e.store.Unlock(ctx, event.UserID, rule.Key)
+		if err != nil {
+			return newlyUnlocked, fmt.Errorf("error unlocking %s: %w", rule.Key, err)
+		}
+		if created {
+			newlyUnlocked = append(newlyUnlocked, rule)
+		}
+	}
+	return newlyUnlocked, nil
+}
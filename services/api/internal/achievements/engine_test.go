@@ -0,0 +1,96 @@
+package achievements
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeCounter struct {
+	counts map[Metric]int64
+	calls  map[Metric]int
+}
+
+func (f *fakeCounter) Count(ctx context.Context, userID int32, metric Metric) (int64, error) {
+	f.calls[metric]++
+	return f.counts[metric], nil
+}
+
+type fakeStore struct {
+	unlocked map[string]bool
+}
+
+func (f *fakeStore) Unlocked(ctx context.Context, userID int32) (map[string]bool, error) {
+	copied := make(map[string]bool, len(f.unlocked))
+	for key := range f.unlocked {
+		copied[key] = true
+	}
+	return copied, nil
+}
+
+func (f *fakeStore) Unlock(ctx context.Context, userID int32, key string) (bool, error) {
+	if f.unlocked[key] {
+		return false, nil
+	}
+	f.unlocked[key] = true
+	return true, nil
+}
+
+func TestEvaluate(t *testing.T) {
+	counter := &fakeCounter{counts: map[Metric]int64{MetricComments: 100}, calls: map[Metric]int{}}
+	store := &fakeStore{unlocked: map[string]bool{}}
+	engine := NewEngine(Rules, counter, store)
+
+	unlocked, err := engine.Evaluate(context.Background(), Event{Type: EventCommentCreated, UserID: 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(unlocked) != 2 || unlocked[0].Key != "first_comment" || unlocked[1].Key != "comments_100" {
+		t.Errorf("Expected first_comment and comments_100 to unlock, got %+v", unlocked)
+	}
+	if counter.calls[MetricComments] != 1 {
+		t.Errorf("Expected comments to be counted once, got %d", counter.calls[MetricComments])
+	}
+
+	// Evaluating again unlocks nothing new and doesn't re-count
+	unlocked, err = engine.Evaluate(context.Background(), Event{Type: EventCommentCreated, UserID: 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(unlocked) != 0 {
+		t.Errorf("Expected nothing new to unlock, got %+v", unlocked)
+	}
+	if counter.calls[MetricComments] != 1 {
+		t.Errorf("Expected no recount once unlocked, got %d calls", counter.calls[MetricComments])
+	}
+}
+
+func TestEvaluateBelowThreshold(t *testing.T) {
+	counter := &fakeCounter{counts: map[Metric]int64{MetricVerificationsGiven: 4}, calls: map[Metric]int{}}
+	store := &fakeStore{unlocked: map[string]bool{}}
+	engine := NewEngine(Rules, counter, store)
+
+	unlocked, err := engine.Evaluate(context.Background(), Event{Type: EventVerificationAdded, UserID: 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(unlocked) != 0 {
+		t.Errorf("Expected nothing to unlock, got %+v", unlocked)
+	}
+}
+
+func TestRulesAreWellFormed(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rule := range Rules {
+		if seen[rule.Key] {
+			t.Errorf("Duplicate achievement key %s", rule.Key)
+		}
+		seen[rule.Key] = true
+		if rule.Threshold < 1 || len(rule.Triggers) == 0 || rule.Name == "" {
+			t.Errorf("Achievement %s is incomplete: %+v", rule.Key, rule)
+		}
+	}
+
+	if _, ok := Find("verified_player"); !ok {
+		t.Error("Expected to find the verified_player achievement")
+	}
+}
@@ -0,0 +1,132 @@
+// Package achievements unlocks badges for users from declarative rules.
+// Each rule names a metric, a threshold and the events that can move the metric;
+// when one of those events happens the metric is re-counted from the database,
+// so users with history from before a rule existed unlock it on their next event.
+package achievements
+
+// EventType is a domain event that can unlock achievements
+type EventType string
+
+const (
+	EventVoteCreated        EventType = "vote_created"
+	EventCommentCreated     EventType = "comment_created"
+	EventVerificationAdded  EventType = "verification_added"
+	EventPredictionRewarded EventType = "prediction_rewarded"
+)
+
+// Metric is a per-user count that rules compare against their threshold
+type Metric string
+
+const (
+	MetricVotes              Metric = "votes"
+	MetricComments           Metric = "comments"
+	MetricCorrectPredictions Metric = "correct_predictions" // Winning debate stances plus scored scorelines
+	MetricExactScores        Metric = "exact_scores"
+	MetricVerificationsGiven Metric = "verifications_given"
+	MetricVerifiedProfiles   Metric = "verified_profiles"
+)
+
+// Rule unlocks an achievement once the user's metric reaches the threshold
+type Rule struct {
+	Key         string
+	Name        string
+	Description string
+	Metric      Metric
+	Threshold   int64
+	Triggers    []EventType
+}
+
+// Rules are every achievement a user can unlock
+var Rules = []Rule{
+	{
+		Key:         "first_vote",
+		Name:        "First Vote",
+		Description: "Vote on a debate",
+		Metric:      MetricVotes,
+		Threshold:   1,
+		Triggers:    []EventType{EventVoteCreated},
+	},
+	{
+		Key:         "votes_100",
+		Name:        "Terrace Regular",
+		Description: "Vote 100 times",
+		Metric:      MetricVotes,
+		Threshold:   100,
+		Triggers:    []EventType{EventVoteCreated},
+	},
+	{
+		Key:         "first_comment",
+		Name:        "Opening Statement",
+		Description: "Comment on a debate",
+		Metric:      MetricComments,
+		Threshold:   1,
+		Triggers:    []EventType{EventCommentCreated},
+	},
+	{
+		Key:         "comments_100",
+		Name:        "Pundit",
+		Description: "Post 100 comments",
+		Metric:      MetricComments,
+		Threshold:   100,
+		Triggers:    []EventType{EventCommentCreated},
+	},
+	{
+		Key:         "first_correct_prediction",
+		Name:        "Called It",
+		Description: "Get a prediction right",
+		Metric:      MetricCorrectPredictions,
+		Threshold:   1,
+		Triggers:    []EventType{EventPredictionRewarded},
+	},
+	{
+		Key:         "correct_predictions_10",
+		Name:        "Oracle",
+		Description: "Get 10 predictions right",
+		Metric:      MetricCorrectPredictions,
+		Threshold:   10,
+		Triggers:    []EventType{EventPredictionRewarded},
+	},
+	{
+		Key:         "exact_score",
+		Name:        "Spot On",
+		Description: "Predict an exact scoreline",
+		Metric:      MetricExactScores,
+		Threshold:   1,
+		Triggers:    []EventType{EventPredictionRewarded},
+	},
+	{
+		Key:         "verified_player",
+		Name:        "Verified Player",
+		Description: "Have your player profile verified by other users",
+		Metric:      MetricVerifiedProfiles,
+		Threshold:   1,
+		Triggers:    []EventType{EventVerificationAdded},
+	},
+	{
+		Key:         "scout",
+		Name:        "Scout",
+		Description: "Verify 5 player profiles",
+		Metric:      MetricVerificationsGiven,
+		Threshold:   5,
+		Triggers:    []EventType{EventVerificationAdded},
+	},
+}
+
+// Find returns the rule with the given key
+func Find(key string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.Key == key {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (r Rule) triggeredBy(event EventType) bool {
+	for _, trigger := range r.Triggers {
+		if trigger == event {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 )
@@ -69,10 +70,13 @@ type MatchStats struct {
 }
 
 type SocialSentiment struct {
-	TwitterSentiment     float64  `json:"twitter_sentiment"` // -1 to 1
-	RedditSentiment      float64  `json:"reddit_sentiment"`  // -1 to 1
-	TopTopics            []string `json:"top_topics"`
-	ControversialMoments []string `json:"controversial_moments"`
+	HomeTeamSentiment    float64            `json:"home_team_sentiment"` // -1 to 1
+	AwayTeamSentiment    float64            `json:"away_team_sentiment"` // -1 to 1
+	HomeSampleSize       int                `json:"home_sample_size"`
+	AwaySampleSize       int                `json:"away_sample_size"`
+	SourceSentiment      map[string]float64 `json:"source_sentiment,omitempty"` // -1 to 1 per source
+	TopTopics            []string           `json:"top_topics"`
+	ControversialMoments []string           `json:"controversial_moments"`
 }
 
 type DebatePrompt struct {
@@ -355,19 +359,34 @@ func (pg *PromptGenerator) buildUserPrompt(matchData MatchData, promptType strin
 	}
 
 	if matchData.SocialSentiment != nil {
-		prompt.WriteString("SOCIAL SENTIMENT:\n")
-		prompt.WriteString(fmt.Sprintf("Twitter Sentiment: %.2f\n", matchData.SocialSentiment.TwitterSentiment))
-		prompt.WriteString(fmt.Sprintf("Reddit Sentiment: %.2f\n", matchData.SocialSentiment.RedditSentiment))
+		sentiment := matchData.SocialSentiment
+		prompt.WriteString("FAN SENTIMENT (-1 very negative to 1 very positive):\n")
+		if sentiment.HomeSampleSize > 0 {
+			prompt.WriteString(fmt.Sprintf("%s: %.2f (%s, %d reactions)\n", matchData.HomeTeam, sentiment.HomeTeamSentiment, describeSentiment(sentiment.HomeTeamSentiment), sentiment.HomeSampleSize))
+		}
+		if sentiment.AwaySampleSize > 0 {
+			prompt.WriteString(fmt.Sprintf("%s: %.2f (%s, %d reactions)\n", matchData.AwayTeam, sentiment.AwayTeamSentiment, describeSentiment(sentiment.AwayTeamSentiment), sentiment.AwaySampleSize))
+		}
+		if len(sentiment.SourceSentiment) > 0 {
+			sources := make([]string, 0, len(sentiment.SourceSentiment))
+			for source := range sentiment.SourceSentiment {
+				sources = append(sources, source)
+			}
+			sort.Strings(sources)
+			for _, source := range sources {
+				prompt.WriteString(fmt.Sprintf("- %s: %.2f\n", source, sentiment.SourceSentiment[source]))
+			}
+		}
 
-		if len(matchData.SocialSentiment.TopTopics) > 0 {
-			prompt.WriteString("Top Topics: ")
-			prompt.WriteString(strings.Join(matchData.SocialSentiment.TopTopics, ", "))
+		if len(sentiment.TopTopics) > 0 {
+			prompt.WriteString("Trending Topics: ")
+			prompt.WriteString(strings.Join(sentiment.TopTopics, ", "))
 			prompt.WriteString("\n")
 		}
 
-		if len(matchData.SocialSentiment.ControversialMoments) > 0 {
+		if len(sentiment.ControversialMoments) > 0 {
 			prompt.WriteString("Controversial Moments:\n")
-			for _, moment := range matchData.SocialSentiment.ControversialMoments {
+			for _, moment := range sentiment.ControversialMoments {
 				prompt.WriteString(fmt.Sprintf("- %s\n", moment))
 			}
 		}
@@ -379,6 +398,22 @@ func (pg *PromptGenerator) buildUserPrompt(matchData MatchData, promptType strin
 	return prompt.String()
 }
 
+// describeSentiment turns a sentiment score into words the model can use directly
+func describeSentiment(score float64) string {
+	switch {
+	case score >= 0.5:
+		return "very positive"
+	case score >= 0.15:
+		return "positive"
+	case score > -0.15:
+		return "mixed"
+	case score > -0.5:
+		return "negative"
+	default:
+		return "very negative"
+	}
+}
+
 func (pg *PromptGenerator) callOpenAI(ctx context.Context, request OpenAIRequest) (*OpenAIResponse, error) {
 	jsonData, err := json.Marshal(request)
 	if err != nil {
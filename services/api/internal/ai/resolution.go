@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PredictionCard is a stance card of a pre-match debate, identified so the
+// model can point at the one that came true
+type PredictionCard struct {
+	ID          int32  `json:"id"`
+	Stance      string `json:"stance"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// PredictionResolution is the model's verdict on a pre-match debate
+type PredictionResolution struct {
+	WinningCardID int32   `json:"winning_card_id"` // 0 when the outcome can't be determined from the data
+	Evidence      string  `json:"evidence"`
+	Confidence    float64 `json:"confidence"` // 0 to 1
+}
+
+// ResolvePrediction asks the model which card of a pre-match debate turned out
+// to be correct, given the final match data
+func (pg *PromptGenerator) ResolvePrediction(ctx context.Context, matchData MatchData, headline string, cards []PredictionCard) (*PredictionResolution, error) {
+	request := OpenAIRequest{
+		Model: "gpt-4o-mini",
+		Messages: []Message{
+			{Role: "system", Content: resolutionSystemPrompt},
+			{Role: "user", Content: buildResolutionPrompt(matchData, headline, cards)},
+		},
+		Temperature: 0,
+		MaxTokens:   400,
+	}
+
+	response, err := pg.callOpenAI(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI API call failed: %w", err)
+	}
+
+	var resolution PredictionResolution
+	if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &resolution); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
+	}
+
+	if resolution.WinningCardID != 0 {
+		found := false
+		for _, card := range cards {
+			if card.ID == resolution.WinningCardID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("model picked unknown card %d", resolution.WinningCardID)
+		}
+	}
+
+	return &resolution, nil
+}
+
+const resolutionSystemPrompt = `You are a football fact checker. Before a match, fans voted on stance cards predicting what would happen. The match is now over. Decide which single card turned out to be correct using ONLY the final match data provided.
+
+Respond with JSON in this structure:
+{
+  "winning_card_id": 123,
+  "evidence": "One or two sentences citing the final data that decides it",
+  "confidence": 0.9
+}
+
+Rules:
+- winning_card_id must be the id of one of the given cards
+- If the data provided does not settle the prediction (e.g. it depends on events that are not in the data), set winning_card_id to 0 and explain what is missing in evidence
+- Do not use outside knowledge about the match
+- confidence is between 0 and 1`
+
+func buildResolutionPrompt(matchData MatchData, headline string, cards []PredictionCard) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("Debate: %s\n\n", headline))
+	prompt.WriteString("CARDS:\n")
+	for _, card := range cards {
+		prompt.WriteString(fmt.Sprintf("- id %d (%s): %s", card.ID, card.Stance, card.Title))
+		if card.Description != "" {
+			prompt.WriteString(fmt.Sprintf(" - %s", card.Description))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString(fmt.Sprintf("\nMatch: %s vs %s\n", matchData.HomeTeam, matchData.AwayTeam))
+	prompt.WriteString(fmt.Sprintf("Status: %s\n", matchData.Status))
+	if matchData.League != "" {
+		prompt.WriteString(fmt.Sprintf("League: %s\n", matchData.League))
+	}
+
+	if matchData.Stats != nil {
+		stats := matchData.Stats
+		prompt.WriteString("\nFINAL STATS:\n")
+		prompt.WriteString(fmt.Sprintf("Score: %d - %d\n", stats.HomeScore, stats.AwayScore))
+		prompt.WriteString(fmt.Sprintf("Shots: %d - %d\n", stats.HomeShots, stats.AwayShots))
+		prompt.WriteString(fmt.Sprintf("Possession: %d%% - %d%%\n", stats.HomePossession, stats.AwayPossession))
+		prompt.WriteString(fmt.Sprintf("Fouls: %d - %d\n", stats.HomeFouls, stats.AwayFouls))
+		prompt.WriteString(fmt.Sprintf("Yellow cards: %d - %d\n", stats.HomeYellowCards, stats.AwayYellowCards))
+		prompt.WriteString(fmt.Sprintf("Red cards: %d - %d\n", stats.HomeRedCards, stats.AwayRedCards))
+	}
+
+	if matchData.Lineups != nil {
+		prompt.WriteString("\nSTARTERS:\n")
+		prompt.WriteString(fmt.Sprintf("Home: %s\n", playerNames(matchData.Lineups.HomeStarters)))
+		prompt.WriteString(fmt.Sprintf("Away: %s\n", playerNames(matchData.Lineups.AwayStarters)))
+		prompt.WriteString("SUBSTITUTES:\n")
+		prompt.WriteString(fmt.Sprintf("Home: %s\n", playerNames(matchData.Lineups.HomeSubstitutes)))
+		prompt.WriteString(fmt.Sprintf("Away: %s\n", playerNames(matchData.Lineups.AwaySubstitutes)))
+	}
+
+	return prompt.String()
+}
+
+func playerNames(players []Player) string {
+	names := make([]string, len(players))
+	for i, player := range players {
+		names[i] = player.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/achievements"
+	"github.com/ArronJLinton/fucci-api/internal/database"
+)
+
+type AchievementResponse struct {
+	Key         string     `json:"key"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Threshold   int64      `json:"threshold"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// achievementCounter counts achievement metrics from the database
+type achievementCounter struct {
+	db *database.Queries
+}
+
+func (ac *achievementCounter) Count(ctx context.Context, userID int32, metric achievements.Metric) (int64, error) {
+	switch metric {
+	case achievements.MetricVotes:
+		return ac.db.CountUserVotes(ctx, userID)
+	case achievements.MetricComments:
+		return ac.db.CountUserComments(ctx, userID)
+	case achievements.MetricCorrectPredictions:
+		return ac.db.CountCorrectPredictions(ctx, userID)
+	case achievements.MetricExactScores:
+		return ac.db.CountExactScorePredictions(ctx, userID)
+	case achievements.MetricVerificationsGiven:
+		return ac.db.CountVerificationsGiven(ctx, userID)
+	case achievements.MetricVerifiedProfiles:
+		return ac.db.CountVerifiedPlayerProfiles(ctx, userID)
+	default:
+		return 0, fmt.Errorf("unknown metric %q", metric)
+	}
+}
+
+// achievementStore keeps unlocked achievements in user_achievements
+type achievementStore struct {
+	db *database.Queries
+}
+
+func (as *achievementStore) Unlocked(ctx context.Context, userID int32) (map[string]bool, error) {
+	rows, err := as.db.ListUserAchievements(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		unlocked[row.AchievementKey] = true
+	}
+	return unlocked, nil
+}
+
+func (as *achievementStore) Unlock(ctx context.Context, userID int32, key string) (bool, error) {
+	_, err := as.db.UnlockAchievement(ctx, database.UnlockAchievementParams{
+		UserID:         userID,
+		AchievementKey: key,
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// evaluateAchievements unlocks any achievements the event completes for the user
+func evaluateAchievements(ctx context.Context, db *database.Queries, eventType achievements.EventType, userID int32) error {
+	engine := achievements.NewEngine(achievements.Rules, &achievementCounter{db: db}, &achievementStore{db: db})
+	unlocked, err := engine.Evaluate(ctx, achievements.Event{Type: eventType, UserID: userID})
+	for _, rule := range unlocked {
+		fmt.Printf("User %d unlocked achievement %s\n", userID, rule.Key)
+	}
+	return err
+}
+
+// recordAchievementEvent evaluates achievements without failing the caller
+func (c *Config) recordAchievementEvent(ctx context.Context, eventType achievements.EventType, userID int32) {
+	if err := evaluateAchievements(ctx, c.DB, eventType, userID); err != nil {
+		fmt.Printf("Failed to evaluate achievements for user %d: %v\n", userID, err)
+	}
+}
+
+// userAchievements returns the user's unlocked achievements in the order they were earned
+func (c *Config) userAchievements(ctx context.Context, userID int32) ([]AchievementResponse, error) {
+	rows, err := c.DB.ListUserAchievements(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]AchievementResponse, 0, len(rows))
+	for _, row := range rows {
+		rule, ok := achievements.Find(row.AchievementKey)
+		if !ok {
+			// The rule has been retired
+			continue
+		}
+		response = append(response, newAchievementResponse(rule, &row.UnlockedAt))
+	}
+	return response, nil
+}
+
+func newAchievementResponse(rule achievements.Rule, unlockedAt *time.Time) AchievementResponse {
+	return AchievementResponse{
+		Key:         rule.Key,
+		Name:        rule.Name,
+		Description: rule.Description,
+		Threshold:   rule.Threshold,
+		UnlockedAt:  unlockedAt,
+	}
+}
+
+// listAchievements returns every achievement that can be unlocked
+func (c *Config) listAchievements(w http.ResponseWriter, r *http.Request) {
+	response := make([]AchievementResponse, len(achievements.Rules))
+	for i, rule := range achievements.Rules {
+		response[i] = newAchievementResponse(rule, nil)
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
@@ -2,15 +2,18 @@ package api
 
 import (
 	"database/sql"
-	"fmt"
 	"net/http"
 
 	"github.com/ArronJLinton/fucci-api/internal/ai"
 	"github.com/ArronJLinton/fucci-api/internal/auth"
 	"github.com/ArronJLinton/fucci-api/internal/cache"
 	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/leaderboard"
+	"github.com/ArronJLinton/fucci-api/internal/predictions"
+	"github.com/ArronJLinton/fucci-api/internal/sentiment"
+	"github.com/ArronJLinton/fucci-api/pkg/blobstore"
+	"github.com/ArronJLinton/fucci-api/pkg/mail"
 	"github.com/go-chi/chi"
-	"github.com/google/uuid"
 )
 
 // InitJWT initializes JWT authentication with the provided secret
@@ -19,15 +22,21 @@ func InitJWT(secret string) error {
 }
 
 type Config struct {
-	DB                 *database.Queries
-	DBConn             *sql.DB
-	FootballAPIKey     string
-	RapidAPIKey        string
-	Cache              cache.CacheInterface
-	APIFootballBaseURL string
-	OpenAIKey          string
-	OpenAIBaseURL      string
-	AIPromptGenerator  *ai.PromptGenerator
+	DB                   *database.Queries
+	DBConn               *sql.DB
+	FootballAPIKey       string
+	RapidAPIKey          string
+	Cache                cache.CacheInterface
+	APIFootballBaseURL   string
+	OpenAIKey            string
+	OpenAIBaseURL        string
+	AIPromptGenerator    *ai.PromptGenerator
+	SentimentScorer      sentiment.Scorer    // Optional, defaults to the lexicon scorer
+	Leaderboards         leaderboard.Store   // Optional, boards are ranked from Postgres when nil
+	ScorePredictionRules predictions.Rules   // Optional, defaults to predictions.DefaultRules
+	Blobs                blobstore.BlobStore // Optional, media uploads are disabled when nil
+	Mailer               mail.Sender         // Optional, email verification is disabled when nil
+	EmailVerificationURL string              // The verification token is appended to it
 }
 
 func New(c Config) http.Handler {
@@ -40,10 +49,10 @@ func New(c Config) http.Handler {
 
 	// Initialize services
 	teamsService := NewTeamsService(c.DB)
-	teamManagersService := NewTeamManagersService(c.DB)
+	teamManagersService := NewTeamManagersService(c.DB, c.DBConn)
 	leaguesService := NewLeaguesService(c.DB)
 	playerProfilesService := &PlayerProfileService{DB: c.DB}
-	verificationsService := &VerificationService{DB: c.DB, PlayerProfileSvc: &PlayerProfileService{DB: c.DB}}
+	verificationsService := &VerificationService{DB: c.DB, DBConn: c.DBConn, Blobs: c.Blobs, PlayerProfileSvc: &PlayerProfileService{DB: c.DB}}
 
 	// Health check routes
 	router.Get("/health", HandleReadiness)
@@ -54,18 +63,38 @@ func New(c Config) http.Handler {
 	authRouter := chi.NewRouter()
 	authRouter.Post("/register", c.handleCreateUser)
 	authRouter.Post("/login", c.handleLogin)
+	authRouter.Post("/verify-email", c.verifyEmail)
 
 	// User routes (authentication required)
 	userRouter := chi.NewRouter()
 	userRouter.Use(auth.RequireAuth)
 	userRouter.Get("/profile", c.handleGetProfile)
 	userRouter.Put("/profile", c.handleUpdateProfile)
+	userRouter.Put("/avatar", c.uploadAvatar)
+	userRouter.Post("/email-verification", c.requestEmailVerification)
+	userRouter.Post("/devices", c.registerDevice)
+	userRouter.Delete("/devices/{token}", c.unregisterDevice)
+	userRouter.Get("/notification-preferences", c.getNotificationPreferences)
+	userRouter.Put("/notification-preferences", c.updateNotificationPreferences)
+	userRouter.Get("/following", c.listFollowing)
+	userRouter.Get("/notifications", c.listNotifications)
+	userRouter.Post("/notifications/read", c.markAllNotificationsRead)
+	userRouter.Post("/notifications/{id}/read", c.markNotificationRead)
 
 	// Temp route for listing all users
 	userRouter.Get("/all", c.handleListAllUsers)
 
 	futbolRouter := chi.NewRouter()
 	futbolRouter.Get("/matches", c.getMatches)
+	futbolRouter.Get("/matches/{id}/predictions", c.getScorePredictionDistribution)
+	futbolRouter.With(auth.RequireAuth).Post("/matches/{id}/predictions", c.createScorePrediction)
+	futbolRouter.With(auth.RequireAuth).Get("/matches/{id}/predictions/me", c.getMyScorePrediction)
+	futbolRouter.With(auth.RequireAuth, auth.RequireRole("admin")).Post("/matches/{id}/predictions/score", c.scoreMatchPredictionsHandler)
+	futbolRouter.With(auth.RequireAuth).Post("/matches/{id}/follow", c.followMatch)
+	futbolRouter.With(auth.RequireAuth).Delete("/matches/{id}/follow", c.unfollowMatch)
+	futbolRouter.Get("/matches/{id}/media", c.listMatchMedia)
+	futbolRouter.With(auth.RequireAuth).Post("/matches/{id}/media", c.uploadMatchMedia)
+	futbolRouter.With(auth.RequireAuth).Delete("/matches/{id}/media/{mediaId}", c.deleteMatchMedia)
 	futbolRouter.Get("/lineup", c.getMatchLineup)
 	futbolRouter.Get("/leagues", c.getLeagues)
 	futbolRouter.Get("/team_standings", c.getLeagueStandingsByTeamId)
@@ -75,82 +104,178 @@ func New(c Config) http.Handler {
 	googleRouter.Get("/search", c.search)
 
 	debateRouter := chi.NewRouter()
-	debateRouter.Post("/", c.createDebate)
+	debateRouter.With(auth.RequireAuth).Post("/", c.createDebate)
 	debateRouter.Get("/top", c.getTopDebates)
+	debateRouter.Get("/trending", c.getTrendingDebates)
+	debateRouter.With(auth.RequireAuth).Get("/feed", c.getDebateFeed)
+	debateRouter.With(auth.RequireAuth, auth.RequireRole("admin")).Get("/proposed", c.listProposedDebates)
+	debateRouter.With(auth.RequireAuth, auth.RequireRole("admin")).Post("/{id}/approve", c.approveDebate)
+	debateRouter.With(auth.RequireAuth, auth.RequireRole("admin")).Post("/{id}/reject", c.rejectDebate)
+	debateRouter.With(auth.RequireAuth, auth.RequireRole("admin")).Post("/{id}/resolve", c.resolveDebate)
 	debateRouter.Get("/generate", c.generateAIPrompt)
 	debateRouter.Post("/generate", c.generateDebate)
 	debateRouter.Get("/health", c.checkDebateGenerationHealth)
 	debateRouter.Get("/match", c.getDebatesByMatch)
 	debateRouter.Get("/{id}", c.getDebate)
-	debateRouter.Post("/cards", c.createDebateCard)
-	debateRouter.Post("/votes", c.createVote)
-	debateRouter.Post("/comments", c.createComment)
+	debateRouter.With(auth.RequireAuth).Post("/cards", c.createDebateCard)
+	debateRouter.With(auth.RequireAuth).Post("/votes", c.createVote)
+	debateRouter.With(auth.RequireAuth).Post("/comments", c.createComment)
 	debateRouter.Get("/{debateId}/comments", c.getComments)
 	// Admin routes for soft delete management
 	debateRouter.Delete("/{id}/hard", c.hardDeleteDebate) // Permanent deletion
 	debateRouter.Post("/{id}/restore", c.restoreDebate)   // Restore soft-deleted debate
 
+	achievementsRouter := chi.NewRouter()
+	achievementsRouter.Get("/", c.listAchievements)
+
+	leaderboardRouter := chi.NewRouter()
+	leaderboardRouter.Get("/", c.getLeaderboard)
+	leaderboardRouter.With(auth.RequireAuth).Get("/me", c.getMyLeaderboardRank)
+	leaderboardRouter.With(auth.RequireAuth, auth.RequireRole("admin")).Post("/recalculate", c.recalculateLeaderboards)
+
 	// Teams routes
 	teamsRouter := chi.NewRouter()
 	teamsRouter.Post("/", teamsService.CreateTeam)
-	teamsRouter.Get("/", teamsService.ListTeams)
-	teamsRouter.Get("/{id}", teamsService.GetTeam)
+	teamsRouter.With(auth.OptionalAuth).Get("/", teamsService.ListTeams)
+	teamsRouter.With(auth.OptionalAuth).Get("/{id}", teamsService.GetTeam)
 	teamsRouter.Put("/{id}", teamsService.UpdateTeam)
 	teamsRouter.Delete("/{id}", teamsService.DeleteTeam)
 	teamsRouter.Get("/{id}/stats", teamsService.GetTeamStats)
+	teamsRouter.With(auth.RequireAuth).Post("/{id}/follow", c.followTeam)
+	teamsRouter.With(auth.RequireAuth).Delete("/{id}/follow", c.unfollowTeam)
+	teamsRouter.With(auth.RequireAuth).Put("/{id}/logo", c.uploadTeamLogo)
+	teamsRouter.Get("/{id}/roster", c.getTeamRoster)
+	teamsRouter.Get("/{id}/top-scorers", c.getTeamTopScorers)
+	teamsRouter.Get("/{id}/top-assists", c.getTeamTopAssists)
+	teamsRouter.With(auth.RequireAuth).Delete("/{id}/roster/{playerId}", c.releasePlayer)
+	teamsRouter.With(auth.RequireAuth).Get("/{id}/invitations", c.listTeamInvitations)
+	teamsRouter.With(auth.RequireAuth).Post("/{id}/invitations", c.invitePlayer)
+	teamsRouter.With(auth.RequireAuth).Get("/{id}/shortlist", c.getTeamShortlist)
+	teamsRouter.With(auth.RequireAuth).Post("/{id}/shortlist", c.shortlistPlayer)
+	teamsRouter.With(auth.RequireAuth).Delete("/{id}/shortlist/{playerId}", c.unshortlistPlayer)
+	teamsRouter.With(auth.RequireAuth).Get("/{id}/trials", c.listTeamTrials)
+	teamsRouter.With(auth.RequireAuth).Post("/{id}/trials", c.inviteToTrial)
+	teamsRouter.Get("/{id}/managers", c.listTeamManagers)
+	teamsRouter.With(auth.RequireAuth).Put("/{id}/managers/{managerId}", c.updateManagerRole)
+	teamsRouter.With(auth.RequireAuth).Delete("/{id}/managers/{managerId}", c.revokeManager)
+	teamsRouter.With(auth.RequireAuth).Get("/{id}/manager-invitations", c.listTeamManagerInvitations)
+	teamsRouter.With(auth.RequireAuth).Post("/{id}/manager-invitations", c.inviteManager)
+
+	// Invitations for players to join team rosters
+	rosterInvitationsRouter := chi.NewRouter()
+	rosterInvitationsRouter.Use(auth.RequireAuth)
+	rosterInvitationsRouter.Get("/", c.listMyInvitations)
+	rosterInvitationsRouter.Post("/{id}/accept", c.acceptInvitation)
+	rosterInvitationsRouter.Post("/{id}/decline", c.declineInvitation)
+	rosterInvitationsRouter.Delete("/{id}", c.cancelInvitation)
+
+	// Invitations for players to trial with teams
+	trialInvitationsRouter := chi.NewRouter()
+	trialInvitationsRouter.Use(auth.RequireAuth)
+	trialInvitationsRouter.Get("/", c.listMyTrials)
+	trialInvitationsRouter.Post("/{id}/accept", c.acceptTrial)
+	trialInvitationsRouter.Post("/{id}/decline", c.declineTrial)
+	trialInvitationsRouter.Delete("/{id}", c.cancelTrial)
+
+	// Invitations for users to help manage teams
+	managerInvitationsRouter := chi.NewRouter()
+	managerInvitationsRouter.Use(auth.RequireAuth)
+	managerInvitationsRouter.Get("/", c.listMyManagerInvitations)
+	managerInvitationsRouter.Post("/{id}/accept", c.acceptManagerInvitation)
+	managerInvitationsRouter.Post("/{id}/decline", c.declineManagerInvitation)
+	managerInvitationsRouter.Delete("/{id}", c.cancelManagerInvitation)
 
-	// Team Managers routes
+	// Team Managers routes. League owners add managers by invitation.
 	teamManagersRouter := chi.NewRouter()
-	teamManagersRouter.Post("/", teamManagersService.CreateTeamManager)
+	teamManagersRouter.With(auth.RequireAuth, auth.RequireRole("admin")).Post("/", teamManagersService.CreateTeamManager)
 	teamManagersRouter.Get("/", teamManagersService.ListTeamManagers)
 	teamManagersRouter.Get("/{id}", teamManagersService.GetTeamManager)
-	teamManagersRouter.Put("/{id}", teamManagersService.UpdateTeamManager)
-	teamManagersRouter.Delete("/{id}", teamManagersService.DeleteTeamManager)
+	teamManagersRouter.With(auth.RequireAuth).Put("/{id}", teamManagersService.UpdateTeamManager)
+	teamManagersRouter.With(auth.RequireAuth).Delete("/{id}", teamManagersService.DeleteTeamManager)
 	teamManagersRouter.Get("/{id}/stats", teamManagersService.GetManagerStats)
 
 	// Leagues routes
 	leaguesRouter := chi.NewRouter()
-	leaguesRouter.Post("/", leaguesService.CreateLeague)
+	leaguesRouter.With(auth.RequireAuth).Post("/", leaguesService.CreateLeague)
 	leaguesRouter.Get("/", leaguesService.ListLeagues)
 	leaguesRouter.Get("/{id}", leaguesService.GetLeague)
-	leaguesRouter.Put("/{id}", leaguesService.UpdateLeague)
-	leaguesRouter.Delete("/{id}", leaguesService.DeleteLeague)
+	leaguesRouter.With(auth.RequireAuth).Put("/{id}", leaguesService.UpdateLeague)
+	leaguesRouter.With(auth.RequireAuth).Delete("/{id}", leaguesService.DeleteLeague)
 	leaguesRouter.Get("/{id}/stats", leaguesService.GetLeagueStats)
+	leaguesRouter.With(auth.RequireAuth).Put("/{id}/logo", c.uploadLeagueLogo)
+	leaguesRouter.Get("/{id}/fixtures", c.listLeagueFixtures)
+	leaguesRouter.With(auth.RequireAuth).Post("/{id}/fixtures", c.createFixture)
+	leaguesRouter.With(auth.RequireAuth).Post("/{id}/schedule/preview", c.previewLeagueSchedule)
+	leaguesRouter.With(auth.RequireAuth).Post("/{id}/schedule", c.generateLeagueSchedule)
+	leaguesRouter.Get("/{id}/standings", c.getLeagueStandings)
+	leaguesRouter.Get("/{id}/standings/rules", c.getLeagueStandingsRules)
+	leaguesRouter.With(auth.RequireAuth).Put("/{id}/standings/rules", c.updateLeagueStandingsRules)
+	leaguesRouter.Get("/{id}/seasons", c.listLeagueSeasons)
+	leaguesRouter.With(auth.RequireAuth).Post("/{id}/seasons", c.createSeason)
+	leaguesRouter.Get("/{id}/roster/rules", c.getLeagueRosterRules)
+	leaguesRouter.Get("/{id}/top-scorers", c.getLeagueTopScorers)
+	leaguesRouter.Get("/{id}/top-assists", c.getLeagueTopAssists)
+	leaguesRouter.With(auth.RequireAuth).Put("/{id}/roster/rules", c.updateLeagueRosterRules)
+
+	// Seasons of local leagues
+	seasonsRouter := chi.NewRouter()
+	seasonsRouter.Get("/{id}", c.getSeason)
+	seasonsRouter.With(auth.RequireAuth).Put("/{id}", c.updateSeason)
+	seasonsRouter.With(auth.RequireAuth).Post("/{id}/teams", c.registerSeasonTeam)
+	seasonsRouter.With(auth.RequireAuth).Delete("/{id}/teams/{teamId}", c.removeSeasonTeam)
+	seasonsRouter.Get("/{id}/registration-windows", c.listRegistrationWindows)
+	seasonsRouter.With(auth.RequireAuth).Post("/{id}/registration-windows", c.createRegistrationWindow)
+	seasonsRouter.With(auth.RequireAuth).Delete("/{id}/registration-windows/{windowId}", c.deleteRegistrationWindow)
+
+	// Fixtures between local teams
+	fixturesRouter := chi.NewRouter()
+	fixturesRouter.Get("/{id}", c.getFixture)
+	fixturesRouter.With(auth.RequireAuth).Put("/{id}", c.updateFixture)
+	fixturesRouter.With(auth.RequireAuth).Put("/{id}/result", c.recordFixtureResult)
+	fixturesRouter.With(auth.RequireAuth).Post("/{id}/result/confirm", c.confirmFixtureResult)
+	fixturesRouter.Get("/{id}/appearances", c.getFixtureAppearances)
+	fixturesRouter.With(auth.RequireAuth).Put("/{id}/appearances", c.recordAppearances)
 
 	// Player Profiles routes
 	playerProfilesRouter := chi.NewRouter()
-	playerProfilesRouter.Post("/", playerProfilesService.CreatePlayerProfile)
-	playerProfilesRouter.Get("/{id}", playerProfilesService.GetPlayerProfile)
-	playerProfilesRouter.Put("/{id}", playerProfilesService.UpdatePlayerProfile)
-	playerProfilesRouter.Delete("/{id}", playerProfilesService.DeletePlayerProfile)
+	playerProfilesRouter.With(auth.RequireAuth).Post("/", playerProfilesService.CreatePlayerProfile)
+	playerProfilesRouter.Get("/search", c.searchPlayers)
+	playerProfilesRouter.With(auth.OptionalAuth).Get("/{id}", playerProfilesService.GetPlayerProfile)
+	playerProfilesRouter.With(auth.RequireAuth).Put("/{id}", playerProfilesService.UpdatePlayerProfile)
+	playerProfilesRouter.With(auth.RequireAuth).Delete("/{id}", playerProfilesService.DeletePlayerProfile)
+	playerProfilesRouter.With(auth.RequireAuth).Post("/{id}/follow", c.followPlayer)
+	playerProfilesRouter.With(auth.RequireAuth).Delete("/{id}/follow", c.unfollowPlayer)
+	playerProfilesRouter.Get("/{id}/transfers", c.listPlayerTransfers)
+	playerProfilesRouter.Get("/{id}/stats", c.getPlayerStats)
+	playerProfilesRouter.Get("/{id}/attributes", c.getPlayerAttributes)
+	playerProfilesRouter.Get("/{id}/attributes/history", c.getAttributeHistory)
+	playerProfilesRouter.With(auth.RequireAuth).Put("/{id}/ratings", c.rateAttributes)
 
 	// Verifications routes
 	verificationsRouter := chi.NewRouter()
-	verificationsRouter.Post("/", verificationsService.AddVerification)
-	verificationsRouter.Delete("/{id}", verificationsService.RemoveVerification)
+	verificationsRouter.With(auth.RequireAuth).Post("/", verificationsService.AddVerification)
+	verificationsRouter.With(auth.RequireAuth).Delete("/{id}", verificationsService.RemoveVerification)
+	verificationsRouter.With(auth.RequireAuth).Post("/{id}/evidence", verificationsService.AddEvidence)
 	verificationsRouter.Get("/player/{playerId}", verificationsService.ListVerifications)
+	verificationsRouter.Get("/player/{playerId}/history", verificationsService.ListVerificationHistory)
 
 	router.Mount("/auth", authRouter)
 	router.Mount("/users", userRouter)
 	router.Mount("/futbol", futbolRouter)
 	router.Mount("/google", googleRouter)
 	router.Mount("/debates", debateRouter)
+	router.Mount("/leaderboards", leaderboardRouter)
+	router.Mount("/achievements", achievementsRouter)
 	router.Mount("/teams", teamsRouter)
 	router.Mount("/team-managers", teamManagersRouter)
+	router.Mount("/roster-invitations", rosterInvitationsRouter)
+	router.Mount("/trial-invitations", trialInvitationsRouter)
+	router.Mount("/manager-invitations", managerInvitationsRouter)
 	router.Mount("/leagues", leaguesRouter)
+	router.Mount("/seasons", seasonsRouter)
+	router.Mount("/fixtures", fixturesRouter)
 	router.Mount("/player-profiles", playerProfilesRouter)
 	router.Mount("/verifications", verificationsRouter)
 
 	return router
 }
-
-// getUserIDFromContext extracts user ID from request context (set by auth middleware)
-func getUserIDFromContext(r *http.Request) uuid.UUID {
-	userID, ok := r.Context().Value("user_id").(int32)
-	if !ok {
-		// Return default UUID if no user ID in context (for backward compatibility)
-		return uuid.MustParse("00000000-0000-0000-0000-000000000001")
-	}
-	// Convert int32 to UUID by creating a zero-padded UUID
-	return uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", userID))
-}
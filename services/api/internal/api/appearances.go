@@ -0,0 +1,452 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+const (
+	topPlayersDefaultLimit = 10
+	topPlayersMaxLimit     = 50
+
+	// cleanSheetMinutes is how long a player has to play without their team
+	// conceding to keep a clean sheet
+	cleanSheetMinutes = 60
+)
+
+// AppearancesRequest replaces one team's appearances in a fixture
+type AppearancesRequest struct {
+	TeamID      uuid.UUID        `json:"team_id"`
+	Appearances []AppearanceData `json:"appearances"`
+}
+
+// AppearanceData is a player's appearance. Goals come from the result's
+// scorers and clean sheets from the score, so they aren't entered here.
+type AppearanceData struct {
+	PlayerProfileID uuid.UUID `json:"player_profile_id"`
+	Minutes         int32     `json:"minutes"`
+	Assists         int32     `json:"assists"`
+	YellowCards     int32     `json:"yellow_cards"`
+	RedCard         bool      `json:"red_card"`
+	Rating          *int32    `json:"rating,omitempty"` // The manager's rating out of 10
+}
+
+type AppearanceResponse struct {
+	AppearanceData
+	Name       string    `json:"name"`
+	TeamID     uuid.UUID `json:"team_id"`
+	Goals      int64     `json:"goals"`
+	CleanSheet bool      `json:"clean_sheet"`
+}
+
+// PlayerSeasonStats are a player's totals for a team in a season. Players who
+// moved mid-season have a row for each team.
+type PlayerSeasonStats struct {
+	LeagueID      uuid.UUID   `json:"league_id"`
+	LeagueName    string      `json:"league_name"`
+	SeasonID      *uuid.UUID  `json:"season_id"` // null in leagues without seasons
+	SeasonName    string      `json:"season_name,omitempty"`
+	Team          FixtureTeam `json:"team"`
+	Appearances   int64       `json:"appearances"`
+	Minutes       int64       `json:"minutes"`
+	Goals         int64       `json:"goals"`
+	Assists       int64       `json:"assists"`
+	YellowCards   int64       `json:"yellow_cards"`
+	RedCards      int64       `json:"red_cards"`
+	CleanSheets   int64       `json:"clean_sheets"`
+	AverageRating *float64    `json:"average_rating"` // null until a manager rates them
+}
+
+type TopPlayer struct {
+	Rank            int         `json:"rank"`
+	PlayerProfileID uuid.UUID   `json:"player_profile_id"`
+	Name            string      `json:"name"`
+	Team            FixtureTeam `json:"team"`
+	Count           int64       `json:"count"` // Goals or assists
+}
+
+type TopPlayersResponse struct {
+	LeagueID uuid.UUID   `json:"league_id"`
+	SeasonID *uuid.UUID  `json:"season_id,omitempty"`
+	Stat     string      `json:"stat"` // goals or assists
+	Players  []TopPlayer `json:"players"`
+}
+
+// recordAppearances enters who played for a team in a finished fixture,
+// replacing any entered before. Only the team's manager and the league's
+// owner can enter them, and only for players on the team at kick-off.
+func (c *Config) recordAppearances(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req AppearancesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.TeamID == uuid.Nil {
+		respondWithError(w, http.StatusBadRequest, "team_id is required")
+		return
+	}
+
+	match, ok := c.fixtureFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := validateAppearances(req, match); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	access, ok := c.fixtureAccessForMatch(w, r, match)
+	if !ok {
+		return
+	}
+	home := req.TeamID == match.HomeTeamID.UUID
+	if !access.organiser && !(home && access.home) && !(!home && access.away) {
+		respondWithError(w, http.StatusForbidden, "Only the team's manager or the league's owner can enter its appearances")
+		return
+	}
+	if match.Status != database.MatchStatusFinished {
+		respondWithError(w, http.StatusConflict, "Enter the fixture's result first")
+		return
+	}
+	if !c.fixtureSeasonOpen(w, ctx, match) {
+		return
+	}
+
+	squad, err := c.DB.ListTeamPlayersAt(ctx, database.ListTeamPlayersAtParams{At: match.MatchDate, TeamID: uuid.NullUUID{UUID: req.TeamID, Valid: true}})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get squad: %v", err))
+		return
+	}
+	onTeam := make(map[uuid.UUID]bool, len(squad))
+	for _, id := range squad {
+		onTeam[id] = true
+	}
+	for _, appearance := range req.Appearances {
+		if !onTeam[appearance.PlayerProfileID] {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Player %s wasn't on the team for this fixture", appearance.PlayerProfileID))
+			return
+		}
+	}
+
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Appearances can't be entered right now")
+		return
+	}
+	if err := c.saveAppearances(ctx, match.ID, userID, req); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save appearances: %v", err))
+		return
+	}
+	c.respondWithAppearances(w, ctx, match)
+}
+
+func (c *Config) saveAppearances(ctx context.Context, matchID uuid.UUID, userID int32, req AppearancesRequest) error {
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	if err := q.DeleteTeamAppearances(ctx, database.DeleteTeamAppearancesParams{MatchID: matchID, TeamID: req.TeamID}); err != nil {
+		return err
+	}
+	for _, appearance := range req.Appearances {
+		params := database.CreateMatchAppearanceParams{
+			MatchID:         matchID,
+			TeamID:          req.TeamID,
+			PlayerProfileID: appearance.PlayerProfileID,
+			Minutes:         appearance.Minutes,
+			Assists:         appearance.Assists,
+			YellowCards:     appearance.YellowCards,
+			RedCard:         appearance.RedCard,
+			RecordedBy:      sql.NullInt32{Int32: userID, Valid: true},
+		}
+		if appearance.Rating != nil {
+			params.Rating = sql.NullInt32{Int32: *appearance.Rating, Valid: true}
+		}
+		if err := q.CreateMatchAppearance(ctx, params); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *Config) getFixtureAppearances(w http.ResponseWriter, r *http.Request) {
+	match, ok := c.fixtureFromRequest(w, r)
+	if !ok {
+		return
+	}
+	c.respondWithAppearances(w, r.Context(), match)
+}
+
+// validateAppearances checks an appearance list against the fixture. A team
+// can't have more assists than goals.
+func validateAppearances(req AppearancesRequest, match database.Match) error {
+	var scored int32
+	switch req.TeamID {
+	case match.HomeTeamID.UUID:
+		scored = match.HomeScore.Int32
+	case match.AwayTeamID.UUID:
+		scored = match.AwayScore.Int32
+	default:
+		return errors.New("team_id must be one of the fixture's teams")
+	}
+
+	var assists int32
+	seen := make(map[uuid.UUID]bool, len(req.Appearances))
+	for _, appearance := range req.Appearances {
+		if appearance.PlayerProfileID == uuid.Nil {
+			return errors.New("Every appearance needs a player_profile_id")
+		}
+		if seen[appearance.PlayerProfileID] {
+			return fmt.Errorf("Player %s is listed twice", appearance.PlayerProfileID)
+		}
+		seen[appearance.PlayerProfileID] = true
+
+		if appearance.Minutes < 0 || appearance.Minutes > 130 {
+			return errors.New("Minutes must be between 0 and 130")
+		}
+		if appearance.Assists < 0 {
+			return errors.New("Assists can't be negative")
+		}
+		if appearance.YellowCards < 0 || appearance.YellowCards > 2 {
+			return errors.New("Yellow cards must be between 0 and 2")
+		}
+		if appearance.Rating != nil && (*appearance.Rating < 1 || *appearance.Rating > 10) {
+			return errors.New("Ratings must be between 1 and 10")
+		}
+		assists += appearance.Assists
+	}
+	if assists > scored {
+		return errors.New("More assists listed than the team scored")
+	}
+	return nil
+}
+
+func (c *Config) respondWithAppearances(w http.ResponseWriter, ctx context.Context, match database.Match) {
+	rows, err := c.DB.ListMatchAppearances(ctx, match.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list appearances: %v", err))
+		return
+	}
+
+	response := make([]AppearanceResponse, 0, len(rows))
+	for _, row := range rows {
+		appearance := AppearanceResponse{
+			AppearanceData: AppearanceData{
+				PlayerProfileID: row.PlayerProfileID,
+				Minutes:         row.Minutes,
+				Assists:         row.Assists,
+				YellowCards:     row.YellowCards,
+				RedCard:         row.RedCard,
+			},
+			Name:       strings.TrimSpace(row.Firstname + " " + row.Lastname),
+			TeamID:     row.TeamID,
+			Goals:      row.Goals,
+			CleanSheet: cleanSheet(match, row.TeamID, row.Minutes),
+		}
+		if row.Rating.Valid {
+			appearance.Rating = &row.Rating.Int32
+		}
+		response = append(response, appearance)
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// cleanSheet reports whether a player for teamID kept a clean sheet, playing
+// at least cleanSheetMinutes without the team conceding
+func cleanSheet(match database.Match, teamID uuid.UUID, minutes int32) bool {
+	conceded := match.HomeScore
+	if teamID == match.HomeTeamID.UUID {
+		conceded = match.AwayScore
+	}
+	return minutes >= cleanSheetMinutes && conceded.Valid && conceded.Int32 == 0
+}
+
+func (c *Config) getPlayerStats(w http.ResponseWriter, r *http.Request) {
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid player profile ID")
+		return
+	}
+	if _, err := c.DB.GetPlayerProfile(r.Context(), profileID); err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Player profile not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+	stats, err := playerSeasonStats(r.Context(), c.DB, profileID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get stats: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, stats)
+}
+
+// playerSeasonStats are a player's totals for each team and season they've
+// played in, from confirmed fixtures, newest season first
+func playerSeasonStats(ctx context.Context, db *database.Queries, profileID uuid.UUID) ([]PlayerSeasonStats, error) {
+	rows, err := db.ListPlayerSeasonStats(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]PlayerSeasonStats, 0, len(rows))
+	for _, row := range rows {
+		season := PlayerSeasonStats{
+			LeagueID:      row.LeagueID,
+			LeagueName:    row.LeagueName,
+			SeasonName:    row.SeasonName.String,
+			Team:          FixtureTeam{ID: row.TeamID, Name: row.TeamName},
+			Appearances:   row.Appearances,
+			Minutes:       row.Minutes,
+			Goals:         row.Goals,
+			Assists:       row.Assists,
+			YellowCards:   row.YellowCards,
+			RedCards:      row.RedCards,
+			CleanSheets:   row.CleanSheets,
+			AverageRating: averageRating(row.RatingTotal, row.Ratings),
+		}
+		if row.SeasonID.Valid {
+			season.SeasonID = &row.SeasonID.UUID
+		}
+		stats = append(stats, season)
+	}
+	return stats, nil
+}
+
+// averageRating is total/count to one decimal place, or nil without ratings
+func averageRating(total, count int64) *float64 {
+	if count == 0 {
+		return nil
+	}
+	average := math.Round(float64(total)/float64(count)*10) / 10
+	return &average
+}
+
+func (c *Config) getLeagueTopScorers(w http.ResponseWriter, r *http.Request) {
+	c.leagueTopPlayers(w, r, "goals")
+}
+
+func (c *Config) getLeagueTopAssists(w http.ResponseWriter, r *http.Request) {
+	c.leagueTopPlayers(w, r, "assists")
+}
+
+// leagueTopPlayers ranks the league's players by goals or assists, for
+// season_id or the current season
+func (c *Config) leagueTopPlayers(w http.ResponseWriter, r *http.Request, stat string) {
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+	c.respondWithTopPlayers(w, r, leagueID, uuid.NullUUID{}, stat)
+}
+
+func (c *Config) getTeamTopScorers(w http.ResponseWriter, r *http.Request) {
+	c.teamTopPlayers(w, r, "goals")
+}
+
+func (c *Config) getTeamTopAssists(w http.ResponseWriter, r *http.Request) {
+	c.teamTopPlayers(w, r, "assists")
+}
+
+// teamTopPlayers ranks a team's players by the goals or assists they've
+// made for it in its league
+func (c *Config) teamTopPlayers(w http.ResponseWriter, r *http.Request, stat string) {
+	team, ok := c.teamFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if !team.LeagueID.Valid {
+		respondWithError(w, http.StatusNotFound, "Team isn't in a league")
+		return
+	}
+	c.respondWithTopPlayers(w, r, team.LeagueID.UUID, uuid.NullUUID{UUID: team.ID, Valid: true}, stat)
+}
+
+func (c *Config) respondWithTopPlayers(w http.ResponseWriter, r *http.Request, leagueID uuid.UUID, teamID uuid.NullUUID, stat string) {
+	ctx := r.Context()
+
+	seasonID, ok := seasonIDParam(w, r)
+	if !ok {
+		return
+	}
+	limit := int32(topPlayersDefaultLimit)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.ParseInt(limitStr, 10, 32); err == nil && l > 0 {
+			limit = min(int32(l), topPlayersMaxLimit)
+		}
+	}
+
+	if _, ok := c.fixtureLeague(w, ctx, leagueID); !ok {
+		return
+	}
+	season, ok := c.leagueSeason(w, ctx, leagueID, seasonID)
+	if !ok {
+		return
+	}
+	teams, err := c.leagueTeamNames(ctx, leagueID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get teams: %v", err))
+		return
+	}
+
+	response := TopPlayersResponse{LeagueID: leagueID, Stat: stat, Players: []TopPlayer{}}
+	if season.ID != uuid.Nil {
+		response.SeasonID = &season.ID
+	}
+	league := uuid.NullUUID{UUID: leagueID, Valid: true}
+	if stat == "goals" {
+		rows, err := c.DB.ListTopScorers(ctx, database.ListTopScorersParams{LeagueID: league, SeasonID: seasonFilter(season), TeamID: teamID, RowLimit: limit})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get top scorers: %v", err))
+			return
+		}
+		for _, row := range rows {
+			response.Players = append(response.Players, TopPlayer{PlayerProfileID: row.PlayerProfileID, Name: strings.TrimSpace(row.Firstname + " " + row.Lastname), Team: FixtureTeam{ID: row.TeamID, Name: teams[row.TeamID]}, Count: row.Goals})
+		}
+	} else {
+		rows, err := c.DB.ListTopAssists(ctx, database.ListTopAssistsParams{LeagueID: league, SeasonID: seasonFilter(season), TeamID: teamID, RowLimit: limit})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get top assists: %v", err))
+			return
+		}
+		for _, row := range rows {
+			response.Players = append(response.Players, TopPlayer{PlayerProfileID: row.PlayerProfileID, Name: strings.TrimSpace(row.Firstname + " " + row.Lastname), Team: FixtureTeam{ID: row.TeamID, Name: teams[row.TeamID]}, Count: row.Assists})
+		}
+	}
+	rankTopPlayers(response.Players)
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// rankTopPlayers ranks players sorted by count, sharing ranks on ties
+func rankTopPlayers(players []TopPlayer) {
+	for i := range players {
+		if i > 0 && players[i].Count == players[i-1].Count {
+			players[i].Rank = players[i-1].Rank
+		} else {
+			players[i].Rank = i + 1
+		}
+	}
+}
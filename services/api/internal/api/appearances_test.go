@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestValidateAppearances(t *testing.T) {
+	home, away := uuid.New(), uuid.New()
+	match := database.Match{
+		HomeTeamID: uuid.NullUUID{UUID: home, Valid: true},
+		AwayTeamID: uuid.NullUUID{UUID: away, Valid: true},
+		HomeScore:  sql.NullInt32{Int32: 2, Valid: true},
+		AwayScore:  sql.NullInt32{Int32: 0, Valid: true},
+	}
+	player, other := uuid.New(), uuid.New()
+	rating := func(r int32) *int32 { return &r }
+
+	tests := []struct {
+		name    string
+		req     AppearancesRequest
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			req: AppearancesRequest{TeamID: home, Appearances: []AppearanceData{
+				{PlayerProfileID: player, Minutes: 90, Assists: 1, Rating: rating(8)},
+				{PlayerProfileID: other, Minutes: 30, Assists: 1, YellowCards: 1},
+			}},
+		},
+		{name: "no appearances", req: AppearancesRequest{TeamID: away}},
+		{name: "another team", req: AppearancesRequest{TeamID: uuid.New()}, wantErr: true},
+		{name: "missing player", req: AppearancesRequest{TeamID: home, Appearances: []AppearanceData{{Minutes: 90}}}, wantErr: true},
+		{name: "player twice", req: AppearancesRequest{TeamID: home, Appearances: []AppearanceData{{PlayerProfileID: player}, {PlayerProfileID: player}}}, wantErr: true},
+		{name: "too many minutes", req: AppearancesRequest{TeamID: home, Appearances: []AppearanceData{{PlayerProfileID: player, Minutes: 131}}}, wantErr: true},
+		{name: "three yellows", req: AppearancesRequest{TeamID: home, Appearances: []AppearanceData{{PlayerProfileID: player, YellowCards: 3}}}, wantErr: true},
+		{name: "rating out of range", req: AppearancesRequest{TeamID: home, Appearances: []AppearanceData{{PlayerProfileID: player, Rating: rating(11)}}}, wantErr: true},
+		{name: "more assists than goals", req: AppearancesRequest{TeamID: away, Appearances: []AppearanceData{{PlayerProfileID: player, Assists: 1}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAppearances(tt.req, match)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAppearances() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCleanSheet(t *testing.T) {
+	home, away := uuid.New(), uuid.New()
+	match := database.Match{
+		HomeTeamID: uuid.NullUUID{UUID: home, Valid: true},
+		AwayTeamID: uuid.NullUUID{UUID: away, Valid: true},
+		HomeScore:  sql.NullInt32{Int32: 1, Valid: true},
+		AwayScore:  sql.NullInt32{Int32: 0, Valid: true},
+	}
+
+	if !cleanSheet(match, home, 90) {
+		t.Error("Expected a clean sheet for the home side")
+	}
+	if cleanSheet(match, home, 59) {
+		t.Error("Expected no clean sheet for a substitute")
+	}
+	if cleanSheet(match, away, 90) {
+		t.Error("Expected no clean sheet for the side that conceded")
+	}
+}
+
+func TestAverageRating(t *testing.T) {
+	if got := averageRating(0, 0); got != nil {
+		t.Errorf("Expected nil without ratings, got %v", *got)
+	}
+	if got := averageRating(20, 3); got == nil || *got != 6.7 {
+		t.Errorf("Expected 6.7, got %v", got)
+	}
+}
+
+func TestRankTopPlayers(t *testing.T) {
+	players := []TopPlayer{{Count: 9}, {Count: 7}, {Count: 7}, {Count: 3}}
+	rankTopPlayers(players)
+
+	want := []int{1, 2, 2, 4}
+	for i, player := range players {
+		if player.Rank != want[i] {
+			t.Errorf("Player %d: expected rank %d, got %d", i, want[i], player.Rank)
+		}
+	}
+}
+
+func TestRecordAppearancesValidation(t *testing.T) {
+	for _, body := range []string{`not json`, `{"appearances":[]}`} {
+		config := &Config{}
+		req := authedRequest("PUT", "/fixtures/x/appearances", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.recordAppearances(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/ratings"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Where a player's attributes come from
+const (
+	attributesSelf      = "self"      // What the player reported
+	attributesCommunity = "community" // Aggregated from other people's ratings
+)
+
+// How a rater knows the player they're rating
+const (
+	relationshipTeammate = "teammate"
+	relationshipOpponent = "opponent"
+	relationshipManager  = "manager"
+)
+
+// Managers see players more closely than teammates or opponents, and the
+// league's owner or the manager of the player's own team counts for more again
+const (
+	weightPlayer           = 1
+	weightManager          = 2
+	weightAppointedManager = 3
+)
+
+const (
+	attributeHistoryDefaultLimit = 50
+	attributeHistoryMaxLimit     = 200
+)
+
+// PlayerAttributes shows what a player reports next to what the people who
+// know them think
+type PlayerAttributes struct {
+	Self      ratings.Attributes   `json:"self"`
+	Community *CommunityAttributes `json:"community"` // null until enough people have rated the player
+}
+
+type CommunityAttributes struct {
+	ratings.Attributes
+	Raters    int32     `json:"raters"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AttributeRatingRequest struct {
+	ratings.Attributes
+}
+
+type AttributeRatingResponse struct {
+	Relationship string             `json:"relationship"`
+	Weight       int32              `json:"weight"`
+	Rating       ratings.Attributes `json:"rating"`
+	Attributes   PlayerAttributes   `json:"attributes"`
+}
+
+type AttributeHistoryEntry struct {
+	Source string `json:"source"`
+	ratings.Attributes
+	Raters     *int32    `json:"raters,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// rateAttributes records the caller's rating of a player, replacing any
+// earlier one, and recomputes the player's community attributes. Only
+// teammates, opponents and managers can rate a player.
+func (c *Config) rateAttributes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req AttributeRatingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(ratings.MinValue); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid player profile ID")
+		return
+	}
+	profile, err := c.DB.GetPlayerProfile(ctx, profileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Player profile not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+	if profile.UserID == userID {
+		respondWithError(w, http.StatusForbidden, "You can't rate your own attributes")
+		return
+	}
+
+	relationship, err := c.DB.GetRaterRelationship(ctx, database.GetRaterRelationshipParams{RaterID: userID, PlayerProfileID: profile.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check how you know this player: %v", err))
+		return
+	}
+	kind, weight, ok := raterWeight(relationship)
+	if !ok {
+		respondWithError(w, http.StatusForbidden, "Only the player's teammates, opponents and managers can rate them")
+		return
+	}
+
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Ratings can't be saved right now")
+		return
+	}
+	rating, err := c.saveAttributeRating(ctx, database.UpsertAttributeRatingParams{
+		PlayerProfileID: profile.ID,
+		RaterUserID:     userID,
+		Relationship:    kind,
+		Weight:          weight,
+		Pace:            req.Pace,
+		Shooting:        req.Shooting,
+		Passing:         req.Passing,
+		Stamina:         req.Stamina,
+		Dribbling:       req.Dribbling,
+		Defending:       req.Defending,
+		Physical:        req.Physical,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save rating: %v", err))
+		return
+	}
+
+	attributes, err := playerAttributes(ctx, c.DB, profile)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get attributes: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, AttributeRatingResponse{
+		Relationship: rating.Relationship,
+		Weight:       rating.Weight,
+		Rating:       req.Attributes,
+		Attributes:   attributes,
+	})
+}
+
+// saveAttributeRating stores a rating and brings the player's community
+// attributes up to date with it, adding a history row when they change
+func (c *Config) saveAttributeRating(ctx context.Context, params database.UpsertAttributeRatingParams) (database.AttributeRating, error) {
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.AttributeRating{}, err
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	rating, err := q.UpsertAttributeRating(ctx, params)
+	if err != nil {
+		return rating, err
+	}
+
+	rows, err := q.ListAttributeRatings(ctx, params.PlayerProfileID)
+	if err != nil {
+		return rating, err
+	}
+	given := make([]ratings.Rating, 0, len(rows))
+	for _, row := range rows {
+		given = append(given, ratings.Rating{Attributes: ratedAttributes(row), Weight: row.Weight})
+	}
+	community, ok := ratings.Aggregate(given)
+	if !ok {
+		return rating, tx.Commit()
+	}
+
+	previous, err := q.GetCommunityAttributes(ctx, params.PlayerProfileID)
+	published := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return rating, err
+	}
+	if err := q.UpsertCommunityAttributes(ctx, database.UpsertCommunityAttributesParams{
+		PlayerProfileID: params.PlayerProfileID,
+		Pace:            community.Pace,
+		Shooting:        community.Shooting,
+		Passing:         community.Passing,
+		Stamina:         community.Stamina,
+		Dribbling:       community.Dribbling,
+		Defending:       community.Defending,
+		Physical:        community.Physical,
+		Raters:          int32(len(rows)),
+	}); err != nil {
+		return rating, err
+	}
+	if !published || communityAttributes(previous) != community {
+		if err := recordAttributes(ctx, q, params.PlayerProfileID, attributesCommunity, community, sql.NullInt32{Int32: int32(len(rows)), Valid: true}); err != nil {
+			return rating, err
+		}
+	}
+	return rating, tx.Commit()
+}
+
+// getPlayerAttributes shows a player's self-reported attributes next to
+// their community ones
+func (c *Config) getPlayerAttributes(w http.ResponseWriter, r *http.Request) {
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid player profile ID")
+		return
+	}
+	profile, err := c.DB.GetPlayerProfile(r.Context(), profileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Player profile not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+	attributes, err := playerAttributes(r.Context(), c.DB, profile)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get attributes: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, attributes)
+}
+
+// getAttributeHistory lists how a player's attributes have changed, newest
+// first. Query params: source (self or community), limit
+func (c *Config) getAttributeHistory(w http.ResponseWriter, r *http.Request) {
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid player profile ID")
+		return
+	}
+
+	params := database.ListAttributeHistoryParams{PlayerProfileID: profileID, RowLimit: attributeHistoryDefaultLimit}
+	switch source := r.URL.Query().Get("source"); source {
+	case "":
+	case attributesSelf, attributesCommunity:
+		params.Source = sql.NullString{String: source, Valid: true}
+	default:
+		respondWithError(w, http.StatusBadRequest, "source must be self or community")
+		return
+	}
+	limit, err := queryInt32(r.URL.Query(), "limit")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if limit > 0 {
+		params.RowLimit = min(limit, attributeHistoryMaxLimit)
+	}
+
+	rows, err := c.DB.ListAttributeHistory(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get attribute history: %v", err))
+		return
+	}
+	history := make([]AttributeHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := AttributeHistoryEntry{
+			Source: row.Source,
+			Attributes: ratings.Attributes{
+				Pace: row.Pace, Shooting: row.Shooting, Passing: row.Passing, Stamina: row.Stamina,
+				Dribbling: row.Dribbling, Defending: row.Defending, Physical: row.Physical,
+			},
+			RecordedAt: row.RecordedAt,
+		}
+		if row.Raters.Valid {
+			entry.Raters = &row.Raters.Int32
+		}
+		history = append(history, entry)
+	}
+	respondWithJSON(w, http.StatusOK, history)
+}
+
+// raterWeight picks the closest relationship a rater has with a player and
+// what their rating counts for. ok is false for strangers.
+func raterWeight(relationship database.GetRaterRelationshipRow) (kind string, weight int32, ok bool) {
+	switch {
+	case relationship.Manager && relationship.Appointed:
+		return relationshipManager, weightAppointedManager, true
+	case relationship.Manager:
+		return relationshipManager, weightManager, true
+	case relationship.Teammate:
+		return relationshipTeammate, weightPlayer, true
+	case relationship.Opponent:
+		return relationshipOpponent, weightPlayer, true
+	}
+	return "", 0, false
+}
+
+// playerAttributes puts a player's self-reported attributes next to their
+// community ones, if they've been published
+func playerAttributes(ctx context.Context, db *database.Queries, profile database.PlayerProfile) (PlayerAttributes, error) {
+	attributes := PlayerAttributes{Self: selfAttributes(profile)}
+	community, err := db.GetCommunityAttributes(ctx, profile.ID)
+	if err == sql.ErrNoRows {
+		return attributes, nil
+	}
+	if err != nil {
+		return attributes, err
+	}
+	attributes.Community = &CommunityAttributes{
+		Attributes: communityAttributes(community),
+		Raters:     community.Raters,
+		UpdatedAt:  community.UpdatedAt,
+	}
+	return attributes, nil
+}
+
+// recordSelfAttributes adds a history row for what a player now reports
+func recordSelfAttributes(ctx context.Context, db *database.Queries, profile database.PlayerProfile) error {
+	return recordAttributes(ctx, db, profile.ID, attributesSelf, selfAttributes(profile), sql.NullInt32{})
+}
+
+func recordAttributes(ctx context.Context, db *database.Queries, profileID uuid.UUID, source string, attributes ratings.Attributes, raters sql.NullInt32) error {
+	return db.CreateAttributeHistory(ctx, database.CreateAttributeHistoryParams{
+		PlayerProfileID: profileID,
+		Source:          source,
+		Pace:            attributes.Pace,
+		Shooting:        attributes.Shooting,
+		Passing:         attributes.Passing,
+		Stamina:         attributes.Stamina,
+		Dribbling:       attributes.Dribbling,
+		Defending:       attributes.Defending,
+		Physical:        attributes.Physical,
+		Raters:          raters,
+	})
+}
+
+func selfAttributes(profile database.PlayerProfile) ratings.Attributes {
+	return ratings.Attributes{
+		Pace: profile.Pace, Shooting: profile.Shooting, Passing: profile.Passing, Stamina: profile.Stamina,
+		Dribbling: profile.Dribbling, Defending: profile.Defending, Physical: profile.Physical,
+	}
+}
+
+func communityAttributes(community database.PlayerCommunityAttribute) ratings.Attributes {
+	return ratings.Attributes{
+		Pace: community.Pace, Shooting: community.Shooting, Passing: community.Passing, Stamina: community.Stamina,
+		Dribbling: community.Dribbling, Defending: community.Defending, Physical: community.Physical,
+	}
+}
+
+func ratedAttributes(rating database.AttributeRating) ratings.Attributes {
+	return ratings.Attributes{
+		Pace: rating.Pace, Shooting: rating.Shooting, Passing: rating.Passing, Stamina: rating.Stamina,
+		Dribbling: rating.Dribbling, Defending: rating.Defending, Physical: rating.Physical,
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestRaterWeight(t *testing.T) {
+	tests := []struct {
+		name         string
+		relationship database.GetRaterRelationshipRow
+		wantKind     string
+		wantWeight   int32
+		wantOK       bool
+	}{
+		{"stranger", database.GetRaterRelationshipRow{}, "", 0, false},
+		{"teammate", database.GetRaterRelationshipRow{Teammate: true}, relationshipTeammate, weightPlayer, true},
+		{"opponent", database.GetRaterRelationshipRow{Opponent: true}, relationshipOpponent, weightPlayer, true},
+		{"manager", database.GetRaterRelationshipRow{Manager: true, Opponent: true}, relationshipManager, weightManager, true},
+		{"appointed manager", database.GetRaterRelationshipRow{Manager: true, Appointed: true}, relationshipManager, weightAppointedManager, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, weight, ok := raterWeight(tt.relationship)
+			if kind != tt.wantKind || weight != tt.wantWeight || ok != tt.wantOK {
+				t.Errorf("raterWeight() = %q, %d, %v, want %q, %d, %v", kind, weight, ok, tt.wantKind, tt.wantWeight, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRateAttributesValidation(t *testing.T) {
+	for _, body := range []string{
+		`not json`,
+		`{"pace":80}`,
+		`{"pace":100,"shooting":70,"passing":70,"stamina":70,"dribbling":70,"defending":70,"physical":70}`,
+	} {
+		config := &Config{}
+		req := authedRequest("PUT", "/player-profiles/x/ratings", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.rateAttributes(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestRateAttributesRequiresAuth(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.rateAttributes(rec, httptest.NewRequest("PUT", "/player-profiles/x/ratings", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestGetAttributeHistoryInvalidSource(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("GET", "/player-profiles/x/attributes/history?source=scouts", nil)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", uuid.New().String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	config.getAttributeHistory(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestCreatePlayerProfileRejectsOutOfRangeAttributes(t *testing.T) {
+	svc := &PlayerProfileService{}
+	body := `{"position":"ST","pace":150}`
+	rec := httptest.NewRecorder()
+
+	svc.CreatePlayerProfile(rec, authedRequest("POST", "/player-profiles", body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestCreatePlayerProfileRequiresAuth(t *testing.T) {
+	svc := &PlayerProfileService{}
+	rec := httptest.NewRecorder()
+
+	svc.CreatePlayerProfile(rec, httptest.NewRequest("POST", "/player-profiles", strings.NewReader(`{"position":"ST"}`)))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestCreatePlayerProfileForAnotherUser(t *testing.T) {
+	svc := &PlayerProfileService{}
+	rec := httptest.NewRecorder()
+
+	svc.CreatePlayerProfile(rec, authedRequest("POST", "/player-profiles", `{"user_id":8,"position":"ST"}`))
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", rec.Code)
+	}
+}
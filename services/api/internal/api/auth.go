@@ -25,16 +25,17 @@ type LoginResponse struct {
 
 // UserResponse represents a user without sensitive data
 type UserResponse struct {
-	ID          int32  `json:"id"`
-	Firstname   string `json:"firstname"`
-	Lastname    string `json:"lastname"`
-	Email       string `json:"email"`
-	DisplayName string `json:"display_name"`
-	AvatarURL   string `json:"avatar_url"`
-	IsVerified  bool   `json:"is_verified"`
-	IsActive    bool   `json:"is_active"`
-	Role        string `json:"role"`
-	CreatedAt   string `json:"created_at"`
+	ID           int32                 `json:"id"`
+	Firstname    string                `json:"firstname"`
+	Lastname     string                `json:"lastname"`
+	Email        string                `json:"email"`
+	DisplayName  string                `json:"display_name"`
+	AvatarURL    string                `json:"avatar_url"`
+	IsVerified   bool                  `json:"is_verified"`
+	IsActive     bool                  `json:"is_active"`
+	Role         string                `json:"role"`
+	CreatedAt    string                `json:"created_at"`
+	Achievements []AchievementResponse `json:"achievements,omitempty"` // Only on the profile
 }
 
 func (c *Config) handleLogin(w http.ResponseWriter, r *http.Request) {
@@ -227,6 +228,11 @@ func (c *Config) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:   createdAt,
 	}
 
+	userResponse.Achievements, err = c.userAchievements(r.Context(), userID)
+	if err != nil {
+		fmt.Printf("Failed to get achievements for user %d: %v\n", userID, err)
+	}
+
 	respondWithJSON(w, http.StatusOK, userResponse)
 }
 
@@ -269,5 +275,10 @@ func (c *Config) handleGetProfile(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:   createdAt,
 	}
 
+	userResponse.Achievements, err = c.userAchievements(r.Context(), userID)
+	if err != nil {
+		fmt.Printf("Failed to get achievements for user %d: %v\n", userID, err)
+	}
+
 	respondWithJSON(w, http.StatusOK, userResponse)
 }
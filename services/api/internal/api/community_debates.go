@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+)
+
+// Debate statuses
+const (
+	debateStatusProposed  = "proposed"
+	debateStatusPublished = "published"
+	debateStatusRejected  = "rejected"
+)
+
+const (
+	// maxCommunityDebatesPerMatch limits how many debates a fan can propose for a single match
+	maxCommunityDebatesPerMatch = 3
+	// communityPromotionThreshold is the engagement score at which a proposed debate is
+	// published without waiting for an admin
+	communityPromotionThreshold = 10.0
+)
+
+type DebateAuthorResponse struct {
+	ID          int32  `json:"id"`
+	Firstname   string `json:"firstname"`
+	Lastname    string `json:"lastname"`
+	DisplayName string `json:"display_name,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}
+
+// newDebateResponse maps a debate row onto the API response without cards or analytics
+func newDebateResponse(debate database.Debate) DebateResponse {
+	response := DebateResponse{
+		ID:          debate.ID,
+		MatchID:     debate.MatchID,
+		DebateType:  debate.DebateType,
+		Headline:    debate.Headline,
+		Description: debate.Description.String,
+		AIGenerated: debate.AiGenerated.Bool,
+		Status:      debate.Status,
+		CreatedAt:   debate.CreatedAt.Time,
+		UpdatedAt:   debate.UpdatedAt.Time,
+	}
+	if debate.PromotedAt.Valid {
+		response.PromotedAt = &debate.PromotedAt.Time
+	}
+	return response
+}
+
+// attachDebateAuthors fills in author info for community debates.
+// responses and debates must be index aligned.
+func (c *Config) attachDebateAuthors(ctx context.Context, responses []*DebateResponse, debates []database.Debate) {
+	var authorIDs []int32
+	for _, debate := range debates {
+		if debate.AuthorID.Valid {
+			authorIDs = append(authorIDs, debate.AuthorID.Int32)
+		}
+	}
+	if len(authorIDs) == 0 {
+		return
+	}
+
+	authors, err := c.DB.GetDebateAuthors(ctx, authorIDs)
+	if err != nil {
+		fmt.Printf("Failed to get debate authors: %v\n", err)
+		return
+	}
+
+	authorsByID := make(map[int32]*DebateAuthorResponse, len(authors))
+	for _, author := range authors {
+		authorsByID[author.ID] = &DebateAuthorResponse{
+			ID:          author.ID,
+			Firstname:   author.Firstname,
+			Lastname:    author.Lastname,
+			DisplayName: author.DisplayName.String,
+			AvatarURL:   author.AvatarUrl.String,
+		}
+	}
+
+	for i, debate := range debates {
+		if debate.AuthorID.Valid {
+			responses[i].Author = authorsByID[debate.AuthorID.Int32]
+		}
+	}
+}
+
+// parseDebateSource maps the "source" filter onto the ai_generated column
+func parseDebateSource(source string) (sql.NullBool, error) {
+	switch source {
+	case "":
+		return sql.NullBool{}, nil
+	case "ai":
+		return sql.NullBool{Bool: true, Valid: true}, nil
+	case "community":
+		return sql.NullBool{Bool: false, Valid: true}, nil
+	default:
+		return sql.NullBool{}, fmt.Errorf("source must be 'ai' or 'community'")
+	}
+}
+
+// promoteDebateIfEngaged publishes a proposed debate once it crosses the engagement threshold
+func (c *Config) promoteDebateIfEngaged(ctx context.Context, debate database.Debate, engagementScore float64) {
+	if engagementScore < communityPromotionThreshold || debate.Status != debateStatusProposed {
+		return
+	}
+
+	if _, err := c.DB.PromoteDebate(ctx, database.PromoteDebateParams{
+		ID:         debate.ID,
+		PromotedBy: sql.NullInt32{},
+	}); err != nil && err != sql.ErrNoRows {
+		fmt.Printf("Failed to promote debate %d: %v\n", debate.ID, err)
+		return
+	}
+	fmt.Printf("Debate %d promoted after reaching engagement score %.2f\n", debate.ID, engagementScore)
+}
+
+// listProposedDebates returns the moderation queue of proposed debates
+func (c *Config) listProposedDebates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := int32(50)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.ParseInt(limitStr, 10, 32); err == nil && l > 0 {
+			limit = int32(l)
+		}
+	}
+
+	debates, err := c.DB.ListProposedDebates(ctx, limit)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get proposed debates: %v", err))
+		return
+	}
+
+	response := make([]DebateResponse, len(debates))
+	responsePtrs := make([]*DebateResponse, len(debates))
+	for i, debate := range debates {
+		response[i] = newDebateResponse(debate)
+		responsePtrs[i] = &response[i]
+	}
+	c.attachDebateAuthors(ctx, responsePtrs, debates)
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// approveDebate publishes a proposed debate on behalf of an admin
+func (c *Config) approveDebate(w http.ResponseWriter, r *http.Request) {
+	c.reviewDebate(w, r, true)
+}
+
+// rejectDebate keeps a proposed debate out of the feed
+func (c *Config) rejectDebate(w http.ResponseWriter, r *http.Request) {
+	c.reviewDebate(w, r, false)
+}
+
+func (c *Config) reviewDebate(w http.ResponseWriter, r *http.Request, approve bool) {
+	ctx := r.Context()
+
+	adminID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	debateID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid debate ID")
+		return
+	}
+
+	var debate database.Debate
+	if approve {
+		debate, err = c.DB.PromoteDebate(ctx, database.PromoteDebateParams{
+			ID:         int32(debateID),
+			PromotedBy: sql.NullInt32{Int32: adminID, Valid: true},
+		})
+	} else {
+		debate, err = c.DB.RejectDebate(ctx, int32(debateID))
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Proposed debate not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to review debate: %v", err))
+		return
+	}
+
+	if approve {
+		if err := c.inboxDebatePromoted(ctx, debate); err != nil {
+			fmt.Printf("Failed to notify author of debate %d: %v\n", debate.ID, err)
+		}
+	}
+
+	response := newDebateResponse(debate)
+	c.attachDebateAuthors(ctx, []*DebateResponse{&response}, []database.Debate{debate})
+	respondWithJSON(w, http.StatusOK, response)
+}
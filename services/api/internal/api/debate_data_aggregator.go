@@ -5,19 +5,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/ArronJLinton/fucci-api/internal/ai"
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/sentiment"
 )
 
 type DebateDataAggregator struct {
 	Config *Config
+	// SentimentSources feed fan sentiment. External sources (Twitter, Reddit, ...)
+	// can be appended alongside our own comments and votes.
+	SentimentSources []sentiment.Source
 }
 
 type MatchDataRequest struct {
 	MatchID         string `json:"match_id"`
+	HomeTeamID      int    `json:"home_team_id"`
+	AwayTeamID      int    `json:"away_team_id"`
 	HomeTeam        string `json:"home_team"`
 	AwayTeam        string `json:"away_team"`
 	Date            string `json:"date"`
@@ -44,6 +51,10 @@ type MatchDataRequest struct {
 func NewDebateDataAggregator(config *Config) *DebateDataAggregator {
 	return &DebateDataAggregator{
 		Config: config,
+		SentimentSources: []sentiment.Source{
+			&commentSentimentSource{db: config.DB},
+			&voteSentimentSource{db: config.DB},
+		},
 	}
 }
 
@@ -121,7 +132,7 @@ func (dda *DebateDataAggregator) AggregateMatchData(ctx context.Context, matchRe
 	}
 
 	// Fetch social media sentiment
-	sentiment, err := dda.fetchSocialSentiment(ctx, matchReq.HomeTeam, matchReq.AwayTeam, matchReq.MatchID)
+	sentiment, err := dda.fetchSocialSentiment(ctx, matchReq)
 	if err != nil {
 		fmt.Printf("Failed to fetch social sentiment: %v\n", err)
 	} else {
@@ -286,33 +297,54 @@ func (dda *DebateDataAggregator) fetchMatchStats(ctx context.Context, matchID st
 	return stats, nil
 }
 
-// fetchNewsHeadlines gets relevant news headlines for the teams
+// newsLookback bounds how far back headlines are considered relevant to a match
+const newsLookback = 7 * 24 * time.Hour
+
+// fetchNewsHeadlines gets relevant news headlines for the teams from ingested articles
 func (dda *DebateDataAggregator) fetchNewsHeadlines(ctx context.Context, homeTeam, awayTeam string) ([]string, error) {
+	if dda.Config.DB == nil {
+		return nil, fmt.Errorf("database not configured")
+	}
+
 	var headlines []string
+	seen := make(map[string]bool)
+	add := func(titles []string) {
+		for _, title := range titles {
+			if !seen[title] {
+				seen[title] = true
+				headlines = append(headlines, title)
+			}
+		}
+	}
+	since := time.Now().Add(-newsLookback)
 
-	// Search for home team news
-	homeHeadlines, err := dda.searchNews(ctx, homeTeam)
+	// Match-up news first, it is the most relevant
+	matchup, err := dda.Config.DB.ListNewsArticlesByTeams(ctx, database.ListNewsArticlesByTeamsParams{
+		Since:      since,
+		HomeTeam:   homeTeam,
+		AwayTeam:   awayTeam,
+		MaxResults: 5,
+	})
 	if err != nil {
-		fmt.Printf("Failed to fetch home team news: %v\n", err)
+		fmt.Printf("Failed to fetch matchup news: %v\n", err)
 	} else {
-		headlines = append(headlines, homeHeadlines...)
+		add(articleTitles(matchup))
 	}
 
-	// Search for away team news
-	awayHeadlines, err := dda.searchNews(ctx, awayTeam)
+	// Search for home team news
+	homeHeadlines, err := dda.searchNews(ctx, homeTeam, since)
 	if err != nil {
-		fmt.Printf("Failed to fetch away team news: %v\n", err)
+		fmt.Printf("Failed to fetch home team news: %v\n", err)
 	} else {
-		headlines = append(headlines, awayHeadlines...)
+		add(homeHeadlines)
 	}
 
-	// Search for match-up news
-	matchupQuery := fmt.Sprintf("%s vs %s", homeTeam, awayTeam)
-	matchupHeadlines, err := dda.searchNews(ctx, matchupQuery)
+	// Search for away team news
+	awayHeadlines, err := dda.searchNews(ctx, awayTeam, since)
 	if err != nil {
-		fmt.Printf("Failed to fetch matchup news: %v\n", err)
+		fmt.Printf("Failed to fetch away team news: %v\n", err)
 	} else {
-		headlines = append(headlines, matchupHeadlines...)
+		add(awayHeadlines)
 	}
 
 	// Limit to top 10 headlines to avoid overwhelming the AI
@@ -323,112 +355,173 @@ func (dda *DebateDataAggregator) fetchNewsHeadlines(ctx context.Context, homeTea
 	return headlines, nil
 }
 
-// searchNews performs a Google News search
-func (dda *DebateDataAggregator) searchNews(ctx context.Context, query string) ([]string, error) {
-	// Construct the URL
-	baseURL := "https://google-news13.p.rapidapi.com/search"
-	params := url.Values{}
-	params.Add("keyword", query)
-	params.Add("lr", "en-US")
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"?"+params.Encode(), nil)
+// searchNews returns recent headlines tagged to a team, falling back to a text
+// search for teams that have no tagged articles yet
+func (dda *DebateDataAggregator) searchNews(ctx context.Context, teamName string, since time.Time) ([]string, error) {
+	articles, err := dda.Config.DB.ListNewsArticlesByTeam(ctx, database.ListNewsArticlesByTeamParams{
+		TeamName:    teamName,
+		PublishedAt: since,
+		Limit:       5,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error creating news request: %w", err)
+		return nil, fmt.Errorf("error fetching team news: %w", err)
+	}
+	if len(articles) > 0 {
+		return articleTitles(articles), nil
 	}
 
-	// Add RapidAPI headers
-	req.Header.Add("x-rapidapi-key", dda.Config.RapidAPIKey)
-	req.Header.Add("x-rapidapi-host", "google-news13.p.rapidapi.com")
-
-	// Make the request
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	articles, err = dda.Config.DB.SearchNewsArticles(ctx, database.SearchNewsArticlesParams{
+		Pattern:    containsPattern(teamName),
+		MaxResults: 5,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error making news request: %w", err)
+		return nil, fmt.Errorf("error searching news: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading news response: %w", err)
+	var recent []database.NewsArticle
+	for _, article := range articles {
+		if article.PublishedAt.After(since) {
+			recent = append(recent, article)
+		}
 	}
+	return articleTitles(recent), nil
+}
 
-	// Check if response is successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("news API returned status %d: %s", resp.StatusCode, string(body))
+func articleTitles(articles []database.NewsArticle) []string {
+	titles := make([]string, 0, len(articles))
+	for _, article := range articles {
+		titles = append(titles, article.Title)
 	}
+	return titles
+}
+
+// sentimentLookback bounds how far back fan reactions are considered
+const sentimentLookback = 14 * 24 * time.Hour
+
+// sentimentFixtureCount is how many of a team's recent fixtures count as its matches
+const sentimentFixtureCount = 10
 
-	// Parse JSON response
-	var newsResponse struct {
-		Items []struct {
-			Title string `json:"title"`
-		} `json:"items"`
+// fetchSocialSentiment scores fan reactions towards each team
+func (dda *DebateDataAggregator) fetchSocialSentiment(ctx context.Context, matchReq MatchDataRequest) (*ai.SocialSentiment, error) {
+	if dda.Config.DB == nil {
+		return nil, fmt.Errorf("database not configured")
 	}
 
-	err = json.Unmarshal(body, &newsResponse)
+	analyzer := sentiment.NewAnalyzer(dda.Config.SentimentScorer, dda.SentimentSources...)
+	since := time.Now().Add(-sentimentLookback)
+
+	homeTeam := sentiment.Team{Name: matchReq.HomeTeam, MatchIDs: dda.teamMatchIDs(ctx, matchReq.HomeTeamID, matchReq.MatchID)}
+	awayTeam := sentiment.Team{Name: matchReq.AwayTeam, MatchIDs: dda.teamMatchIDs(ctx, matchReq.AwayTeamID, matchReq.MatchID)}
+
+	home, err := analyzer.Analyze(ctx, homeTeam, since, 5)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing news response: %w", err)
+		fmt.Printf("Failed to analyze %s sentiment: %v\n", homeTeam.Name, err)
 	}
-
-	// Extract headlines
-	var headlines []string
-	for _, item := range newsResponse.Items {
-		if item.Title != "" {
-			headlines = append(headlines, item.Title)
+	away, err := analyzer.Analyze(ctx, awayTeam, since, 5)
+	if err != nil {
+		fmt.Printf("Failed to analyze %s sentiment: %v\n", awayTeam.Name, err)
+	}
+
+	// Don't hand the AI made-up numbers when nobody has reacted yet
+	if home.SampleSize == 0 && away.SampleSize == 0 {
+		return nil, fmt.Errorf("no fan reactions found for match %s", matchReq.MatchID)
+	}
+
+	result := &ai.SocialSentiment{
+		HomeTeamSentiment:    home.Score,
+		AwayTeamSentiment:    away.Score,
+		HomeSampleSize:       home.SampleSize,
+		AwaySampleSize:       away.SampleSize,
+		SourceSentiment:      make(map[string]float64),
+		TopTopics:            mergeUnique(6, home.Topics, away.Topics),
+		ControversialMoments: mergeUnique(3, home.Controversial, away.Controversial),
+	}
+
+	// Per-source score across both fanbases, weighted by how many reactions each team had
+	for _, source := range dda.SentimentSources {
+		name := source.Name()
+		homeScore, inHome := home.BySource[name]
+		awayScore, inAway := away.BySource[name]
+		switch {
+		case inHome && inAway:
+			total := float64(home.SampleSize + away.SampleSize)
+			result.SourceSentiment[name] = math.Round((homeScore*float64(home.SampleSize)+awayScore*float64(away.SampleSize))/total*100) / 100
+		case inHome:
+			result.SourceSentiment[name] = homeScore
+		case inAway:
+			result.SourceSentiment[name] = awayScore
 		}
 	}
 
-	return headlines, nil
+	return result, nil
 }
 
-// fetchSocialSentiment gets social media sentiment data
-func (dda *DebateDataAggregator) fetchSocialSentiment(ctx context.Context, homeTeam, awayTeam, matchID string) (*ai.SocialSentiment, error) {
-	sentiment := &ai.SocialSentiment{
-		TwitterSentiment:     0.0,
-		RedditSentiment:      0.0,
-		TopTopics:            []string{},
-		ControversialMoments: []string{},
+// mergeUnique interleaves the lists, dropping duplicates, up to limit items
+// teamMatchIDs returns the fixture being debated plus the team's most recent
+// fixtures, so fan reactions are only taken from debates on the team's matches.
+// If the team's fixtures can't be fetched only the current fixture is used.
+func (dda *DebateDataAggregator) teamMatchIDs(ctx context.Context, teamID int, matchID string) []string {
+	ids := []string{matchID}
+	if teamID == 0 {
+		return ids
 	}
 
-	// Fetch Twitter sentiment for home team
-	homeTwitterSentiment, err := dda.fetchTwitterSentiment(ctx, homeTeam)
-	if err != nil {
-		fmt.Printf("Failed to fetch home team Twitter sentiment: %v\n", err)
-	} else {
-		sentiment.TwitterSentiment = (sentiment.TwitterSentiment + homeTwitterSentiment) / 2
+	baseURL := dda.Config.APIFootballBaseURL
+	if baseURL == "" {
+		baseURL = "https://api-football-v1.p.rapidapi.com/v3"
 	}
 
-	// Fetch Twitter sentiment for away team
-	awayTwitterSentiment, err := dda.fetchTwitterSentiment(ctx, awayTeam)
-	if err != nil {
-		fmt.Printf("Failed to fetch away team Twitter sentiment: %v\n", err)
-	} else {
-		sentiment.TwitterSentiment = (sentiment.TwitterSentiment + awayTwitterSentiment) / 2
+	url := fmt.Sprintf("%s/fixtures?team=%d&last=%d", baseURL, teamID, sentimentFixtureCount)
+	headers := map[string]string{
+		"Content-Type":   "application/json",
+		"x-rapidapi-key": dda.Config.FootballAPIKey,
 	}
 
-	// For now, we'll use placeholder data for Reddit sentiment
-	// You can implement Reddit API integration later
-	sentiment.RedditSentiment = 0.0
+	resp, err := HTTPRequest("GET", url, headers, nil)
+	if err != nil {
+		fmt.Printf("Failed to fetch fixtures for team %d: %v\n", teamID, err)
+		return ids
+	}
+	defer resp.Body.Close()
 
-	// Extract top topics from team names
-	sentiment.TopTopics = []string{
-		fmt.Sprintf("%s performance", homeTeam),
-		fmt.Sprintf("%s performance", awayTeam),
-		"match analysis",
-		"team tactics",
+	var fixturesResponse struct {
+		Response []struct {
+			Fixture struct {
+				ID int `json:"id"`
+			} `json:"fixture"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&fixturesResponse); err != nil {
+		fmt.Printf("Failed to parse fixtures for team %d: %v\n", teamID, err)
+		return ids
 	}
 
-	return sentiment, nil
+	for _, item := range fixturesResponse.Response {
+		if id := strconv.Itoa(item.Fixture.ID); id != matchID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
-// fetchTwitterSentiment gets Twitter sentiment for a team
-func (dda *DebateDataAggregator) fetchTwitterSentiment(ctx context.Context, teamName string) (float64, error) {
-	// Use your existing Twitter API endpoint
-	// For now, return a placeholder sentiment value
-	// You can implement actual Twitter sentiment analysis later
-
-	// Placeholder: return a neutral sentiment
-	return 0.0, nil
+func mergeUnique(limit int, lists ...[]string) []string {
+	merged := []string{}
+	seen := make(map[string]bool)
+	for i := 0; len(merged) < limit; i++ {
+		added := false
+		for _, list := range lists {
+			if i >= len(list) {
+				continue
+			}
+			added = true
+			if !seen[list[i]] && len(merged) < limit {
+				seen[list[i]] = true
+				merged = append(merged, list[i])
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return merged
 }
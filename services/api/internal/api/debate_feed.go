@@ -0,0 +1,287 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/cache"
+	"github.com/ArronJLinton/fucci-api/internal/database"
+)
+
+const (
+	// feedCandidateWindow is how far back debates are considered for the feed
+	feedCandidateWindow = 14 * 24 * time.Hour
+	// feedCandidateLimit bounds how many debates are ranked per user
+	feedCandidateLimit = 300
+	// feedVelocityWindow is the window used to measure engagement velocity
+	feedVelocityWindow = 24 * time.Hour
+	// feedRecencyHalfLife is the age at which a debate's freshness is halved
+	feedRecencyHalfLife = 24 * time.Hour
+
+	feedDefaultPageSize = 20
+	feedMaxPageSize     = 50
+)
+
+// Feed ranking weights
+const (
+	feedFollowBoost    = 3.0
+	feedRecencyWeight  = 2.0
+	feedVelocityWeight = 1.0
+	// feedVotedPenalty scales down debates the user has already voted on so
+	// that new debates surface first, without hiding the ones they joined
+	feedVotedPenalty = 0.4
+)
+
+type DebateFeedItem struct {
+	DebateResponse
+	Score    float64 `json:"score"`
+	Followed bool    `json:"followed"`
+	HasVoted bool    `json:"has_voted"`
+}
+
+type DebateFeedResponse struct {
+	Items      []DebateFeedItem `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// getDebateFeed returns the authenticated user's ranked debate feed
+func (c *Config) getDebateFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	limit := feedDefaultPageSize
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			respondWithError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(l, feedMaxPageSize)
+	}
+
+	var after *feedCursor
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		cursor, err := decodeFeedCursor(cursorStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		after = &cursor
+	}
+
+	items, err := c.loadDebateFeed(ctx, userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get debate feed: %v", err))
+		return
+	}
+
+	page, next := pageDebateFeed(items, after, limit)
+	respondWithJSON(w, http.StatusOK, DebateFeedResponse{
+		Items:      page,
+		NextCursor: next,
+	})
+}
+
+// loadDebateFeed returns the user's fully ranked feed, from cache when possible
+func (c *Config) loadDebateFeed(ctx context.Context, userID int32) ([]DebateFeedItem, error) {
+	cacheKey := debateFeedCacheKey(userID)
+
+	if c.Cache != nil {
+		exists, err := c.Cache.Exists(ctx, cacheKey)
+		if err != nil {
+			log.Printf("Cache check error: %v\n", err)
+		} else if exists {
+			var items []DebateFeedItem
+			if err := c.Cache.Get(ctx, cacheKey, &items); err == nil {
+				return items, nil
+			}
+			log.Printf("Cache get error: %v\n", err)
+		}
+	}
+
+	now := time.Now()
+	rows, err := c.DB.ListDebateFeedCandidates(ctx, database.ListDebateFeedCandidatesParams{
+		UserID:        userID,
+		VelocitySince: now.Add(-feedVelocityWindow),
+		Since:         now.Add(-feedCandidateWindow),
+		MaxResults:    feedCandidateLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := rankDebateFeed(rows, now)
+
+	authorIDs := make(map[int32]sql.NullInt32, len(rows))
+	for _, row := range rows {
+		authorIDs[row.ID] = row.AuthorID
+	}
+	debates := make([]database.Debate, len(items))
+	responses := make([]*DebateResponse, len(items))
+	for i := range items {
+		debates[i] = database.Debate{ID: items[i].ID, AuthorID: authorIDs[items[i].ID]}
+		responses[i] = &items[i].DebateResponse
+	}
+	c.attachDebateAuthors(ctx, responses, debates)
+
+	if c.Cache != nil {
+		if err := c.Cache.Set(ctx, cacheKey, items, cache.FeedTTL); err != nil {
+			log.Printf("Failed to cache debate feed: %v\n", err)
+		}
+	}
+	return items, nil
+}
+
+// invalidateDebateFeed drops the user's cached feed so their next request
+// reflects what they have just voted on
+func (c *Config) invalidateDebateFeed(ctx context.Context, userID int32) {
+	if c.Cache == nil {
+		return
+	}
+	if err := c.Cache.Delete(ctx, debateFeedCacheKey(userID)); err != nil {
+		log.Printf("Failed to invalidate debate feed for user %d: %v\n", userID, err)
+	}
+}
+
+func debateFeedCacheKey(userID int32) string {
+	return fmt.Sprintf("debate_feed:user:%d", userID)
+}
+
+// debateFeedScore combines follows, freshness and engagement velocity into a single score
+func debateFeedScore(row database.ListDebateFeedCandidatesRow, now time.Time) float64 {
+	score := 0.0
+	if row.IsFollowed {
+		score += feedFollowBoost
+	}
+
+	if row.CreatedAt.Valid {
+		age := now.Sub(row.CreatedAt.Time)
+		if age < 0 {
+			age = 0
+		}
+		score += feedRecencyWeight * math.Pow(0.5, age.Hours()/feedRecencyHalfLife.Hours())
+	}
+
+	// Comments take more effort than votes, matching the engagement score weighting
+	velocity := float64(row.RecentVotes) + float64(row.RecentComments)*2.0
+	score += feedVelocityWeight * math.Log1p(velocity)
+
+	if row.HasVoted {
+		score *= feedVotedPenalty
+	}
+	return score
+}
+
+// rankDebateFeed scores the candidates and orders them best first
+func rankDebateFeed(rows []database.ListDebateFeedCandidatesRow, now time.Time) []DebateFeedItem {
+	items := make([]DebateFeedItem, 0, len(rows))
+	for _, row := range rows {
+		response := newDebateResponse(database.Debate{
+			ID:          row.ID,
+			MatchID:     row.MatchID,
+			DebateType:  row.DebateType,
+			Headline:    row.Headline,
+			Description: row.Description,
+			AiGenerated: row.AiGenerated,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+			Status:      row.Status,
+			PromotedAt:  row.PromotedAt,
+		})
+		response.Analytics = &DebateAnalyticsResponse{
+			ID:              row.ID,
+			DebateID:        row.ID,
+			TotalVotes:      int(row.TotalVotes),
+			TotalComments:   int(row.TotalComments),
+			EngagementScore: float64(row.TotalVotes) + float64(row.TotalComments)*2.0,
+			CreatedAt:       row.CreatedAt.Time,
+			UpdatedAt:       row.UpdatedAt.Time,
+		}
+
+		items = append(items, DebateFeedItem{
+			DebateResponse: response,
+			Score:          debateFeedScore(row, now),
+			Followed:       row.IsFollowed,
+			HasVoted:       row.HasVoted,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return feedItemBefore(items[i].Score, items[i].ID, items[j].Score, items[j].ID)
+	})
+	return items
+}
+
+// feedItemBefore orders by score, breaking ties on the newer (higher) ID
+func feedItemBefore(scoreA float64, idA int32, scoreB float64, idB int32) bool {
+	if scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	return idA > idB
+}
+
+// feedCursor is the position of the last item on the previous page.
+// Paging by (score, id) instead of offset keeps pages stable when the
+// cached feed is rebuilt between requests.
+type feedCursor struct {
+	Score float64
+	ID    int32
+}
+
+func encodeFeedCursor(cursor feedCursor) string {
+	raw := strconv.FormatFloat(cursor.Score, 'g', -1, 64) + ":" + strconv.FormatInt(int64(cursor.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(s string) (feedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return feedCursor{}, err
+	}
+	scoreStr, idStr, found := strings.Cut(string(raw), ":")
+	if !found {
+		return feedCursor{}, fmt.Errorf("malformed cursor")
+	}
+	score, err := strconv.ParseFloat(scoreStr, 64)
+	if err != nil {
+		return feedCursor{}, err
+	}
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		return feedCursor{}, err
+	}
+	return feedCursor{Score: score, ID: int32(id)}, nil
+}
+
+// pageDebateFeed returns up to limit items ranked after the cursor and the
+// cursor for the following page, which is empty on the last page
+func pageDebateFeed(items []DebateFeedItem, after *feedCursor, limit int) ([]DebateFeedItem, string) {
+	start := 0
+	if after != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return feedItemBefore(after.Score, after.ID, items[i].Score, items[i].ID)
+		})
+	}
+
+	end := min(start+limit, len(items))
+	page := items[start:end]
+	if end >= len(items) || len(page) == 0 {
+		return page, ""
+	}
+	last := page[len(page)-1]
+	return page, encodeFeedCursor(feedCursor{Score: last.Score, ID: last.ID})
+}
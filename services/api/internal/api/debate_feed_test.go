@@ -0,0 +1,109 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+)
+
+func TestFeedCursorRoundTrip(t *testing.T) {
+	cursor := feedCursor{Score: 2.718281828459045, ID: 42}
+
+	decoded, err := decodeFeedCursor(encodeFeedCursor(cursor))
+	if err != nil {
+		t.Fatalf("Unexpected error decoding cursor: %v", err)
+	}
+	if decoded != cursor {
+		t.Errorf("Expected cursor %+v, got %+v", cursor, decoded)
+	}
+
+	if _, err := decodeFeedCursor("not-a-cursor"); err == nil {
+		t.Error("Expected error for malformed cursor")
+	}
+}
+
+func TestRankDebateFeed(t *testing.T) {
+	now := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(-d), Valid: true}
+	}
+
+	rows := []database.ListDebateFeedCandidatesRow{
+		{ID: 1, CreatedAt: at(time.Hour)},
+		{ID: 2, CreatedAt: at(48 * time.Hour), IsFollowed: true},
+		{ID: 3, CreatedAt: at(time.Hour), HasVoted: true},
+		{ID: 4, CreatedAt: at(72 * time.Hour), RecentVotes: 10},
+	}
+
+	items := rankDebateFeed(rows, now)
+
+	order := make([]int32, len(items))
+	for i, item := range items {
+		order[i] = item.ID
+	}
+	want := []int32{2, 4, 1, 3}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected order %v, got %v", want, order)
+		}
+	}
+
+	if !items[0].Followed {
+		t.Error("Expected followed flag on followed debate")
+	}
+	if !items[3].HasVoted {
+		t.Error("Expected has_voted flag on voted debate")
+	}
+}
+
+func TestPageDebateFeed(t *testing.T) {
+	items := []DebateFeedItem{
+		{DebateResponse: DebateResponse{ID: 5}, Score: 3},
+		{DebateResponse: DebateResponse{ID: 4}, Score: 2},
+		{DebateResponse: DebateResponse{ID: 9}, Score: 1},
+		{DebateResponse: DebateResponse{ID: 7}, Score: 1},
+		{DebateResponse: DebateResponse{ID: 1}, Score: 0.5},
+	}
+
+	page, next := pageDebateFeed(items, nil, 2)
+	if len(page) != 2 || page[0].ID != 5 || page[1].ID != 4 {
+		t.Fatalf("Unexpected first page: %+v", page)
+	}
+	if next == "" {
+		t.Fatal("Expected a next cursor after the first page")
+	}
+
+	cursor, err := decodeFeedCursor(next)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding cursor: %v", err)
+	}
+	page, next = pageDebateFeed(items, &cursor, 2)
+	if len(page) != 2 || page[0].ID != 9 || page[1].ID != 7 {
+		t.Fatalf("Unexpected second page: %+v", page)
+	}
+
+	cursor, _ = decodeFeedCursor(next)
+	page, next = pageDebateFeed(items, &cursor, 2)
+	if len(page) != 1 || page[0].ID != 1 {
+		t.Fatalf("Unexpected last page: %+v", page)
+	}
+	if next != "" {
+		t.Errorf("Expected no cursor on the last page, got %q", next)
+	}
+}
+
+func TestGetDebateFeedRequiresAuth(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("GET", "/debates/feed", nil)
+	rec := httptest.NewRecorder()
+
+	config.getDebateFeed(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,16 +13,23 @@ import (
 
 	"github.com/ArronJLinton/fucci-api/internal/ai"
 	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
 	"github.com/go-chi/chi"
 )
 
 // Debate API types
 type CreateDebateRequest struct {
-	MatchID     string `json:"match_id"`
-	DebateType  string `json:"debate_type"` // "pre_match" or "post_match"
-	Headline    string `json:"headline"`
+	MatchID     string                   `json:"match_id"`
+	DebateType  string                   `json:"debate_type"` // "pre_match" or "post_match"
+	Headline    string                   `json:"headline"`
+	Description string                   `json:"description"`
+	Cards       []CreateDebateCardFields `json:"cards,omitempty"`
+}
+
+type CreateDebateCardFields struct {
+	Stance      string `json:"stance"` // "agree", "disagree", "wildcard"
+	Title       string `json:"title"`
 	Description string `json:"description"`
-	AIGenerated bool   `json:"ai_generated"`
 }
 
 type GenerateDebateRequest struct {
@@ -35,7 +43,6 @@ type CreateDebateCardRequest struct {
 	Stance      string `json:"stance"` // "agree", "disagree", "wildcard"
 	Title       string `json:"title"`
 	Description string `json:"description"`
-	AIGenerated bool   `json:"ai_generated"`
 }
 
 type CreateVoteRequest struct {
@@ -51,16 +58,20 @@ type CreateCommentRequest struct {
 }
 
 type DebateResponse struct {
-	ID          int32                    `json:"id"`
-	MatchID     string                   `json:"match_id"`
-	DebateType  string                   `json:"debate_type"`
-	Headline    string                   `json:"headline"`
-	Description string                   `json:"description"`
-	AIGenerated bool                     `json:"ai_generated"`
-	CreatedAt   time.Time                `json:"created_at"`
-	UpdatedAt   time.Time                `json:"updated_at"`
-	Cards       []DebateCardResponse     `json:"cards,omitempty"`
-	Analytics   *DebateAnalyticsResponse `json:"analytics,omitempty"`
+	ID          int32                     `json:"id"`
+	MatchID     string                    `json:"match_id"`
+	DebateType  string                    `json:"debate_type"`
+	Headline    string                    `json:"headline"`
+	Description string                    `json:"description"`
+	AIGenerated bool                      `json:"ai_generated"`
+	Status      string                    `json:"status"`
+	Author      *DebateAuthorResponse     `json:"author,omitempty"`
+	PromotedAt  *time.Time                `json:"promoted_at,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+	Cards       []DebateCardResponse      `json:"cards,omitempty"`
+	Analytics   *DebateAnalyticsResponse  `json:"analytics,omitempty"`
+	Resolution  *DebateResolutionResponse `json:"resolution,omitempty"`
 }
 
 type DebateCardResponse struct {
@@ -74,6 +85,7 @@ type DebateCardResponse struct {
 	UpdatedAt   time.Time     `json:"updated_at"`
 	VoteCounts  VoteCounts    `json:"vote_counts"`
 	UserVote    *VoteResponse `json:"user_vote,omitempty"`
+	IsWinner    bool          `json:"is_winner,omitempty"`
 }
 
 type VoteCounts struct {
@@ -104,19 +116,28 @@ type CommentResponse struct {
 }
 
 type DebateAnalyticsResponse struct {
-	ID              int32     `json:"id"`
-	DebateID        int32     `json:"debate_id"`
-	TotalVotes      int       `json:"total_votes"`
-	TotalComments   int       `json:"total_comments"`
-	EngagementScore float64   `json:"engagement_score"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                 int32     `json:"id"`
+	DebateID           int32     `json:"debate_id"`
+	TotalVotes         int       `json:"total_votes"`
+	TotalComments      int       `json:"total_comments"`
+	UniqueParticipants int       `json:"unique_participants"`
+	EngagementScore    float64   `json:"engagement_score"`
+	TrendingScore      float64   `json:"trending_score"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 // Debate API handlers
 func (c *Config) createDebate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	role, _ := r.Context().Value("user_role").(string)
+
 	var req CreateDebateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
@@ -134,13 +155,58 @@ func (c *Config) createDebate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, card := range req.Cards {
+		if card.Title == "" {
+			respondWithError(w, http.StatusBadRequest, "card title is required")
+			return
+		}
+		if card.Stance != "agree" && card.Stance != "disagree" && card.Stance != "wildcard" {
+			respondWithError(w, http.StatusBadRequest, "card stance must be 'agree', 'disagree', or 'wildcard'")
+			return
+		}
+	}
+
+	// League points are earned in whichever league the fixture belongs to
+	fixture, err := c.fetchFixture(ctx, req.MatchID)
+	if err != nil {
+		if errors.Is(err, errFixtureNotFound) {
+			respondWithError(w, http.StatusNotFound, "Match not found")
+			return
+		}
+		respondWithError(w, http.StatusBadGateway, fmt.Sprintf("Failed to get match: %v", err))
+		return
+	}
+	leagueID := sql.NullInt32{Int32: int32(fixture.LeagueID), Valid: fixture.LeagueID != 0}
+
+	// Fan debates start as proposals; admins publish directly
+	status := debateStatusProposed
+	if role == "admin" {
+		status = debateStatusPublished
+	} else {
+		count, err := c.DB.CountDebatesByAuthorForMatch(ctx, database.CountDebatesByAuthorForMatchParams{
+			MatchID:  req.MatchID,
+			AuthorID: sql.NullInt32{Int32: userID, Valid: true},
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check debate limit: %v", err))
+			return
+		}
+		if count >= maxCommunityDebatesPerMatch {
+			respondWithError(w, http.StatusTooManyRequests, fmt.Sprintf("You can propose at most %d debates per match", maxCommunityDebatesPerMatch))
+			return
+		}
+	}
+
 	// Create debate in database
 	debate, err := c.DB.CreateDebate(ctx, database.CreateDebateParams{
 		MatchID:     req.MatchID,
 		DebateType:  req.DebateType,
 		Headline:    req.Headline,
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		AiGenerated: sql.NullBool{Bool: req.AIGenerated, Valid: true},
+		AiGenerated: sql.NullBool{Bool: false, Valid: true},
+		AuthorID:    sql.NullInt32{Int32: userID, Valid: true},
+		Status:      status,
+		LeagueID:    leagueID,
 	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create debate: %v", err))
@@ -148,20 +214,35 @@ func (c *Config) createDebate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create analytics record
-	_, err = c.DB.CreateDebateAnalytics(ctx, database.CreateDebateAnalyticsParams{
+	_, err = c.DB.UpdateDebateAnalytics(ctx, database.UpdateDebateAnalyticsParams{
 		DebateID:        sql.NullInt32{Int32: debate.ID, Valid: true},
 		TotalVotes:      sql.NullInt32{Int32: 0, Valid: true},
 		TotalComments:   sql.NullInt32{Int32: 0, Valid: true},
 		EngagementScore: sql.NullString{String: "0.0", Valid: true},
+		TrendingScore:   trendingScore(0, 0, 0, debate.CreatedAt.Time),
 	})
 	if err != nil {
 		// Log error but don't fail the request
 		fmt.Printf("Failed to create debate analytics: %v\n", err)
 	}
 
+	for _, card := range req.Cards {
+		_, err := c.DB.CreateDebateCard(ctx, database.CreateDebateCardParams{
+			DebateID:    sql.NullInt32{Int32: debate.ID, Valid: true},
+			Stance:      card.Stance,
+			Title:       card.Title,
+			Description: sql.NullString{String: card.Description, Valid: card.Description != ""},
+			AiGenerated: sql.NullBool{Bool: false, Valid: true},
+		})
+		if err != nil {
+			fmt.Printf("Failed to create debate card: %v\n", err)
+		}
+	}
+
 	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
 		"message":   "Debate created successfully",
 		"debate_id": debate.ID,
+		"status":    debate.Status,
 	})
 }
 
@@ -201,16 +282,8 @@ func (c *Config) getDebate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build response
-	response := DebateResponse{
-		ID:          debate.ID,
-		MatchID:     debate.MatchID,
-		DebateType:  debate.DebateType,
-		Headline:    debate.Headline,
-		Description: debate.Description.String,
-		AIGenerated: debate.AiGenerated.Bool,
-		CreatedAt:   debate.CreatedAt.Time,
-		UpdatedAt:   debate.UpdatedAt.Time,
-	}
+	response := newDebateResponse(debate)
+	c.attachDebateAuthors(ctx, []*DebateResponse{&response}, []database.Debate{debate})
 
 	// Add cards with vote counts
 	cardIDs := make([]int32, len(cards))
@@ -275,16 +348,23 @@ func (c *Config) getDebate(w http.ResponseWriter, r *http.Request) {
 		}
 
 		response.Analytics = &DebateAnalyticsResponse{
-			ID:              analytics.ID,
-			DebateID:        analytics.DebateID.Int32,
-			TotalVotes:      int(analytics.TotalVotes.Int32),
-			TotalComments:   int(analytics.TotalComments.Int32),
-			EngagementScore: engagementScore,
-			CreatedAt:       analytics.CreatedAt.Time,
-			UpdatedAt:       analytics.UpdatedAt.Time,
+			ID:                 analytics.ID,
+			DebateID:           analytics.DebateID.Int32,
+			TotalVotes:         int(analytics.TotalVotes.Int32),
+			TotalComments:      int(analytics.TotalComments.Int32),
+			UniqueParticipants: int(analytics.UniqueParticipants),
+			EngagementScore:    engagementScore,
+			TrendingScore:      analytics.TrendingScore,
+			CreatedAt:          analytics.CreatedAt.Time,
+			UpdatedAt:          analytics.UpdatedAt.Time,
 		}
 	}
 
+	// Show the resolved banner once a prediction has been settled
+	if debate.DebateType == "pre_match" {
+		c.attachDebateResolution(ctx, &response, cards)
+	}
+
 	respondWithJSON(w, http.StatusOK, response)
 }
 
@@ -297,33 +377,56 @@ func (c *Config) getDebatesByMatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	debates, err := c.DB.GetDebatesByMatch(ctx, matchID)
+	// Only published debates are shown unless proposals are explicitly requested
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = debateStatusPublished
+	}
+	if status != debateStatusPublished && status != debateStatusProposed {
+		respondWithError(w, http.StatusBadRequest, "status must be 'published' or 'proposed'")
+		return
+	}
+
+	aiGenerated, err := parseDebateSource(r.URL.Query().Get("source"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	debates, err := c.DB.ListDebatesByMatch(ctx, database.ListDebatesByMatchParams{
+		MatchID:     matchID,
+		Status:      sql.NullString{String: status, Valid: true},
+		AiGenerated: aiGenerated,
+	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get debates: %v", err))
 		return
 	}
 
 	// Convert to response format
-	var response []DebateResponse
-	for _, debate := range debates {
-		response = append(response, DebateResponse{
-			ID:          debate.ID,
-			MatchID:     debate.MatchID,
-			DebateType:  debate.DebateType,
-			Headline:    debate.Headline,
-			Description: debate.Description.String,
-			AIGenerated: debate.AiGenerated.Bool,
-			CreatedAt:   debate.CreatedAt.Time,
-			UpdatedAt:   debate.UpdatedAt.Time,
-		})
+	response := make([]DebateResponse, len(debates))
+	responsePtrs := make([]*DebateResponse, len(debates))
+	for i, debate := range debates {
+		response[i] = newDebateResponse(debate)
+		responsePtrs[i] = &response[i]
 	}
+	c.attachDebateAuthors(ctx, responsePtrs, debates)
 
 	respondWithJSON(w, http.StatusOK, response)
 }
 
+// createDebateCard adds a card to a debate. Authors can add cards to their
+// debates while they're proposed; admins can add them to any debate. Cards
+// added here are never marked AI generated.
 func (c *Config) createDebateCard(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	var req CreateDebateCardRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
@@ -341,13 +444,33 @@ func (c *Config) createDebateCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	debate, err := c.DB.GetDebate(ctx, req.DebateID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Debate not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get debate: %v", err))
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" {
+		if !debate.AuthorID.Valid || debate.AuthorID.Int32 != userID {
+			respondWithError(w, http.StatusForbidden, "Only the debate's author can add cards to it")
+			return
+		}
+		if debate.Status != debateStatusProposed {
+			respondWithError(w, http.StatusConflict, "Cards can only be added while the debate is proposed")
+			return
+		}
+	}
+
 	// Create debate card
 	card, err := c.DB.CreateDebateCard(ctx, database.CreateDebateCardParams{
-		DebateID:    sql.NullInt32{Int32: req.DebateID, Valid: true},
+		DebateID:    sql.NullInt32{Int32: debate.ID, Valid: true},
 		Stance:      req.Stance,
 		Title:       req.Title,
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		AiGenerated: sql.NullBool{Bool: req.AIGenerated, Valid: true},
+		AiGenerated: sql.NullBool{Bool: false, Valid: true},
 	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create debate card: %v", err))
@@ -363,6 +486,12 @@ func (c *Config) createDebateCard(w http.ResponseWriter, r *http.Request) {
 func (c *Config) createVote(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	var req CreateVoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
@@ -380,11 +509,66 @@ func (c *Config) createVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get user ID from context (you'll need to implement authentication)
-	userID := int32(1) // TODO: Get from auth context
+	card, err := c.DB.GetDebateCard(ctx, req.DebateCardID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Debate card not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get debate card: %v", err))
+		return
+	}
+
+	debate, err := c.DB.GetDebate(ctx, card.DebateID.Int32)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Debate not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get debate: %v", err))
+		return
+	}
+
+	// Predictions close at kickoff
+	prediction := debate.DebateType == "pre_match"
+	if prediction {
+		fixture, err := c.fetchFixture(ctx, debate.MatchID)
+		if err != nil {
+			if errors.Is(err, errFixtureNotFound) {
+				respondWithError(w, http.StatusNotFound, "Match not found")
+				return
+			}
+			respondWithError(w, http.StatusBadGateway, fmt.Sprintf("Failed to get match: %v", err))
+			return
+		}
+		if fixture.locked(time.Now()) {
+			respondWithError(w, http.StatusConflict, errPredictionsLocked.Error())
+			return
+		}
+	}
+
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create vote: %v", err))
+		return
+	}
+	defer tx.Rollback()
+	qtx := c.DB.WithTx(tx)
+
+	// A user backs one outcome per prediction debate
+	if prediction && req.VoteType == "upvote" {
+		if err := backPrediction(ctx, qtx, debate.ID, card.ID, userID); err != nil {
+			if errors.Is(err, errPredictionBacked) {
+				respondWithError(w, http.StatusConflict, err.Error())
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create vote: %v", err))
+			return
+		}
+	}
 
 	// Create vote
-	vote, err := c.DB.CreateVote(ctx, database.CreateVoteParams{
+	vote, err := qtx.CreateVote(ctx, database.CreateVoteParams{
 		DebateCardID: sql.NullInt32{Int32: req.DebateCardID, Valid: true},
 		UserID:       sql.NullInt32{Int32: userID, Valid: true},
 		VoteType:     req.VoteType,
@@ -395,8 +579,23 @@ func (c *Config) createVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update analytics
-	c.updateDebateAnalytics(ctx, req.DebateCardID)
+	// Analytics, points and achievements are updated by the VoteCast subscribers
+	if err := events.Append(ctx, tx, events.VoteCast{
+		VoteID:       vote.ID,
+		DebateID:     card.DebateID.Int32,
+		DebateCardID: req.DebateCardID,
+		UserID:       userID,
+		VoteType:     req.VoteType,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create vote: %v", err))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create vote: %v", err))
+		return
+	}
+
+	c.invalidateDebateFeed(ctx, userID)
 
 	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
 		"message": "Vote created successfully",
@@ -404,9 +603,37 @@ func (c *Config) createVote(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// backPrediction checks that the user hasn't upvoted another card in the
+// prediction debate. The debate stays locked until the vote is committed.
+func backPrediction(ctx context.Context, qtx *database.Queries, debateID, cardID, userID int32) error {
+	if _, err := qtx.LockDebate(ctx, debateID); err != nil {
+		return err
+	}
+	backed, err := qtx.GetPredictionUpvote(ctx, database.GetPredictionUpvoteParams{
+		DebateID: sql.NullInt32{Int32: debateID, Valid: true},
+		UserID:   sql.NullInt32{Int32: userID, Valid: true},
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if backed.Int32 != cardID {
+		return errPredictionBacked
+	}
+	return nil
+}
+
 func (c *Config) createComment(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	var req CreateCommentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
@@ -419,9 +646,6 @@ func (c *Config) createComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get user ID from context (you'll need to implement authentication)
-	userID := int32(1) // TODO: Get from auth context
-
 	// Create comment
 	var parentCommentID sql.NullInt32
 	if req.ParentCommentID != nil && *req.ParentCommentID > 0 {
@@ -430,7 +654,14 @@ func (c *Config) createComment(w http.ResponseWriter, r *http.Request) {
 		parentCommentID = sql.NullInt32{Valid: false}
 	}
 
-	comment, err := c.DB.CreateComment(ctx, database.CreateCommentParams{
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create comment: %v", err))
+		return
+	}
+	defer tx.Rollback()
+
+	comment, err := c.DB.WithTx(tx).CreateComment(ctx, database.CreateCommentParams{
 		DebateID:        sql.NullInt32{Int32: req.DebateID, Valid: true},
 		ParentCommentID: parentCommentID,
 		UserID:          sql.NullInt32{Int32: userID, Valid: true},
@@ -441,8 +672,19 @@ func (c *Config) createComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update analytics
-	c.updateDebateAnalytics(ctx, req.DebateID)
+	// Analytics, points and achievements are updated by the CommentPosted subscribers
+	posted := events.CommentPosted{CommentID: comment.ID, DebateID: req.DebateID, UserID: userID}
+	if parentCommentID.Valid {
+		posted.ParentCommentID = &parentCommentID.Int32
+	}
+	if err := events.Append(ctx, tx, posted); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create comment: %v", err))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create comment: %v", err))
+		return
+	}
 
 	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
 		"message":    "Comment created successfully",
@@ -623,8 +865,11 @@ func (c *Config) generateDebate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if debate already exists for this match and type
-	existingDebates, err := c.DB.GetDebatesByMatch(ctx, req.MatchID)
+	// Check if an AI debate already exists for this match and type
+	existingDebates, err := c.DB.ListDebatesByMatch(ctx, database.ListDebatesByMatchParams{
+		MatchID:     req.MatchID,
+		AiGenerated: sql.NullBool{Bool: true, Valid: true},
+	})
 	if err == nil {
 		for _, existing := range existingDebates {
 			if existing.DebateType == req.DebateType {
@@ -685,13 +930,24 @@ func (c *Config) generateDebate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The debate, its cards and the DebateGenerated event are saved together
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create debate: %v", err))
+		return
+	}
+	defer tx.Rollback()
+	qtx := c.DB.WithTx(tx)
+
 	// Create the debate in the database
-	debate, err := c.DB.CreateDebate(ctx, database.CreateDebateParams{
+	debate, err := qtx.CreateDebate(ctx, database.CreateDebateParams{
 		MatchID:     req.MatchID,
 		DebateType:  req.DebateType,
 		Headline:    prompt.Headline,
 		Description: sql.NullString{String: prompt.Description, Valid: prompt.Description != ""},
 		AiGenerated: sql.NullBool{Bool: true, Valid: true},
+		Status:      debateStatusPublished,
+		LeagueID:    sql.NullInt32{Int32: int32(matchInfo.LeagueID), Valid: matchInfo.LeagueID != 0},
 	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create debate: %v", err))
@@ -699,11 +955,12 @@ func (c *Config) generateDebate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create analytics record
-	_, err = c.DB.CreateDebateAnalytics(ctx, database.CreateDebateAnalyticsParams{
+	_, err = qtx.UpdateDebateAnalytics(ctx, database.UpdateDebateAnalyticsParams{
 		DebateID:        sql.NullInt32{Int32: debate.ID, Valid: true},
 		TotalVotes:      sql.NullInt32{Int32: 0, Valid: true},
 		TotalComments:   sql.NullInt32{Int32: 0, Valid: true},
 		EngagementScore: sql.NullString{String: "0.0", Valid: true},
+		TrendingScore:   trendingScore(0, 0, 0, debate.CreatedAt.Time),
 	})
 	if err != nil {
 		fmt.Printf("Failed to create debate analytics: %v\n", err)
@@ -725,7 +982,7 @@ func (c *Config) generateDebate(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Create the card in the database
-		dbCard, err := c.DB.CreateDebateCard(ctx, database.CreateDebateCardParams{
+		dbCard, err := qtx.CreateDebateCard(ctx, database.CreateDebateCardParams{
 			DebateID:    sql.NullInt32{Int32: debate.ID, Valid: true},
 			Stance:      card.Stance,
 			Title:       card.Title,
@@ -762,6 +1019,23 @@ func (c *Config) generateDebate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := events.Append(ctx, tx, events.DebateGenerated{
+		DebateID:   debate.ID,
+		MatchID:    debate.MatchID,
+		DebateType: debate.DebateType,
+		Headline:   debate.Headline,
+		LeagueID:   debate.LeagueID.Int32,
+		HomeTeam:   matchInfo.HomeTeam,
+		AwayTeam:   matchInfo.AwayTeam,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create debate: %v", err))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create debate: %v", err))
+		return
+	}
+
 	// Build the complete response
 	response := DebateResponse{
 		ID:          debate.ID,
@@ -820,16 +1094,8 @@ func (c *Config) getDebateByID(w http.ResponseWriter, r *http.Request, debateID
 	}
 
 	// Build response
-	response := DebateResponse{
-		ID:          debate.ID,
-		MatchID:     debate.MatchID,
-		DebateType:  debate.DebateType,
-		Headline:    debate.Headline,
-		Description: debate.Description.String,
-		AIGenerated: debate.AiGenerated.Bool,
-		CreatedAt:   debate.CreatedAt.Time,
-		UpdatedAt:   debate.UpdatedAt.Time,
-	}
+	response := newDebateResponse(debate)
+	c.attachDebateAuthors(ctx, []*DebateResponse{&response}, []database.Debate{debate})
 
 	// Add cards with vote counts
 	cardIDs := make([]int32, len(cards))
@@ -894,16 +1160,23 @@ func (c *Config) getDebateByID(w http.ResponseWriter, r *http.Request, debateID
 		}
 
 		response.Analytics = &DebateAnalyticsResponse{
-			ID:              analytics.ID,
-			DebateID:        analytics.DebateID.Int32,
-			TotalVotes:      int(analytics.TotalVotes.Int32),
-			TotalComments:   int(analytics.TotalComments.Int32),
-			EngagementScore: engagementScore,
-			CreatedAt:       analytics.CreatedAt.Time,
-			UpdatedAt:       analytics.UpdatedAt.Time,
+			ID:                 analytics.ID,
+			DebateID:           analytics.DebateID.Int32,
+			TotalVotes:         int(analytics.TotalVotes.Int32),
+			TotalComments:      int(analytics.TotalComments.Int32),
+			UniqueParticipants: int(analytics.UniqueParticipants),
+			EngagementScore:    engagementScore,
+			TrendingScore:      analytics.TrendingScore,
+			CreatedAt:          analytics.CreatedAt.Time,
+			UpdatedAt:          analytics.UpdatedAt.Time,
 		}
 	}
 
+	// Show the resolved banner once a prediction has been settled
+	if debate.DebateType == "pre_match" {
+		c.attachDebateResolution(ctx, &response, cards)
+	}
+
 	respondWithJSON(w, http.StatusOK, response)
 }
 
@@ -945,9 +1218,11 @@ func (c *Config) getMatchInfo(ctx context.Context, matchID string) (*MatchInfo,
 			} `json:"fixture"`
 			Teams struct {
 				Home struct {
+					ID   int    `json:"id"`
 					Name string `json:"name"`
 				} `json:"home"`
 				Away struct {
+					ID   int    `json:"id"`
 					Name string `json:"name"`
 				} `json:"away"`
 			} `json:"teams"`
@@ -974,6 +1249,7 @@ func (c *Config) getMatchInfo(ctx context.Context, matchID string) (*MatchInfo,
 				} `json:"penalty"`
 			} `json:"score"`
 			League struct {
+				ID     int    `json:"id"`
 				Name   string `json:"name"`
 				Season int    `json:"season"`
 			} `json:"league"`
@@ -990,6 +1266,7 @@ func (c *Config) getMatchInfo(ctx context.Context, matchID string) (*MatchInfo,
 	}
 
 	match := matchResponse.Response[0]
+	c.recordFixtureTeams(ctx, matchID, match.Teams.Home.Name, match.Teams.Away.Name)
 
 	// Determine final score based on match status
 	var homeScore, awayScore int
@@ -1016,6 +1293,8 @@ func (c *Config) getMatchInfo(ctx context.Context, matchID string) (*MatchInfo,
 	}
 
 	return &MatchInfo{
+		HomeTeamID:      match.Teams.Home.ID,
+		AwayTeamID:      match.Teams.Away.ID,
 		HomeTeam:        match.Teams.Home.Name,
 		AwayTeam:        match.Teams.Away.Name,
 		Date:            match.Fixture.Date,
@@ -1036,6 +1315,7 @@ func (c *Config) getMatchInfo(ctx context.Context, matchID string) (*MatchInfo,
 		AwayRedCards:    0,
 		Venue:           match.Fixture.Venue.Name,
 		League:          match.League.Name,
+		LeagueID:        match.League.ID,
 		Season:          fmt.Sprintf("%d", match.League.Season),
 	}, nil
 }
@@ -1052,7 +1332,16 @@ func (c *Config) getTopDebates(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	debates, err := c.DB.GetTopDebates(ctx, limit)
+	aiGenerated, err := parseDebateSource(r.URL.Query().Get("source"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	debates, err := c.DB.GetTopDebates(ctx, database.GetTopDebatesParams{
+		AiGenerated: aiGenerated,
+		MaxResults:  limit,
+	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get top debates: %v", err))
 		return
@@ -1068,9 +1357,13 @@ func (c *Config) getTopDebates(w http.ResponseWriter, r *http.Request) {
 			Headline:    debate.Headline,
 			Description: debate.Description.String,
 			AIGenerated: debate.AiGenerated.Bool,
+			Status:      debate.Status,
 			CreatedAt:   debate.CreatedAt.Time,
 			UpdatedAt:   debate.UpdatedAt.Time,
 		}
+		if debate.PromotedAt.Valid {
+			debateResponse.PromotedAt = &debate.PromotedAt.Time
+		}
 
 		if debate.TotalVotes.Valid {
 			engagementScore := 0.0
@@ -1098,22 +1391,19 @@ func (c *Config) getTopDebates(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, response)
 }
 
-// Helper function to update debate analytics
-func (c *Config) updateDebateAnalytics(ctx context.Context, debateCardID int32) {
-	// Get the debate ID from the card
-	card, err := c.DB.GetDebateCard(ctx, debateCardID)
+// updateDebateAnalytics recomputes the engagement and trending scores of a single
+// debate. It runs on every vote and comment, so the trending order is kept up
+// to date without a periodic job.
+func (c *Config) updateDebateAnalytics(ctx context.Context, debateID int32) error {
+	debate, err := c.DB.GetDebate(ctx, debateID)
 	if err != nil {
-		fmt.Printf("Failed to get debate card: %v\n", err)
-		return
+		return fmt.Errorf("error fetching debate: %w", err)
 	}
 
-	debateID := card.DebateID.Int32
-
 	// Get vote counts for all cards in this debate
 	cards, err := c.DB.GetDebateCards(ctx, sql.NullInt32{Int32: debateID, Valid: true})
 	if err != nil {
-		fmt.Printf("Failed to get debate cards: %v\n", err)
-		return
+		return fmt.Errorf("error fetching debate cards: %w", err)
 	}
 
 	cardIDs := make([]int32, len(cards))
@@ -1123,8 +1413,7 @@ func (c *Config) updateDebateAnalytics(ctx context.Context, debateCardID int32)
 
 	voteCounts, err := c.DB.GetVoteCounts(ctx, cardIDs)
 	if err != nil {
-		fmt.Printf("Failed to get vote counts: %v\n", err)
-		return
+		return fmt.Errorf("error fetching vote counts: %w", err)
 	}
 
 	// Calculate total votes
@@ -1136,8 +1425,12 @@ func (c *Config) updateDebateAnalytics(ctx context.Context, debateCardID int32)
 	// Get comment count
 	commentCount, err := c.DB.GetCommentCount(ctx, sql.NullInt32{Int32: debateID, Valid: true})
 	if err != nil {
-		fmt.Printf("Failed to get comment count: %v\n", err)
-		return
+		return fmt.Errorf("error fetching comment count: %w", err)
+	}
+
+	participants, err := c.DB.CountDebateParticipants(ctx, sql.NullInt32{Int32: debateID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("error counting debate participants: %w", err)
 	}
 
 	// Calculate engagement score (votes + comments * 2 for comment weight)
@@ -1145,14 +1438,19 @@ func (c *Config) updateDebateAnalytics(ctx context.Context, debateCardID int32)
 
 	// Update analytics
 	_, err = c.DB.UpdateDebateAnalytics(ctx, database.UpdateDebateAnalyticsParams{
-		DebateID:        sql.NullInt32{Int32: debateID, Valid: true},
-		TotalVotes:      sql.NullInt32{Int32: int32(totalVotes), Valid: true},
-		TotalComments:   sql.NullInt32{Int32: int32(commentCount), Valid: true},
-		EngagementScore: sql.NullString{String: fmt.Sprintf("%.2f", engagementScore), Valid: true},
+		DebateID:           sql.NullInt32{Int32: debateID, Valid: true},
+		TotalVotes:         sql.NullInt32{Int32: int32(totalVotes), Valid: true},
+		TotalComments:      sql.NullInt32{Int32: int32(commentCount), Valid: true},
+		EngagementScore:    sql.NullString{String: fmt.Sprintf("%.2f", engagementScore), Valid: true},
+		UniqueParticipants: int32(participants),
+		TrendingScore:      trendingScore(totalVotes, int(commentCount), int(participants), debate.CreatedAt.Time),
 	})
 	if err != nil {
-		fmt.Printf("Failed to update debate analytics: %v\n", err)
+		return fmt.Errorf("error updating debate analytics: %w", err)
 	}
+
+	c.promoteDebateIfEngaged(ctx, debate, engagementScore)
+	return nil
 }
 
 // checkDebateGenerationHealth checks if all components needed for debate generation are working
@@ -1172,7 +1470,7 @@ func (c *Config) checkDebateGenerationHealth(w http.ResponseWriter, r *http.Requ
 
 	// Test database connection
 	if c.DB != nil {
-		_, err := c.DB.GetTopDebates(ctx, 1)
+		_, err := c.DB.GetTopDebates(ctx, database.GetTopDebatesParams{MaxResults: 1})
 		if err != nil {
 			health["status"] = "unhealthy"
 			health["database_error"] = err.Error()
@@ -1266,6 +1564,8 @@ func (c *Config) restoreDebate(w http.ResponseWriter, r *http.Request) {
 
 // MatchInfo represents detailed information about a match
 type MatchInfo struct {
+	HomeTeamID      int
+	AwayTeamID      int
 	HomeTeam        string
 	AwayTeam        string
 	Date            string
@@ -1286,6 +1586,7 @@ type MatchInfo struct {
 	AwayRedCards    int
 	Venue           string
 	League          string
+	LeagueID        int
 	Season          string
 }
 
@@ -1293,6 +1594,8 @@ type MatchInfo struct {
 func (c *Config) buildMatchDataRequest(matchID string, matchInfo *MatchInfo) MatchDataRequest {
 	return MatchDataRequest{
 		MatchID:         matchID,
+		HomeTeamID:      matchInfo.HomeTeamID,
+		AwayTeamID:      matchInfo.AwayTeamID,
 		HomeTeam:        matchInfo.HomeTeam,
 		AwayTeam:        matchInfo.AwayTeam,
 		Date:            matchInfo.Date,
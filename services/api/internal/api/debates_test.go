@@ -1,9 +1,16 @@
 package api
 
 import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ArronJLinton/fucci-api/internal/cache"
+	"github.com/ArronJLinton/fucci-api/internal/database"
 )
 
 func TestDebateDataAggregator(t *testing.T) {
@@ -39,7 +46,10 @@ func TestCreateDebateRequest(t *testing.T) {
 			DebateType:  "pre_match",
 			Headline:    "Test Debate",
 			Description: "Test Description",
-			AIGenerated: true,
+			Cards: []CreateDebateCardFields{
+				{Stance: "agree", Title: "Yes"},
+				{Stance: "disagree", Title: "No"},
+			},
 		}
 
 		if req.MatchID != "12345" {
@@ -51,12 +61,118 @@ func TestCreateDebateRequest(t *testing.T) {
 		if req.Headline != "Test Debate" {
 			t.Errorf("Expected Headline to be 'Test Debate', got %s", req.Headline)
 		}
-		if !req.AIGenerated {
-			t.Error("Expected AIGenerated to be true")
+		if len(req.Cards) != 2 {
+			t.Errorf("Expected 2 cards, got %d", len(req.Cards))
 		}
 	})
 }
 
+func TestCreateDebateRequiresAuth(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("POST", "/debates", strings.NewReader(`{"match_id":"1","debate_type":"pre_match","headline":"Test"}`))
+	rec := httptest.NewRecorder()
+
+	config.createDebate(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestCreateDebateValidatesCards(t *testing.T) {
+	config := &Config{}
+	body := `{"match_id":"1","debate_type":"pre_match","headline":"Test","cards":[{"stance":"maybe","title":"?"}]}`
+	req := httptest.NewRequest("POST", "/debates", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), "user_id", int32(7))
+	ctx = context.WithValue(ctx, "user_role", "fan")
+	rec := httptest.NewRecorder()
+
+	config.createDebate(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestCreateDebateUnknownFixture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":0,"response":[]}`))
+	}))
+	defer server.Close()
+
+	config := &Config{APIFootballBaseURL: server.URL}
+	body := `{"match_id":"404","debate_type":"pre_match","headline":"Test","league_id":39}`
+	req := httptest.NewRequest("POST", "/debates", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), "user_id", int32(7))
+	rec := httptest.NewRecorder()
+
+	config.createDebate(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+}
+
+func TestCreateDebateCardRequiresAuth(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("POST", "/debates/cards", strings.NewReader(`{"debate_id":1,"stance":"agree","title":"Yes"}`))
+	rec := httptest.NewRecorder()
+
+	config.createDebateCard(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestParseDebateSource(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    sql.NullBool
+		wantErr bool
+	}{
+		{"", sql.NullBool{}, false},
+		{"ai", sql.NullBool{Bool: true, Valid: true}, false},
+		{"community", sql.NullBool{Bool: false, Valid: true}, false},
+		{"bots", sql.NullBool{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDebateSource(tt.source)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDebateSource(%q) error = %v, wantErr %v", tt.source, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("parseDebateSource(%q) = %+v, want %+v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestNewDebateResponse(t *testing.T) {
+	promotedAt := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+	debate := database.Debate{
+		ID:          3,
+		MatchID:     "12345",
+		DebateType:  "pre_match",
+		Headline:    "Fan debate",
+		AiGenerated: sql.NullBool{Bool: false, Valid: true},
+		AuthorID:    sql.NullInt32{Int32: 7, Valid: true},
+		Status:      debateStatusPublished,
+		PromotedAt:  sql.NullTime{Time: promotedAt, Valid: true},
+	}
+
+	response := newDebateResponse(debate)
+	if response.Status != debateStatusPublished {
+		t.Errorf("Expected status 'published', got %s", response.Status)
+	}
+	if response.AIGenerated {
+		t.Error("Expected community debate to not be AI generated")
+	}
+	if response.PromotedAt == nil || !response.PromotedAt.Equal(promotedAt) {
+		t.Errorf("Expected promoted_at %v, got %v", promotedAt, response.PromotedAt)
+	}
+}
+
 func TestCreateVoteRequest(t *testing.T) {
 	t.Run("valid vote request", func(t *testing.T) {
 		req := CreateVoteRequest{
@@ -256,3 +372,25 @@ func TestMultipleEmojiVotesOnDebateCard(t *testing.T) {
 		t.Errorf("Expected 👍 emoji count to be 2 after second vote, got %d", voteCounts.Emojis["👍"])
 	}
 }
+
+func TestTeamMatchIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("team") != "42" {
+			t.Errorf("Expected fixtures for team 42, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"response":[{"fixture":{"id":1035}},{"fixture":{"id":1001}},{"fixture":{"id":998}}]}`))
+	}))
+	defer server.Close()
+
+	aggregator := NewDebateDataAggregator(&Config{APIFootballBaseURL: server.URL})
+
+	ids := aggregator.teamMatchIDs(context.Background(), 42, "1035")
+	if strings.Join(ids, ",") != "1035,1001,998" {
+		t.Errorf("Expected the current fixture once followed by recent ones, got %v", ids)
+	}
+
+	// Without a team ID only the fixture being debated counts
+	if ids := aggregator.teamMatchIDs(context.Background(), 0, "1035"); len(ids) != 1 || ids[0] != "1035" {
+		t.Errorf("Expected only the current fixture, got %v", ids)
+	}
+}
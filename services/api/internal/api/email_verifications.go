@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/mail"
+)
+
+// emailVerificationTTL is how long a verification link works for
+const emailVerificationTTL = 24 * time.Hour
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type VerifyEmailResponse struct {
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+}
+
+// requestEmailVerification sends the caller a link to verify their email
+func (c *Config) requestEmailVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	if c.Mailer == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Email verification is not available")
+		return
+	}
+	user, err := c.DB.GetUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get user: %v", err))
+		return
+	}
+	if err := c.sendEmailVerification(r.Context(), user); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to send verification email: %v", err))
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verifyEmail uses the token from a verification link. It also hands the
+// pending manager invitations sent to the address to the account.
+func (c *Config) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "A token is required")
+		return
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Email verification is not available")
+		return
+	}
+
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to verify email: %v", err))
+		return
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	verification, err := q.UseEmailVerification(ctx, hashToken(req.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusBadRequest, "This link is invalid or has expired")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to verify email: %v", err))
+		return
+	}
+	user, err := q.GetUser(ctx, verification.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get user: %v", err))
+		return
+	}
+	if strings.ToLower(user.Email) != verification.Email {
+		respondWithError(w, http.StatusConflict, "Your email has changed since this link was sent")
+		return
+	}
+	if err := q.BindManagerInvitations(ctx, database.BindManagerInvitationsParams{
+		InviteeID: sql.NullInt32{Int32: user.ID, Valid: true},
+		Email:     verification.Email,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update invitations: %v", err))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to verify email: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, VerifyEmailResponse{Email: user.Email, Verified: true})
+}
+
+// sendEmailVerification emails the user a single-use link to verify their
+// current address
+func (c *Config) sendEmailVerification(ctx context.Context, user database.User) error {
+	token, err := newVerificationToken()
+	if err != nil {
+		return err
+	}
+	email := strings.ToLower(user.Email)
+	if _, err := c.DB.CreateEmailVerification(ctx, database.CreateEmailVerificationParams{
+		UserID:    user.ID,
+		Email:     email,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}); err != nil {
+		return err
+	}
+	return c.Mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body:    fmt.Sprintf("Follow this link to verify your email for Fucci:\n\n%s\n\nIt works for 24 hours.", c.EmailVerificationURL+url.QueryEscape(token)),
+	})
+}
+
+// sendSignUpVerification emails a new user their verification link. Failing
+// to send doesn't stop sign up; they can ask for another.
+func (c *Config) sendSignUpVerification(ctx context.Context, userID int32) {
+	if c.Mailer == nil {
+		return
+	}
+	user, err := c.DB.GetUser(ctx, userID)
+	if err == nil {
+		err = c.sendEmailVerification(ctx, user)
+	}
+	if err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", userID, err)
+	}
+}
+
+// verifiedEmail returns the user's email, lower cased, and whether they've
+// verified it
+func (c *Config) verifiedEmail(ctx context.Context, userID int32) (string, bool, error) {
+	email, err := c.DB.GetVerifiedEmail(ctx, userID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return email, true, nil
+}
+
+func newVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken is what's stored for a token, so a database leak doesn't leak
+// working links
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
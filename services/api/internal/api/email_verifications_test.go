@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ArronJLinton/fucci-api/pkg/mail"
+)
+
+func TestHashToken(t *testing.T) {
+	token, err := newVerificationToken()
+	if err != nil {
+		t.Fatalf("newVerificationToken: %v", err)
+	}
+	if len(token) != 64 {
+		t.Errorf("token length = %d, want 64", len(token))
+	}
+	if hashToken(token) != hashToken(token) || hashToken(token) == token {
+		t.Error("hashToken should be stable and not the token itself")
+	}
+}
+
+func TestRequestEmailVerification(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&Config{Mailer: &mail.FakeSender{}}).requestEmailVerification(rec, httptest.NewRequest("POST", "/users/email-verification", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	(&Config{}).requestEmailVerification(rec, authedRequest("POST", "/users/email-verification", ""))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 without a mailer, got %d", rec.Code)
+	}
+}
+
+func TestVerifyEmailRequiresToken(t *testing.T) {
+	for _, body := range []string{`not json`, `{}`, `{"token":""}`} {
+		rec := httptest.NewRecorder()
+		(&Config{}).verifyEmail(rec, httptest.NewRequest("POST", "/auth/verify-email", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
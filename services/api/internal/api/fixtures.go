@@ -0,0 +1,619 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// maxGoalMinute allows for stoppage time in extra time
+const maxGoalMinute = 130
+
+type CreateFixtureRequest struct {
+	HomeTeamID uuid.UUID  `json:"home_team_id"`
+	AwayTeamID uuid.UUID  `json:"away_team_id"`
+	MatchDate  time.Time  `json:"match_date"`
+	Venue      string     `json:"venue"`
+	SeasonID   *uuid.UUID `json:"season_id"` // Defaults to the league's current season
+}
+
+// UpdateFixtureRequest reschedules a fixture, postpones it or cancels it.
+// Omitted fields are left as they are.
+type UpdateFixtureRequest struct {
+	MatchDate *time.Time `json:"match_date"`
+	Venue     *string    `json:"venue"`
+	Status    *string    `json:"status"` // scheduled, postponed or cancelled
+}
+
+type FixtureResultRequest struct {
+	HomeScore int32             `json:"home_score"`
+	AwayScore int32             `json:"away_score"`
+	Goals     []FixtureGoalData `json:"goals"`
+}
+
+// FixtureGoalData is a goal in a result. TeamID is the team the goal counts
+// for, so an own goal is credited to the scorer's opponents.
+type FixtureGoalData struct {
+	TeamID          uuid.UUID  `json:"team_id"`
+	PlayerProfileID *uuid.UUID `json:"player_profile_id,omitempty"`
+	ScorerName      string     `json:"scorer_name,omitempty"`
+	Minute          *int32     `json:"minute,omitempty"`
+	OwnGoal         bool       `json:"own_goal"`
+	Penalty         bool       `json:"penalty"`
+}
+
+type FixtureTeam struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Score *int32    `json:"score,omitempty"`
+}
+
+type FixtureConfirmation struct {
+	HomeConfirmed bool `json:"home_confirmed"`
+	AwayConfirmed bool `json:"away_confirmed"`
+	Confirmed     bool `json:"confirmed"` // Both sides have confirmed
+}
+
+type FixtureResponse struct {
+	ID           uuid.UUID            `json:"id"`
+	LeagueID     uuid.UUID            `json:"league_id"`
+	SeasonID     *uuid.UUID           `json:"season_id,omitempty"`
+	HomeTeam     FixtureTeam          `json:"home_team"`
+	AwayTeam     FixtureTeam          `json:"away_team"`
+	MatchDate    time.Time            `json:"match_date"`
+	Venue        string               `json:"venue,omitempty"`
+	Status       string               `json:"status"`
+	Round        int32                `json:"round,omitempty"`        // Round of a generated schedule
+	Confirmation *FixtureConfirmation `json:"confirmation,omitempty"` // Only once a result is entered
+	Goals        []FixtureGoalData    `json:"goals,omitempty"`
+}
+
+// fixtureAccess is what a user may do with a fixture. Organisers (admins and
+// the league's owner) act for both sides, team managers for their own.
+type fixtureAccess struct {
+	organiser bool
+	home      bool
+	away      bool
+}
+
+func (a fixtureAccess) any() bool {
+	return a.organiser || a.home || a.away
+}
+
+// fixtureUpdateStatuses are the statuses that can be set directly. Results
+// are entered separately and finish the fixture.
+var fixtureUpdateStatuses = map[database.MatchStatus]bool{
+	database.MatchStatusScheduled: true,
+	database.MatchStatusPostponed: true,
+	database.MatchStatusCancelled: true,
+}
+
+// createFixture schedules a match between two teams in the league. The
+// league's owner can schedule any fixture, a manager only their team's.
+func (c *Config) createFixture(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+
+	var req CreateFixtureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.HomeTeamID == uuid.Nil || req.AwayTeamID == uuid.Nil || req.MatchDate.IsZero() {
+		respondWithError(w, http.StatusBadRequest, "home_team_id, away_team_id and match_date are required")
+		return
+	}
+	if req.HomeTeamID == req.AwayTeamID {
+		respondWithError(w, http.StatusBadRequest, "A team can't play itself")
+		return
+	}
+
+	league, ok := c.fixtureLeague(w, ctx, leagueID)
+	if !ok {
+		return
+	}
+	season, ok := c.leagueSeason(w, ctx, leagueID, req.SeasonID)
+	if !ok {
+		return
+	}
+	if season.Archived {
+		respondWithError(w, http.StatusConflict, "Season is archived")
+		return
+	}
+	seasonTeams, err := c.seasonTeams(ctx, leagueID, season)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get teams: %v", err))
+		return
+	}
+	teams := make(map[uuid.UUID]string, len(seasonTeams))
+	for _, team := range seasonTeams {
+		teams[team.ID] = team.Name
+	}
+	if _, ok := teams[req.HomeTeamID]; !ok {
+		respondWithError(w, http.StatusBadRequest, "Home team is not in this league's season")
+		return
+	}
+	if _, ok := teams[req.AwayTeamID]; !ok {
+		respondWithError(w, http.StatusBadRequest, "Away team is not in this league's season")
+		return
+	}
+
+	access, err := c.fixtureAccess(r, league, req.HomeTeamID, req.AwayTeamID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+		return
+	}
+	if !access.any() {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner or the teams' managers can schedule this fixture")
+		return
+	}
+
+	match, err := c.DB.CreateFixture(ctx, database.CreateFixtureParams{
+		LeagueID:   uuid.NullUUID{UUID: leagueID, Valid: true},
+		HomeTeamID: uuid.NullUUID{UUID: req.HomeTeamID, Valid: true},
+		AwayTeamID: uuid.NullUUID{UUID: req.AwayTeamID, Valid: true},
+		MatchDate:  req.MatchDate,
+		Venue:      sql.NullString{String: req.Venue, Valid: req.Venue != ""},
+		CreatedBy:  sql.NullInt32{Int32: userID, Valid: true},
+		SeasonID:   seasonFilter(season),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create fixture: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newFixtureResponse(match, teams, nil))
+}
+
+// listLeagueFixtures returns a league's fixtures in date order, for
+// ?season_id= or the current season. ?status= and ?team_id= narrow it down.
+func (c *Config) listLeagueFixtures(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+
+	params := database.ListLeagueFixturesParams{LeagueID: uuid.NullUUID{UUID: leagueID, Valid: true}}
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		status := database.MatchStatus(statusStr)
+		switch status {
+		case database.MatchStatusScheduled, database.MatchStatusLive, database.MatchStatusFinished, database.MatchStatusPostponed, database.MatchStatusCancelled:
+			params.Status = database.NullMatchStatus{MatchStatus: status, Valid: true}
+		default:
+			respondWithError(w, http.StatusBadRequest, "Invalid status")
+			return
+		}
+	}
+	if teamStr := r.URL.Query().Get("team_id"); teamStr != "" {
+		teamID, err := uuid.Parse(teamStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid team ID")
+			return
+		}
+		params.TeamID = uuid.NullUUID{UUID: teamID, Valid: true}
+	}
+	seasonID, ok := seasonIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if _, ok := c.fixtureLeague(w, ctx, leagueID); !ok {
+		return
+	}
+	season, ok := c.leagueSeason(w, ctx, leagueID, seasonID)
+	if !ok {
+		return
+	}
+	params.SeasonID = seasonFilter(season)
+	matches, err := c.DB.ListLeagueFixtures(ctx, params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list fixtures: %v", err))
+		return
+	}
+	teams, err := c.leagueTeamNames(ctx, leagueID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get teams: %v", err))
+		return
+	}
+
+	fixtures := make([]FixtureResponse, 0, len(matches))
+	for _, match := range matches {
+		fixtures = append(fixtures, newFixtureResponse(match, teams, nil))
+	}
+	respondWithJSON(w, http.StatusOK, fixtures)
+}
+
+// getFixture returns a fixture with its goal scorers
+func (c *Config) getFixture(w http.ResponseWriter, r *http.Request) {
+	match, ok := c.fixtureFromRequest(w, r)
+	if !ok {
+		return
+	}
+	c.respondWithFixture(w, r.Context(), match)
+}
+
+// updateFixture reschedules, postpones or cancels a fixture that hasn't been played
+func (c *Config) updateFixture(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if _, ok := r.Context().Value("user_id").(int32); !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req UpdateFixtureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Status != nil && !fixtureUpdateStatuses[database.MatchStatus(*req.Status)] {
+		respondWithError(w, http.StatusBadRequest, "status must be scheduled, postponed or cancelled")
+		return
+	}
+
+	match, ok := c.fixtureFromRequest(w, r)
+	if !ok {
+		return
+	}
+	access, ok := c.fixtureAccessForMatch(w, r, match)
+	if !ok {
+		return
+	}
+	if !access.any() {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner or the teams' managers can change this fixture")
+		return
+	}
+	if match.Status == database.MatchStatusFinished {
+		respondWithError(w, http.StatusConflict, "Fixture has already been played")
+		return
+	}
+	if !c.fixtureSeasonOpen(w, ctx, match) {
+		return
+	}
+
+	params := database.UpdateFixtureScheduleParams{
+		ID:        match.ID,
+		MatchDate: match.MatchDate,
+		Venue:     match.Venue,
+		Status:    match.Status,
+	}
+	if req.MatchDate != nil {
+		params.MatchDate = *req.MatchDate
+	}
+	if req.Venue != nil {
+		params.Venue = sql.NullString{String: *req.Venue, Valid: *req.Venue != ""}
+	}
+	if req.Status != nil {
+		params.Status = database.MatchStatus(*req.Status)
+	}
+
+	updated, err := c.DB.UpdateFixtureSchedule(ctx, params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update fixture: %v", err))
+		return
+	}
+	c.respondWithFixture(w, ctx, updated)
+}
+
+// recordFixtureResult enters or corrects a result and its goal scorers. A
+// result from the league's owner is confirmed for both sides. One from a
+// manager only counts as their side's confirmation, so the other manager has
+// to confirm it.
+func (c *Config) recordFixtureResult(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req FixtureResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.HomeScore < 0 || req.AwayScore < 0 {
+		respondWithError(w, http.StatusBadRequest, "Scores can't be negative")
+		return
+	}
+
+	match, ok := c.fixtureFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := validateFixtureGoals(req, match.HomeTeamID.UUID, match.AwayTeamID.UUID); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	access, ok := c.fixtureAccessForMatch(w, r, match)
+	if !ok {
+		return
+	}
+	if !access.any() {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner or the teams' managers can enter this result")
+		return
+	}
+	if match.Status == database.MatchStatusCancelled {
+		respondWithError(w, http.StatusConflict, "Fixture has been cancelled")
+		return
+	}
+	if !c.fixtureSeasonOpen(w, ctx, match) {
+		return
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Results can't be entered right now")
+		return
+	}
+
+	updated, err := c.saveFixtureResult(ctx, match.ID, userID, access, req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save result: %v", err))
+		return
+	}
+	c.respondWithFixture(w, ctx, updated)
+}
+
+func (c *Config) saveFixtureResult(ctx context.Context, matchID uuid.UUID, userID int32, access fixtureAccess, req FixtureResultRequest) (database.Match, error) {
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Match{}, err
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	match, err := q.RecordFixtureResult(ctx, database.RecordFixtureResultParams{
+		HomeScore:     sql.NullInt32{Int32: req.HomeScore, Valid: true},
+		AwayScore:     sql.NullInt32{Int32: req.AwayScore, Valid: true},
+		EnteredBy:     sql.NullInt32{Int32: userID, Valid: true},
+		HomeConfirmed: access.organiser || access.home,
+		AwayConfirmed: access.organiser || access.away,
+		ID:            matchID,
+	})
+	if err != nil {
+		return database.Match{}, err
+	}
+
+	if err := q.DeleteMatchGoals(ctx, matchID); err != nil {
+		return database.Match{}, err
+	}
+	for _, goal := range req.Goals {
+		params := database.CreateMatchGoalParams{
+			MatchID:    matchID,
+			TeamID:     goal.TeamID,
+			ScorerName: sql.NullString{String: goal.ScorerName, Valid: goal.ScorerName != ""},
+			OwnGoal:    goal.OwnGoal,
+			Penalty:    goal.Penalty,
+		}
+		if goal.PlayerProfileID != nil {
+			params.PlayerProfileID = uuid.NullUUID{UUID: *goal.PlayerProfileID, Valid: true}
+		}
+		if goal.Minute != nil {
+			params.Minute = sql.NullInt32{Int32: *goal.Minute, Valid: true}
+		}
+		if err := q.CreateMatchGoal(ctx, params); err != nil {
+			return database.Match{}, err
+		}
+	}
+
+	return match, tx.Commit()
+}
+
+// confirmFixtureResult confirms an entered result for the caller's side
+func (c *Config) confirmFixtureResult(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if _, ok := r.Context().Value("user_id").(int32); !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	match, ok := c.fixtureFromRequest(w, r)
+	if !ok {
+		return
+	}
+	access, ok := c.fixtureAccessForMatch(w, r, match)
+	if !ok {
+		return
+	}
+	if !access.any() {
+		respondWithError(w, http.StatusForbidden, "Only the teams' managers can confirm this result")
+		return
+	}
+	if match.Status != database.MatchStatusFinished {
+		respondWithError(w, http.StatusConflict, "No result has been entered")
+		return
+	}
+	if !c.fixtureSeasonOpen(w, ctx, match) {
+		return
+	}
+
+	updated, err := c.DB.ConfirmFixtureResult(ctx, database.ConfirmFixtureResultParams{
+		Home: access.organiser || access.home,
+		Away: access.organiser || access.away,
+		ID:   match.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to confirm result: %v", err))
+		return
+	}
+	c.respondWithFixture(w, ctx, updated)
+}
+
+// validateFixtureGoals checks each goal belongs to one of the teams and that
+// no more goals are credited to a team than it scored
+func validateFixtureGoals(req FixtureResultRequest, homeTeamID, awayTeamID uuid.UUID) error {
+	var homeGoals, awayGoals int32
+	for _, goal := range req.Goals {
+		switch goal.TeamID {
+		case homeTeamID:
+			homeGoals++
+		case awayTeamID:
+			awayGoals++
+		default:
+			return errors.New("Goals must be credited to one of the fixture's teams")
+		}
+		if goal.Minute != nil && (*goal.Minute < 0 || *goal.Minute > maxGoalMinute) {
+			return fmt.Errorf("Goal minutes must be between 0 and %d", maxGoalMinute)
+		}
+	}
+	if homeGoals > req.HomeScore || awayGoals > req.AwayScore {
+		return errors.New("More goals listed than the score allows")
+	}
+	return nil
+}
+
+// fixtureFromRequest loads the local fixture in the id URL param. It responds
+// with an error and returns false when there isn't one.
+func (c *Config) fixtureFromRequest(w http.ResponseWriter, r *http.Request) (database.Match, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid fixture ID")
+		return database.Match{}, false
+	}
+	match, err := c.DB.GetFixture(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Fixture not found")
+			return database.Match{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get fixture: %v", err))
+		return database.Match{}, false
+	}
+	return match, true
+}
+
+func (c *Config) fixtureLeague(w http.ResponseWriter, ctx context.Context, leagueID uuid.UUID) (database.League, bool) {
+	league, err := c.DB.GetLeague(ctx, leagueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "League not found")
+			return database.League{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get league: %v", err))
+		return database.League{}, false
+	}
+	return league, true
+}
+
+func (c *Config) fixtureAccessForMatch(w http.ResponseWriter, r *http.Request, match database.Match) (fixtureAccess, bool) {
+	league, ok := c.fixtureLeague(w, r.Context(), match.LeagueID.UUID)
+	if !ok {
+		return fixtureAccess{}, false
+	}
+	access, err := c.fixtureAccess(r, league, match.HomeTeamID.UUID, match.AwayTeamID.UUID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+		return fixtureAccess{}, false
+	}
+	return access, true
+}
+
+func (c *Config) fixtureAccess(r *http.Request, league database.League, homeTeamID, awayTeamID uuid.UUID) (fixtureAccess, error) {
+	userID, _ := r.Context().Value("user_id").(int32)
+	if role, _ := r.Context().Value("user_role").(string); role == "admin" || league.OwnerID == userID {
+		return fixtureAccess{organiser: true}, nil
+	}
+
+	var access fixtureAccess
+	var err error
+	access.home, err = c.DB.IsTeamManager(r.Context(), database.IsTeamManagerParams{TeamID: homeTeamID, UserID: userID, Roles: rolesWith(permissionMatchDay)})
+	if err != nil {
+		return fixtureAccess{}, err
+	}
+	access.away, err = c.DB.IsTeamManager(r.Context(), database.IsTeamManagerParams{TeamID: awayTeamID, UserID: userID, Roles: rolesWith(permissionMatchDay)})
+	if err != nil {
+		return fixtureAccess{}, err
+	}
+	return access, nil
+}
+
+// leagueTeamNames maps the IDs of a league's teams to their names
+func (c *Config) leagueTeamNames(ctx context.Context, leagueID uuid.UUID) (map[uuid.UUID]string, error) {
+	teams, err := c.DB.GetTeamsByLeague(ctx, uuid.NullUUID{UUID: leagueID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(teams))
+	for _, team := range teams {
+		names[team.ID] = team.Name
+	}
+	return names, nil
+}
+
+func (c *Config) respondWithFixture(w http.ResponseWriter, ctx context.Context, match database.Match) {
+	goals, err := c.DB.ListMatchGoals(ctx, match.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get goals: %v", err))
+		return
+	}
+	teams, err := c.leagueTeamNames(ctx, match.LeagueID.UUID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get teams: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newFixtureResponse(match, teams, goals))
+}
+
+func newFixtureResponse(match database.Match, teams map[uuid.UUID]string, goals []database.MatchGoal) FixtureResponse {
+	response := FixtureResponse{
+		ID:        match.ID,
+		LeagueID:  match.LeagueID.UUID,
+		HomeTeam:  FixtureTeam{ID: match.HomeTeamID.UUID, Name: teams[match.HomeTeamID.UUID]},
+		AwayTeam:  FixtureTeam{ID: match.AwayTeamID.UUID, Name: teams[match.AwayTeamID.UUID]},
+		MatchDate: match.MatchDate,
+		Venue:     match.Venue.String,
+		Status:    string(match.Status),
+		Round:     match.Round.Int32,
+	}
+	if match.SeasonID.Valid {
+		response.SeasonID = &match.SeasonID.UUID
+	}
+	if match.Status == database.MatchStatusFinished {
+		response.HomeTeam.Score = &match.HomeScore.Int32
+		response.AwayTeam.Score = &match.AwayScore.Int32
+		response.Confirmation = &FixtureConfirmation{
+			HomeConfirmed: match.HomeConfirmedAt.Valid,
+			AwayConfirmed: match.AwayConfirmedAt.Valid,
+			Confirmed:     match.HomeConfirmedAt.Valid && match.AwayConfirmedAt.Valid,
+		}
+	}
+	for _, goal := range goals {
+		data := FixtureGoalData{
+			TeamID:     goal.TeamID,
+			ScorerName: goal.ScorerName.String,
+			OwnGoal:    goal.OwnGoal,
+			Penalty:    goal.Penalty,
+		}
+		if goal.PlayerProfileID.Valid {
+			data.PlayerProfileID = &goal.PlayerProfileID.UUID
+		}
+		if goal.Minute.Valid {
+			data.Minute = &goal.Minute.Int32
+		}
+		response.Goals = append(response.Goals, data)
+	}
+	return response
+}
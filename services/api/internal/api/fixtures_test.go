@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestValidateFixtureGoals(t *testing.T) {
+	home, away := uuid.New(), uuid.New()
+	minute := func(m int32) *int32 { return &m }
+
+	tests := []struct {
+		name    string
+		req     FixtureResultRequest
+		wantErr bool
+	}{
+		{
+			name: "goals match the score",
+			req: FixtureResultRequest{HomeScore: 2, AwayScore: 1, Goals: []FixtureGoalData{
+				{TeamID: home, ScorerName: "Smith", Minute: minute(12)},
+				{TeamID: home, OwnGoal: true},
+				{TeamID: away, ScorerName: "Jones", Minute: minute(90), Penalty: true},
+			}},
+		},
+		{
+			name: "scorers can be left out",
+			req:  FixtureResultRequest{HomeScore: 3, AwayScore: 0, Goals: []FixtureGoalData{{TeamID: home}}},
+		},
+		{
+			name:    "more goals than the score",
+			req:     FixtureResultRequest{HomeScore: 0, AwayScore: 1, Goals: []FixtureGoalData{{TeamID: home}}},
+			wantErr: true,
+		},
+		{
+			name:    "goal for another team",
+			req:     FixtureResultRequest{HomeScore: 1, Goals: []FixtureGoalData{{TeamID: uuid.New()}}},
+			wantErr: true,
+		},
+		{
+			name:    "minute out of range",
+			req:     FixtureResultRequest{HomeScore: 1, Goals: []FixtureGoalData{{TeamID: home, Minute: minute(131)}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFixtureGoals(tt.req, home, away)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFixtureGoals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateFixtureValidation(t *testing.T) {
+	team := uuid.New().String()
+	tests := []struct {
+		name     string
+		leagueID string
+		body     string
+	}{
+		{"invalid league", "abc", `{}`},
+		{"missing teams", uuid.New().String(), `{"match_date":"2025-11-01T10:00:00Z"}`},
+		{"same team", uuid.New().String(), `{"home_team_id":"` + team + `","away_team_id":"` + team + `","match_date":"2025-11-01T10:00:00Z"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			req := authedRequest("POST", "/leagues/"+tt.leagueID+"/fixtures", tt.body)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", tt.leagueID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+			rec := httptest.NewRecorder()
+
+			config.createFixture(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestUpdateFixtureInvalidStatus(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("PUT", "/fixtures/abc", `{"status":"finished"}`)
+	rec := httptest.NewRecorder()
+
+	config.updateFixture(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestRecordFixtureResultNegativeScore(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("PUT", "/fixtures/abc/result", `{"home_score":-1,"away_score":0}`)
+	rec := httptest.NewRecorder()
+
+	config.recordFixtureResult(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestConfirmFixtureResultRequiresAuth(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("POST", "/fixtures/abc/result/confirm", nil)
+	rec := httptest.NewRecorder()
+
+	config.confirmFixtureResult(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
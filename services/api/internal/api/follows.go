@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+var (
+	errNotFollowable   = errors.New("not found")
+	errInvalidFollowID = errors.New("invalid id")
+)
+
+type FollowResponse struct {
+	Following     bool  `json:"following"`
+	FollowerCount int64 `json:"follower_count"`
+}
+
+type FollowedItem struct {
+	Type       string     `json:"type"`               // team, player or match
+	ID         string     `json:"id"`                 // Team or player profile ID, or the match's internal ID
+	MatchID    string     `json:"match_id,omitempty"` // API-Football fixture ID for matches
+	FollowedAt *time.Time `json:"followed_at,omitempty"`
+}
+
+// FollowStats are added to team and player responses
+type FollowStats struct {
+	FollowerCount int64 `json:"follower_count"`
+	Following     bool  `json:"following"` // Whether the caller follows it; false when signed out
+}
+
+func (c *Config) followTeam(w http.ResponseWriter, r *http.Request) {
+	c.setFollow(w, r, database.FollowableTypeTeam, c.teamFollowID, true)
+}
+
+func (c *Config) unfollowTeam(w http.ResponseWriter, r *http.Request) {
+	c.setFollow(w, r, database.FollowableTypeTeam, c.teamFollowID, false)
+}
+
+func (c *Config) followPlayer(w http.ResponseWriter, r *http.Request) {
+	c.setFollow(w, r, database.FollowableTypePlayer, c.playerFollowID, true)
+}
+
+func (c *Config) unfollowPlayer(w http.ResponseWriter, r *http.Request) {
+	c.setFollow(w, r, database.FollowableTypePlayer, c.playerFollowID, false)
+}
+
+func (c *Config) followMatch(w http.ResponseWriter, r *http.Request) {
+	c.setFollow(w, r, database.FollowableTypeMatch, c.matchFollowID, true)
+}
+
+func (c *Config) unfollowMatch(w http.ResponseWriter, r *http.Request) {
+	c.setFollow(w, r, database.FollowableTypeMatch, c.matchFollowID, false)
+}
+
+// setFollow follows or unfollows whatever resolveID finds for the request.
+// Both are idempotent.
+func (c *Config) setFollow(w http.ResponseWriter, r *http.Request, followableType database.FollowableType, resolveID func(r *http.Request, follow bool) (uuid.UUID, error), follow bool) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	id, err := resolveID(r, follow)
+	if err != nil {
+		switch {
+		case errors.Is(err, errNotFollowable):
+			respondWithError(w, http.StatusNotFound, fmt.Sprintf("%s not found", followableType))
+		case errors.Is(err, errInvalidFollowID):
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Invalid %s ID", followableType))
+		default:
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find %s: %v", followableType, err))
+		}
+		return
+	}
+
+	if follow {
+		_, err = c.DB.FollowEntity(ctx, database.FollowEntityParams{UserID: userID, FollowableType: followableType, FollowableID: id})
+	} else {
+		_, err = c.DB.UnfollowEntity(ctx, database.UnfollowEntityParams{UserID: userID, FollowableType: followableType, FollowableID: id})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update follow: %v", err))
+		return
+	}
+
+	stats, err := followStats(ctx, c.DB, followableType, []uuid.UUID{id}, userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to count followers: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, FollowResponse{Following: follow, FollowerCount: stats[id].FollowerCount})
+}
+
+func (c *Config) teamFollowID(r *http.Request, follow bool) (uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, errInvalidFollowID
+	}
+	if _, err := c.DB.GetTeam(r.Context(), id); err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, errNotFollowable
+		}
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (c *Config) playerFollowID(r *http.Request, follow bool) (uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, errInvalidFollowID
+	}
+	if _, err := c.DB.GetPlayerProfile(r.Context(), id); err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, errNotFollowable
+		}
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// matchFollowID maps an API-Football fixture ID to its row in matches, adding
+// the row the first time anyone follows the fixture
+func (c *Config) matchFollowID(r *http.Request, follow bool) (uuid.UUID, error) {
+	ctx := r.Context()
+	matchID := chi.URLParam(r, "id")
+
+	id, err := c.DB.GetMatchIDByExternalID(ctx, matchID)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return uuid.Nil, err
+	}
+	if !follow {
+		return uuid.Nil, errNotFollowable
+	}
+
+	fixture, err := c.fetchFixture(ctx, matchID)
+	if err != nil {
+		if errors.Is(err, errFixtureNotFound) {
+			return uuid.Nil, errNotFollowable
+		}
+		return uuid.Nil, err
+	}
+	return c.DB.UpsertExternalMatch(ctx, database.UpsertExternalMatchParams{
+		ExternalMatchID: matchID,
+		MatchDate:       fixture.Kickoff,
+	})
+}
+
+// listFollowing returns what the user follows, newest first. ?type= filters to
+// team, player or match.
+func (c *Config) listFollowing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var filter database.NullFollowableType
+	if typeStr := r.URL.Query().Get("type"); typeStr != "" {
+		followableType := database.FollowableType(typeStr)
+		switch followableType {
+		case database.FollowableTypeTeam, database.FollowableTypePlayer, database.FollowableTypeMatch:
+			filter = database.NullFollowableType{FollowableType: followableType, Valid: true}
+		default:
+			respondWithError(w, http.StatusBadRequest, "type must be team, player or match")
+			return
+		}
+	}
+
+	rows, err := c.DB.ListUserFollows(r.Context(), database.ListUserFollowsParams{
+		UserID:         userID,
+		FollowableType: filter,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list follows: %v", err))
+		return
+	}
+
+	items := make([]FollowedItem, 0, len(rows))
+	for _, row := range rows {
+		item := FollowedItem{
+			Type:    string(row.FollowableType),
+			ID:      row.FollowableID.String(),
+			MatchID: row.ExternalMatchID.String,
+		}
+		if row.CreatedAt.Valid {
+			item.FollowedAt = &row.CreatedAt.Time
+		}
+		items = append(items, item)
+	}
+
+	respondWithJSON(w, http.StatusOK, items)
+}
+
+// followStats counts followers of each ID and whether userID follows them.
+// Pass a userID of 0 for signed out callers.
+func followStats(ctx context.Context, db *database.Queries, followableType database.FollowableType, ids []uuid.UUID, userID int32) (map[uuid.UUID]FollowStats, error) {
+	stats := make(map[uuid.UUID]FollowStats, len(ids))
+	if len(ids) == 0 {
+		return stats, nil
+	}
+
+	counts, err := db.CountFollowers(ctx, database.CountFollowersParams{
+		FollowableType: followableType,
+		FollowableIds:  ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range counts {
+		stats[row.FollowableID] = FollowStats{FollowerCount: row.Followers}
+	}
+
+	if userID == 0 {
+		return stats, nil
+	}
+	followed, err := db.ListFollowedAmong(ctx, database.ListFollowedAmongParams{
+		UserID:         userID,
+		FollowableType: followableType,
+		FollowableIds:  ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range followed {
+		s := stats[id]
+		s.Following = true
+		stats[id] = s
+	}
+	return stats, nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestFollowTeamInvalidID(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("POST", "/teams/abc/follow", "")
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "abc")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	config.followTeam(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestFollowRequiresAuth(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("POST", "/teams/abc/follow", nil)
+	rec := httptest.NewRecorder()
+
+	config.followPlayer(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestListFollowingInvalidType(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("GET", "/users/following?type=league", "")
+	rec := httptest.NewRecorder()
+
+	config.listFollowing(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
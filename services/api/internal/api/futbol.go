@@ -7,11 +7,13 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
 
 	"github.com/ArronJLinton/fucci-api/internal/cache"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
 )
 
 type GetMatchesParams struct {
@@ -66,17 +68,17 @@ func (c *Config) getMatches(w http.ResponseWriter, r *http.Request) {
 	}
 	// If not in cache or error occurred, fetch from API
 	// Build URL with optional league filter
-	url := fmt.Sprintf("https://api-football-v1.p.rapidapi.com/v3/fixtures?date=%s", date)
+	url := fmt.Sprintf("%s/fixtures?date=%s", c.footballBaseURL(), date)
 	if leagueID != "" {
-		// When filtering by league, RapidAPI requires a season parameter
-		// Extract year from date to determine season
+		// When filtering by league, RapidAPI requires a season parameter,
+		// which is the season the league is in on the date
 		dateTime, err := time.Parse("2006-01-02", date)
 		if err != nil {
 			log.Printf("ERROR: Invalid date format: %s\n", date)
 			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Invalid date format: %s. Expected YYYY-MM-DD", date))
 			return
 		}
-		season := dateTime.Year()
+		season := c.leagueSeasonOn(ctx, leagueID, dateTime)
 		url = fmt.Sprintf("%s&league=%s&season=%d", url, leagueID, season)
 		log.Printf("URL: %s\n", url)
 		log.Printf("Filtering matches by league_id: %s, season: %d\n", leagueID, season)
@@ -119,6 +121,18 @@ func (c *Config) getMatches(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, match := range data.Response {
+		c.trackFixture(ctx, events.MatchStatusChanged{
+			MatchID:   strconv.Itoa(match.Fixture.ID),
+			LeagueID:  int32(match.League.ID),
+			HomeTeam:  match.Teams.Home.Name,
+			AwayTeam:  match.Teams.Away.Name,
+			ToStatus:  match.Fixture.Status.Short,
+			HomeGoals: int32(match.Goals.Home),
+			AwayGoals: int32(match.Goals.Away),
+		})
+	}
+
 	// Determine cache TTL based on match statuses
 	ttl := cache.DefaultTTL
 	if len(data.Response) > 0 {
@@ -423,9 +437,8 @@ func (c *Config) getTeamSquad(id int32, ctx context.Context) (*GetSquadResponse,
 func (c *Config) getLeagues(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Derive current year dynamically
-	currentYear := time.Now().Year()
-	cacheKey := fmt.Sprintf("leagues:%d", currentYear)
+	// Each league's current season, whichever year it started in
+	cacheKey := "leagues:current"
 
 	// Try to get from cache first
 	var data GetLeaguesResponse
@@ -456,7 +469,7 @@ func (c *Config) getLeagues(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Cache get error: %v\n", err)
 	}
 
-	url := "https://api-football-v1.p.rapidapi.com/v3/leagues?season=2025"
+	url := fmt.Sprintf("%s/leagues?current=true", c.footballBaseURL())
 	headers := map[string]string{
 		"Content-Type":   "application/json",
 		"x-rapidapi-key": c.FootballAPIKey,
@@ -508,11 +521,20 @@ func (c *Config) getLeagueStandingsByTeamId(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// TODO: Dynamically set the season year
-	currentYear := time.Now().Year()
+	// Defaults to the current season of the team's league
+	var season int
+	if seasonStr := queryParams.Get("season"); seasonStr != "" {
+		var err error
+		if season, err = strconv.Atoi(seasonStr); err != nil {
+			respondWithError(w, http.StatusBadRequest, "season must be a year")
+			return
+		}
+	} else {
+		season = c.teamCurrentSeason(ctx, teamId)
+	}
 
 	// Generate cache key
-	cacheKey := fmt.Sprintf("team_standings:%s:%d", teamId, currentYear)
+	cacheKey := fmt.Sprintf("team_standings:%s:%d", teamId, season)
 
 	// Try to get from cache first
 	var data GetLeagueStandingsByTeamIdResponse
@@ -528,7 +550,7 @@ func (c *Config) getLeagueStandingsByTeamId(w http.ResponseWriter, r *http.Reque
 		log.Printf("Cache get error: %v\n", err)
 	}
 
-	url := fmt.Sprintf("https://api-football-v1.p.rapidapi.com/v3/standings?season=%d&team=%s", currentYear, teamId)
+	url := fmt.Sprintf("%s/standings?season=%d&team=%s", c.footballBaseURL(), season, teamId)
 	headers := map[string]string{
 		"Content-Type":   "application/json",
 		"x-rapidapi-key": c.FootballAPIKey,
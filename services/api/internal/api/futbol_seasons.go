@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/cache"
+)
+
+// API-Football numbers seasons by the year they start, so a league running
+// August to May is still in its 2025 season in March 2026. These resolve the
+// season from a league's coverage data instead of the calendar.
+
+// leagueSeasonOn is leagueID's season on date, or date's year when
+// API-Football can't say
+func (c *Config) leagueSeasonOn(ctx context.Context, leagueID string, date time.Time) int {
+	seasons, err := c.leagueSeasons(ctx, leagueID)
+	if err != nil {
+		log.Printf("Failed to get seasons for league %s: %v\n", leagueID, err)
+		return date.Year()
+	}
+	if season, ok := seasonOn(seasons, date); ok {
+		return season
+	}
+	return date.Year()
+}
+
+// teamCurrentSeason is the current season of the league teamID plays in, or
+// this year when API-Football can't say
+func (c *Config) teamCurrentSeason(ctx context.Context, teamID string) int {
+	cacheKey := fmt.Sprintf("team_season:%s", teamID)
+
+	var season int
+	exists, err := c.Cache.Exists(ctx, cacheKey)
+	if err != nil {
+		log.Printf("Cache check error: %v\n", err)
+	} else if exists {
+		err = c.Cache.Get(ctx, cacheKey, &season)
+		if err == nil {
+			return season
+		}
+		log.Printf("Cache get error: %v\n", err)
+	}
+
+	response, err := handleClientRequest[GetLeaguesResponse](fmt.Sprintf("%s/leagues?team=%s&current=true", c.footballBaseURL(), teamID), "GET", c.footballHeaders())
+	if err != nil {
+		log.Printf("Failed to get leagues for team %s: %v\n", teamID, err)
+		return time.Now().Year()
+	}
+	season, ok := currentLeagueSeason(response)
+	if !ok {
+		return time.Now().Year()
+	}
+
+	if err := c.Cache.Set(ctx, cacheKey, season, cache.TeamInfoTTL); err != nil {
+		log.Printf("Cache set error: %v\n", err)
+	}
+	return season
+}
+
+// currentLeagueSeason is the current season among a team's competitions.
+// Cups often run on a different calendar, so a league's season wins.
+func currentLeagueSeason(data *GetLeaguesResponse) (int, bool) {
+	season, found := 0, false
+	for _, l := range data.Response {
+		for _, s := range l.Seasons {
+			if !s.Current {
+				continue
+			}
+			if l.League.Type == "League" {
+				return s.Year, true
+			}
+			if !found {
+				season, found = s.Year, true
+			}
+		}
+	}
+	return season, found
+}
+
+// leagueSeasons fetches a league's seasons, cached for a day
+func (c *Config) leagueSeasons(ctx context.Context, leagueID string) ([]LeagueSeason, error) {
+	cacheKey := fmt.Sprintf("league_seasons:%s", leagueID)
+
+	var seasons []LeagueSeason
+	exists, err := c.Cache.Exists(ctx, cacheKey)
+	if err != nil {
+		log.Printf("Cache check error: %v\n", err)
+	} else if exists {
+		err = c.Cache.Get(ctx, cacheKey, &seasons)
+		if err == nil {
+			return seasons, nil
+		}
+		log.Printf("Cache get error: %v\n", err)
+	}
+
+	response, err := handleClientRequest[GetLeaguesResponse](fmt.Sprintf("%s/leagues?id=%s", c.footballBaseURL(), leagueID), "GET", c.footballHeaders())
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range response.Response {
+		seasons = append(seasons, l.Seasons...)
+	}
+	if len(seasons) == 0 {
+		return nil, fmt.Errorf("no seasons for league %s", leagueID)
+	}
+
+	if err := c.Cache.Set(ctx, cacheKey, seasons, cache.TeamInfoTTL); err != nil {
+		log.Printf("Cache set error: %v\n", err)
+	}
+	return seasons, nil
+}
+
+// seasonOn is the season whose dates include date, otherwise the one
+// API-Football marks as current
+func seasonOn(seasons []LeagueSeason, date time.Time) (int, bool) {
+	day := date.Format(time.DateOnly)
+	for _, s := range seasons {
+		// Dates are YYYY-MM-DD, so they compare as strings
+		if s.Start != "" && s.End != "" && s.Start <= day && day <= s.End {
+			return s.Year, true
+		}
+	}
+	for _, s := range seasons {
+		if s.Current {
+			return s.Year, true
+		}
+	}
+	return 0, false
+}
+
+// footballBaseURL is the configured API-Football URL, RapidAPI's by default
+func (c *Config) footballBaseURL() string {
+	if c.APIFootballBaseURL == "" {
+		return "https://api-football-v1.p.rapidapi.com/v3"
+	}
+	return c.APIFootballBaseURL
+}
+
+func (c *Config) footballHeaders() map[string]string {
+	return map[string]string{
+		"Content-Type":   "application/json",
+		"x-rapidapi-key": c.FootballAPIKey,
+	}
+}
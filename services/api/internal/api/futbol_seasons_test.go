@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSeasonOn(t *testing.T) {
+	seasons := []LeagueSeason{
+		{Year: 2024, Start: "2024-08-16", End: "2025-05-25"},
+		{Year: 2025, Start: "2025-08-15", End: "2026-05-24", Current: true},
+	}
+
+	tests := []struct {
+		date   string
+		want   int
+		wantOK bool
+	}{
+		{"2026-03-14", 2025, true}, // Spring belongs to the season that started last year
+		{"2025-05-25", 2024, true},
+		{"2025-07-01", 2025, true}, // Between seasons falls back to the current one
+	}
+
+	for _, tt := range tests {
+		date, _ := time.Parse(time.DateOnly, tt.date)
+		got, ok := seasonOn(seasons, date)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("seasonOn(%s) = %d, %v, want %d, %v", tt.date, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if _, ok := seasonOn([]LeagueSeason{{Year: 2020, Start: "2020-01-01", End: "2020-12-31"}}, time.Now()); ok {
+		t.Error("Expected no season without a match or a current one")
+	}
+}
+
+func TestCurrentLeagueSeason(t *testing.T) {
+	var data GetLeaguesResponse
+	err := json.Unmarshal([]byte(`{"response": [
+		{"league": {"id": 45, "type": "Cup"}, "seasons": [{"year": 2026, "current": true}]},
+		{"league": {"id": 39, "type": "League"}, "seasons": [{"year": 2024}, {"year": 2025, "current": true}]}
+	]}`), &data)
+	if err != nil {
+		t.Fatalf("Failed to decode leagues: %v", err)
+	}
+
+	if season, ok := currentLeagueSeason(&data); !ok || season != 2025 {
+		t.Errorf("Expected the league's 2025 season, got %d, %v", season, ok)
+	}
+
+	data.Response = data.Response[:1]
+	if season, ok := currentLeagueSeason(&data); !ok || season != 2026 {
+		t.Errorf("Expected the cup's 2026 season without a league, got %d, %v", season, ok)
+	}
+}
@@ -794,7 +794,7 @@ func TestLeaguesCache(t *testing.T) {
 		}
 
 		// Verify cache key exists
-		exists, err := cache.Exists(context.Background(), "leagues:2025")
+		exists, err := cache.Exists(context.Background(), "leagues:current")
 		if err != nil || !exists {
 			t.Error("Leagues cache key should exist")
 		}
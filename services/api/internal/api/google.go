@@ -1,32 +1,36 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"net/url"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/ArronJLinton/fucci-api/internal/cache"
+	"github.com/ArronJLinton/fucci-api/internal/database"
 )
 
 type GoogleNewsResponse struct {
-	Status string `json:"status"`
-	Items  []struct {
-		Timestamp string `json:"timestamp"`
-		Title     string `json:"title"`
-		Snippet   string `json:"snippet"`
-		Images    struct {
-			Thumbnail        string `json:"thumbnail"`
-			ThumbnailProxied string `json:"thumbnailProxied"`
-		} `json:"images"`
-		NewsUrl   string `json:"newsUrl"`
-		Publisher string `json:"publisher"`
-	} `json:"items"`
+	Status string           `json:"status"`
+	Items  []GoogleNewsItem `json:"items"`
 }
 
+type GoogleNewsItem struct {
+	Timestamp string `json:"timestamp"`
+	Title     string `json:"title"`
+	Snippet   string `json:"snippet"`
+	Images    struct {
+		Thumbnail        string `json:"thumbnail"`
+		ThumbnailProxied string `json:"thumbnailProxied"`
+	} `json:"images"`
+	NewsUrl   string `json:"newsUrl"`
+	Publisher string `json:"publisher"`
+}
+
+// newsSearchLimit caps the number of articles returned by the news search
+const newsSearchLimit = 20
+
 func (c *Config) search(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -43,7 +47,8 @@ func (c *Config) search(w http.ResponseWriter, r *http.Request) {
 		language = "en-US"
 	}
 
-	// Generate cache key
+	// Generate cache key (articles are not stored per language; the key keeps the
+	// existing format so cached entries and clients stay compatible)
 	cacheKey := fmt.Sprintf("google_news:%s:%s", query, language)
 
 	// Try to get from cache first
@@ -60,55 +65,23 @@ func (c *Config) search(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Cache get error: %v\n", err)
 	}
 
-	// Construct the URL
-	baseURL := "https://google-news13.p.rapidapi.com/search"
-	params := url.Values{}
-	params.Add("keyword", query)
-	params.Add("lr", language)
-
-	// Create HTTP request
-	req, err := http.NewRequest("GET", baseURL+"?"+params.Encode(), nil)
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to create request")
+	if c.DB == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "News search is not available")
 		return
 	}
 
-	// Add RapidAPI headers
-	req.Header.Add("x-rapidapi-key", c.RapidAPIKey)
-	req.Header.Add("x-rapidapi-host", "google-news13.p.rapidapi.com")
-
-	// Make the request
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	// Search the locally ingested articles
+	articles, err := c.DB.SearchNewsArticles(ctx, database.SearchNewsArticlesParams{
+		Pattern:    containsPattern(query),
+		MaxResults: newsSearchLimit,
+	})
 	if err != nil {
-		log.Printf("Error making request: %v", err)
+		log.Printf("Error searching news: %v", err)
 		respondWithError(w, http.StatusInternalServerError, "Failed to fetch news")
 		return
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to read response")
-		return
-	}
-
-	// Check if response is successful
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API returned status %d: %s", resp.StatusCode, string(body))
-		respondWithError(w, resp.StatusCode, fmt.Sprintf("News API error: %s", string(body)))
-		return
-	}
-
-	// Parse JSON response
-	if err := json.Unmarshal(body, &newsResponse); err != nil {
-		log.Printf("Error parsing JSON: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to parse response")
-		return
-	}
+	newsResponse = toGoogleNewsResponse(articles)
 
 	// Cache the response for 30 minutes (news data changes frequently)
 	err = c.Cache.Set(ctx, cacheKey, newsResponse, cache.NewsTTL)
@@ -119,3 +92,28 @@ func (c *Config) search(w http.ResponseWriter, r *http.Request) {
 	// Return the response
 	respondWithJSON(w, http.StatusOK, newsResponse)
 }
+
+// toGoogleNewsResponse maps stored articles onto the response shape the clients already consume
+func toGoogleNewsResponse(articles []database.NewsArticle) GoogleNewsResponse {
+	response := GoogleNewsResponse{Status: "success"}
+	for _, article := range articles {
+		var item GoogleNewsItem
+		item.Timestamp = strconv.FormatInt(article.PublishedAt.UnixMilli(), 10)
+		item.Title = article.Title
+		item.Snippet = article.Summary.String
+		item.Images.Thumbnail = article.ImageUrl.String
+		item.NewsUrl = article.Url
+		item.Publisher = article.Publisher.String
+		response.Items = append(response.Items, item)
+	}
+	return response
+}
+
+// likeEscaper escapes the characters ILIKE treats specially, so searching
+// for "100%" or "_" matches them literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern is an ILIKE pattern matching text that contains s
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
@@ -125,3 +125,17 @@ func TestGoogleNewsCacheErrorHandling(t *testing.T) {
 		t.Log("Function handled cache errors without panicking")
 	})
 }
+
+func TestContainsPattern(t *testing.T) {
+	tests := map[string]string{
+		"Arsenal":    "%Arsenal%",
+		"100%":       `%100\%%`,
+		"man_utd":    `%man\_utd%`,
+		`back\slash`: `%back\\slash%`,
+	}
+	for input, want := range tests {
+		if got := containsPattern(input); got != want {
+			t.Errorf("containsPattern(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
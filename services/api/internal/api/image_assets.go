@@ -0,0 +1,358 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/media"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Owners of uploaded images, stored in image_assets.owner_type
+const (
+	assetUserAvatar = "user_avatar"
+	assetTeamLogo   = "team_logo"
+	assetLeagueLogo = "league_logo"
+)
+
+const (
+	// assetGracePeriod keeps replaced files around for clients still showing the old URL
+	assetGracePeriod = 24 * time.Hour
+	assetGCBatchSize = 100
+)
+
+// assetSizes are the sizes every avatar and logo is stored at, largest first.
+// The owner's URL points at the largest. Images are never scaled up.
+var assetSizes = []int{512, 128}
+
+type imageAssetKind struct {
+	prefix string // Blob key prefix
+	square bool   // Avatars are cropped square; logos keep their shape
+	png    bool   // Logos are stored as PNG to keep transparency
+}
+
+var imageAssetKinds = map[string]imageAssetKind{
+	assetUserAvatar: {prefix: "avatars/users", square: true},
+	assetTeamLogo:   {prefix: "logos/teams", png: true},
+	assetLeagueLogo: {prefix: "logos/leagues", png: true},
+}
+
+type ImageAssetResponse struct {
+	URL   string            `json:"url"`
+	Sizes map[string]string `json:"sizes"` // URL of each size, keyed by its longest side in pixels
+}
+
+// uploadAvatar replaces the caller's avatar
+func (c *Config) uploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	c.uploadImageAsset(w, r, assetUserAvatar, strconv.Itoa(int(userID)), func(ctx context.Context, q *database.Queries, url sql.NullString) error {
+		return q.UpdateUserAvatar(ctx, database.UpdateUserAvatarParams{ID: userID, AvatarUrl: url})
+	})
+}
+
+// uploadTeamLogo replaces a team's logo. Admins, the team's managers and the
+// owner of its league can change it.
+func (c *Config) uploadTeamLogo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	teamID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	if _, err := c.DB.GetTeam(ctx, teamID); err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Team not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+		return
+	}
+
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" {
+		canManage, err := c.DB.CanManageTeam(ctx, database.CanManageTeamParams{TeamID: teamID, UserID: userID, Roles: rolesWith(permissionProfile)})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+			return
+		}
+		if !canManage {
+			respondWithError(w, http.StatusForbidden, "Only the team's managers can change its logo")
+			return
+		}
+	}
+
+	c.uploadImageAsset(w, r, assetTeamLogo, teamID.String(), func(ctx context.Context, q *database.Queries, url sql.NullString) error {
+		return q.UpdateTeamLogo(ctx, database.UpdateTeamLogoParams{ID: teamID, LogoUrl: url})
+	})
+}
+
+// uploadLeagueLogo replaces a league's logo. Admins and the league's owner can change it.
+func (c *Config) uploadLeagueLogo(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+	league, err := c.DB.GetLeague(r.Context(), leagueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "League not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get league: %v", err))
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can change its logo")
+		return
+	}
+
+	c.uploadImageAsset(w, r, assetLeagueLogo, leagueID.String(), func(ctx context.Context, q *database.Queries, url sql.NullString) error {
+		return q.UpdateLeagueLogo(ctx, database.UpdateLeagueLogoParams{ID: leagueID, LogoUrl: url})
+	})
+}
+
+// uploadImageAsset stores an uploaded image at the standard sizes, then
+// points the owner at it with setURL and marks its previous upload for
+// garbage collection, all in one transaction
+func (c *Config) uploadImageAsset(w http.ResponseWriter, r *http.Request, ownerType, ownerID string, setURL func(context.Context, *database.Queries, sql.NullString) error) {
+	ctx := r.Context()
+	kind := imageAssetKinds[ownerType]
+
+	if c.Blobs == nil || c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Image uploads are not available")
+		return
+	}
+
+	img, ok := readImageUpload(w, r)
+	if !ok {
+		return
+	}
+
+	variants, err := imageVariants(img, kind)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to resize image: %v", err))
+		return
+	}
+
+	extension, contentType := ".jpg", "image/jpeg"
+	if kind.png {
+		extension, contentType = ".png", "image/png"
+	}
+	assetID := uuid.New()
+	response := ImageAssetResponse{Sizes: make(map[string]string, len(variants))}
+	keys := make([]string, 0, len(variants))
+	for i, variant := range variants {
+		key := fmt.Sprintf("%s/%s/%s_%d%s", kind.prefix, ownerID, assetID, assetSizes[i], extension)
+		if err := c.Blobs.Put(ctx, key, bytes.NewReader(variant), int64(len(variant)), contentType); err != nil {
+			c.deleteBlobs(ctx, keys)
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to store image: %v", err))
+			return
+		}
+		keys = append(keys, key)
+		response.Sizes[strconv.Itoa(assetSizes[i])] = c.Blobs.URL(key)
+	}
+	response.URL = c.Blobs.URL(keys[0])
+
+	if err := c.saveImageAsset(ctx, ownerType, ownerID, response.URL, keys, setURL); err != nil {
+		c.deleteBlobs(ctx, keys)
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save image: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func (c *Config) saveImageAsset(ctx context.Context, ownerType, ownerID, url string, keys []string, setURL func(context.Context, *database.Queries, sql.NullString) error) error {
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	if _, err := q.ReplaceImageAssets(ctx, database.ReplaceImageAssetsParams{OwnerType: ownerType, OwnerID: ownerID}); err != nil {
+		return err
+	}
+	if _, err := q.CreateImageAsset(ctx, database.CreateImageAssetParams{
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		Url:         url,
+		StorageKeys: keys,
+	}); err != nil {
+		return err
+	}
+	if err := setURL(ctx, q, sql.NullString{String: url, Valid: true}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// readImageUpload decodes the image in the "file" field. It responds with an
+// error and returns false when it isn't a usable image.
+func readImageUpload(w http.ResponseWriter, r *http.Request) (image.Image, bool) {
+	file, _, ok := formFile(w, r, media.MaxImageSize)
+	if !ok {
+		return nil, false
+	}
+	defer r.MultipartForm.RemoveAll()
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, media.MaxImageSize+1))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to read upload")
+		return nil, false
+	}
+	if int64(len(data)) > media.MaxImageSize {
+		respondWithError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Images are limited to %d MB", media.MaxImageSize>>20))
+		return nil, false
+	}
+
+	contentType, mediaType, _, err := media.Detect(data)
+	if err != nil || mediaType != media.TypeImage {
+		respondWithError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Unsupported image type %s; upload a JPEG, PNG or GIF", contentType))
+		return nil, false
+	}
+
+	// Decoding and re-encoding drops EXIF data, including any location
+	img, err := media.DecodeUpload(data)
+	if err != nil {
+		if errors.Is(err, media.ErrImageTooLarge) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Image dimensions are too large")
+			return nil, false
+		}
+		respondWithError(w, http.StatusBadRequest, "Invalid image")
+		return nil, false
+	}
+	return img, true
+}
+
+// imageVariants encodes the image at each of assetSizes
+func imageVariants(img image.Image, kind imageAssetKind) ([][]byte, error) {
+	variants := make([][]byte, 0, len(assetSizes))
+	for _, size := range assetSizes {
+		var resized image.Image
+		if kind.square {
+			resized = media.Fill(img, size)
+		} else {
+			resized = media.Resize(img, size)
+		}
+
+		var encoded []byte
+		var err error
+		if kind.png {
+			encoded, err = media.EncodePNG(resized)
+		} else {
+			encoded, err = media.EncodeJPEG(resized)
+		}
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, encoded)
+	}
+	return variants, nil
+}
+
+// deleteBlobs removes files, logging failures. It reports whether they're all gone.
+func (c *Config) deleteBlobs(ctx context.Context, keys []string) bool {
+	deleted := true
+	for _, key := range keys {
+		if err := c.Blobs.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete %s: %v\n", key, err)
+			deleted = false
+		}
+	}
+	return deleted
+}
+
+// ImageAssetCollector deletes the files of replaced avatars and logos
+type ImageAssetCollector struct {
+	config *Config
+}
+
+func NewImageAssetCollector(c Config) *ImageAssetCollector {
+	return &ImageAssetCollector{config: &c}
+}
+
+// Run collects replaced assets every interval until the context is cancelled
+func (ac *ImageAssetCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ac.CollectOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CollectOnce deletes assets replaced more than a day ago, including ones
+// whose owner was deleted or whose URL was changed by hand
+func (ac *ImageAssetCollector) CollectOnce(ctx context.Context) {
+	c := ac.config
+	if c.Blobs == nil {
+		return
+	}
+
+	if _, err := c.DB.MarkDetachedImageAssets(ctx); err != nil {
+		log.Printf("Failed to find detached image assets: %v\n", err)
+		return
+	}
+
+	assets, err := c.DB.ListReplacedImageAssets(ctx, database.ListReplacedImageAssetsParams{
+		ReplacedBefore: time.Now().Add(-assetGracePeriod),
+		MaxResults:     assetGCBatchSize,
+	})
+	if err != nil {
+		log.Printf("Failed to list replaced image assets: %v\n", err)
+		return
+	}
+
+	collected := 0
+	for _, asset := range assets {
+		// Keep the row until every file is gone so failures are retried
+		if !c.deleteBlobs(ctx, asset.StorageKeys) {
+			continue
+		}
+		if err := c.DB.DeleteImageAsset(ctx, asset.ID); err != nil {
+			log.Printf("Failed to delete image asset %d: %v\n", asset.ID, err)
+			continue
+		}
+		collected++
+	}
+	if collected > 0 {
+		log.Printf("Garbage-collected %d replaced image assets\n", collected)
+	}
+}
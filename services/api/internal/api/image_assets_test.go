@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestImageVariants(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 600))
+
+	avatars, err := imageVariants(img, imageAssetKinds[assetUserAvatar])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, variant := range avatars {
+		config, format, err := image.DecodeConfig(bytes.NewReader(variant))
+		if err != nil || format != "jpeg" || config.Width != assetSizes[i] || config.Height != assetSizes[i] {
+			t.Errorf("avatar %d is a %dx%d %s (%v), want a square %d JPEG", i, config.Width, config.Height, format, err, assetSizes[i])
+		}
+	}
+
+	// Logos keep their shape and transparency
+	logos, err := imageVariants(img, imageAssetKinds[assetTeamLogo])
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(logos[0]))
+	if err != nil || format != "png" || config.Width != 512 || config.Height != 307 {
+		t.Errorf("logo is a %dx%d %s (%v), want a 512x307 PNG", config.Width, config.Height, format, err)
+	}
+}
+
+func TestReadImageUpload(t *testing.T) {
+	var encoded bytes.Buffer
+	png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 20, 10)))
+
+	rec := httptest.NewRecorder()
+	img, ok := readImageUpload(rec, mediaUpload(t, encoded.Bytes()))
+	if !ok || img.Bounds().Dx() != 20 {
+		t.Fatalf("expected the PNG to decode, got status %d", rec.Code)
+	}
+
+	// GIF89a sniffs as an image but isn't a valid one
+	for content, want := range map[string]int{
+		"GIF89a":                   http.StatusBadRequest,
+		"<html></html>":            http.StatusUnsupportedMediaType,
+		"\x00\x00\x00\x18ftypmp42": http.StatusUnsupportedMediaType,
+	} {
+		rec := httptest.NewRecorder()
+		if _, ok := readImageUpload(rec, mediaUpload(t, []byte(content))); ok || rec.Code != want {
+			t.Errorf("upload %q: got status %d, want %d", content, rec.Code, want)
+		}
+	}
+}
+
+func TestUploadTeamLogoInvalidID(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("PUT", "/teams/abc/logo", "")
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "abc")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	config.uploadTeamLogo(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestUploadAvatarRequiresBlobStore(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.uploadAvatar(rec, authedRequest("PUT", "/users/avatar", ""))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+}
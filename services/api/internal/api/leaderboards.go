@@ -0,0 +1,596 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/achievements"
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/leaderboard"
+	"github.com/google/uuid"
+)
+
+// Point sources recorded in point_awards
+const (
+	pointSourcePrediction      = "prediction"
+	pointSourceScorePrediction = "score_prediction"
+	pointSourceVote            = "vote"
+	pointSourceComment         = "comment"
+)
+
+const (
+	votePoints    = 1
+	commentPoints = 2
+	// dailyEngagementCap limits how many points a fan can earn per UTC day from
+	// votes and comments, so predictions stay the main way up the table
+	dailyEngagementCap = 20
+
+	leaderboardDefaultLimit = 25
+	leaderboardMaxLimit     = 100
+)
+
+type LeaderboardEntryResponse struct {
+	Rank        int64  `json:"rank"`
+	UserID      int32  `json:"user_id"`
+	Points      int64  `json:"points"`
+	Firstname   string `json:"firstname,omitempty"`
+	Lastname    string `json:"lastname,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}
+
+type LeaderboardResponse struct {
+	Scope      string                     `json:"scope"`
+	ScopeID    string                     `json:"scope_id,omitempty"`
+	Period     string                     `json:"period"`
+	Week       string                     `json:"week,omitempty"`
+	Total      int64                      `json:"total"`
+	Entries    []LeaderboardEntryResponse `json:"entries"`
+	NextOffset *int64                     `json:"next_offset,omitempty"`
+}
+
+type LeaderboardRankResponse struct {
+	Scope   string `json:"scope"`
+	ScopeID string `json:"scope_id,omitempty"`
+	Period  string `json:"period"`
+	Week    string `json:"week,omitempty"`
+	Total   int64  `json:"total"`
+	Rank    *int64 `json:"rank"` // Null until the user has points on this board
+	Points  int64  `json:"points"`
+}
+
+type RecalculateLeaderboardsRequest struct {
+	MatchID string `json:"match_id,omitempty"` // Re-score and re-award predictions for this fixture
+	Week    string `json:"week,omitempty"`     // Weekly boards to rebuild, defaults to the current week
+}
+
+// awardPoints records an award in the ledger and adds it to every board it counts
+// towards. It returns false when the award was already recorded, which is what
+// makes re-running a matchday safe.
+func (c *Config) awardPoints(ctx context.Context, award database.CreatePointAwardParams) (bool, error) {
+	created, err := createPointAward(ctx, c.DB, award)
+	if err != nil || created == nil {
+		return false, err
+	}
+	c.addToLeaderboards(ctx, *created)
+	return true, nil
+}
+
+// createPointAward records the award along with the teams the user follows now,
+// which decides the team boards it counts towards. It returns nil when the
+// award was already recorded.
+func createPointAward(ctx context.Context, db *database.Queries, award database.CreatePointAwardParams) (*database.PointAward, error) {
+	teamIDs, err := db.ListFollowedTeamIDs(ctx, award.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching followed teams: %w", err)
+	}
+	award.TeamIds = append([]uuid.UUID{}, teamIDs...)
+
+	created, err := db.CreatePointAward(ctx, award)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &created, nil
+}
+
+// addToLeaderboards adds a recorded award to the boards that have already been built
+func (c *Config) addToLeaderboards(ctx context.Context, award database.PointAward) {
+	if c.Leaderboards == nil {
+		return
+	}
+
+	var leagueID string
+	if award.LeagueID.Valid {
+		leagueID = strconv.Itoa(int(award.LeagueID.Int32))
+	}
+	teams := make([]string, len(award.TeamIds))
+	for i, teamID := range award.TeamIds {
+		teams[i] = teamID.String()
+	}
+
+	for _, board := range leaderboard.BoardsFor(award.AwardedAt, leagueID, teams) {
+		// A board that hasn't been built yet is rebuilt from Postgres on first
+		// read, so incrementing it now would leave it partial
+		exists, err := c.Leaderboards.Exists(ctx, board)
+		if err != nil || !exists {
+			continue
+		}
+		if err := c.Leaderboards.Incr(ctx, board, award.UserID, int64(award.Points)); err != nil {
+			fmt.Printf("Failed to update leaderboard %s: %v\n", board.Key(), err)
+		}
+	}
+}
+
+// awardEngagementPoints awards points for a vote or comment, up to the daily cap
+func (c *Config) awardEngagementPoints(ctx context.Context, userID int32, sourceType string, sourceID int32, points int32, debateID int32) error {
+	if c.DBConn == nil {
+		return fmt.Errorf("database connection not available")
+	}
+
+	var leagueID sql.NullInt32
+	if debate, err := c.DB.GetDebate(ctx, debateID); err == nil {
+		leagueID = debate.LeagueID
+	}
+
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := c.DB.WithTx(tx)
+
+	// Votes and comments by the same user are capped one at a time, so
+	// concurrent ones can't each see room under the cap
+	if err := qtx.LockUserPoints(ctx, userID); err != nil {
+		return fmt.Errorf("error locking engagement points: %w", err)
+	}
+
+	startOfDay := time.Now().UTC().Truncate(24 * time.Hour)
+	earned, err := qtx.GetEngagementPointsSince(ctx, database.GetEngagementPointsSinceParams{
+		UserID: userID,
+		Since:  startOfDay,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching engagement points: %w", err)
+	}
+	points = int32(min(int64(points), dailyEngagementCap-earned))
+	if points <= 0 {
+		return nil
+	}
+
+	created, err := createPointAward(ctx, qtx, database.CreatePointAwardParams{
+		UserID:     userID,
+		SourceType: sourceType,
+		SourceID:   strconv.Itoa(int(sourceID)),
+		Points:     points,
+		LeagueID:   leagueID,
+	})
+	if err != nil {
+		return fmt.Errorf("error awarding %s points: %w", sourceType, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if created != nil {
+		c.addToLeaderboards(ctx, *created)
+	}
+	return nil
+}
+
+// awardPredictionCredits copies a resolved debate's prediction credits into the
+// points ledger. It is safe to call again for the same debate.
+func (c *Config) awardPredictionCredits(ctx context.Context, debate database.Debate) (int, error) {
+	credits, err := c.DB.ListPredictionCredits(ctx, debate.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	awarded := 0
+	for _, credit := range credits {
+		created, err := c.awardPoints(ctx, database.CreatePointAwardParams{
+			UserID:     credit.UserID,
+			SourceType: pointSourcePrediction,
+			SourceID:   strconv.Itoa(int(debate.ID)),
+			Points:     credit.Points,
+			LeagueID:   debate.LeagueID,
+		})
+		if err != nil {
+			return awarded, err
+		}
+		if created {
+			awarded++
+			c.recordAchievementEvent(ctx, achievements.EventPredictionRewarded, credit.UserID)
+		}
+	}
+	return awarded, nil
+}
+
+// parseLeaderboard reads the board from the scope, id, period and week query parameters
+func parseLeaderboard(r *http.Request, now time.Time) (leaderboard.Board, error) {
+	query := r.URL.Query()
+	board := leaderboard.Board{
+		Scope:   leaderboard.Scope(query.Get("scope")),
+		ScopeID: query.Get("id"),
+		Period:  leaderboard.Period(query.Get("period")),
+		Week:    query.Get("week"),
+	}
+	if board.Scope == "" {
+		board.Scope = leaderboard.ScopeGlobal
+	}
+	if board.Period == "" {
+		board.Period = leaderboard.PeriodAllTime
+	}
+	if board.Period == leaderboard.PeriodWeekly && board.Week == "" {
+		board.Week = leaderboard.Week(now)
+	}
+	if board.Period != leaderboard.PeriodWeekly {
+		board.Week = ""
+	}
+
+	if err := board.Validate(); err != nil {
+		return board, err
+	}
+	switch board.Scope {
+	case leaderboard.ScopeLeague:
+		if _, err := strconv.ParseInt(board.ScopeID, 10, 32); err != nil {
+			return board, fmt.Errorf("id must be a numeric league ID")
+		}
+	case leaderboard.ScopeTeam:
+		if _, err := uuid.Parse(board.ScopeID); err != nil {
+			return board, fmt.Errorf("id must be a team UUID")
+		}
+	}
+	return board, nil
+}
+
+// boardTotals sums every user's points on the board from the ledger
+func (c *Config) boardTotals(ctx context.Context, board leaderboard.Board) (map[int32]int64, error) {
+	since, until, err := board.Window()
+	if err != nil {
+		return nil, err
+	}
+
+	params := database.SumPointsByUserParams{
+		Since: sql.NullTime{Time: since, Valid: !since.IsZero()},
+		Until: sql.NullTime{Time: until, Valid: !until.IsZero()},
+	}
+	switch board.Scope {
+	case leaderboard.ScopeLeague:
+		leagueID, err := strconv.ParseInt(board.ScopeID, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		params.LeagueID = sql.NullInt32{Int32: int32(leagueID), Valid: true}
+	case leaderboard.ScopeTeam:
+		teamID, err := uuid.Parse(board.ScopeID)
+		if err != nil {
+			return nil, err
+		}
+		params.TeamID = uuid.NullUUID{UUID: teamID, Valid: true}
+	}
+
+	rows, err := c.DB.SumPointsByUser(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[int32]int64, len(rows))
+	for _, row := range rows {
+		totals[row.UserID] = row.Points
+	}
+	return totals, nil
+}
+
+// rebuildLeaderboard replaces the board in the store with the ledger's totals
+func (c *Config) rebuildLeaderboard(ctx context.Context, board leaderboard.Board) error {
+	totals, err := c.boardTotals(ctx, board)
+	if err != nil {
+		return err
+	}
+	return c.Leaderboards.Replace(ctx, board, totals)
+}
+
+// ensureLeaderboard builds the board from Postgres if it isn't in the store yet
+func (c *Config) ensureLeaderboard(ctx context.Context, board leaderboard.Board) error {
+	exists, err := c.Leaderboards.Exists(ctx, board)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return c.rebuildLeaderboard(ctx, board)
+}
+
+// rankTotals orders users by points, highest first. Ties are broken by user ID
+// so that pages are stable.
+func rankTotals(totals map[int32]int64) []leaderboard.Entry {
+	entries := make([]leaderboard.Entry, 0, len(totals))
+	for userID, points := range totals {
+		entries = append(entries, leaderboard.Entry{UserID: userID, Points: points})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Points != entries[j].Points {
+			return entries[i].Points > entries[j].Points
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+	for i := range entries {
+		entries[i].Rank = int64(i) + 1
+	}
+	return entries
+}
+
+// leaderboardPage returns a page of the board and the number of users on it.
+// Without a store the board is ranked straight from Postgres.
+func (c *Config) leaderboardPage(ctx context.Context, board leaderboard.Board, offset, limit int64) ([]leaderboard.Entry, int64, error) {
+	if c.Leaderboards != nil {
+		if err := c.ensureLeaderboard(ctx, board); err != nil {
+			return nil, 0, err
+		}
+		total, err := c.Leaderboards.Count(ctx, board)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries, err := c.Leaderboards.Page(ctx, board, offset, limit)
+		return entries, total, err
+	}
+
+	totals, err := c.boardTotals(ctx, board)
+	if err != nil {
+		return nil, 0, err
+	}
+	ranked := rankTotals(totals)
+	total := int64(len(ranked))
+	if offset >= total {
+		return nil, total, nil
+	}
+	return ranked[offset:min(offset+limit, total)], total, nil
+}
+
+// leaderboardRank returns the user's entry on the board, or nil when they have no points on it
+func (c *Config) leaderboardRank(ctx context.Context, board leaderboard.Board, userID int32) (*leaderboard.Entry, int64, error) {
+	if c.Leaderboards != nil {
+		if err := c.ensureLeaderboard(ctx, board); err != nil {
+			return nil, 0, err
+		}
+		total, err := c.Leaderboards.Count(ctx, board)
+		if err != nil {
+			return nil, 0, err
+		}
+		entry, err := c.Leaderboards.Rank(ctx, board, userID)
+		return entry, total, err
+	}
+
+	totals, err := c.boardTotals(ctx, board)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, entry := range rankTotals(totals) {
+		if entry.UserID == userID {
+			return &entry, int64(len(totals)), nil
+		}
+	}
+	return nil, int64(len(totals)), nil
+}
+
+// getLeaderboard returns a page of a leaderboard.
+// Query params: scope (global, league or team), id, period (alltime or weekly), week, limit, offset
+func (c *Config) getLeaderboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	board, err := parseLeaderboard(r, time.Now())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := int64(leaderboardDefaultLimit)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.ParseInt(limitStr, 10, 32); err == nil && l > 0 {
+			limit = min(l, leaderboardMaxLimit)
+		}
+	}
+	var offset int64
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.ParseInt(offsetStr, 10, 32); err == nil && o > 0 {
+			offset = o
+		}
+	}
+
+	entries, total, err := c.leaderboardPage(ctx, board, offset, limit)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get leaderboard: %v", err))
+		return
+	}
+
+	response := LeaderboardResponse{
+		Scope:   string(board.Scope),
+		ScopeID: board.ScopeID,
+		Period:  string(board.Period),
+		Week:    board.Week,
+		Total:   total,
+		Entries: c.leaderboardEntries(ctx, entries),
+	}
+	if next := offset + int64(len(entries)); len(entries) > 0 && next < total {
+		response.NextOffset = &next
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// leaderboardEntries adds user names and avatars to ranked entries
+func (c *Config) leaderboardEntries(ctx context.Context, entries []leaderboard.Entry) []LeaderboardEntryResponse {
+	response := make([]LeaderboardEntryResponse, len(entries))
+	userIDs := make([]int32, len(entries))
+	for i, entry := range entries {
+		response[i] = LeaderboardEntryResponse{Rank: entry.Rank, UserID: entry.UserID, Points: entry.Points}
+		userIDs[i] = entry.UserID
+	}
+	if len(userIDs) == 0 {
+		return response
+	}
+
+	users, err := c.DB.GetUserSummaries(ctx, userIDs)
+	if err != nil {
+		fmt.Printf("Failed to get leaderboard users: %v\n", err)
+		return response
+	}
+	usersByID := make(map[int32]database.GetUserSummariesRow, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+	for i := range response {
+		if user, ok := usersByID[response[i].UserID]; ok {
+			response[i].Firstname = user.Firstname
+			response[i].Lastname = user.Lastname
+			response[i].DisplayName = user.DisplayName.String
+			response[i].AvatarURL = user.AvatarUrl.String
+		}
+	}
+	return response
+}
+
+// getMyLeaderboardRank returns the authenticated user's rank on a leaderboard
+func (c *Config) getMyLeaderboardRank(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	board, err := parseLeaderboard(r, time.Now())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entry, total, err := c.leaderboardRank(ctx, board, userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get leaderboard rank: %v", err))
+		return
+	}
+
+	response := LeaderboardRankResponse{
+		Scope:   string(board.Scope),
+		ScopeID: board.ScopeID,
+		Period:  string(board.Period),
+		Week:    board.Week,
+		Total:   total,
+	}
+	if entry != nil {
+		response.Rank = &entry.Rank
+		response.Points = entry.Points
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// recalculateLeaderboards re-awards a matchday's debate and score predictions and rebuilds the
+// boards from the ledger. Awards are unique per source, so running it twice
+// gives the same totals.
+func (c *Config) recalculateLeaderboards(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req RecalculateLeaderboardsRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.Week == "" {
+		req.Week = leaderboard.Week(time.Now())
+	} else if _, err := leaderboard.WeekStart(req.Week); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	awarded := 0
+	if req.MatchID != "" {
+		_, n, err := c.scoreMatchPredictions(ctx, req.MatchID)
+		if err != nil && !errors.Is(err, errMatchNotFinished) && !errors.Is(err, errFixtureNotFound) {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to score predictions: %v", err))
+			return
+		}
+		awarded += n
+
+		debates, err := c.DB.ListResolvedDebatesByMatch(ctx, req.MatchID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get resolved debates: %v", err))
+			return
+		}
+		for _, debate := range debates {
+			n, err := c.awardPredictionCredits(ctx, debate)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to award debate %d: %v", debate.ID, err))
+				return
+			}
+			awarded += n
+		}
+	}
+
+	rebuilt := 0
+	if c.Leaderboards != nil {
+		boards, err := c.allLeaderboards(ctx, req.Week)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list leaderboards: %v", err))
+			return
+		}
+		for _, board := range boards {
+			if err := c.rebuildLeaderboard(ctx, board); err != nil {
+				respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to rebuild %s: %v", board.Key(), err))
+				return
+			}
+			rebuilt++
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"awarded":        awarded,
+		"boards_rebuilt": rebuilt,
+		"week":           req.Week,
+	})
+}
+
+// allLeaderboards lists the all-time boards and the given week's boards for
+// every scope that can have points
+func (c *Config) allLeaderboards(ctx context.Context, week string) ([]leaderboard.Board, error) {
+	scopes := []leaderboard.Board{{Scope: leaderboard.ScopeGlobal}}
+
+	leagues, err := c.DB.ListAwardedLeagues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, league := range leagues {
+		scopes = append(scopes, leaderboard.Board{Scope: leaderboard.ScopeLeague, ScopeID: strconv.Itoa(int(league.Int32))})
+	}
+
+	teams, err := c.DB.ListAwardedTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		scopes = append(scopes, leaderboard.Board{Scope: leaderboard.ScopeTeam, ScopeID: team.String()})
+	}
+
+	boards := make([]leaderboard.Board, 0, len(scopes)*2)
+	for _, scope := range scopes {
+		allTime, weekly := scope, scope
+		allTime.Period = leaderboard.PeriodAllTime
+		weekly.Period = leaderboard.PeriodWeekly
+		weekly.Week = week
+		boards = append(boards, allTime, weekly)
+	}
+	return boards, nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/leaderboard"
+)
+
+func TestRankTotals(t *testing.T) {
+	entries := rankTotals(map[int32]int64{4: 10, 2: 30, 9: 10, 1: 5})
+
+	want := []leaderboard.Entry{
+		{UserID: 2, Points: 30, Rank: 1},
+		{UserID: 4, Points: 10, Rank: 2},
+		{UserID: 9, Points: 10, Rank: 3},
+		{UserID: 1, Points: 5, Rank: 4},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(entries))
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("Entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestParseLeaderboard(t *testing.T) {
+	now := time.Date(2025, 10, 22, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		query   string
+		want    leaderboard.Board
+		wantErr bool
+	}{
+		{"", leaderboard.Board{Scope: leaderboard.ScopeGlobal, Period: leaderboard.PeriodAllTime}, false},
+		{"period=weekly", leaderboard.Board{Scope: leaderboard.ScopeGlobal, Period: leaderboard.PeriodWeekly, Week: "2025-W43"}, false},
+		{"scope=league&id=39&period=weekly&week=2025-W40", leaderboard.Board{Scope: leaderboard.ScopeLeague, ScopeID: "39", Period: leaderboard.PeriodWeekly, Week: "2025-W40"}, false},
+		{"scope=team&id=7d5b0c1e-7f43-4a0e-9d3c-2f1a6b8e9c10", leaderboard.Board{Scope: leaderboard.ScopeTeam, ScopeID: "7d5b0c1e-7f43-4a0e-9d3c-2f1a6b8e9c10", Period: leaderboard.PeriodAllTime}, false},
+		{"scope=league", leaderboard.Board{}, true},
+		{"scope=league&id=premier", leaderboard.Board{}, true},
+		{"scope=team&id=42", leaderboard.Board{}, true},
+		{"period=monthly", leaderboard.Board{}, true},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/leaderboards?"+tt.query, nil)
+		got, err := parseLeaderboard(req, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLeaderboard(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseLeaderboard(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGetMyLeaderboardRankRequiresAuth(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("GET", "/leaderboards/me", nil)
+	rec := httptest.NewRecorder()
+
+	config.getMyLeaderboardRank(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestGetLeaderboardValidatesScope(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("GET", "/leaderboards?scope=league", nil)
+	ctx := context.WithValue(req.Context(), "user_id", int32(7))
+	rec := httptest.NewRecorder()
+
+	config.getLeaderboard(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+)
+
+func TestCreateLeagueRequiresAuthentication(t *testing.T) {
+	service := NewLeaguesService(nil)
+	rec := httptest.NewRecorder()
+
+	service.CreateLeague(rec, httptest.NewRequest("POST", "/leagues", strings.NewReader(`{"name":"Sunday League"}`)))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestCanManageLeague(t *testing.T) {
+	league := database.League{OwnerID: 7}
+
+	if !canManageLeague(authedRequest("PUT", "/leagues/1", ""), league) {
+		t.Error("the league's owner should be able to manage it")
+	}
+
+	other := database.League{OwnerID: 8}
+	if canManageLeague(authedRequest("PUT", "/leagues/1", ""), other) {
+		t.Error("another user shouldn't be able to manage the league")
+	}
+
+	admin := authedRequest("PUT", "/leagues/1", "")
+	admin = admin.WithContext(context.WithValue(admin.Context(), "user_role", "admin"))
+	if !canManageLeague(admin, other) {
+		t.Error("admins should be able to manage any league")
+	}
+
+	if canManageLeague(httptest.NewRequest("PUT", "/leagues/1", nil), database.League{}) {
+		t.Error("an unauthenticated request shouldn't be able to manage a league")
+	}
+}
@@ -0,0 +1,665 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Roles a manager can have on their team
+const (
+	managerHeadCoach = "head_coach"
+	managerAssistant = "assistant"
+	managerAdmin     = "admin"
+)
+
+// What a team's managers can do, depending on their role
+const (
+	permissionRoster   = "roster"    // Invite and release players, and register for seasons
+	permissionRecruit  = "recruit"   // Shortlists and trials
+	permissionMatchDay = "match_day" // Results and appearances
+	permissionProfile  = "profile"   // The team's logo
+)
+
+// managerRoles lists the roles from most to least senior
+var managerRoles = []string{managerHeadCoach, managerAssistant, managerAdmin}
+
+var rolePermissions = map[string][]string{
+	managerHeadCoach: {permissionRoster, permissionRecruit, permissionMatchDay, permissionProfile},
+	managerAssistant: {permissionRecruit, permissionMatchDay},
+	managerAdmin:     {permissionRoster, permissionProfile},
+}
+
+// permissionActions finish "Only the team's managers can ..." when a
+// manager's role doesn't have the permission
+var permissionActions = map[string]string{
+	permissionRoster:   "manage its roster",
+	permissionRecruit:  "recruit for it",
+	permissionMatchDay: "manage its match days",
+	permissionProfile:  "change its details",
+}
+
+// managerInvitationTTL is how long the invitee has to answer
+const managerInvitationTTL = 14 * 24 * time.Hour
+
+type ManagerInvitationRequest struct {
+	Email   string `json:"email"`
+	Role    string `json:"role"`
+	Message string `json:"message"`
+}
+
+type ManagerInvitationResponse struct {
+	ID         uuid.UUID   `json:"id"`
+	LeagueID   uuid.UUID   `json:"league_id"`
+	LeagueName string      `json:"league_name,omitempty"`
+	Team       FixtureTeam `json:"team"`
+	Email      string      `json:"email"`
+	InviteeID  *int32      `json:"invitee_id,omitempty"` // Once bound to a verified account
+	Role       string      `json:"role"`
+	Message    string      `json:"message,omitempty"`
+	Status     string      `json:"status"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type TeamManagerResponse struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      int32     `json:"user_id"`
+	Name        string    `json:"name"`
+	Title       string    `json:"title,omitempty"`
+	Role        string    `json:"role"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ManagerRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// inviteManager asks someone, by email, to help manage the team. Only the
+// league's owner and admins can invite managers. When the email belongs to
+// an account that has verified it, the invitation is bound to that account
+// straight away and they get an inbox notification. Otherwise it's bound
+// once someone verifies the address.
+func (c *Config) inviteManager(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req ManagerInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "A valid email is required")
+		return
+	}
+	if !validManagerRole(req.Role) {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("role must be one of %s", strings.Join(managerRoles, ", ")))
+		return
+	}
+
+	team, league, ok := c.ownedTeam(w, r, userID)
+	if !ok {
+		return
+	}
+	var inviteeID sql.NullInt32
+	invitee, err := c.DB.FindUserByEmail(ctx, email)
+	if err != nil && err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get user: %v", err))
+		return
+	}
+	if err == nil {
+		existing, err := c.DB.GetLeagueManagerByUser(ctx, database.GetLeagueManagerByUserParams{LeagueID: league.ID, UserID: invitee.ID})
+		if err != nil && err != sql.ErrNoRows {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check existing managers: %v", err))
+			return
+		}
+		if err == nil && existing.TeamID.Valid && existing.TeamID.UUID == team.ID {
+			respondWithError(w, http.StatusConflict, "They already manage this team")
+			return
+		}
+		verified, ok, err := c.verifiedEmail(ctx, invitee.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check their email: %v", err))
+			return
+		}
+		inviteeID = sql.NullInt32{Int32: invitee.ID, Valid: ok && verified == email}
+	}
+
+	invitation, err := c.DB.CreateManagerInvitation(ctx, database.CreateManagerInvitationParams{
+		LeagueID:  league.ID,
+		TeamID:    team.ID,
+		Email:     email,
+		InviteeID: inviteeID,
+		Role:      req.Role,
+		InvitedBy: sql.NullInt32{Int32: userID, Valid: true},
+		Message:   sql.NullString{String: req.Message, Valid: req.Message != ""},
+		ExpiresAt: time.Now().Add(managerInvitationTTL),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "They already have a pending invitation to manage this team")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create invitation: %v", err))
+		return
+	}
+
+	if inviteeID.Valid {
+		err = addNotification(ctx, c.DB, inviteeID.Int32, userNotification{
+			Type:      notificationManagerInvite,
+			Title:     "Manager invitation",
+			Body:      fmt.Sprintf("%s invited you to help manage %s", league.Name, team.Name),
+			Data:      map[string]string{"manager_invitation_id": invitation.ID.String(), "team_id": team.ID.String(), "role": invitation.Role},
+			DedupeKey: fmt.Sprintf("manager_invitation:%s", invitation.ID),
+		})
+		if err != nil {
+			log.Printf("Failed to notify user %d of manager invitation: %v", inviteeID.Int32, err)
+		}
+	}
+
+	respondWithJSON(w, http.StatusCreated, newManagerInvitationResponse(invitation, league.Name, team.Name, time.Now()))
+}
+
+// listTeamManagerInvitations returns the team's pending manager invitations
+// to its league's owner
+func (c *Config) listTeamManagerInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	team, league, ok := c.ownedTeam(w, r, userID)
+	if !ok {
+		return
+	}
+	invitations, err := c.DB.ListTeamManagerInvitations(r.Context(), team.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list invitations: %v", err))
+		return
+	}
+
+	now := time.Now()
+	response := make([]ManagerInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		response = append(response, newManagerInvitationResponse(invitation, league.Name, team.Name, now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// listMyManagerInvitations returns the caller's pending invitations, the
+// ones bound to their account once they verified their email
+func (c *Config) listMyManagerInvitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	rows, err := c.DB.ListManagerInvitationsByInvitee(ctx, sql.NullInt32{Int32: userID, Valid: true})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list invitations: %v", err))
+		return
+	}
+	now := time.Now()
+	response := make([]ManagerInvitationResponse, 0, len(rows))
+	for _, row := range rows {
+		invitation := database.ManagerInvitation{ID: row.ID, LeagueID: row.LeagueID, TeamID: row.TeamID, Email: row.Email, InviteeID: row.InviteeID, Role: row.Role, Message: row.Message, Status: row.Status, ExpiresAt: row.ExpiresAt, CreatedAt: row.CreatedAt}
+		response = append(response, newManagerInvitationResponse(invitation, row.LeagueName, row.TeamName, now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// acceptManagerInvitation makes the caller one of the team's managers and
+// promotes them to the team_manager role. Someone already managing another
+// team in the league has to step down there first. The new role is in their
+// token from their next login.
+func (c *Config) acceptManagerInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	invitation, ok := c.managerInvitationForUser(w, r, userID)
+	if !ok {
+		return
+	}
+
+	existing, err := c.DB.GetLeagueManagerByUser(ctx, database.GetLeagueManagerByUserParams{LeagueID: invitation.LeagueID, UserID: userID})
+	if err != nil && err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check existing managers: %v", err))
+		return
+	}
+	managing := err == nil
+	if managing && existing.TeamID.Valid && existing.TeamID.UUID != invitation.TeamID {
+		respondWithError(w, http.StatusConflict, "You already manage another team in this league")
+		return
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Invitations can't be accepted right now")
+		return
+	}
+
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to accept invitation: %v", err))
+		return
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	if _, err := q.RespondToManagerInvitation(ctx, database.RespondToManagerInvitationParams{
+		Status:     invitationAccepted,
+		AcceptedBy: sql.NullInt32{Int32: userID, Valid: true},
+		ID:         invitation.ID,
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusConflict, "Invitation has already been answered")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to accept invitation: %v", err))
+		return
+	}
+	teamID := uuid.NullUUID{UUID: invitation.TeamID, Valid: true}
+	var manager database.TeamManager
+	if managing {
+		manager, err = q.AssignTeamManager(ctx, database.AssignTeamManagerParams{ID: existing.ID, TeamID: teamID, Role: invitation.Role})
+	} else {
+		manager, err = q.CreateTeamManager(ctx, database.CreateTeamManagerParams{UserID: userID, LeagueID: invitation.LeagueID, TeamID: teamID, Role: invitation.Role})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to add manager: %v", err))
+		return
+	}
+	if err := q.PromoteToTeamManager(ctx, userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update role: %v", err))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to accept invitation: %v", err))
+		return
+	}
+
+	user, err := c.DB.GetUser(ctx, userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get user: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newTeamManagerResponse(manager, user.Firstname, user.Lastname))
+}
+
+// declineManagerInvitation turns an invitation down
+func (c *Config) declineManagerInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	invitation, ok := c.managerInvitationForUser(w, r, userID)
+	if !ok {
+		return
+	}
+	c.answerManagerInvitation(w, r, invitation, invitationDeclined)
+}
+
+// cancelManagerInvitation withdraws a pending invitation. Only the league's
+// owner and admins can cancel it.
+func (c *Config) cancelManagerInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	invitation, ok := c.managerInvitationFromRequest(w, r)
+	if !ok {
+		return
+	}
+	league, ok := c.fixtureLeague(w, r.Context(), invitation.LeagueID)
+	if !ok {
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can cancel this invitation")
+		return
+	}
+	if invitation.Status != invitationPending {
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("Invitation has already been %s", invitation.Status))
+		return
+	}
+	c.answerManagerInvitation(w, r, invitation, invitationCancelled)
+}
+
+// listTeamManagers returns the team's managers and what each can do
+func (c *Config) listTeamManagers(w http.ResponseWriter, r *http.Request) {
+	team, ok := c.teamFromRequest(w, r)
+	if !ok {
+		return
+	}
+	rows, err := c.DB.ListTeamManagersByTeam(r.Context(), uuid.NullUUID{UUID: team.ID, Valid: true})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list managers: %v", err))
+		return
+	}
+
+	response := make([]TeamManagerResponse, 0, len(rows))
+	for _, row := range rows {
+		manager := database.TeamManager{ID: row.ID, UserID: row.UserID, LeagueID: row.LeagueID, TeamID: row.TeamID, Title: row.Title, Role: row.Role, CreatedAt: row.CreatedAt}
+		response = append(response, newTeamManagerResponse(manager, row.Firstname, row.Lastname))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// updateManagerRole changes what one of the team's managers can do. Only the
+// league's owner and admins can change roles.
+func (c *Config) updateManagerRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req ManagerRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !validManagerRole(req.Role) {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("role must be one of %s", strings.Join(managerRoles, ", ")))
+		return
+	}
+
+	team, _, ok := c.ownedTeam(w, r, userID)
+	if !ok {
+		return
+	}
+	manager, ok := c.teamManagerFromRequest(w, r, team)
+	if !ok {
+		return
+	}
+	updated, err := c.DB.AssignTeamManager(r.Context(), database.AssignTeamManagerParams{ID: manager.ID, TeamID: manager.TeamID, Role: req.Role})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update manager: %v", err))
+		return
+	}
+	user, err := c.DB.GetUser(r.Context(), updated.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get user: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newTeamManagerResponse(updated, user.Firstname, user.Lastname))
+}
+
+// revokeManager removes one of the team's managers. The league's owner and
+// admins can remove anyone, and managers can step down. Someone left
+// managing no teams goes back to being a fan.
+func (c *Config) revokeManager(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	team, ok := c.teamFromRequest(w, r)
+	if !ok {
+		return
+	}
+	manager, ok := c.teamManagerFromRequest(w, r, team)
+	if !ok {
+		return
+	}
+	if manager.UserID != userID {
+		league, ok := c.fixtureLeague(w, ctx, manager.LeagueID)
+		if !ok {
+			return
+		}
+		if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+			respondWithError(w, http.StatusForbidden, "Only the league's owner can remove the team's managers")
+			return
+		}
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Managers can't be removed right now")
+		return
+	}
+
+	if err := removeTeamManager(ctx, c.DBConn, c.DB, manager); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to remove manager: %v", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeTeamManager deletes the manager and demotes their user when it was
+// the last team they managed, all or nothing
+func removeTeamManager(ctx context.Context, conn *sql.DB, db *database.Queries, manager database.TeamManager) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := db.WithTx(tx)
+
+	if err := q.UnsetTeamManager(ctx, uuid.NullUUID{UUID: manager.ID, Valid: true}); err != nil {
+		return err
+	}
+	if err := q.DeleteTeamManager(ctx, manager.ID); err != nil {
+		return err
+	}
+	if _, err := q.DemoteTeamManager(ctx, manager.UserID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// answerManagerInvitation records the answer to a pending invitation
+func (c *Config) answerManagerInvitation(w http.ResponseWriter, r *http.Request, invitation database.ManagerInvitation, status string) {
+	ctx := r.Context()
+
+	updated, err := c.DB.RespondToManagerInvitation(ctx, database.RespondToManagerInvitationParams{Status: status, ID: invitation.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusConflict, "Invitation has already been answered")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to answer invitation: %v", err))
+		return
+	}
+	team, err := c.DB.GetTeam(ctx, updated.TeamID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newManagerInvitationResponse(updated, "", team.Name, time.Now()))
+}
+
+// managerInvitationForUser loads the invitation in the id URL param and checks
+// it was sent to the caller and can still be answered. Invitations not yet
+// bound to an account need the caller to have verified the email they were
+// sent to.
+func (c *Config) managerInvitationForUser(w http.ResponseWriter, r *http.Request, userID int32) (database.ManagerInvitation, bool) {
+	invitation, ok := c.managerInvitationFromRequest(w, r)
+	if !ok {
+		return database.ManagerInvitation{}, false
+	}
+	if invitation.InviteeID.Valid {
+		if invitation.InviteeID.Int32 != userID {
+			respondWithError(w, http.StatusForbidden, "This invitation is for someone else")
+			return database.ManagerInvitation{}, false
+		}
+	} else {
+		email, verified, err := c.verifiedEmail(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check your email: %v", err))
+			return database.ManagerInvitation{}, false
+		}
+		if !verified {
+			respondWithError(w, http.StatusForbidden, "Verify your email to answer invitations sent to it")
+			return database.ManagerInvitation{}, false
+		}
+		if email != invitation.Email {
+			respondWithError(w, http.StatusForbidden, "This invitation is for someone else")
+			return database.ManagerInvitation{}, false
+		}
+	}
+	switch status := managerInvitationStatus(invitation, time.Now()); status {
+	case invitationPending:
+	case invitationExpired:
+		respondWithError(w, http.StatusConflict, "Invitation has expired")
+		return database.ManagerInvitation{}, false
+	default:
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("Invitation has already been %s", status))
+		return database.ManagerInvitation{}, false
+	}
+	return invitation, true
+}
+
+func (c *Config) managerInvitationFromRequest(w http.ResponseWriter, r *http.Request) (database.ManagerInvitation, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid invitation ID")
+		return database.ManagerInvitation{}, false
+	}
+	invitation, err := c.DB.GetManagerInvitation(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Invitation not found")
+			return database.ManagerInvitation{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get invitation: %v", err))
+		return database.ManagerInvitation{}, false
+	}
+	return invitation, true
+}
+
+// ownedTeam loads the team in the id URL param and its league, and checks
+// the caller owns the league
+func (c *Config) ownedTeam(w http.ResponseWriter, r *http.Request, userID int32) (database.Team, database.League, bool) {
+	team, ok := c.teamFromRequest(w, r)
+	if !ok {
+		return database.Team{}, database.League{}, false
+	}
+	if !team.LeagueID.Valid {
+		respondWithError(w, http.StatusBadRequest, "Team is not in a league")
+		return database.Team{}, database.League{}, false
+	}
+	league, ok := c.fixtureLeague(w, r.Context(), team.LeagueID.UUID)
+	if !ok {
+		return database.Team{}, database.League{}, false
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can manage the team's managers")
+		return database.Team{}, database.League{}, false
+	}
+	return team, league, true
+}
+
+// teamManagerFromRequest loads the manager in the managerId URL param and
+// checks they manage the team
+func (c *Config) teamManagerFromRequest(w http.ResponseWriter, r *http.Request, team database.Team) (database.TeamManager, bool) {
+	managerID, err := uuid.Parse(chi.URLParam(r, "managerId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid manager ID")
+		return database.TeamManager{}, false
+	}
+	manager, err := c.DB.GetTeamManager(r.Context(), managerID)
+	if err != nil && err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get manager: %v", err))
+		return database.TeamManager{}, false
+	}
+	if err == sql.ErrNoRows || !manager.TeamID.Valid || manager.TeamID.UUID != team.ID {
+		respondWithError(w, http.StatusNotFound, "Manager not found on this team")
+		return database.TeamManager{}, false
+	}
+	return manager, true
+}
+
+func validManagerRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// rolesWith lists the manager roles that have the permission
+func rolesWith(permission string) []string {
+	var roles []string
+	for _, role := range managerRoles {
+		for _, p := range rolePermissions[role] {
+			if p == permission {
+				roles = append(roles, role)
+				break
+			}
+		}
+	}
+	return roles
+}
+
+// normalizeEmail checks the address is valid and lower cases it
+func normalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(address.Address), nil
+}
+
+// managerInvitationStatus reports pending invitations past their expiry as
+// expired
+func managerInvitationStatus(invitation database.ManagerInvitation, now time.Time) string {
+	if invitation.Status == invitationPending && !now.Before(invitation.ExpiresAt) {
+		return invitationExpired
+	}
+	return invitation.Status
+}
+
+func newManagerInvitationResponse(invitation database.ManagerInvitation, leagueName, teamName string, now time.Time) ManagerInvitationResponse {
+	var inviteeID *int32
+	if invitation.InviteeID.Valid {
+		inviteeID = &invitation.InviteeID.Int32
+	}
+	return ManagerInvitationResponse{
+		ID:         invitation.ID,
+		LeagueID:   invitation.LeagueID,
+		LeagueName: leagueName,
+		Team:       FixtureTeam{ID: invitation.TeamID, Name: teamName},
+		Email:      invitation.Email,
+		InviteeID:  inviteeID,
+		Role:       invitation.Role,
+		Message:    invitation.Message.String,
+		Status:     managerInvitationStatus(invitation, now),
+		ExpiresAt:  invitation.ExpiresAt,
+		CreatedAt:  invitation.CreatedAt,
+	}
+}
+
+func newTeamManagerResponse(manager database.TeamManager, firstname, lastname string) TeamManagerResponse {
+	return TeamManagerResponse{
+		ID:          manager.ID,
+		UserID:      manager.UserID,
+		Name:        strings.TrimSpace(firstname + " " + lastname),
+		Title:       manager.Title.String,
+		Role:        manager.Role,
+		Permissions: rolePermissions[manager.Role],
+		CreatedAt:   manager.CreatedAt,
+	}
+}
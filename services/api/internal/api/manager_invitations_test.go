@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestRolesWith(t *testing.T) {
+	tests := []struct {
+		permission string
+		want       []string
+	}{
+		{permissionRoster, []string{managerHeadCoach, managerAdmin}},
+		{permissionRecruit, []string{managerHeadCoach, managerAssistant}},
+		{permissionMatchDay, []string{managerHeadCoach, managerAssistant}},
+		{permissionProfile, []string{managerHeadCoach, managerAdmin}},
+		{"unknown", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permission, func(t *testing.T) {
+			if got := rolesWith(tt.permission); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rolesWith(%q) = %v, want %v", tt.permission, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	if got, err := normalizeEmail("  Coach@Example.COM "); err != nil || got != "coach@example.com" {
+		t.Errorf("normalizeEmail() = %q, %v, want coach@example.com", got, err)
+	}
+	for _, email := range []string{"", "coach", "@example.com"} {
+		if _, err := normalizeEmail(email); err == nil {
+			t.Errorf("normalizeEmail(%q) should fail", email)
+		}
+	}
+}
+
+func TestManagerInvitationStatus(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		invitation database.ManagerInvitation
+		want       string
+	}{
+		{"pending", database.ManagerInvitation{Status: invitationPending, ExpiresAt: now.Add(time.Hour)}, invitationPending},
+		{"expired", database.ManagerInvitation{Status: invitationPending, ExpiresAt: now}, invitationExpired},
+		{"declined after expiry", database.ManagerInvitation{Status: invitationDeclined, ExpiresAt: now.Add(-time.Hour)}, invitationDeclined},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := managerInvitationStatus(tt.invitation, now); got != tt.want {
+				t.Errorf("managerInvitationStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInviteManagerValidation(t *testing.T) {
+	for _, body := range []string{
+		`not json`,
+		`{"email":"coach","role":"assistant"}`,
+		`{"email":"coach@example.com"}`,
+		`{"email":"coach@example.com","role":"owner"}`,
+	} {
+		config := &Config{}
+		req := authedRequest("POST", "/teams/x/manager-invitations", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.inviteManager(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestUpdateManagerRoleValidation(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("PUT", "/teams/x/managers/y", `{"role":"captain"}`)
+	rec := httptest.NewRecorder()
+
+	config.updateManagerRole(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestAcceptManagerInvitationRequiresAuth(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.acceptManagerInvitation(rec, httptest.NewRequest("POST", "/manager-invitations/x/accept", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestUpdateTeamManagerRejectsTeamChange(t *testing.T) {
+	svc := &TeamManagersService{}
+	req := authedRequest("PUT", "/team-managers/x", `{"team_id":"`+uuid.New().String()+`"}`)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", uuid.New().String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	svc.UpdateTeamManager(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/media"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// mediaFormMemory is how much of an upload is kept in memory before spilling to disk
+const mediaFormMemory = 8 << 20
+
+type MediaResponse struct {
+	ID           int32     `json:"id"`
+	MatchID      string    `json:"match_id"`
+	Type         string    `json:"type"` // image or video
+	ContentType  string    `json:"content_type,omitempty"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	Status       string    `json:"status"` // processing until the thumbnail is ready
+	UploadedBy   *int32    `json:"uploaded_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func newMediaResponse(m database.Medium) MediaResponse {
+	response := MediaResponse{
+		ID:           m.ID,
+		MatchID:      m.MatchID,
+		Type:         m.MediaType,
+		ContentType:  m.ContentType,
+		URL:          m.MediaUrl,
+		ThumbnailURL: m.ThumbnailUrl.String,
+		Status:       m.Status,
+		CreatedAt:    m.CreatedAt,
+	}
+	if m.UserID.Valid {
+		response.UploadedBy = &m.UserID.Int32
+	}
+	return response
+}
+
+// uploadMatchMedia takes a multipart upload with the file in the "file" field.
+// The type is sniffed from the content, not trusted from the client.
+func (c *Config) uploadMatchMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	if c.Blobs == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Media uploads are not available")
+		return
+	}
+
+	matchID := chi.URLParam(r, "id")
+	if _, err := strconv.Atoi(matchID); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid match ID")
+		return
+	}
+
+	file, header, ok := formFile(w, r, media.MaxVideoSize)
+	if !ok {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		respondWithError(w, http.StatusBadRequest, "Failed to read upload")
+		return
+	}
+	contentType, mediaType, extension, err := media.Detect(head[:n])
+	if err != nil {
+		respondWithError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Unsupported media type %s; upload a JPEG, PNG, GIF, MP4 or WebM", contentType))
+		return
+	}
+	if header.Size > media.MaxSize(mediaType) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("%s uploads are limited to %d MB", mediaType, media.MaxSize(mediaType)>>20))
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to read upload")
+		return
+	}
+
+	var body io.Reader = file
+	size := header.Size
+	if mediaType == media.TypeImage {
+		// Re-encoding drops EXIF data, including any location
+		data, err := io.ReadAll(file)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Failed to read upload")
+			return
+		}
+		clean, err := media.StripMetadata(data, contentType)
+		if err != nil {
+			if errors.Is(err, media.ErrImageTooLarge) {
+				respondWithError(w, http.StatusRequestEntityTooLarge, "Image dimensions are too large")
+				return
+			}
+			respondWithError(w, http.StatusBadRequest, "Invalid image")
+			return
+		}
+		body, size = bytes.NewReader(clean), int64(len(clean))
+	}
+
+	if _, err := c.fetchFixture(ctx, matchID); err != nil {
+		if errors.Is(err, errFixtureNotFound) {
+			respondWithError(w, http.StatusNotFound, "Match not found")
+			return
+		}
+		respondWithError(w, http.StatusBadGateway, fmt.Sprintf("Failed to get match: %v", err))
+		return
+	}
+
+	key := fmt.Sprintf("media/%s/%s%s", matchID, uuid.New(), extension)
+	if err := c.Blobs.Put(ctx, key, body, size, contentType); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to store upload: %v", err))
+		return
+	}
+
+	// The workers pick it up from here to generate a thumbnail
+	item, err := c.DB.CreateMedia(ctx, database.CreateMediaParams{
+		MatchID:     matchID,
+		MediaUrl:    c.Blobs.URL(key),
+		UserID:      sql.NullInt32{Int32: userID, Valid: true},
+		MediaType:   mediaType,
+		ContentType: contentType,
+		SizeBytes:   size,
+		StorageKey:  sql.NullString{String: key, Valid: true},
+		Status:      media.StatusProcessing,
+	})
+	if err != nil {
+		if err := c.Blobs.Delete(ctx, key); err != nil {
+			log.Printf("Failed to remove orphaned upload %s: %v\n", key, err)
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save upload: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newMediaResponse(item))
+}
+
+// formFile reads the "file" field of a multipart upload of up to maxSize bytes.
+// It responds with an error and returns false when there isn't one.
+func formFile(w http.ResponseWriter, r *http.Request, maxSize int64) (multipart.File, *multipart.FileHeader, bool) {
+	// Leave room for the multipart framing around the largest allowed file
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+	if err := r.ParseMultipartForm(mediaFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Uploads are limited to %d MB", maxSize>>20))
+			return nil, nil, false
+		}
+		respondWithError(w, http.StatusBadRequest, "Expected a multipart upload")
+		return nil, nil, false
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		r.MultipartForm.RemoveAll()
+		respondWithError(w, http.StatusBadRequest, "file is required")
+		return nil, nil, false
+	}
+	return file, header, true
+}
+
+// listMatchMedia returns a match's gallery, newest first
+func (c *Config) listMatchMedia(w http.ResponseWriter, r *http.Request) {
+	rows, err := c.DB.GetMediaByMatchId(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get media: %v", err))
+		return
+	}
+
+	items := make([]MediaResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, newMediaResponse(row))
+	}
+	respondWithJSON(w, http.StatusOK, items)
+}
+
+// deleteMatchMedia removes an upload and its files. Only the uploader can delete it.
+func (c *Config) deleteMatchMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "mediaId"), 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid media ID")
+		return
+	}
+
+	item, err := c.DB.GetMedia(ctx, int32(id))
+	if err == sql.ErrNoRows || (err == nil && item.MatchID != chi.URLParam(r, "id")) {
+		respondWithError(w, http.StatusNotFound, "Media not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get media: %v", err))
+		return
+	}
+	if !item.UserID.Valid || item.UserID.Int32 != userID {
+		respondWithError(w, http.StatusForbidden, "You can only delete your own uploads")
+		return
+	}
+
+	if err := c.DB.DeleteMediaById(ctx, item.ID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete media: %v", err))
+		return
+	}
+
+	if c.Blobs != nil {
+		for _, key := range []sql.NullString{item.StorageKey, item.ThumbnailKey} {
+			if !key.Valid {
+				continue
+			}
+			if err := c.Blobs.Delete(ctx, key.String); err != nil {
+				log.Printf("Failed to remove %s for media %d: %v\n", key.String, item.ID, err)
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
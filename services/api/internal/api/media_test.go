@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/blobstore"
+	"github.com/go-chi/chi"
+)
+
+// mediaUpload builds an authenticated multipart upload for match 1035045
+func mediaUpload(t *testing.T, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	req := authedRequest("POST", "/futbol/matches/1035045/media", body.String())
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "1035045")
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+}
+
+func TestUploadMatchMediaRequiresBlobStore(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.uploadMatchMedia(rec, mediaUpload(t, []byte("GIF89a")))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+}
+
+func TestUploadMatchMediaRejectsUnsupportedTypes(t *testing.T) {
+	config := &Config{Blobs: blobstore.NewLocalStore(t.TempDir(), "")}
+	rec := httptest.NewRecorder()
+
+	// Named photo.jpg, but it's HTML
+	config.uploadMatchMedia(rec, mediaUpload(t, []byte("<html><script>alert(1)</script></html>")))
+
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected status 415, got %d", rec.Code)
+	}
+}
+
+func TestUploadMatchMediaRejectsCorruptImages(t *testing.T) {
+	config := &Config{Blobs: blobstore.NewLocalStore(t.TempDir(), "")}
+	rec := httptest.NewRecorder()
+
+	// Sniffs as a JPEG but can't be decoded, so it can't be stripped of metadata
+	config.uploadMatchMedia(rec, mediaUpload(t, []byte("\xFF\xD8\xFF\xE0 not a real photo")))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestUploadMatchMediaRequiresFile(t *testing.T) {
+	config := &Config{Blobs: blobstore.NewLocalStore(t.TempDir(), "")}
+	req := authedRequest("POST", "/futbol/matches/1035045/media", "")
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "1035045")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	config.uploadMatchMedia(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestNewMediaResponse(t *testing.T) {
+	response := newMediaResponse(database.Medium{
+		ID:           3,
+		MatchID:      "1035045",
+		MediaUrl:     "http://localhost:8080/uploads/media/1035045/a.jpg",
+		MediaType:    "image",
+		ThumbnailUrl: sql.NullString{String: "http://localhost:8080/uploads/thumbnails/media/1035045/a.jpg", Valid: true},
+		Status:       "ready",
+		UserID:       sql.NullInt32{Int32: 7, Valid: true},
+	})
+
+	if response.ThumbnailURL == "" || response.UploadedBy == nil || *response.UploadedBy != 7 {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+	"github.com/go-chi/chi"
+)
+
+// Inbox-only notification types. Replies (notificationCommentReply) go to both
+// the inbox and push.
+const (
+	notificationProfileVerified   = "profile_verified"
+	notificationDebatePromoted    = "debate_promoted"
+	notificationDebateVotes       = "debate_votes"
+	notificationRosterInvite      = "roster_invitation"
+	notificationPlayerShortlisted = "player_shortlisted"
+	notificationTrialInvite       = "trial_invitation"
+	notificationTrialResponse     = "trial_response"
+	notificationManagerInvite     = "manager_invitation"
+)
+
+const (
+	inboxDefaultPageSize = 20
+	inboxMaxPageSize     = 100
+
+	// Read notifications are kept for inboxReadRetention, unread ones for inboxRetention
+	inboxReadRetention = 30 * 24 * time.Hour
+	inboxRetention     = 90 * 24 * time.Hour
+)
+
+// debateVoteMilestones are the vote counts at which a debate's author hears about it
+var debateVoteMilestones = []int64{10, 50, 100, 500, 1000}
+
+type NotificationResponse struct {
+	ID        int64             `json:"id"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data"`
+	ReadAt    *time.Time        `json:"read_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type NotificationsResponse struct {
+	Items       []NotificationResponse `json:"items"`
+	UnreadCount int64                  `json:"unread_count"`
+	NextCursor  string                 `json:"next_cursor,omitempty"`
+}
+
+// listNotifications returns the user's inbox, newest first. ?unread=true only
+// returns unread notifications.
+func (c *Config) listNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	limit := inboxDefaultPageSize
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			respondWithError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(l, inboxMaxPageSize)
+	}
+
+	var before sql.NullInt64
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		id, err := decodeInboxCursor(cursorStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		before = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	// Fetch one extra to know whether there's another page
+	rows, err := c.DB.ListNotifications(ctx, database.ListNotificationsParams{
+		UserID:     userID,
+		BeforeID:   before,
+		UnreadOnly: r.URL.Query().Get("unread") == "true",
+		MaxResults: int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get notifications: %v", err))
+		return
+	}
+
+	unread, err := c.DB.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to count notifications: %v", err))
+		return
+	}
+
+	response := NotificationsResponse{Items: make([]NotificationResponse, 0, len(rows)), UnreadCount: unread}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		response.NextCursor = encodeInboxCursor(rows[len(rows)-1].ID)
+	}
+	for _, row := range rows {
+		response.Items = append(response.Items, newNotificationResponse(row))
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func newNotificationResponse(row database.Notification) NotificationResponse {
+	response := NotificationResponse{
+		ID:        row.ID,
+		Type:      row.NotificationType,
+		Title:     row.Title,
+		Body:      row.Body,
+		Data:      map[string]string{},
+		CreatedAt: row.CreatedAt,
+	}
+	if err := json.Unmarshal(row.Data, &response.Data); err != nil {
+		log.Printf("Failed to decode data for notification %d: %v\n", row.ID, err)
+	}
+	if row.ReadAt.Valid {
+		response.ReadAt = &row.ReadAt.Time
+	}
+	return response
+}
+
+func (c *Config) markNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	updated, err := c.DB.MarkNotificationRead(r.Context(), database.MarkNotificationReadParams{ID: id, UserID: userID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to mark notification read: %v", err))
+		return
+	}
+	if updated == 0 {
+		respondWithError(w, http.StatusNotFound, "Notification not found")
+		return
+	}
+
+	c.respondWithUnreadCount(w, r.Context(), userID)
+}
+
+func (c *Config) markAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	if _, err := c.DB.MarkAllNotificationsRead(r.Context(), userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to mark notifications read: %v", err))
+		return
+	}
+
+	c.respondWithUnreadCount(w, r.Context(), userID)
+}
+
+func (c *Config) respondWithUnreadCount(w http.ResponseWriter, ctx context.Context, userID int32) {
+	unread, err := c.DB.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to count notifications: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]int64{"unread_count": unread})
+}
+
+// inbox cursors are the ID of the last notification on the previous page
+func encodeInboxCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeInboxCursor(s string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(raw), 10, 64)
+}
+
+// addNotification adds a notification to the user's inbox, once per dedupe key
+func addNotification(ctx context.Context, db *database.Queries, userID int32, notification userNotification) error {
+	payload, err := notification.payload()
+	if err != nil {
+		return err
+	}
+	_, err = db.CreateNotification(ctx, database.CreateNotificationParams{
+		UserID:           userID,
+		NotificationType: notification.Type,
+		Title:            notification.Title,
+		Body:             notification.Body,
+		Data:             payload,
+		DedupeKey:        notification.DedupeKey,
+	})
+	return err
+}
+
+func (c *Config) inboxCommentReply(ctx context.Context, e events.CommentPosted) error {
+	recipient, notification, err := c.commentReply(ctx, e)
+	if err != nil || notification == nil {
+		return err
+	}
+	return addNotification(ctx, c.DB, recipient, *notification)
+}
+
+func (c *Config) inboxVerification(ctx context.Context, e events.VerificationAdded) error {
+	if e.PlayerUserID == e.VerifierUserID {
+		return nil
+	}
+	verifier, err := c.DB.GetUser(ctx, e.VerifierUserID)
+	if err != nil {
+		return err
+	}
+	return addNotification(ctx, c.DB, e.PlayerUserID, userNotification{
+		Type:      notificationProfileVerified,
+		Title:     "New verification",
+		Body:      fmt.Sprintf("%s verified your player profile", verifier.Firstname),
+		Data:      map[string]string{"player_profile_id": e.PlayerProfileID},
+		DedupeKey: fmt.Sprintf("verification:%s", e.VerificationID),
+	})
+}
+
+// inboxDebateVotes tells a community debate's author when it passes a vote milestone
+func (c *Config) inboxDebateVotes(ctx context.Context, e events.VoteCast) error {
+	debate, err := c.DB.GetDebate(ctx, e.DebateID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// AI-generated debates have no author
+	if !debate.AuthorID.Valid || debate.AuthorID.Int32 == e.UserID {
+		return nil
+	}
+
+	votes, err := c.DB.CountDebateVotes(ctx, e.DebateID)
+	if err != nil {
+		return err
+	}
+	milestone := reachedMilestone(votes)
+	if milestone == 0 {
+		return nil
+	}
+	return addNotification(ctx, c.DB, debate.AuthorID.Int32, userNotification{
+		Type:      notificationDebateVotes,
+		Title:     "Your debate is taking off",
+		Body:      fmt.Sprintf("%q has %d votes", debate.Headline, milestone),
+		Data:      map[string]string{"debate_id": strconv.Itoa(int(debate.ID))},
+		DedupeKey: fmt.Sprintf("debate_votes:%d:%d", debate.ID, milestone),
+	})
+}
+
+// reachedMilestone returns the highest vote milestone reached, or 0
+func reachedMilestone(votes int64) int64 {
+	var reached int64
+	for _, milestone := range debateVoteMilestones {
+		if votes >= milestone {
+			reached = milestone
+		}
+	}
+	return reached
+}
+
+// inboxDebatePromoted tells the author their proposed debate is now published
+func (c *Config) inboxDebatePromoted(ctx context.Context, debate database.Debate) error {
+	if !debate.AuthorID.Valid {
+		return nil
+	}
+	return addNotification(ctx, c.DB, debate.AuthorID.Int32, userNotification{
+		Type:      notificationDebatePromoted,
+		Title:     "Your debate was approved",
+		Body:      fmt.Sprintf("%q is now live", debate.Headline),
+		Data:      map[string]string{"debate_id": strconv.Itoa(int(debate.ID)), "match_id": debate.MatchID},
+		DedupeKey: fmt.Sprintf("debate_promoted:%d", debate.ID),
+	})
+}
+
+// NotificationCleaner removes old inbox notifications
+type NotificationCleaner struct {
+	config *Config
+}
+
+func NewNotificationCleaner(c Config) *NotificationCleaner {
+	return &NotificationCleaner{config: &c}
+}
+
+// Run cleans up every interval until the context is cancelled
+func (nc *NotificationCleaner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		nc.CleanOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CleanOnce deletes read notifications after 30 days and all notifications after 90
+func (nc *NotificationCleaner) CleanOnce(ctx context.Context) {
+	now := time.Now()
+	deleted, err := nc.config.DB.DeleteExpiredNotifications(ctx, database.DeleteExpiredNotificationsParams{
+		CreatedBefore: now.Add(-inboxRetention),
+		ReadBefore:    now.Add(-inboxReadRetention),
+	})
+	if err != nil {
+		log.Printf("Failed to clean up notifications: %v\n", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d expired notifications\n", deleted)
+	}
+}
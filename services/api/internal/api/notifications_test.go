@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+)
+
+func TestInboxCursorRoundTrip(t *testing.T) {
+	cursor := encodeInboxCursor(1234)
+	id, err := decodeInboxCursor(cursor)
+	if err != nil || id != 1234 {
+		t.Errorf("decodeInboxCursor(%q) = %d, %v; want 1234", cursor, id, err)
+	}
+
+	if _, err := decodeInboxCursor("not a cursor!"); err == nil {
+		t.Error("expected an error for a malformed cursor")
+	}
+}
+
+func TestReachedMilestone(t *testing.T) {
+	tests := []struct {
+		votes int64
+		want  int64
+	}{
+		{0, 0},
+		{9, 0},
+		{10, 10},
+		{49, 10},
+		{120, 100},
+		{5000, 1000},
+	}
+	for _, tt := range tests {
+		if got := reachedMilestone(tt.votes); got != tt.want {
+			t.Errorf("reachedMilestone(%d) = %d, want %d", tt.votes, got, tt.want)
+		}
+	}
+}
+
+func TestNewNotificationResponse(t *testing.T) {
+	readAt := time.Date(2025, 10, 25, 16, 0, 0, 0, time.UTC)
+	data, _ := json.Marshal(map[string]string{"type": notificationCommentReply, "debate_id": "4"})
+
+	response := newNotificationResponse(database.Notification{
+		ID:               9,
+		NotificationType: notificationCommentReply,
+		Title:            "New reply",
+		Data:             data,
+		ReadAt:           sql.NullTime{Time: readAt, Valid: true},
+	})
+
+	if response.Data["debate_id"] != "4" || response.Type != notificationCommentReply {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if response.ReadAt == nil || !response.ReadAt.Equal(readAt) {
+		t.Errorf("ReadAt = %v, want %v", response.ReadAt, readAt)
+	}
+}
+
+func TestMarkNotificationReadInvalidID(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("POST", "/users/notifications/abc/read", "")
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "abc")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	config.markNotificationRead(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestListNotificationsRequiresAuth(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.listNotifications(rec, httptest.NewRequest("GET", "/users/notifications", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
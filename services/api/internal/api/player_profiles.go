@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/ratings"
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 )
@@ -18,6 +20,15 @@ type PlayerProfileService struct {
 	DB *database.Queries
 }
 
+// PlayerProfileResponse is a player profile with its follower count, match
+// stats and community-rated attributes
+type PlayerProfileResponse struct {
+	database.PlayerProfile
+	FollowStats
+	SeasonStats []PlayerSeasonStats `json:"season_stats"`
+	Attributes  PlayerAttributes    `json:"attributes"`
+}
+
 // CreatePlayerProfileRequest represents the JSON request for creating a player profile
 type CreatePlayerProfileRequest struct {
 	UserID    int32      `json:"user_id"`
@@ -35,14 +46,44 @@ type CreatePlayerProfileRequest struct {
 	Physical  int32      `json:"physical"`
 }
 
-// Handler: Create player profile
+// Handler: Create player profile. Players create their own; admins can
+// create one for any user_id.
 func (svc *PlayerProfileService) CreatePlayerProfile(w http.ResponseWriter, r *http.Request) {
+	callerID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
 	var req CreatePlayerProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("Error decoding request: %v", err)
 		http.Error(w, "invalid request", http.StatusBadRequest)
 		return
 	}
+	if req.UserID == 0 {
+		req.UserID = callerID
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && req.UserID != callerID {
+		http.Error(w, "You can only create your own player profile", http.StatusForbidden)
+		return
+	}
+
+	// Players join a team through its roster invitations, see rosters.go
+	if req.TeamID != nil {
+		http.Error(w, "team_id can't be set, players join a team by accepting its invitation", http.StatusBadRequest)
+		return
+	}
+
+	// Attributes are out of 99, and 0 leaves one unreported
+	attributes := ratings.Attributes{
+		Pace: req.Pace, Shooting: req.Shooting, Passing: req.Passing, Stamina: req.Stamina,
+		Dribbling: req.Dribbling, Defending: req.Defending, Physical: req.Physical,
+	}
+	if err := attributes.Validate(0); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Debug: Log the request
 	log.Printf("Creating player profile for user_id: %d", req.UserID)
@@ -63,14 +104,8 @@ func (svc *PlayerProfileService) CreatePlayerProfile(w http.ResponseWriter, r *h
 	log.Printf("User found: %s %s (ID: %d)", user.Firstname, user.Lastname, user.ID)
 
 	// Convert to database params
-	var teamID uuid.NullUUID
-	if req.TeamID != nil {
-		teamID = uuid.NullUUID{UUID: *req.TeamID, Valid: true}
-	}
-
 	dbParams := database.CreatePlayerProfileParams{
 		UserID:    req.UserID,
-		TeamID:    teamID,
 		Position:  req.Position,
 		Age:       req.Age,
 		Country:   req.Country,
@@ -92,6 +127,9 @@ func (svc *PlayerProfileService) CreatePlayerProfile(w http.ResponseWriter, r *h
 	}
 
 	log.Printf("Player profile created successfully with ID: %s", profile.ID)
+	if err := recordSelfAttributes(r.Context(), svc.DB, profile); err != nil {
+		log.Printf("Error recording attribute history: %v", err)
+	}
 	json.NewEncoder(w).Encode(profile)
 }
 
@@ -112,32 +150,104 @@ func (svc *PlayerProfileService) GetPlayerProfile(w http.ResponseWriter, r *http
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	json.NewEncoder(w).Encode(profile)
+	userID, _ := r.Context().Value("user_id").(int32)
+	stats, err := followStats(r.Context(), svc.DB, database.FollowableTypePlayer, []uuid.UUID{profile.ID}, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	seasonStats, err := playerSeasonStats(r.Context(), svc.DB, profile.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	attributes, err := playerAttributes(r.Context(), svc.DB, profile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(PlayerProfileResponse{PlayerProfile: profile, FollowStats: stats[profile.ID], SeasonStats: seasonStats, Attributes: attributes})
 }
 
-// Handler: Update player profile
+// Handler: Update player profile. Only the player and admins can update it.
 func (svc *PlayerProfileService) UpdatePlayerProfile(w http.ResponseWriter, r *http.Request) {
+	callerID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
 	var req database.UpdatePlayerProfileParams
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request", http.StatusBadRequest)
 		return
 	}
+	req.ID = id
+	attributes := ratings.Attributes{
+		Pace: req.Pace, Shooting: req.Shooting, Passing: req.Passing, Stamina: req.Stamina,
+		Dribbling: req.Dribbling, Defending: req.Defending, Physical: req.Physical,
+	}
+	if err := attributes.Validate(0); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	previous, err := svc.DB.GetPlayerProfile(r.Context(), req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && previous.UserID != callerID {
+		http.Error(w, "You can only update your own player profile", http.StatusForbidden)
+		return
+	}
 	profile, err := svc.DB.UpdatePlayerProfile(r.Context(), req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if selfAttributes(profile) != selfAttributes(previous) {
+		if err := recordSelfAttributes(r.Context(), svc.DB, profile); err != nil {
+			log.Printf("Error recording attribute history: %v", err)
+		}
+	}
 	json.NewEncoder(w).Encode(profile)
 }
 
-// Handler: Delete player profile
+// Handler: Delete player profile. Only the player and admins can delete it.
 func (svc *PlayerProfileService) DeletePlayerProfile(w http.ResponseWriter, r *http.Request) {
+	callerID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
+	profile, err := svc.DB.GetPlayerProfile(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && profile.UserID != callerID {
+		http.Error(w, "You can only delete your own player profile", http.StatusForbidden)
+		return
+	}
 	if err := svc.DB.DeletePlayerProfile(r.Context(), id); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -145,19 +255,47 @@ func (svc *PlayerProfileService) DeletePlayerProfile(w http.ResponseWriter, r *h
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// RecalculateIsVerified sets is_verified=true if 3+ unique verifications exist
-func (svc *PlayerProfileService) RecalculateIsVerified(ctx context.Context, profileID string) error {
+// RecalculateIsVerified sets is_verified once verificationThreshold verifiers
+// with a role have unexpired verifications, logging any change with why
+func (svc *PlayerProfileService) RecalculateIsVerified(ctx context.Context, profileID string, change verificationChange) error {
 	id, err := uuid.Parse(profileID)
 	if err != nil {
 		return err
 	}
-	count, err := svc.DB.CountVerificationsByPlayer(ctx, id)
+	return recalculateIsVerified(ctx, svc.DB, id, change)
+}
+
+// recalculateIsVerified is RecalculateIsVerified with the queries to use, so it
+// can run in the same transaction as the change that triggered it
+func recalculateIsVerified(ctx context.Context, db *database.Queries, id uuid.UUID, change verificationChange) error {
+	profile, err := db.GetPlayerProfile(ctx, id)
 	if err != nil {
 		return err
 	}
-	isVerified := count >= 3
-	return svc.DB.UpdatePlayerVerificationStatus(ctx, database.UpdatePlayerVerificationStatusParams{
+	count, err := db.CountQualifyingVerifications(ctx, database.CountQualifyingVerificationsParams{
+		PlayerProfileID: id,
+		Now:             time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	isVerified := count >= verificationThreshold
+	if isVerified == profile.IsVerified {
+		return nil
+	}
+	if err := db.UpdatePlayerVerificationStatus(ctx, database.UpdatePlayerVerificationStatusParams{
 		ID:         id,
 		IsVerified: isVerified,
+	}); err != nil {
+		return err
+	}
+	return db.CreateVerificationAuditEntry(ctx, database.CreateVerificationAuditEntryParams{
+		PlayerProfileID: id,
+		WasVerified:     profile.IsVerified,
+		IsVerified:      isVerified,
+		Reason:          change.Reason,
+		VerificationID:  change.VerificationID,
+		ActorUserID:     change.ActorUserID,
+		Verifications:   int32(count),
 	})
 }
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+	"github.com/go-chi/chi"
+)
+
+// Push notification types users can opt out of
+const (
+	notificationKickoff      = "kickoff"
+	notificationGoal         = "goal"
+	notificationNewDebate    = "new_debate"
+	notificationCommentReply = "comment_reply"
+)
+
+var notificationTypes = []string{notificationKickoff, notificationGoal, notificationNewDebate, notificationCommentReply}
+
+var devicePlatforms = map[string]bool{"ios": true, "android": true, "web": true}
+
+type RegisterDeviceRequest struct {
+	Token    string `json:"token"`
+	Platform string `json:"platform"` // ios, android or web
+}
+
+type DeviceResponse struct {
+	ID       int32  `json:"id"`
+	Token    string `json:"token"`
+	Platform string `json:"platform"`
+}
+
+// NotificationPreferences maps each notification type to whether it's enabled
+type NotificationPreferences map[string]bool
+
+// userNotification is pushed to a device or added to the inbox
+type userNotification struct {
+	Type      string
+	Title     string
+	Body      string
+	Data      map[string]string
+	DedupeKey string // Unique per user, so re-delivered events don't notify twice
+}
+
+// payload is the notification's data, including its type, as JSON
+func (n userNotification) payload() (json.RawMessage, error) {
+	data := map[string]string{"type": n.Type}
+	for k, v := range n.Data {
+		data[k] = v
+	}
+	return json.Marshal(data)
+}
+
+func (c *Config) registerDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req RegisterDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Token == "" || !devicePlatforms[req.Platform] {
+		respondWithError(w, http.StatusBadRequest, "token and platform (ios, android or web) are required")
+		return
+	}
+
+	device, err := c.DB.UpsertDeviceToken(r.Context(), database.UpsertDeviceTokenParams{
+		UserID:   userID,
+		Token:    req.Token,
+		Platform: req.Platform,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to register device: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, DeviceResponse{ID: device.ID, Token: device.Token, Platform: device.Platform})
+}
+
+func (c *Config) unregisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	deleted, err := c.DB.DeleteDeviceToken(r.Context(), database.DeleteDeviceTokenParams{
+		UserID: userID,
+		Token:  chi.URLParam(r, "token"),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to unregister device: %v", err))
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Config) getNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	preferences, err := c.notificationPreferences(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get notification preferences: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, preferences)
+}
+
+// updateNotificationPreferences takes a partial map, e.g. {"goal": false}
+func (c *Config) updateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	for notificationType := range req {
+		if !isNotificationType(notificationType) {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Unknown notification type %q", notificationType))
+			return
+		}
+	}
+
+	for notificationType, enabled := range req {
+		if err := c.DB.UpsertNotificationPreference(r.Context(), database.UpsertNotificationPreferenceParams{
+			UserID:           userID,
+			NotificationType: notificationType,
+			Enabled:          enabled,
+		}); err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update notification preferences: %v", err))
+			return
+		}
+	}
+
+	preferences, err := c.notificationPreferences(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get notification preferences: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, preferences)
+}
+
+// notificationPreferences returns every type, enabled unless the user opted out
+func (c *Config) notificationPreferences(ctx context.Context, userID int32) (NotificationPreferences, error) {
+	rows, err := c.DB.ListNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make(NotificationPreferences, len(notificationTypes))
+	for _, notificationType := range notificationTypes {
+		preferences[notificationType] = true
+	}
+	for _, row := range rows {
+		if isNotificationType(row.NotificationType) {
+			preferences[row.NotificationType] = row.Enabled
+		}
+	}
+	return preferences, nil
+}
+
+func isNotificationType(notificationType string) bool {
+	for _, t := range notificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// queuePush queues a notification for the users who have a device registered
+// and haven't opted out of its type
+func (c *Config) queuePush(ctx context.Context, userIDs []int32, notification userNotification) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	payload, err := notification.payload()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.DB.EnqueuePushNotifications(ctx, database.EnqueuePushNotificationsParams{
+		NotificationType: notification.Type,
+		Title:            notification.Title,
+		Body:             notification.Body,
+		Data:             payload,
+		DedupeKey:        notification.DedupeKey,
+		UserIds:          userIDs,
+	})
+	return err
+}
+
+// notifyMatchFollowers pushes to everyone following the match or either team
+func (c *Config) notifyMatchFollowers(ctx context.Context, matchID, homeTeam, awayTeam string, notification userNotification) error {
+	followers, err := c.DB.ListMatchFollowers(ctx, database.ListMatchFollowersParams{
+		MatchID:  matchID,
+		HomeTeam: homeTeam,
+		AwayTeam: awayTeam,
+	})
+	if err != nil {
+		return err
+	}
+	return c.queuePush(ctx, followers, notification)
+}
+
+func (c *Config) notifyKickoff(ctx context.Context, e events.MatchStatusChanged) error {
+	// Only the first half starting counts as kick off
+	if e.ToStatus != "1H" || !openPredictionStatuses[e.FromStatus] {
+		return nil
+	}
+	return c.notifyMatchFollowers(ctx, e.MatchID, e.HomeTeam, e.AwayTeam, userNotification{
+		Type:      notificationKickoff,
+		Title:     "Kick off",
+		Body:      fmt.Sprintf("%s vs %s has started", e.HomeTeam, e.AwayTeam),
+		Data:      map[string]string{"match_id": e.MatchID},
+		DedupeKey: fmt.Sprintf("kickoff:%s", e.MatchID),
+	})
+}
+
+func (c *Config) notifyGoal(ctx context.Context, e events.GoalScored) error {
+	return c.notifyMatchFollowers(ctx, e.MatchID, e.HomeTeam, e.AwayTeam, userNotification{
+		Type:      notificationGoal,
+		Title:     "Goal!",
+		Body:      fmt.Sprintf("%s %d-%d %s", e.HomeTeam, e.HomeGoals, e.AwayGoals, e.AwayTeam),
+		Data:      map[string]string{"match_id": e.MatchID},
+		DedupeKey: fmt.Sprintf("goal:%s:%d-%d", e.MatchID, e.HomeGoals, e.AwayGoals),
+	})
+}
+
+func (c *Config) notifyNewDebate(ctx context.Context, e events.DebateGenerated) error {
+	return c.notifyMatchFollowers(ctx, e.MatchID, e.HomeTeam, e.AwayTeam, userNotification{
+		Type:      notificationNewDebate,
+		Title:     "New debate",
+		Body:      e.Headline,
+		Data:      map[string]string{"match_id": e.MatchID, "debate_id": strconv.Itoa(int(e.DebateID))},
+		DedupeKey: fmt.Sprintf("debate:%d", e.DebateID),
+	})
+}
+
+func (c *Config) notifyCommentReply(ctx context.Context, e events.CommentPosted) error {
+	recipient, notification, err := c.commentReply(ctx, e)
+	if err != nil || notification == nil {
+		return err
+	}
+	return c.queuePush(ctx, []int32{recipient}, *notification)
+}
+
+// commentReply builds the notification for the author of the comment being
+// replied to. It's nil if the comment isn't a reply or they replied to themselves.
+func (c *Config) commentReply(ctx context.Context, e events.CommentPosted) (int32, *userNotification, error) {
+	if e.ParentCommentID == nil {
+		return 0, nil, nil
+	}
+	parent, err := c.DB.GetComment(ctx, *e.ParentCommentID)
+	if err == sql.ErrNoRows {
+		return 0, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if !parent.UserID.Valid || parent.UserID.Int32 == e.UserID {
+		return 0, nil, nil
+	}
+
+	replier, err := c.DB.GetUser(ctx, e.UserID)
+	if err != nil {
+		return 0, nil, err
+	}
+	return parent.UserID.Int32, &userNotification{
+		Type:  notificationCommentReply,
+		Title: "New reply",
+		Body:  fmt.Sprintf("%s replied to your comment", replier.Firstname),
+		Data: map[string]string{
+			"debate_id":  strconv.Itoa(int(e.DebateID)),
+			"comment_id": strconv.Itoa(int(e.CommentID)),
+		},
+		DedupeKey: fmt.Sprintf("reply:%d", e.CommentID),
+	}, nil
+}
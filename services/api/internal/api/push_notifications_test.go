@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+)
+
+func authedRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), "user_id", int32(7)))
+}
+
+func TestRegisterDeviceValidation(t *testing.T) {
+	config := &Config{}
+
+	for _, body := range []string{`{"platform":"ios"}`, `{"token":"abc","platform":"blackberry"}`, `not json`} {
+		rec := httptest.NewRecorder()
+		config.registerDevice(rec, authedRequest("POST", "/users/devices", body))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestUpdateNotificationPreferencesRejectsUnknownTypes(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.updateNotificationPreferences(rec, authedRequest("PUT", "/users/notification-preferences", `{"goal":false,"red_card":false}`))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestNotifyKickoffIgnoresOtherTransitions(t *testing.T) {
+	// A nil DB would panic if anything were queued
+	config := &Config{}
+
+	for _, change := range []events.MatchStatusChanged{
+		{MatchID: "1035", FromStatus: "1H", ToStatus: "HT"},
+		{MatchID: "1035", FromStatus: "HT", ToStatus: "2H"},
+		{MatchID: "1035", FromStatus: "2H", ToStatus: "FT"},
+	} {
+		if err := config.notifyKickoff(context.Background(), change); err != nil {
+			t.Errorf("notifyKickoff(%s -> %s) = %v", change.FromStatus, change.ToStatus, err)
+		}
+	}
+}
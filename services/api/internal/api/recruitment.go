@@ -0,0 +1,628 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+const (
+	playerSearchDefaultLimit = 20
+	playerSearchMaxLimit     = 100
+)
+
+// playerSearchSorts are the sort values SearchPlayerProfiles understands.
+// newest is the default.
+var playerSearchSorts = map[string]bool{
+	"newest": true, "age": true, "height": true, "overall": true,
+	"pace": true, "shooting": true, "passing": true, "stamina": true,
+	"dribbling": true, "defending": true, "physical": true,
+}
+
+type PlayerSearchResult struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     int32      `json:"user_id"`
+	Name       string     `json:"name"`
+	TeamID     *uuid.UUID `json:"team_id"` // null for free agents
+	Position   string     `json:"position"`
+	Age        int32      `json:"age"`
+	Country    string     `json:"country"`
+	HeightCm   int32      `json:"height_cm"`
+	Pace       int32      `json:"pace"`
+	Shooting   int32      `json:"shooting"`
+	Passing    int32      `json:"passing"`
+	Stamina    int32      `json:"stamina"`
+	Dribbling  int32      `json:"dribbling"`
+	Defending  int32      `json:"defending"`
+	Physical   int32      `json:"physical"`
+	Overall    int32      `json:"overall"` // Average of the attributes
+	IsVerified bool       `json:"is_verified"`
+	// AttributesSource is community once the player's ratings are published
+	// and self while the attributes are what they reported
+	AttributesSource string `json:"attributes_source"`
+}
+
+type PlayerSearchResponse struct {
+	Total      int64                `json:"total"`
+	NextOffset *int64               `json:"next_offset,omitempty"`
+	Players    []PlayerSearchResult `json:"players"`
+}
+
+type ShortlistRequest struct {
+	PlayerProfileID uuid.UUID `json:"player_profile_id"`
+	Note            string    `json:"note"`
+}
+
+type ShortlistedPlayer struct {
+	PlayerSearchResult
+	Note          string    `json:"note,omitempty"`
+	ShortlistedAt time.Time `json:"shortlisted_at"`
+}
+
+type TrialInvitationRequest struct {
+	PlayerProfileID uuid.UUID `json:"player_profile_id"`
+	TrialAt         time.Time `json:"trial_at"`
+	Location        string    `json:"location"`
+	Message         string    `json:"message"`
+}
+
+type TrialInvitationResponse struct {
+	ID              uuid.UUID   `json:"id"`
+	Team            FixtureTeam `json:"team"`
+	PlayerProfileID uuid.UUID   `json:"player_profile_id"`
+	PlayerName      string      `json:"player_name,omitempty"`
+	TrialAt         time.Time   `json:"trial_at"`
+	Location        string      `json:"location,omitempty"`
+	Message         string      `json:"message,omitempty"`
+	Status          string      `json:"status"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// searchPlayers finds players to recruit, free agents unless free_agents=false.
+// Query params: position, country, min_age, max_age, min_height, max_height,
+// min_<attribute>, verified, free_agents, sort, order, limit, offset
+func (c *Config) searchPlayers(w http.ResponseWriter, r *http.Request) {
+	params, err := parsePlayerSearch(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := c.DB.SearchPlayerProfiles(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to search players: %v", err))
+		return
+	}
+
+	response := PlayerSearchResponse{Players: make([]PlayerSearchResult, 0, len(rows))}
+	for _, row := range rows {
+		response.Total = row.TotalCount
+		player := newPlayerSearchResult(database.PlayerProfile{
+			ID: row.ID, UserID: row.UserID, TeamID: row.TeamID, Position: row.Position, Age: row.Age, Country: row.Country, HeightCm: row.HeightCm,
+			Pace: row.Pace, Shooting: row.Shooting, Passing: row.Passing, Stamina: row.Stamina, Dribbling: row.Dribbling, Defending: row.Defending, Physical: row.Physical,
+			IsVerified: row.IsVerified,
+		}, row.Firstname, row.Lastname)
+		if row.CommunityRaters.Valid {
+			player.AttributesSource = attributesCommunity
+		}
+		response.Players = append(response.Players, player)
+	}
+	if next := int64(params.PageOffset) + int64(len(rows)); len(rows) > 0 && next < response.Total {
+		response.NextOffset = &next
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// parsePlayerSearch turns search query params into SearchPlayerProfiles
+// params. Unset filters match everyone.
+func parsePlayerSearch(query url.Values) (database.SearchPlayerProfilesParams, error) {
+	params := database.SearchPlayerProfilesParams{
+		FreeAgentsOnly: query.Get("free_agents") != "false",
+		VerifiedOnly:   query.Get("verified") == "true",
+		Position:       sql.NullString{String: query.Get("position"), Valid: query.Get("position") != ""},
+		Country:        sql.NullString{String: query.Get("country"), Valid: query.Get("country") != ""},
+		Descending:     query.Get("order") != "asc",
+		PageLimit:      playerSearchDefaultLimit,
+	}
+
+	var err error
+	nullInts := []struct {
+		name string
+		dst  *sql.NullInt32
+	}{
+		{"min_age", &params.MinAge}, {"max_age", &params.MaxAge},
+		{"min_height", &params.MinHeight}, {"max_height", &params.MaxHeight},
+	}
+	for _, p := range nullInts {
+		var n int32
+		if n, err = queryInt32(query, p.name); err != nil {
+			return params, err
+		}
+		*p.dst = sql.NullInt32{Int32: n, Valid: query.Get(p.name) != ""}
+	}
+
+	minimums := []struct {
+		name string
+		dst  *int32
+	}{
+		{"min_pace", &params.MinPace}, {"min_shooting", &params.MinShooting}, {"min_passing", &params.MinPassing},
+		{"min_stamina", &params.MinStamina}, {"min_dribbling", &params.MinDribbling},
+		{"min_defending", &params.MinDefending}, {"min_physical", &params.MinPhysical},
+	}
+	for _, p := range minimums {
+		if *p.dst, err = queryInt32(query, p.name); err != nil {
+			return params, err
+		}
+	}
+
+	if params.MinAge.Valid && params.MaxAge.Valid && params.MinAge.Int32 > params.MaxAge.Int32 {
+		return params, fmt.Errorf("min_age can't be more than max_age")
+	}
+	if params.MinHeight.Valid && params.MaxHeight.Valid && params.MinHeight.Int32 > params.MaxHeight.Int32 {
+		return params, fmt.Errorf("min_height can't be more than max_height")
+	}
+
+	params.Sort = query.Get("sort")
+	if params.Sort == "" {
+		params.Sort = "newest"
+	}
+	if !playerSearchSorts[params.Sort] {
+		return params, fmt.Errorf("invalid sort %q", params.Sort)
+	}
+	if order := query.Get("order"); order != "" && order != "asc" && order != "desc" {
+		return params, fmt.Errorf("order must be asc or desc")
+	}
+
+	if limit, err := queryInt32(query, "limit"); err != nil {
+		return params, err
+	} else if limit > 0 {
+		params.PageLimit = min(limit, playerSearchMaxLimit)
+	}
+	if params.PageOffset, err = queryInt32(query, "offset"); err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
+// queryInt32 is a non-negative integer query param, or 0 when it isn't set
+func queryInt32(query url.Values, name string) (int32, error) {
+	s := query.Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return int32(n), nil
+}
+
+// getTeamShortlist lists the players a team's managers have shortlisted
+func (c *Config) getTeamShortlist(w http.ResponseWriter, r *http.Request) {
+	team, ok := c.managedTeam(w, r, permissionRecruit)
+	if !ok {
+		return
+	}
+	rows, err := c.DB.ListTeamShortlist(r.Context(), team.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get shortlist: %v", err))
+		return
+	}
+
+	response := make([]ShortlistedPlayer, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, ShortlistedPlayer{
+			PlayerSearchResult: newPlayerSearchResult(database.PlayerProfile{
+				ID: row.ID, UserID: row.UserID, TeamID: row.TeamID, Position: row.Position, Age: row.Age, Country: row.Country, HeightCm: row.HeightCm,
+				Pace: row.Pace, Shooting: row.Shooting, Passing: row.Passing, Stamina: row.Stamina, Dribbling: row.Dribbling, Defending: row.Defending, Physical: row.Physical,
+				IsVerified: row.IsVerified,
+			}, row.Firstname, row.Lastname),
+			Note:          row.Note.String,
+			ShortlistedAt: row.ShortlistedAt,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// shortlistPlayer adds a player to a team's shortlist. The player is told a
+// team is interested the first time it shortlists them.
+func (c *Config) shortlistPlayer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req ShortlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.PlayerProfileID == uuid.Nil {
+		respondWithError(w, http.StatusBadRequest, "player_profile_id is required")
+		return
+	}
+
+	team, ok := c.managedTeam(w, r, permissionRecruit)
+	if !ok {
+		return
+	}
+	profile, ok := c.recruitablePlayer(w, ctx, req.PlayerProfileID, team)
+	if !ok {
+		return
+	}
+
+	added, err := c.DB.AddShortlistedPlayer(ctx, database.AddShortlistedPlayerParams{
+		TeamID:          team.ID,
+		PlayerProfileID: profile.ID,
+		AddedBy:         sql.NullInt32{Int32: userID, Valid: true},
+		Note:            sql.NullString{String: req.Note, Valid: req.Note != ""},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to shortlist player: %v", err))
+		return
+	}
+	if added == 0 {
+		respondWithError(w, http.StatusConflict, "Player is already shortlisted")
+		return
+	}
+
+	err = addNotification(ctx, c.DB, profile.UserID, userNotification{
+		Type:      notificationPlayerShortlisted,
+		Title:     "A team is interested in you",
+		Body:      fmt.Sprintf("%s added you to their shortlist", team.Name),
+		Data:      map[string]string{"team_id": team.ID.String()},
+		DedupeKey: fmt.Sprintf("player_shortlisted:%s:%s", team.ID, profile.ID),
+	})
+	if err != nil {
+		log.Printf("Failed to notify player %s of shortlisting: %v", profile.ID, err)
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (c *Config) unshortlistPlayer(w http.ResponseWriter, r *http.Request) {
+	playerID, err := uuid.Parse(chi.URLParam(r, "playerId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid player profile ID")
+		return
+	}
+	team, ok := c.managedTeam(w, r, permissionRecruit)
+	if !ok {
+		return
+	}
+
+	removed, err := c.DB.RemoveShortlistedPlayer(r.Context(), database.RemoveShortlistedPlayerParams{TeamID: team.ID, PlayerProfileID: playerID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to remove player from shortlist: %v", err))
+		return
+	}
+	if removed == 0 {
+		respondWithError(w, http.StatusNotFound, "Player is not shortlisted")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// inviteToTrial invites a player to a trial session. Only the team's
+// managers, the league's owner and admins can invite players.
+func (c *Config) inviteToTrial(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req TrialInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.PlayerProfileID == uuid.Nil || req.TrialAt.IsZero() {
+		respondWithError(w, http.StatusBadRequest, "player_profile_id and trial_at are required")
+		return
+	}
+	if !req.TrialAt.After(time.Now()) {
+		respondWithError(w, http.StatusBadRequest, "trial_at must be in the future")
+		return
+	}
+
+	team, ok := c.managedTeam(w, r, permissionRecruit)
+	if !ok {
+		return
+	}
+	profile, ok := c.recruitablePlayer(w, ctx, req.PlayerProfileID, team)
+	if !ok {
+		return
+	}
+
+	trial, err := c.DB.CreateTrialInvitation(ctx, database.CreateTrialInvitationParams{
+		TeamID:          team.ID,
+		PlayerProfileID: profile.ID,
+		InvitedBy:       sql.NullInt32{Int32: userID, Valid: true},
+		TrialAt:         req.TrialAt,
+		Location:        sql.NullString{String: req.Location, Valid: req.Location != ""},
+		Message:         sql.NullString{String: req.Message, Valid: req.Message != ""},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "Player already has a pending trial invitation from this team")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create trial invitation: %v", err))
+		return
+	}
+
+	err = addNotification(ctx, c.DB, profile.UserID, userNotification{
+		Type:      notificationTrialInvite,
+		Title:     "Trial invitation",
+		Body:      fmt.Sprintf("%s invited you to a trial on %s", team.Name, trial.TrialAt.UTC().Format("Mon 2 Jan 15:04 MST")),
+		Data:      map[string]string{"trial_invitation_id": trial.ID.String(), "team_id": team.ID.String()},
+		DedupeKey: fmt.Sprintf("trial_invitation:%s", trial.ID),
+	})
+	if err != nil {
+		log.Printf("Failed to notify player %s of trial: %v", profile.ID, err)
+	}
+
+	respondWithJSON(w, http.StatusCreated, newTrialInvitationResponse(trial, team.Name, "", time.Now()))
+}
+
+// listTeamTrials returns a team's upcoming trials to its managers
+func (c *Config) listTeamTrials(w http.ResponseWriter, r *http.Request) {
+	team, ok := c.managedTeam(w, r, permissionRecruit)
+	if !ok {
+		return
+	}
+	rows, err := c.DB.ListTeamTrialInvitations(r.Context(), team.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list trials: %v", err))
+		return
+	}
+
+	now := time.Now()
+	response := make([]TrialInvitationResponse, 0, len(rows))
+	for _, row := range rows {
+		trial := database.TrialInvitation{ID: row.ID, TeamID: row.TeamID, PlayerProfileID: row.PlayerProfileID, TrialAt: row.TrialAt, Location: row.Location, Message: row.Message, Status: row.Status, CreatedAt: row.CreatedAt}
+		response = append(response, newTrialInvitationResponse(trial, team.Name, strings.TrimSpace(row.Firstname+" "+row.Lastname), now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// listMyTrials returns the caller's upcoming trials
+func (c *Config) listMyTrials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	profile, err := c.DB.GetPlayerProfileByUser(ctx, userID)
+	if err == sql.ErrNoRows {
+		respondWithJSON(w, http.StatusOK, []TrialInvitationResponse{})
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+
+	rows, err := c.DB.ListPlayerTrialInvitations(ctx, profile.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list trials: %v", err))
+		return
+	}
+	now := time.Now()
+	response := make([]TrialInvitationResponse, 0, len(rows))
+	for _, row := range rows {
+		trial := database.TrialInvitation{ID: row.ID, TeamID: row.TeamID, PlayerProfileID: row.PlayerProfileID, TrialAt: row.TrialAt, Location: row.Location, Message: row.Message, Status: row.Status, CreatedAt: row.CreatedAt}
+		response = append(response, newTrialInvitationResponse(trial, row.TeamName, "", now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func (c *Config) acceptTrial(w http.ResponseWriter, r *http.Request) {
+	c.answerTrial(w, r, invitationAccepted)
+}
+
+func (c *Config) declineTrial(w http.ResponseWriter, r *http.Request) {
+	c.answerTrial(w, r, invitationDeclined)
+}
+
+// answerTrial records the player's answer to a trial invitation and lets
+// whoever sent it know
+func (c *Config) answerTrial(w http.ResponseWriter, r *http.Request, status string) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	trial, ok := c.trialFromRequest(w, r)
+	if !ok {
+		return
+	}
+	profile, err := c.DB.GetPlayerProfile(ctx, trial.PlayerProfileID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+	if profile.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "This invitation is for another player")
+		return
+	}
+	switch current := trialStatus(trial, time.Now()); current {
+	case invitationPending:
+	case invitationExpired:
+		respondWithError(w, http.StatusConflict, "Trial has already taken place")
+		return
+	default:
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("Invitation has already been %s", current))
+		return
+	}
+
+	updated, err := c.DB.RespondToTrialInvitation(ctx, database.RespondToTrialInvitationParams{ID: trial.ID, Status: status})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusConflict, "Invitation has already been answered")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to answer invitation: %v", err))
+		return
+	}
+	team, err := c.DB.GetTeam(ctx, updated.TeamID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+		return
+	}
+
+	if updated.InvitedBy.Valid {
+		err = addNotification(ctx, c.DB, updated.InvitedBy.Int32, userNotification{
+			Type:      notificationTrialResponse,
+			Title:     "Trial invitation " + status,
+			Body:      fmt.Sprintf("A player %s your invitation to trial with %s", status, team.Name),
+			Data:      map[string]string{"trial_invitation_id": updated.ID.String(), "player_profile_id": profile.ID.String(), "status": status},
+			DedupeKey: fmt.Sprintf("trial_response:%s", updated.ID),
+		})
+		if err != nil {
+			log.Printf("Failed to notify user %d of trial response: %v", updated.InvitedBy.Int32, err)
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, newTrialInvitationResponse(updated, team.Name, "", time.Now()))
+}
+
+// cancelTrial withdraws a pending trial invitation. Only the team's
+// managers, the league's owner and admins can cancel it.
+func (c *Config) cancelTrial(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	trial, ok := c.trialFromRequest(w, r)
+	if !ok {
+		return
+	}
+	canManage, err := c.canManageTeam(r, trial.TeamID, userID, permissionRecruit)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+		return
+	}
+	if !canManage {
+		respondWithError(w, http.StatusForbidden, "Only the team's managers can cancel this invitation")
+		return
+	}
+	if trial.Status != invitationPending {
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("Invitation has already been %s", trial.Status))
+		return
+	}
+
+	if _, err := c.DB.RespondToTrialInvitation(r.Context(), database.RespondToTrialInvitationParams{ID: trial.ID, Status: invitationCancelled}); err != nil && err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to cancel invitation: %v", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Config) trialFromRequest(w http.ResponseWriter, r *http.Request) (database.TrialInvitation, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid trial invitation ID")
+		return database.TrialInvitation{}, false
+	}
+	trial, err := c.DB.GetTrialInvitation(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Trial invitation not found")
+			return database.TrialInvitation{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get trial invitation: %v", err))
+		return database.TrialInvitation{}, false
+	}
+	return trial, true
+}
+
+// recruitablePlayer loads a player the team can shortlist or invite to a
+// trial, which is anyone not already on it
+func (c *Config) recruitablePlayer(w http.ResponseWriter, ctx context.Context, profileID uuid.UUID, team database.Team) (database.PlayerProfile, bool) {
+	profile, err := c.DB.GetPlayerProfile(ctx, profileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Player profile not found")
+			return database.PlayerProfile{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return database.PlayerProfile{}, false
+	}
+	if profile.TeamID.Valid && profile.TeamID.UUID == team.ID {
+		respondWithError(w, http.StatusConflict, "Player is already on this team")
+		return database.PlayerProfile{}, false
+	}
+	return profile, true
+}
+
+// trialStatus is the invitation's status, with pending invitations whose
+// trial has passed reported as expired
+func trialStatus(trial database.TrialInvitation, now time.Time) string {
+	if trial.Status == invitationPending && !now.Before(trial.TrialAt) {
+		return invitationExpired
+	}
+	return trial.Status
+}
+
+func newPlayerSearchResult(profile database.PlayerProfile, firstname, lastname string) PlayerSearchResult {
+	result := PlayerSearchResult{
+		ID:         profile.ID,
+		UserID:     profile.UserID,
+		Name:       strings.TrimSpace(firstname + " " + lastname),
+		Position:   profile.Position,
+		Age:        profile.Age,
+		Country:    profile.Country,
+		HeightCm:   profile.HeightCm,
+		Pace:       profile.Pace,
+		Shooting:   profile.Shooting,
+		Passing:    profile.Passing,
+		Stamina:    profile.Stamina,
+		Dribbling:  profile.Dribbling,
+		Defending:  profile.Defending,
+		Physical:   profile.Physical,
+		Overall:    (profile.Pace + profile.Shooting + profile.Passing + profile.Stamina + profile.Dribbling + profile.Defending + profile.Physical) / 7,
+		IsVerified: profile.IsVerified,
+
+		AttributesSource: attributesSelf,
+	}
+	if profile.TeamID.Valid {
+		result.TeamID = &profile.TeamID.UUID
+	}
+	return result
+}
+
+func newTrialInvitationResponse(trial database.TrialInvitation, teamName, playerName string, now time.Time) TrialInvitationResponse {
+	return TrialInvitationResponse{
+		ID:              trial.ID,
+		Team:            FixtureTeam{ID: trial.TeamID, Name: teamName},
+		PlayerProfileID: trial.PlayerProfileID,
+		PlayerName:      playerName,
+		TrialAt:         trial.TrialAt,
+		Location:        trial.Location.String,
+		Message:         trial.Message.String,
+		Status:          trialStatus(trial, now),
+		CreatedAt:       trial.CreatedAt,
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestParsePlayerSearch(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		params, err := parsePlayerSearch(url.Values{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !params.FreeAgentsOnly || params.VerifiedOnly || params.Position.Valid || params.MinAge.Valid {
+			t.Errorf("Expected free agents with no filters, got %+v", params)
+		}
+		if params.Sort != "newest" || !params.Descending || params.PageLimit != playerSearchDefaultLimit || params.PageOffset != 0 {
+			t.Errorf("Unexpected sort or paging: %+v", params)
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		query, _ := url.ParseQuery("position=ST&country=Ghana&min_age=18&max_age=25&min_height=175&min_pace=70&min_finishing=1&verified=true&free_agents=false&sort=pace&order=asc&limit=500&offset=40")
+		params, err := parsePlayerSearch(query)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if params.FreeAgentsOnly || !params.VerifiedOnly {
+			t.Errorf("Expected verified players on any team, got %+v", params)
+		}
+		if params.Position.String != "ST" || params.Country.String != "Ghana" {
+			t.Errorf("Unexpected position or country: %+v", params)
+		}
+		if params.MinAge.Int32 != 18 || params.MaxAge.Int32 != 25 || params.MinHeight.Int32 != 175 || params.MaxHeight.Valid {
+			t.Errorf("Unexpected age or height: %+v", params)
+		}
+		if params.MinPace != 70 || params.MinShooting != 0 {
+			t.Errorf("Unexpected attribute minimums: %+v", params)
+		}
+		if params.Sort != "pace" || params.Descending || params.PageLimit != playerSearchMaxLimit || params.PageOffset != 40 {
+			t.Errorf("Unexpected sort or paging: %+v", params)
+		}
+	})
+
+	for _, q := range []string{"min_age=abc", "min_pace=-1", "min_age=30&max_age=20", "min_height=190&max_height=170", "sort=salary", "order=up", "offset=x"} {
+		t.Run(q, func(t *testing.T) {
+			query, _ := url.ParseQuery(q)
+			if _, err := parsePlayerSearch(query); err == nil {
+				t.Errorf("Expected an error for %s", q)
+			}
+		})
+	}
+}
+
+func TestTrialStatus(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		trial database.TrialInvitation
+		want  string
+	}{
+		{"pending", database.TrialInvitation{Status: invitationPending, TrialAt: now.Add(time.Hour)}, invitationPending},
+		{"trial has passed", database.TrialInvitation{Status: invitationPending, TrialAt: now}, invitationExpired},
+		{"accepted", database.TrialInvitation{Status: invitationAccepted, TrialAt: now.Add(-time.Hour)}, invitationAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trialStatus(tt.trial, now); got != tt.want {
+				t.Errorf("trialStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInviteToTrialValidation(t *testing.T) {
+	player := uuid.New().String()
+	for _, body := range []string{
+		`not json`,
+		`{"player_profile_id":"` + player + `"}`,
+		`{"player_profile_id":"` + player + `","trial_at":"2020-01-01T18:00:00Z"}`,
+	} {
+		config := &Config{}
+		req := authedRequest("POST", "/teams/x/trials", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.inviteToTrial(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestShortlistPlayerValidation(t *testing.T) {
+	for _, body := range []string{`not json`, `{"note":"Quick winger"}`} {
+		config := &Config{}
+		req := authedRequest("POST", "/teams/x/shortlist", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.shortlistPlayer(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestSearchPlayersInvalidQuery(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.searchPlayers(rec, httptest.NewRequest("GET", "/player-profiles/search?sort=salary", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
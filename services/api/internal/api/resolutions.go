@@ -0,0 +1,474 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/ai"
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+)
+
+// Resolution methods
+const (
+	resolutionMethodAI    = "ai"
+	resolutionMethodAdmin = "admin"
+)
+
+const (
+	// predictionCreditPoints are awarded to each user who upvoted the winning card
+	predictionCreditPoints = 10
+	// resolutionMinConfidence is the lowest AI confidence accepted without an admin
+	resolutionMinConfidence = 0.7
+	// resolutionLookback bounds how old an unresolved prediction can be before it's abandoned
+	resolutionLookback = 30 * 24 * time.Hour
+	// resolutionBatchSize is how many unresolved debates are checked per sweep
+	resolutionBatchSize = 50
+	// inconclusiveRetryTTL stops the sweep from asking the AI again about a debate
+	// it couldn't settle until more data may be available
+	inconclusiveRetryTTL = 6 * time.Hour
+)
+
+// resolvableMatchStatuses are the fixture statuses at which a prediction can be settled
+var resolvableMatchStatuses = map[string]bool{
+	"FT":  true,
+	"AET": true,
+	"PEN": true,
+}
+
+var (
+	errDebateAlreadyResolved = errors.New("debate is already resolved")
+	errMatchNotFinished      = errors.New("match has not finished")
+	errResolutionUnavailable = errors.New("AI resolution is not configured")
+	errPredictionBacked      = errors.New("you have already backed another prediction in this debate")
+)
+
+type ResolveDebateRequest struct {
+	// WinningCardID is set by an admin deciding manually. When omitted the AI decides.
+	WinningCardID *int32 `json:"winning_card_id,omitempty"`
+	Evidence      string `json:"evidence,omitempty"`
+}
+
+// DebateResolutionResponse is rendered as the "resolved" banner on a debate
+type DebateResolutionResponse struct {
+	WinningCardID int32           `json:"winning_card_id"`
+	WinningStance string          `json:"winning_stance,omitempty"`
+	Method        string          `json:"method"`
+	Evidence      string          `json:"evidence"`
+	Confidence    *float64        `json:"confidence,omitempty"`
+	FinalStatus   string          `json:"final_status"`
+	HomeScore     int32           `json:"home_score"`
+	AwayScore     int32           `json:"away_score"`
+	FinalStats    json.RawMessage `json:"final_stats,omitempty"`
+	ResolvedAt    time.Time       `json:"resolved_at"`
+}
+
+// predictionVerdict is the decision on which card came true, from either the AI or an admin
+type predictionVerdict struct {
+	WinningCardID int32
+	Evidence      string
+	Method        string
+	ResolvedBy    sql.NullInt32
+	Confidence    sql.NullFloat64
+}
+
+// finalMatch is the final state of a fixture used as evidence for a resolution
+type finalMatch struct {
+	Info    *MatchInfo
+	Data    ai.MatchData
+	Kickoff time.Time
+}
+
+// resolveDebate settles a pre-match debate. An admin can pick the winning
+// card with their own evidence, or leave it out to let the AI decide.
+func (c *Config) resolveDebate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	adminID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	debateID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid debate ID")
+		return
+	}
+
+	var req ResolveDebateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.WinningCardID != nil && req.Evidence == "" {
+		respondWithError(w, http.StatusBadRequest, "evidence is required when choosing the winning card")
+		return
+	}
+
+	debate, err := c.DB.GetDebate(ctx, int32(debateID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Debate not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get debate: %v", err))
+		return
+	}
+	if debate.DebateType != "pre_match" || debate.Status != debateStatusPublished {
+		respondWithError(w, http.StatusBadRequest, "Only published pre_match debates can be resolved")
+		return
+	}
+
+	cards, err := c.DB.GetDebateCards(ctx, sql.NullInt32{Int32: debate.ID, Valid: true})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get debate cards: %v", err))
+		return
+	}
+
+	match, err := c.loadFinalMatch(ctx, debate.MatchID)
+	if err != nil {
+		if errors.Is(err, errMatchNotFinished) {
+			respondWithError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get match result: %v", err))
+		return
+	}
+
+	var verdict *predictionVerdict
+	if req.WinningCardID != nil {
+		if !hasCard(cards, *req.WinningCardID) {
+			respondWithError(w, http.StatusBadRequest, "winning_card_id does not belong to this debate")
+			return
+		}
+		verdict = &predictionVerdict{
+			WinningCardID: *req.WinningCardID,
+			Evidence:      req.Evidence,
+			Method:        resolutionMethodAdmin,
+			ResolvedBy:    sql.NullInt32{Int32: adminID, Valid: true},
+		}
+	} else {
+		verdict, err = c.judgePrediction(ctx, debate, cards, match)
+		if err != nil {
+			if errors.Is(err, errResolutionUnavailable) {
+				respondWithError(w, http.StatusServiceUnavailable, err.Error())
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to resolve debate: %v", err))
+			return
+		}
+		if verdict.WinningCardID == 0 {
+			respondWithJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":    "The outcome could not be determined automatically; choose the winning card manually",
+				"evidence": verdict.Evidence,
+			})
+			return
+		}
+	}
+
+	resolution, credited, err := c.saveDebateResolution(ctx, debate, match, *verdict)
+	if err != nil {
+		if errors.Is(err, errDebateAlreadyResolved) {
+			respondWithError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save resolution: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"resolution":      newDebateResolutionResponse(resolution, cards),
+		"credited_voters": credited,
+	})
+}
+
+// loadFinalMatch fetches the final result, stats and lineups of a finished fixture
+func (c *Config) loadFinalMatch(ctx context.Context, matchID string) (*finalMatch, error) {
+	info, err := c.getMatchInfo(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if !resolvableMatchStatuses[info.Status] {
+		return nil, fmt.Errorf("%w (status: %s)", errMatchNotFinished, info.Status)
+	}
+	// Only votes cast before kickoff are credited
+	kickoff, err := time.Parse(time.RFC3339, info.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kickoff time %q: %w", info.Date, err)
+	}
+
+	data := ai.MatchData{
+		MatchID:  matchID,
+		HomeTeam: info.HomeTeam,
+		AwayTeam: info.AwayTeam,
+		Date:     info.Date,
+		Status:   info.Status,
+		Venue:    info.Venue,
+		League:   info.League,
+		Season:   info.Season,
+		Stats: &ai.MatchStats{
+			HomeScore: info.HomeScore,
+			AwayScore: info.AwayScore,
+			HomeGoals: info.HomeGoals,
+			AwayGoals: info.AwayGoals,
+		},
+	}
+
+	aggregator := NewDebateDataAggregator(c)
+	if stats, err := aggregator.fetchMatchStats(ctx, matchID); err != nil {
+		fmt.Printf("Failed to fetch final stats for match %s: %v\n", matchID, err)
+	} else {
+		stats.HomeScore = info.HomeScore
+		stats.AwayScore = info.AwayScore
+		stats.HomeGoals = info.HomeGoals
+		stats.AwayGoals = info.AwayGoals
+		data.Stats = stats
+	}
+
+	// Lineups settle "X will start" and "Y will be benched" predictions
+	if lineups, err := aggregator.fetchLineups(ctx, matchID); err != nil {
+		fmt.Printf("Failed to fetch lineups for match %s: %v\n", matchID, err)
+	} else {
+		data.Lineups = lineups
+	}
+
+	return &finalMatch{Info: info, Data: data, Kickoff: kickoff}, nil
+}
+
+// judgePrediction asks the AI which card came true. A verdict with no winning
+// card means the AI couldn't settle it with enough confidence.
+func (c *Config) judgePrediction(ctx context.Context, debate database.Debate, cards []database.DebateCard, match *finalMatch) (*predictionVerdict, error) {
+	if c.AIPromptGenerator == nil {
+		return nil, errResolutionUnavailable
+	}
+
+	predictionCards := make([]ai.PredictionCard, len(cards))
+	for i, card := range cards {
+		predictionCards[i] = ai.PredictionCard{
+			ID:          card.ID,
+			Stance:      card.Stance,
+			Title:       card.Title,
+			Description: card.Description.String,
+		}
+	}
+
+	result, err := c.AIPromptGenerator.ResolvePrediction(ctx, match.Data, debate.Headline, predictionCards)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := &predictionVerdict{
+		WinningCardID: result.WinningCardID,
+		Evidence:      result.Evidence,
+		Method:        resolutionMethodAI,
+		Confidence:    sql.NullFloat64{Float64: result.Confidence, Valid: true},
+	}
+	if result.Confidence < resolutionMinConfidence {
+		verdict.WinningCardID = 0
+	}
+	return verdict, nil
+}
+
+// saveDebateResolution stores the verdict and credits everyone who upvoted the
+// winning card before kickoff. Both happen in one transaction so voters are credited exactly once.
+func (c *Config) saveDebateResolution(ctx context.Context, debate database.Debate, match *finalMatch, verdict predictionVerdict) (database.DebateResolution, int64, error) {
+	finalStats, err := json.Marshal(match.Data.Stats)
+	if err != nil {
+		return database.DebateResolution{}, 0, err
+	}
+
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.DebateResolution{}, 0, err
+	}
+	defer tx.Rollback()
+	qtx := c.DB.WithTx(tx)
+
+	resolution, err := qtx.CreateDebateResolution(ctx, database.CreateDebateResolutionParams{
+		DebateID:      debate.ID,
+		WinningCardID: verdict.WinningCardID,
+		Method:        verdict.Method,
+		ResolvedBy:    verdict.ResolvedBy,
+		Evidence:      verdict.Evidence,
+		Confidence:    verdict.Confidence,
+		FinalStatus:   match.Info.Status,
+		HomeScore:     int32(match.Info.HomeScore),
+		AwayScore:     int32(match.Info.AwayScore),
+		FinalStats:    finalStats,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return database.DebateResolution{}, 0, errDebateAlreadyResolved
+		}
+		return database.DebateResolution{}, 0, err
+	}
+
+	credited, err := qtx.CreditPredictionVoters(ctx, database.CreditPredictionVotersParams{
+		Points:       predictionCreditPoints,
+		DebateCardID: verdict.WinningCardID,
+		Kickoff:      match.Kickoff,
+	})
+	if err != nil {
+		return database.DebateResolution{}, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return database.DebateResolution{}, 0, err
+	}
+
+	fmt.Printf("Debate %d resolved by %s: card %d won, %d voters credited\n", debate.ID, verdict.Method, verdict.WinningCardID, credited)
+
+	// Leaderboard points are awarded after the commit; anything missed here is
+	// picked up by POST /leaderboards/recalculate for the match
+	if _, err := c.awardPredictionCredits(ctx, debate); err != nil {
+		fmt.Printf("Failed to award prediction points for debate %d: %v\n", debate.ID, err)
+	}
+	return resolution, credited, nil
+}
+
+// attachDebateResolution adds the resolved banner and marks the winning card
+func (c *Config) attachDebateResolution(ctx context.Context, response *DebateResponse, cards []database.DebateCard) {
+	resolution, err := c.DB.GetDebateResolution(ctx, response.ID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			fmt.Printf("Failed to get debate resolution: %v\n", err)
+		}
+		return
+	}
+
+	response.Resolution = newDebateResolutionResponse(resolution, cards)
+	for i := range response.Cards {
+		response.Cards[i].IsWinner = response.Cards[i].ID == resolution.WinningCardID
+	}
+}
+
+func newDebateResolutionResponse(resolution database.DebateResolution, cards []database.DebateCard) *DebateResolutionResponse {
+	response := &DebateResolutionResponse{
+		WinningCardID: resolution.WinningCardID,
+		Method:        resolution.Method,
+		Evidence:      resolution.Evidence,
+		FinalStatus:   resolution.FinalStatus,
+		HomeScore:     resolution.HomeScore,
+		AwayScore:     resolution.AwayScore,
+		FinalStats:    resolution.FinalStats,
+		ResolvedAt:    resolution.CreatedAt,
+	}
+	if resolution.Confidence.Valid {
+		response.Confidence = &resolution.Confidence.Float64
+	}
+	for _, card := range cards {
+		if card.ID == resolution.WinningCardID {
+			response.WinningStance = card.Stance
+			break
+		}
+	}
+	return response
+}
+
+func hasCard(cards []database.DebateCard, cardID int32) bool {
+	for _, card := range cards {
+		if card.ID == cardID {
+			return true
+		}
+	}
+	return false
+}
+
+// DebateResolver periodically settles pre-match debates whose fixtures have finished
+type DebateResolver struct {
+	config *Config
+}
+
+// NewDebateResolver creates a resolver. It needs an OpenAI key to judge predictions.
+func NewDebateResolver(c Config) *DebateResolver {
+	if c.AIPromptGenerator == nil && c.OpenAIKey != "" {
+		c.AIPromptGenerator = ai.NewPromptGenerator(c.OpenAIKey, c.OpenAIBaseURL, c.Cache)
+	}
+	return &DebateResolver{config: &c}
+}
+
+// Run resolves finished predictions every interval until the context is cancelled
+func (dr *DebateResolver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		dr.ResolveOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResolveOnce checks every recent unresolved pre-match debate and lets the AI
+// settle the ones whose match has finished
+func (dr *DebateResolver) ResolveOnce(ctx context.Context) {
+	c := dr.config
+
+	debates, err := c.DB.ListUnresolvedPredictionDebates(ctx, database.ListUnresolvedPredictionDebatesParams{
+		Since:      time.Now().Add(-resolutionLookback),
+		MaxResults: resolutionBatchSize,
+	})
+	if err != nil {
+		log.Printf("Failed to list unresolved debates: %v\n", err)
+		return
+	}
+
+	// Several debates usually share a fixture, so only fetch each match once
+	matches := make(map[string]*finalMatch)
+	for _, debate := range debates {
+		skipKey := fmt.Sprintf("debate_resolution:inconclusive:%d", debate.ID)
+		if c.Cache != nil {
+			if skip, err := c.Cache.Exists(ctx, skipKey); err == nil && skip {
+				continue
+			}
+		}
+
+		match, fetched := matches[debate.MatchID]
+		if !fetched {
+			match, err = c.loadFinalMatch(ctx, debate.MatchID)
+			if err != nil && !errors.Is(err, errMatchNotFinished) {
+				log.Printf("Failed to load match %s for resolution: %v\n", debate.MatchID, err)
+			}
+			matches[debate.MatchID] = match
+		}
+		if match == nil {
+			continue
+		}
+
+		cards, err := c.DB.GetDebateCards(ctx, sql.NullInt32{Int32: debate.ID, Valid: true})
+		if err != nil || len(cards) == 0 {
+			continue
+		}
+
+		verdict, err := c.judgePrediction(ctx, debate, cards, match)
+		if err != nil {
+			log.Printf("Failed to judge debate %d: %v\n", debate.ID, err)
+			continue
+		}
+		if verdict.WinningCardID == 0 {
+			log.Printf("Debate %d left for an admin: %s\n", debate.ID, verdict.Evidence)
+			if c.Cache != nil {
+				if err := c.Cache.Set(ctx, skipKey, verdict.Evidence, inconclusiveRetryTTL); err != nil {
+					log.Printf("Failed to mark debate %d inconclusive: %v\n", debate.ID, err)
+				}
+			}
+			continue
+		}
+
+		if _, _, err := c.saveDebateResolution(ctx, debate, match, *verdict); err != nil && !errors.Is(err, errDebateAlreadyResolved) {
+			log.Printf("Failed to save resolution for debate %d: %v\n", debate.ID, err)
+		}
+	}
+}
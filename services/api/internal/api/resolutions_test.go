@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/ai"
+	"github.com/ArronJLinton/fucci-api/internal/database"
+)
+
+func TestResolveDebateRequiresEvidence(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("POST", "/debates/1/resolve", strings.NewReader(`{"winning_card_id":3}`))
+	ctx := context.WithValue(req.Context(), "user_id", int32(1))
+	rec := httptest.NewRecorder()
+
+	config.resolveDebate(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestNewDebateResolutionResponse(t *testing.T) {
+	resolvedAt := time.Date(2025, 10, 20, 22, 0, 0, 0, time.UTC)
+	resolution := database.DebateResolution{
+		DebateID:      1,
+		WinningCardID: 11,
+		Method:        resolutionMethodAI,
+		Evidence:      "Home side won 2-0",
+		Confidence:    sql.NullFloat64{Float64: 0.92, Valid: true},
+		FinalStatus:   "FT",
+		HomeScore:     2,
+		AwayScore:     0,
+		CreatedAt:     resolvedAt,
+	}
+	cards := []database.DebateCard{
+		{ID: 10, Stance: "disagree"},
+		{ID: 11, Stance: "agree"},
+	}
+
+	response := newDebateResolutionResponse(resolution, cards)
+	if response.WinningStance != "agree" {
+		t.Errorf("Expected winning stance 'agree', got %s", response.WinningStance)
+	}
+	if response.Confidence == nil || *response.Confidence != 0.92 {
+		t.Errorf("Expected confidence 0.92, got %v", response.Confidence)
+	}
+	if !response.ResolvedAt.Equal(resolvedAt) {
+		t.Errorf("Expected resolved_at %v, got %v", resolvedAt, response.ResolvedAt)
+	}
+}
+
+func TestJudgePrediction(t *testing.T) {
+	cards := []database.DebateCard{
+		{ID: 10, Stance: "agree", Title: "Home win by two"},
+		{ID: 11, Stance: "disagree", Title: "Away side avoid defeat"},
+	}
+	match := &finalMatch{
+		Info: &MatchInfo{Status: "FT", HomeScore: 2, AwayScore: 0},
+		Data: ai.MatchData{HomeTeam: "Arsenal", AwayTeam: "Chelsea", Status: "FT", Stats: &ai.MatchStats{HomeScore: 2}},
+	}
+
+	t.Run("without AI configured", func(t *testing.T) {
+		config := &Config{}
+		if _, err := config.judgePrediction(context.Background(), database.Debate{}, cards, match); !errors.Is(err, errResolutionUnavailable) {
+			t.Errorf("Expected errResolutionUnavailable, got %v", err)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		verdict    string
+		wantCardID int32
+		wantErr    bool
+	}{
+		{"confident verdict", `{"winning_card_id":10,"evidence":"Finished 2-0","confidence":0.95}`, 10, false},
+		{"low confidence", `{"winning_card_id":10,"evidence":"Unclear","confidence":0.4}`, 0, false},
+		{"unknown card", `{"winning_card_id":99,"evidence":"?","confidence":0.9}`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var response ai.OpenAIResponse
+				response.Choices = make([]struct {
+					Message struct {
+						Content string `json:"content"`
+					} `json:"message"`
+				}, 1)
+				response.Choices[0].Message.Content = tt.verdict
+				json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			config := &Config{AIPromptGenerator: ai.NewPromptGenerator("test-key", server.URL, nil)}
+			verdict, err := config.judgePrediction(context.Background(), database.Debate{Headline: "Who wins?"}, cards, match)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("judgePrediction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if verdict.WinningCardID != tt.wantCardID {
+				t.Errorf("Expected winning card %d, got %d", tt.wantCardID, verdict.WinningCardID)
+			}
+			if verdict.Method != resolutionMethodAI {
+				t.Errorf("Expected method 'ai', got %s", verdict.Method)
+			}
+		})
+	}
+}
+
+func TestLoadFinalMatchKickoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":[{"fixture":{"date":"2025-10-18T14:00:00+00:00","status":{"short":"FT"}},"teams":{"home":{"name":"Arsenal"},"away":{"name":"Chelsea"}},"goals":{"home":2,"away":0}}]}`))
+	}))
+	defer server.Close()
+
+	config := &Config{APIFootballBaseURL: server.URL}
+	match, err := config.loadFinalMatch(context.Background(), "1035")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC); !match.Kickoff.Equal(want) {
+		t.Errorf("Expected kickoff %v, got %v", want, match.Kickoff)
+	}
+}
@@ -0,0 +1,808 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Roster invitation statuses. Pending invitations past their expiry are
+// reported as expired.
+const (
+	invitationPending   = "pending"
+	invitationAccepted  = "accepted"
+	invitationDeclined  = "declined"
+	invitationCancelled = "cancelled"
+	invitationExpired   = "expired"
+)
+
+// invitationTTL is how long a player has to answer an invitation
+const invitationTTL = 14 * 24 * time.Hour
+
+var (
+	errRosterFull        = errors.New("roster is full")
+	errPlayerTeamChanged = errors.New("player's team changed")
+)
+
+type RosterInvitationRequest struct {
+	PlayerProfileID uuid.UUID `json:"player_profile_id"`
+	Message         string    `json:"message"`
+}
+
+type RosterInvitationResponse struct {
+	ID              uuid.UUID   `json:"id"`
+	Team            FixtureTeam `json:"team"`
+	PlayerProfileID uuid.UUID   `json:"player_profile_id"`
+	PlayerName      string      `json:"player_name,omitempty"`
+	Message         string      `json:"message,omitempty"`
+	Status          string      `json:"status"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+type RosterPlayer struct {
+	PlayerProfileID uuid.UUID `json:"player_profile_id"`
+	UserID          int32     `json:"user_id"`
+	Name            string    `json:"name"`
+	Position        string    `json:"position"`
+	IsVerified      bool      `json:"is_verified"`
+}
+
+type RosterResponse struct {
+	TeamID     uuid.UUID      `json:"team_id"`
+	MaxPlayers *int32         `json:"max_players"` // null when the league has no limit
+	Players    []RosterPlayer `json:"players"`
+}
+
+type PlayerTransferResponse struct {
+	ID        uuid.UUID    `json:"id"`
+	FromTeam  *FixtureTeam `json:"from_team"` // null when joining as a free agent
+	ToTeam    *FixtureTeam `json:"to_team"`   // null when released or leaving
+	SeasonID  *uuid.UUID   `json:"season_id,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type RosterRules struct {
+	MaxPlayers *int32 `json:"max_players"` // null for no limit
+}
+
+// getTeamRoster lists a team's players and its league's roster limit
+func (c *Config) getTeamRoster(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	team, ok := c.teamFromRequest(w, r)
+	if !ok {
+		return
+	}
+	players, err := c.DB.ListPlayerProfilesByTeam(ctx, uuid.NullUUID{UUID: team.ID, Valid: true})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get players: %v", err))
+		return
+	}
+	limit, err := c.rosterLimit(ctx, team)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get roster rules: %v", err))
+		return
+	}
+
+	response := RosterResponse{TeamID: team.ID, MaxPlayers: limit, Players: make([]RosterPlayer, 0, len(players))}
+	for _, p := range players {
+		response.Players = append(response.Players, RosterPlayer{
+			PlayerProfileID: p.ID,
+			UserID:          p.UserID,
+			Name:            strings.TrimSpace(p.Firstname + " " + p.Lastname),
+			Position:        p.Position,
+			IsVerified:      p.IsVerified,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// invitePlayer asks a player to join the team. Only the team's managers, the
+// league's owner and admins can invite players.
+func (c *Config) invitePlayer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req RosterInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.PlayerProfileID == uuid.Nil {
+		respondWithError(w, http.StatusBadRequest, "player_profile_id is required")
+		return
+	}
+
+	team, ok := c.managedTeam(w, r, permissionRoster)
+	if !ok {
+		return
+	}
+	profile, err := c.DB.GetPlayerProfile(ctx, req.PlayerProfileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Player profile not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+	if profile.TeamID.Valid && profile.TeamID.UUID == team.ID {
+		respondWithError(w, http.StatusConflict, "Player is already on this team")
+		return
+	}
+	if full, err := c.rosterFull(ctx, team); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check roster size: %v", err))
+		return
+	} else if full {
+		respondWithError(w, http.StatusConflict, "Roster is full")
+		return
+	}
+
+	invitation, err := c.DB.CreateRosterInvitation(ctx, database.CreateRosterInvitationParams{
+		TeamID:          team.ID,
+		PlayerProfileID: profile.ID,
+		InvitedBy:       sql.NullInt32{Int32: userID, Valid: true},
+		Message:         sql.NullString{String: req.Message, Valid: req.Message != ""},
+		ExpiresAt:       time.Now().Add(invitationTTL),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "Player already has a pending invitation from this team")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create invitation: %v", err))
+		return
+	}
+
+	err = addNotification(ctx, c.DB, profile.UserID, userNotification{
+		Type:      notificationRosterInvite,
+		Title:     "Team invitation",
+		Body:      fmt.Sprintf("%s invited you to join their squad", team.Name),
+		Data:      map[string]string{"invitation_id": invitation.ID.String(), "team_id": team.ID.String()},
+		DedupeKey: fmt.Sprintf("roster_invitation:%s", invitation.ID),
+	})
+	if err != nil {
+		log.Printf("Failed to notify player %s of invitation: %v", profile.ID, err)
+	}
+
+	respondWithJSON(w, http.StatusCreated, newRosterInvitationResponse(invitation, team.Name, "", time.Now()))
+}
+
+// listTeamInvitations returns the team's pending invitations to its managers
+func (c *Config) listTeamInvitations(w http.ResponseWriter, r *http.Request) {
+	team, ok := c.managedTeam(w, r, permissionRoster)
+	if !ok {
+		return
+	}
+	rows, err := c.DB.ListTeamRosterInvitations(r.Context(), team.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list invitations: %v", err))
+		return
+	}
+
+	now := time.Now()
+	response := make([]RosterInvitationResponse, 0, len(rows))
+	for _, row := range rows {
+		invitation := database.RosterInvitation{ID: row.ID, TeamID: row.TeamID, PlayerProfileID: row.PlayerProfileID, Message: row.Message, Status: row.Status, ExpiresAt: row.ExpiresAt, CreatedAt: row.CreatedAt}
+		response = append(response, newRosterInvitationResponse(invitation, team.Name, strings.TrimSpace(row.Firstname+" "+row.Lastname), now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// listMyInvitations returns the pending invitations to the caller's player profile
+func (c *Config) listMyInvitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	profile, err := c.DB.GetPlayerProfileByUser(ctx, userID)
+	if err == sql.ErrNoRows {
+		respondWithJSON(w, http.StatusOK, []RosterInvitationResponse{})
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+
+	rows, err := c.DB.ListPlayerRosterInvitations(ctx, profile.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list invitations: %v", err))
+		return
+	}
+	now := time.Now()
+	response := make([]RosterInvitationResponse, 0, len(rows))
+	for _, row := range rows {
+		invitation := database.RosterInvitation{ID: row.ID, TeamID: row.TeamID, PlayerProfileID: row.PlayerProfileID, Message: row.Message, Status: row.Status, ExpiresAt: row.ExpiresAt, CreatedAt: row.CreatedAt}
+		response = append(response, newRosterInvitationResponse(invitation, row.TeamName, "", now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// acceptInvitation moves the caller onto the inviting team. It has to happen
+// inside one of the season's registration windows, and the team's roster
+// can't be full.
+func (c *Config) acceptInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	invitation, profile, ok := c.invitationForPlayer(w, r, userID)
+	if !ok {
+		return
+	}
+	team, err := c.DB.GetTeam(ctx, invitation.TeamID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+		return
+	}
+	if profile.TeamID.Valid && profile.TeamID.UUID == team.ID {
+		respondWithError(w, http.StatusConflict, "You're already on this team")
+		return
+	}
+
+	season, err := c.teamSeason(ctx, team)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get season: %v", err))
+		return
+	}
+	open, err := c.playerRegistrationOpen(ctx, season, time.Now())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get registration windows: %v", err))
+		return
+	}
+	if !open {
+		respondWithError(w, http.StatusConflict, "Player registration is closed for this season")
+		return
+	}
+	if full, err := c.rosterFull(ctx, team); err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check roster size: %v", err))
+		return
+	} else if full {
+		respondWithError(w, http.StatusConflict, "Roster is full")
+		return
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Invitations can't be accepted right now")
+		return
+	}
+
+	transfer, err := c.transferPlayer(ctx, profile, uuid.NullUUID{UUID: team.ID, Valid: true}, season, uuid.NullUUID{UUID: invitation.ID, Valid: true}, userID, func(q *database.Queries) error {
+		_, err := q.RespondToRosterInvitation(ctx, database.RespondToRosterInvitationParams{ID: invitation.ID, Status: invitationAccepted})
+		return err
+	})
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			respondWithError(w, http.StatusConflict, "Invitation has already been answered")
+		case errors.Is(err, errRosterFull):
+			respondWithError(w, http.StatusConflict, "Roster is full")
+		case errors.Is(err, errPlayerTeamChanged):
+			respondWithError(w, http.StatusConflict, "Your team changed while joining, please try again")
+		default:
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to join team: %v", err))
+		}
+		return
+	}
+	c.respondWithTransfer(w, ctx, http.StatusOK, transfer)
+}
+
+// declineInvitation turns an invitation down
+func (c *Config) declineInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	invitation, _, ok := c.invitationForPlayer(w, r, userID)
+	if !ok {
+		return
+	}
+	c.answerInvitation(w, r, invitation, invitationDeclined)
+}
+
+// cancelInvitation withdraws a pending invitation. Only the team's managers,
+// the league's owner and admins can cancel it.
+func (c *Config) cancelInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	invitation, ok := c.invitationFromRequest(w, r)
+	if !ok {
+		return
+	}
+	canManage, err := c.canManageTeam(r, invitation.TeamID, userID, permissionRoster)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+		return
+	}
+	if !canManage {
+		respondWithError(w, http.StatusForbidden, "Only the team's managers can cancel this invitation")
+		return
+	}
+	if invitation.Status != invitationPending {
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("Invitation has already been %s", invitation.Status))
+		return
+	}
+
+	if _, err := c.DB.RespondToRosterInvitation(ctx, database.RespondToRosterInvitationParams{ID: invitation.ID, Status: invitationCancelled}); err != nil && err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to cancel invitation: %v", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// releasePlayer takes a player off the team. Managers can release players,
+// and players can leave. Releases aren't limited to registration windows.
+func (c *Config) releasePlayer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	playerID, err := uuid.Parse(chi.URLParam(r, "playerId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid player profile ID")
+		return
+	}
+
+	team, ok := c.teamFromRequest(w, r)
+	if !ok {
+		return
+	}
+	profile, err := c.DB.GetPlayerProfile(ctx, playerID)
+	if err != nil && err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return
+	}
+	if err == sql.ErrNoRows || !profile.TeamID.Valid || profile.TeamID.UUID != team.ID {
+		respondWithError(w, http.StatusNotFound, "Player is not on this team")
+		return
+	}
+	if profile.UserID != userID {
+		canManage, err := c.canManageTeam(r, team.ID, userID, permissionRoster)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+			return
+		}
+		if !canManage {
+			respondWithError(w, http.StatusForbidden, "Only the team's managers can release players")
+			return
+		}
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Players can't be released right now")
+		return
+	}
+
+	season, err := c.teamSeason(ctx, team)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get season: %v", err))
+		return
+	}
+	if _, err := c.transferPlayer(ctx, profile, uuid.NullUUID{}, season, uuid.NullUUID{}, userID, nil); err != nil {
+		if errors.Is(err, errPlayerTeamChanged) {
+			respondWithError(w, http.StatusConflict, "Player is no longer on this team")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to release player: %v", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listPlayerTransfers returns a player's team history, newest first
+func (c *Config) listPlayerTransfers(w http.ResponseWriter, r *http.Request) {
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid player profile ID")
+		return
+	}
+	rows, err := c.DB.ListPlayerTransfers(r.Context(), profileID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list transfers: %v", err))
+		return
+	}
+
+	response := make([]PlayerTransferResponse, 0, len(rows))
+	for _, row := range rows {
+		transfer := database.PlayerTransfer{ID: row.ID, FromTeamID: row.FromTeamID, ToTeamID: row.ToTeamID, SeasonID: row.SeasonID, CreatedAt: row.CreatedAt}
+		response = append(response, newPlayerTransferResponse(transfer, row.FromTeamName.String, row.ToTeamName.String))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func (c *Config) getLeagueRosterRules(w http.ResponseWriter, r *http.Request) {
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+	if _, ok := c.fixtureLeague(w, r.Context(), leagueID); !ok {
+		return
+	}
+	limit, err := c.leagueRosterLimit(r.Context(), leagueID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get roster rules: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, RosterRules{MaxPlayers: limit})
+}
+
+// updateLeagueRosterRules sets how many players each team in the league can
+// register. Teams already over a new limit keep their players but can't add
+// more. Only admins and the league's owner can change it.
+func (c *Config) updateLeagueRosterRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+
+	var req RosterRules
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.MaxPlayers != nil && *req.MaxPlayers <= 0 {
+		respondWithError(w, http.StatusBadRequest, "max_players must be positive, or null for no limit")
+		return
+	}
+
+	league, ok := c.fixtureLeague(w, ctx, leagueID)
+	if !ok {
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can change its roster rules")
+		return
+	}
+
+	if req.MaxPlayers == nil {
+		err = c.DB.DeleteLeagueRosterRules(ctx, leagueID)
+	} else {
+		_, err = c.DB.UpsertLeagueRosterRules(ctx, database.UpsertLeagueRosterRulesParams{LeagueID: leagueID, MaxPlayers: *req.MaxPlayers})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save roster rules: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, req)
+}
+
+// transferPlayer moves a player to a team, or off their team when to is
+// null, and records the transfer. also, when set, runs in the same
+// transaction before the move.
+//
+// The team being joined and the player's profile are locked first, so the
+// roster limit is checked against the current count and the transfer records
+// the team the player is really leaving. It fails with errPlayerTeamChanged
+// when the player has moved since profile was read.
+func (c *Config) transferPlayer(ctx context.Context, profile database.PlayerProfile, to uuid.NullUUID, season database.Season, invitationID uuid.NullUUID, userID int32, also func(q *database.Queries) error) (database.PlayerTransfer, error) {
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.PlayerTransfer{}, err
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	var leagueID uuid.NullUUID
+	if to.Valid {
+		if leagueID, err = q.LockTeam(ctx, to.UUID); err != nil {
+			return database.PlayerTransfer{}, err
+		}
+	}
+	from, err := q.LockPlayerTeam(ctx, profile.ID)
+	if err != nil {
+		return database.PlayerTransfer{}, err
+	}
+	if from != profile.TeamID {
+		return database.PlayerTransfer{}, errPlayerTeamChanged
+	}
+	if to.Valid && leagueID.Valid {
+		rules, err := q.GetLeagueRosterRules(ctx, leagueID.UUID)
+		if err != nil && err != sql.ErrNoRows {
+			return database.PlayerTransfer{}, err
+		}
+		if err == nil {
+			count, err := q.CountTeamPlayers(ctx, to)
+			if err != nil {
+				return database.PlayerTransfer{}, err
+			}
+			if count >= int64(rules.MaxPlayers) {
+				return database.PlayerTransfer{}, errRosterFull
+			}
+		}
+	}
+
+	if also != nil {
+		if err := also(q); err != nil {
+			return database.PlayerTransfer{}, err
+		}
+	}
+	if _, err := q.UpdatePlayerTeam(ctx, database.UpdatePlayerTeamParams{ID: profile.ID, TeamID: to}); err != nil {
+		return database.PlayerTransfer{}, err
+	}
+	transfer, err := q.CreatePlayerTransfer(ctx, database.CreatePlayerTransferParams{
+		PlayerProfileID: profile.ID,
+		FromTeamID:      from,
+		ToTeamID:        to,
+		SeasonID:        seasonFilter(season),
+		InvitationID:    invitationID,
+		RecordedBy:      sql.NullInt32{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		return database.PlayerTransfer{}, err
+	}
+	return transfer, tx.Commit()
+}
+
+// answerInvitation records the player's answer to a pending invitation
+func (c *Config) answerInvitation(w http.ResponseWriter, r *http.Request, invitation database.RosterInvitation, status string) {
+	updated, err := c.DB.RespondToRosterInvitation(r.Context(), database.RespondToRosterInvitationParams{ID: invitation.ID, Status: status})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusConflict, "Invitation has already been answered")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to answer invitation: %v", err))
+		return
+	}
+	team, err := c.DB.GetTeam(r.Context(), updated.TeamID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newRosterInvitationResponse(updated, team.Name, "", time.Now()))
+}
+
+// invitationForPlayer loads the invitation in the id URL param and checks it's
+// for the caller's player profile and can still be answered
+func (c *Config) invitationForPlayer(w http.ResponseWriter, r *http.Request, userID int32) (database.RosterInvitation, database.PlayerProfile, bool) {
+	invitation, ok := c.invitationFromRequest(w, r)
+	if !ok {
+		return database.RosterInvitation{}, database.PlayerProfile{}, false
+	}
+	profile, err := c.DB.GetPlayerProfile(r.Context(), invitation.PlayerProfileID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get player profile: %v", err))
+		return database.RosterInvitation{}, database.PlayerProfile{}, false
+	}
+	if profile.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "This invitation is for another player")
+		return database.RosterInvitation{}, database.PlayerProfile{}, false
+	}
+	switch status := invitationStatus(invitation, time.Now()); status {
+	case invitationPending:
+	case invitationExpired:
+		respondWithError(w, http.StatusConflict, "Invitation has expired")
+		return database.RosterInvitation{}, database.PlayerProfile{}, false
+	default:
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("Invitation has already been %s", status))
+		return database.RosterInvitation{}, database.PlayerProfile{}, false
+	}
+	return invitation, profile, true
+}
+
+func (c *Config) invitationFromRequest(w http.ResponseWriter, r *http.Request) (database.RosterInvitation, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid invitation ID")
+		return database.RosterInvitation{}, false
+	}
+	invitation, err := c.DB.GetRosterInvitation(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Invitation not found")
+			return database.RosterInvitation{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get invitation: %v", err))
+		return database.RosterInvitation{}, false
+	}
+	return invitation, true
+}
+
+// teamFromRequest loads the team in the id URL param
+func (c *Config) teamFromRequest(w http.ResponseWriter, r *http.Request) (database.Team, bool) {
+	teamID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid team ID")
+		return database.Team{}, false
+	}
+	team, err := c.DB.GetTeam(r.Context(), teamID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Team not found")
+			return database.Team{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+		return database.Team{}, false
+	}
+	return team, true
+}
+
+// managedTeam loads the team in the id URL param and checks the caller has
+// the permission for it
+func (c *Config) managedTeam(w http.ResponseWriter, r *http.Request, permission string) (database.Team, bool) {
+	team, ok := c.teamFromRequest(w, r)
+	if !ok {
+		return database.Team{}, false
+	}
+	userID, _ := r.Context().Value("user_id").(int32)
+	canManage, err := c.canManageTeam(r, team.ID, userID, permission)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+		return database.Team{}, false
+	}
+	if !canManage {
+		respondWithError(w, http.StatusForbidden, fmt.Sprintf("Only the team's managers can %s", permissionActions[permission]))
+		return database.Team{}, false
+	}
+	return team, true
+}
+
+// canManageTeam is true for admins, its league's owner and the team's
+// managers whose role has the permission
+func (c *Config) canManageTeam(r *http.Request, teamID uuid.UUID, userID int32, permission string) (bool, error) {
+	if role, _ := r.Context().Value("user_role").(string); role == "admin" {
+		return true, nil
+	}
+	return c.DB.CanManageTeam(r.Context(), database.CanManageTeamParams{TeamID: teamID, UserID: userID, Roles: rolesWith(permission)})
+}
+
+// teamSeason is the current season of the team's league, or the zero Season
+// when it has none
+func (c *Config) teamSeason(ctx context.Context, team database.Team) (database.Season, error) {
+	if !team.LeagueID.Valid {
+		return database.Season{}, nil
+	}
+	season, err := c.DB.GetCurrentSeason(ctx, database.GetCurrentSeasonParams{LeagueID: team.LeagueID.UUID, Today: time.Now().UTC()})
+	if err == sql.ErrNoRows {
+		return database.Season{}, nil
+	}
+	return season, err
+}
+
+// playerRegistrationOpen reports whether players can join teams. Seasons
+// without registration windows, and leagues without seasons, are always open.
+func (c *Config) playerRegistrationOpen(ctx context.Context, season database.Season, now time.Time) (bool, error) {
+	if season.ID == uuid.Nil {
+		return true, nil
+	}
+	windows, err := c.DB.ListSeasonRegistrationWindows(ctx, season.ID)
+	if err != nil {
+		return false, err
+	}
+	return inRegistrationWindow(windows, now), nil
+}
+
+func inRegistrationWindow(windows []database.SeasonRegistrationWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, window := range windows {
+		if !now.Before(window.OpensAt) && now.Before(window.ClosesAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// rosterLimit is the most players the team can have, or nil for no limit
+func (c *Config) rosterLimit(ctx context.Context, team database.Team) (*int32, error) {
+	if !team.LeagueID.Valid {
+		return nil, nil
+	}
+	return c.leagueRosterLimit(ctx, team.LeagueID.UUID)
+}
+
+func (c *Config) leagueRosterLimit(ctx context.Context, leagueID uuid.UUID) (*int32, error) {
+	rules, err := c.DB.GetLeagueRosterRules(ctx, leagueID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rules.MaxPlayers, nil
+}
+
+func (c *Config) rosterFull(ctx context.Context, team database.Team) (bool, error) {
+	limit, err := c.rosterLimit(ctx, team)
+	if err != nil || limit == nil {
+		return false, err
+	}
+	count, err := c.DB.CountTeamPlayers(ctx, uuid.NullUUID{UUID: team.ID, Valid: true})
+	if err != nil {
+		return false, err
+	}
+	return count >= int64(*limit), nil
+}
+
+// invitationStatus is the invitation's status, with pending invitations past
+// their expiry reported as expired
+func invitationStatus(invitation database.RosterInvitation, now time.Time) string {
+	if invitation.Status == invitationPending && !now.Before(invitation.ExpiresAt) {
+		return invitationExpired
+	}
+	return invitation.Status
+}
+
+func (c *Config) respondWithTransfer(w http.ResponseWriter, ctx context.Context, status int, transfer database.PlayerTransfer) {
+	names := make(map[uuid.UUID]string, 2)
+	for _, id := range []uuid.NullUUID{transfer.FromTeamID, transfer.ToTeamID} {
+		if !id.Valid {
+			continue
+		}
+		team, err := c.DB.GetTeam(ctx, id.UUID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+			return
+		}
+		names[team.ID] = team.Name
+	}
+	respondWithJSON(w, status, newPlayerTransferResponse(transfer, names[transfer.FromTeamID.UUID], names[transfer.ToTeamID.UUID]))
+}
+
+func newRosterInvitationResponse(invitation database.RosterInvitation, teamName, playerName string, now time.Time) RosterInvitationResponse {
+	return RosterInvitationResponse{
+		ID:              invitation.ID,
+		Team:            FixtureTeam{ID: invitation.TeamID, Name: teamName},
+		PlayerProfileID: invitation.PlayerProfileID,
+		PlayerName:      playerName,
+		Message:         invitation.Message.String,
+		Status:          invitationStatus(invitation, now),
+		ExpiresAt:       invitation.ExpiresAt,
+		CreatedAt:       invitation.CreatedAt,
+	}
+}
+
+func newPlayerTransferResponse(transfer database.PlayerTransfer, fromName, toName string) PlayerTransferResponse {
+	response := PlayerTransferResponse{ID: transfer.ID, CreatedAt: transfer.CreatedAt}
+	if transfer.FromTeamID.Valid {
+		response.FromTeam = &FixtureTeam{ID: transfer.FromTeamID.UUID, Name: fromName}
+	}
+	if transfer.ToTeamID.Valid {
+		response.ToTeam = &FixtureTeam{ID: transfer.ToTeamID.UUID, Name: toName}
+	}
+	if transfer.SeasonID.Valid {
+		response.SeasonID = &transfer.SeasonID.UUID
+	}
+	return response
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestInRegistrationWindow(t *testing.T) {
+	opens := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	summer := database.SeasonRegistrationWindow{OpensAt: opens, ClosesAt: opens.AddDate(0, 1, 0)}
+	winter := database.SeasonRegistrationWindow{OpensAt: opens.AddDate(0, 5, 0), ClosesAt: opens.AddDate(0, 6, 0)}
+
+	tests := []struct {
+		name    string
+		windows []database.SeasonRegistrationWindow
+		now     time.Time
+		want    bool
+	}{
+		{"no windows", nil, opens, true},
+		{"as it opens", []database.SeasonRegistrationWindow{summer}, opens, true},
+		{"as it closes", []database.SeasonRegistrationWindow{summer}, summer.ClosesAt, false},
+		{"between windows", []database.SeasonRegistrationWindow{summer, winter}, opens.AddDate(0, 3, 0), false},
+		{"in the second window", []database.SeasonRegistrationWindow{summer, winter}, winter.OpensAt.Add(time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inRegistrationWindow(tt.windows, tt.now); got != tt.want {
+				t.Errorf("inRegistrationWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvitationStatus(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		invitation database.RosterInvitation
+		want       string
+	}{
+		{"pending", database.RosterInvitation{Status: invitationPending, ExpiresAt: now.Add(time.Hour)}, invitationPending},
+		{"expired", database.RosterInvitation{Status: invitationPending, ExpiresAt: now}, invitationExpired},
+		{"accepted after expiry", database.RosterInvitation{Status: invitationAccepted, ExpiresAt: now.Add(-time.Hour)}, invitationAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invitationStatus(tt.invitation, now); got != tt.want {
+				t.Errorf("invitationStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvitePlayerValidation(t *testing.T) {
+	for _, body := range []string{`not json`, `{"message":"Join us"}`} {
+		config := &Config{}
+		req := authedRequest("POST", "/teams/x/invitations", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.invitePlayer(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestAcceptInvitationRequiresAuth(t *testing.T) {
+	config := &Config{}
+	rec := httptest.NewRecorder()
+
+	config.acceptInvitation(rec, httptest.NewRequest("POST", "/roster-invitations/x/accept", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestUpdateLeagueRosterRulesValidation(t *testing.T) {
+	for _, body := range []string{`not json`, `{"max_players":0}`, `{"max_players":-5}`} {
+		config := &Config{}
+		req := authedRequest("PUT", "/leagues/x/roster/rules", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.updateLeagueRosterRules(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestCreateRegistrationWindowValidation(t *testing.T) {
+	for _, body := range []string{
+		`{"opens_at":"2025-08-01T00:00:00Z"}`,
+		`{"opens_at":"2025-08-01T00:00:00Z","closes_at":"2025-07-01T00:00:00Z"}`,
+	} {
+		config := &Config{}
+		req := authedRequest("POST", "/seasons/x/registration-windows", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.New().String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		rec := httptest.NewRecorder()
+
+		config.createRegistrationWindow(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestCreatePlayerProfileRejectsTeam(t *testing.T) {
+	svc := &PlayerProfileService{}
+	body := `{"team_id":"` + uuid.New().String() + `","position":"ST"}`
+	rec := httptest.NewRecorder()
+
+	svc.CreatePlayerProfile(rec, authedRequest("POST", "/player-profiles", body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,351 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/schedule"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+type ScheduleRequest struct {
+	DoubleRoundRobin bool            `json:"double_round_robin"`
+	StartDate        time.Time       `json:"start_date"`     // Kickoff times are in its UTC offset
+	EndDate          *time.Time      `json:"end_date"`       // Defaults to the season's end date
+	BlackoutDates    []string        `json:"blackout_dates"` // YYYY-MM-DD
+	Venues           []ScheduleVenue `json:"venues"`
+	Regenerate       bool            `json:"regenerate"` // Replace unplayed fixtures
+	SeasonID         *uuid.UUID      `json:"season_id"`  // Defaults to the league's current season
+}
+
+type ScheduleVenue struct {
+	Name             string         `json:"name"`
+	Pitches          int            `json:"pitches"`
+	Slots            []ScheduleSlot `json:"slots"`
+	UnavailableDates []string       `json:"unavailable_dates"` // YYYY-MM-DD
+}
+
+type ScheduleSlot struct {
+	Day     string `json:"day"`     // monday to sunday
+	Kickoff string `json:"kickoff"` // HH:MM
+}
+
+type ScheduledFixture struct {
+	Round     int         `json:"round"`
+	HomeTeam  FixtureTeam `json:"home_team"`
+	AwayTeam  FixtureTeam `json:"away_team"`
+	MatchDate time.Time   `json:"match_date"`
+	Venue     string      `json:"venue,omitempty"`
+}
+
+type SchedulePreviewResponse struct {
+	Fixtures []ScheduledFixture `json:"fixtures"`
+	Kept     int                `json:"kept"`     // Played fixtures left as they are
+	Replaced int                `json:"replaced"` // Unplayed fixtures the schedule replaces
+}
+
+type ScheduleResponse struct {
+	Fixtures []FixtureResponse `json:"fixtures"`
+	Kept     int               `json:"kept"`
+	Replaced int               `json:"replaced"`
+}
+
+// leagueSchedule is a generated schedule and what it replaces
+type leagueSchedule struct {
+	leagueID   uuid.UUID
+	season     database.Season // Zero for leagues without seasons
+	regenerate bool
+	fixtures   []schedule.Fixture
+	teams      map[uuid.UUID]database.Team
+	kept       int
+	replaced   int
+}
+
+// previewLeagueSchedule shows the schedule generateLeagueSchedule would create
+func (c *Config) previewLeagueSchedule(w http.ResponseWriter, r *http.Request) {
+	plan, ok := c.planLeagueSchedule(w, r)
+	if !ok {
+		return
+	}
+
+	response := SchedulePreviewResponse{Fixtures: make([]ScheduledFixture, 0, len(plan.fixtures)), Kept: plan.kept, Replaced: plan.replaced}
+	for _, f := range plan.fixtures {
+		home, away := plan.teams[f.Home], plan.teams[f.Away]
+		response.Fixtures = append(response.Fixtures, ScheduledFixture{
+			Round:     f.Round,
+			HomeTeam:  FixtureTeam{ID: home.ID, Name: home.Name},
+			AwayTeam:  FixtureTeam{ID: away.ID, Name: away.Name},
+			MatchDate: f.Kickoff,
+			Venue:     plan.venue(f),
+		})
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// generateLeagueSchedule creates a round-robin schedule for the teams
+// registered for the season. A league that already has unplayed fixtures needs regenerate set,
+// which replaces them. Played fixtures are always kept and their pairings
+// aren't scheduled again.
+func (c *Config) generateLeagueSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	plan, ok := c.planLeagueSchedule(w, r)
+	if !ok {
+		return
+	}
+	if plan.replaced > 0 && !plan.regenerate {
+		respondWithError(w, http.StatusConflict, "League already has unplayed fixtures, set regenerate to replace them")
+		return
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Schedules can't be generated right now")
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(int32)
+	matches, err := c.saveLeagueSchedule(ctx, userID, plan)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save schedule: %v", err))
+		return
+	}
+
+	names := make(map[uuid.UUID]string, len(plan.teams))
+	for id, team := range plan.teams {
+		names[id] = team.Name
+	}
+	response := ScheduleResponse{Fixtures: make([]FixtureResponse, 0, len(matches)), Kept: plan.kept, Replaced: plan.replaced}
+	for _, match := range matches {
+		response.Fixtures = append(response.Fixtures, newFixtureResponse(match, names, nil))
+	}
+	respondWithJSON(w, http.StatusCreated, response)
+}
+
+func (c *Config) saveLeagueSchedule(ctx context.Context, userID int32, plan leagueSchedule) ([]database.Match, error) {
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	if _, err := q.DeleteUnplayedLeagueFixtures(ctx, database.DeleteUnplayedLeagueFixturesParams{
+		LeagueID: uuid.NullUUID{UUID: plan.leagueID, Valid: true},
+		SeasonID: seasonFilter(plan.season),
+	}); err != nil {
+		return nil, err
+	}
+	matches := make([]database.Match, 0, len(plan.fixtures))
+	for _, f := range plan.fixtures {
+		venue := plan.venue(f)
+		match, err := q.CreateFixture(ctx, database.CreateFixtureParams{
+			LeagueID:   uuid.NullUUID{UUID: plan.leagueID, Valid: true},
+			HomeTeamID: uuid.NullUUID{UUID: f.Home, Valid: true},
+			AwayTeamID: uuid.NullUUID{UUID: f.Away, Valid: true},
+			MatchDate:  f.Kickoff,
+			Venue:      sql.NullString{String: venue, Valid: venue != ""},
+			CreatedBy:  sql.NullInt32{Int32: userID, Valid: userID != 0},
+			Round:      sql.NullInt32{Int32: int32(f.Round), Valid: true},
+			SeasonID:   seasonFilter(plan.season),
+		})
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	return matches, tx.Commit()
+}
+
+// planLeagueSchedule checks the caller organises the league and generates its
+// schedule. It responds with an error and returns false when it can't.
+func (c *Config) planLeagueSchedule(w http.ResponseWriter, r *http.Request) (leagueSchedule, bool) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return leagueSchedule{}, false
+	}
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return leagueSchedule{}, false
+	}
+
+	var req ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return leagueSchedule{}, false
+	}
+	opts, err := scheduleOptions(req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return leagueSchedule{}, false
+	}
+
+	league, ok := c.fixtureLeague(w, ctx, leagueID)
+	if !ok {
+		return leagueSchedule{}, false
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can schedule its season")
+		return leagueSchedule{}, false
+	}
+
+	season, ok := c.leagueSeason(w, ctx, leagueID, req.SeasonID)
+	if !ok {
+		return leagueSchedule{}, false
+	}
+	if season.Archived {
+		respondWithError(w, http.StatusConflict, "Season is archived")
+		return leagueSchedule{}, false
+	}
+	if season.ID != uuid.Nil && req.EndDate == nil {
+		// Leave the whole of the last day for kickoffs
+		opts.End = time.Date(season.EndDate.Year(), season.EndDate.Month(), season.EndDate.Day()+1, 0, 0, 0, 0, opts.Start.Location())
+	}
+
+	teams, err := c.seasonTeams(ctx, leagueID, season)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get teams: %v", err))
+		return leagueSchedule{}, false
+	}
+	existing, err := c.DB.ListLeagueFixtures(ctx, database.ListLeagueFixturesParams{
+		LeagueID: uuid.NullUUID{UUID: leagueID, Valid: true},
+		SeasonID: seasonFilter(season),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list fixtures: %v", err))
+		return leagueSchedule{}, false
+	}
+
+	plan := leagueSchedule{leagueID: leagueID, season: season, regenerate: req.Regenerate, teams: make(map[uuid.UUID]database.Team, len(teams))}
+	for _, team := range teams {
+		plan.teams[team.ID] = team
+		opts.Teams = append(opts.Teams, team.ID)
+	}
+	for _, match := range existing {
+		if match.Status != database.MatchStatusFinished && match.Status != database.MatchStatusLive {
+			plan.replaced++
+			continue
+		}
+		plan.kept++
+		opts.Played = append(opts.Played, schedule.Fixture{
+			Pairing: schedule.Pairing{Home: match.HomeTeamID.UUID, Away: match.AwayTeamID.UUID},
+			Kickoff: match.MatchDate,
+		})
+	}
+	if len(opts.Venues) == 0 {
+		// Without venues every match kicks off at the start time on the
+		// start date's weekday, at the home team's ground
+		opts.Venues = []schedule.Venue{{
+			Slots:   []schedule.Slot{{Weekday: opts.Start.Weekday(), Kickoff: opts.Start.Sub(startOfDay(opts.Start))}},
+			Pitches: len(teams),
+		}}
+	}
+
+	plan.fixtures, err = schedule.Generate(opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, schedule.ErrTooFewTeams), errors.Is(err, schedule.ErrNoSlots), errors.Is(err, schedule.ErrDoesNotFit):
+			respondWithError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Can't schedule the season: %v", err))
+		default:
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to generate schedule: %v", err))
+		}
+		return leagueSchedule{}, false
+	}
+	return plan, true
+}
+
+// venue is where a fixture is played, the home team's ground when the
+// schedule didn't say
+func (s leagueSchedule) venue(f schedule.Fixture) string {
+	if f.Venue != "" {
+		return f.Venue
+	}
+	return s.teams[f.Home].Stadium.String
+}
+
+// scheduleOptions validates a schedule request. Dates and kickoff times are
+// in the start date's location. Teams and played fixtures are added later.
+func scheduleOptions(req ScheduleRequest) (schedule.Options, error) {
+	if req.StartDate.IsZero() {
+		return schedule.Options{}, errors.New("start_date is required")
+	}
+	loc := req.StartDate.Location()
+	opts := schedule.Options{Double: req.DoubleRoundRobin, Start: req.StartDate}
+	if req.EndDate != nil {
+		if !req.EndDate.After(req.StartDate) {
+			return schedule.Options{}, errors.New("end_date must be after start_date")
+		}
+		opts.End = *req.EndDate
+	}
+
+	var err error
+	if opts.Blackouts, err = parseScheduleDates(req.BlackoutDates, loc); err != nil {
+		return schedule.Options{}, err
+	}
+
+	for _, v := range req.Venues {
+		if strings.TrimSpace(v.Name) == "" || len(v.Slots) == 0 {
+			return schedule.Options{}, errors.New("Venues need a name and at least one slot")
+		}
+		if v.Pitches < 0 {
+			return schedule.Options{}, errors.New("pitches can't be negative")
+		}
+		venue := schedule.Venue{Name: v.Name, Pitches: v.Pitches}
+		for _, s := range v.Slots {
+			weekday, ok := weekdays[strings.ToLower(s.Day)]
+			if !ok {
+				return schedule.Options{}, fmt.Errorf("Invalid day %q", s.Day)
+			}
+			kickoff, err := time.Parse("15:04", s.Kickoff)
+			if err != nil {
+				return schedule.Options{}, fmt.Errorf("Invalid kickoff %q, use HH:MM", s.Kickoff)
+			}
+			venue.Slots = append(venue.Slots, schedule.Slot{
+				Weekday: weekday,
+				Kickoff: kickoff.Sub(startOfDay(kickoff)),
+			})
+		}
+		if venue.Unavailable, err = parseScheduleDates(v.UnavailableDates, loc); err != nil {
+			return schedule.Options{}, err
+		}
+		opts.Venues = append(opts.Venues, venue)
+	}
+	return opts, nil
+}
+
+func parseScheduleDates(dates []string, loc *time.Location) ([]time.Time, error) {
+	parsed := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		t, err := time.ParseInLocation(time.DateOnly, date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid date %q, use YYYY-MM-DD", date)
+		}
+		parsed = append(parsed, t)
+	}
+	return parsed, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+func TestScheduleOptions(t *testing.T) {
+	start := time.Date(2025, 9, 6, 10, 0, 0, 0, time.FixedZone("BST", 3600))
+	req := ScheduleRequest{
+		DoubleRoundRobin: true,
+		StartDate:        start,
+		BlackoutDates:    []string{"2025-12-27"},
+		Venues: []ScheduleVenue{{
+			Name:             "Hackney Marshes",
+			Pitches:          3,
+			Slots:            []ScheduleSlot{{Day: "Saturday", Kickoff: "09:30"}, {Day: "sunday", Kickoff: "14:00"}},
+			UnavailableDates: []string{"2025-10-11"},
+		}},
+	}
+
+	opts, err := scheduleOptions(req)
+	if err != nil {
+		t.Fatalf("scheduleOptions() error = %v", err)
+	}
+	if !opts.Double || !opts.Start.Equal(start) {
+		t.Errorf("Expected a double round-robin from %v, got %+v", start, opts)
+	}
+	if len(opts.Blackouts) != 1 || opts.Blackouts[0].Location() != start.Location() {
+		t.Errorf("Expected one blackout in the start date's location, got %v", opts.Blackouts)
+	}
+	venue := opts.Venues[0]
+	if venue.Pitches != 3 || len(venue.Slots) != 2 || len(venue.Unavailable) != 1 {
+		t.Fatalf("Unexpected venue %+v", venue)
+	}
+	if venue.Slots[0].Weekday != time.Saturday || venue.Slots[0].Kickoff != 9*time.Hour+30*time.Minute {
+		t.Errorf("Unexpected first slot %+v", venue.Slots[0])
+	}
+}
+
+func TestScheduleOptionsInvalid(t *testing.T) {
+	start := time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
+	before := start.AddDate(0, 0, -1)
+	slot := []ScheduleSlot{{Day: "saturday", Kickoff: "10:00"}}
+
+	tests := []struct {
+		name string
+		req  ScheduleRequest
+	}{
+		{"no start date", ScheduleRequest{}},
+		{"end before start", ScheduleRequest{StartDate: start, EndDate: &before}},
+		{"bad blackout", ScheduleRequest{StartDate: start, BlackoutDates: []string{"27/12/2025"}}},
+		{"venue without a name", ScheduleRequest{StartDate: start, Venues: []ScheduleVenue{{Slots: slot}}}},
+		{"venue without slots", ScheduleRequest{StartDate: start, Venues: []ScheduleVenue{{Name: "Park"}}}},
+		{"bad day", ScheduleRequest{StartDate: start, Venues: []ScheduleVenue{{Name: "Park", Slots: []ScheduleSlot{{Day: "caturday", Kickoff: "10:00"}}}}}},
+		{"bad kickoff", ScheduleRequest{StartDate: start, Venues: []ScheduleVenue{{Name: "Park", Slots: []ScheduleSlot{{Day: "saturday", Kickoff: "10am"}}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := scheduleOptions(tt.req); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestPreviewLeagueScheduleInvalidRequest(t *testing.T) {
+	config := &Config{}
+	req := authedRequest("POST", "/leagues/abc/schedule/preview", `{}`)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "00000000-0000-0000-0000-000000000001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	config.previewLeagueSchedule(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,476 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/achievements"
+	"github.com/ArronJLinton/fucci-api/internal/cache"
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/predictions"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+	"github.com/go-chi/chi"
+)
+
+const (
+	// maxPredictedGoals rejects joke scorelines
+	maxPredictedGoals = 20
+	// scorePredictionBatchSize is how many fixtures are scored per sweep
+	scorePredictionBatchSize = 50
+	// minMatchDuration is how long after kickoff a fixture is first checked for a result
+	minMatchDuration = 105 * time.Minute
+)
+
+// openPredictionStatuses are the fixture statuses at which predictions are accepted
+var openPredictionStatuses = map[string]bool{
+	"NS":  true, // Not started
+	"TBD": true, // Kickoff time to be defined
+}
+
+var (
+	errPredictionsLocked = errors.New("predictions are locked at kickoff")
+	errFixtureNotFound   = errors.New("fixture not found")
+)
+
+type CreateScorePredictionRequest struct {
+	HomeGoals *int32 `json:"home_goals"`
+	AwayGoals *int32 `json:"away_goals"`
+}
+
+type ScorePredictionResponse struct {
+	ID        int32      `json:"id"`
+	MatchID   string     `json:"match_id"`
+	HomeGoals int32      `json:"home_goals"`
+	AwayGoals int32      `json:"away_goals"`
+	KickoffAt time.Time  `json:"kickoff_at"`
+	Points    *int32     `json:"points,omitempty"`
+	Outcome   string     `json:"outcome,omitempty"`
+	ScoredAt  *time.Time `json:"scored_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type ScorelineShare struct {
+	HomeGoals   int32   `json:"home_goals"`
+	AwayGoals   int32   `json:"away_goals"`
+	Predictions int64   `json:"predictions"`
+	Percentage  float64 `json:"percentage"`
+}
+
+// ScorePredictionDistribution is the crowd's view of a fixture
+type ScorePredictionDistribution struct {
+	MatchID    string           `json:"match_id"`
+	Total      int64            `json:"total"`
+	HomeWin    float64          `json:"home_win"` // Percentage of predictions
+	Draw       float64          `json:"draw"`
+	AwayWin    float64          `json:"away_win"`
+	Scorelines []ScorelineShare `json:"scorelines"`
+}
+
+// fixtureSummary is what predictions need to know about an API-Football fixture
+type fixtureSummary struct {
+	ID       int
+	LeagueID int
+	Kickoff  time.Time
+	Status   string
+	FullTime predictions.Score // Score after 90 minutes
+}
+
+// locked reports whether predictions for the fixture are closed
+func (f fixtureSummary) locked(now time.Time) bool {
+	return !now.Before(f.Kickoff) || !openPredictionStatuses[f.Status]
+}
+
+// fetchFixture looks up a fixture by ID, caching it briefly so a burst of
+// predictions doesn't hit the football API for every request
+func (c *Config) fetchFixture(ctx context.Context, matchID string) (*fixtureSummary, error) {
+	cacheKey := fmt.Sprintf("fixture:%s", matchID)
+	if c.Cache != nil {
+		exists, err := c.Cache.Exists(ctx, cacheKey)
+		if err != nil {
+			log.Printf("Cache check error: %v\n", err)
+		} else if exists {
+			var cached fixtureSummary
+			if err := c.Cache.Get(ctx, cacheKey, &cached); err == nil {
+				return &cached, nil
+			}
+		}
+	}
+
+	baseURL := c.APIFootballBaseURL
+	if baseURL == "" {
+		baseURL = "https://api-football-v1.p.rapidapi.com/v3"
+	}
+	headers := map[string]string{
+		"Content-Type":   "application/json",
+		"x-rapidapi-key": c.FootballAPIKey,
+	}
+
+	resp, err := HTTPRequest("GET", fmt.Sprintf("%s/fixtures?id=%s", baseURL, matchID), headers, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching fixture: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixture response: %w", err)
+	}
+
+	var data GetMatchesAPIResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("error parsing fixture response: %w", err)
+	}
+	if len(data.Response) == 0 {
+		return nil, errFixtureNotFound
+	}
+
+	match := data.Response[0]
+	fixture := &fixtureSummary{
+		ID:       match.Fixture.ID,
+		LeagueID: match.League.ID,
+		Kickoff:  time.Unix(int64(match.Fixture.Timestamp), 0).UTC(),
+		Status:   match.Fixture.Status.Short,
+		FullTime: predictions.Score{
+			Home: int32(match.Score.Fulltime.Home),
+			Away: int32(match.Score.Fulltime.Away),
+		},
+	}
+
+	c.trackFixture(ctx, events.MatchStatusChanged{
+		MatchID:   matchID,
+		LeagueID:  int32(match.League.ID),
+		HomeTeam:  match.Teams.Home.Name,
+		AwayTeam:  match.Teams.Away.Name,
+		ToStatus:  fixture.Status,
+		HomeGoals: int32(match.Goals.Home),
+		AwayGoals: int32(match.Goals.Away),
+	})
+	c.recordFixtureTeams(ctx, matchID, match.Teams.Home.Name, match.Teams.Away.Name)
+
+	if c.Cache != nil {
+		// Kickoff times move, so unfinished fixtures are only cached briefly
+		ttl := cache.LiveMatchTTL
+		if resolvableMatchStatuses[fixture.Status] {
+			ttl = cache.TeamInfoTTL
+		}
+		if err := c.Cache.Set(ctx, cacheKey, fixture, ttl); err != nil {
+			log.Printf("Cache set error: %v\n", err)
+		}
+	}
+	return fixture, nil
+}
+
+// recordFixtureTeams remembers who plays in an API-Football fixture so follows
+// of either team can be matched to the fixture's debates
+func (c *Config) recordFixtureTeams(ctx context.Context, matchID, homeTeam, awayTeam string) {
+	if c.DB == nil || homeTeam == "" || awayTeam == "" {
+		return
+	}
+	err := c.DB.UpsertFixtureTeams(ctx, database.UpsertFixtureTeamsParams{
+		MatchID:  matchID,
+		HomeTeam: homeTeam,
+		AwayTeam: awayTeam,
+	})
+	if err != nil {
+		log.Printf("Failed to record teams for fixture %s: %v\n", matchID, err)
+	}
+}
+
+func newScorePredictionResponse(prediction database.ScorePrediction) ScorePredictionResponse {
+	response := ScorePredictionResponse{
+		ID:        prediction.ID,
+		MatchID:   prediction.MatchID,
+		HomeGoals: prediction.HomeGoals,
+		AwayGoals: prediction.AwayGoals,
+		KickoffAt: prediction.KickoffAt,
+		Outcome:   prediction.Outcome.String,
+		UpdatedAt: prediction.UpdatedAt,
+	}
+	if prediction.Points.Valid {
+		response.Points = &prediction.Points.Int32
+	}
+	if prediction.ScoredAt.Valid {
+		response.ScoredAt = &prediction.ScoredAt.Time
+	}
+	return response
+}
+
+// createScorePrediction records or updates the user's scoreline for a fixture until kickoff
+func (c *Config) createScorePrediction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	matchID := chi.URLParam(r, "id")
+	if _, err := strconv.Atoi(matchID); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid match ID")
+		return
+	}
+
+	var req CreateScorePredictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.HomeGoals == nil || req.AwayGoals == nil {
+		respondWithError(w, http.StatusBadRequest, "home_goals and away_goals are required")
+		return
+	}
+	if *req.HomeGoals < 0 || *req.AwayGoals < 0 || *req.HomeGoals > maxPredictedGoals || *req.AwayGoals > maxPredictedGoals {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("goals must be between 0 and %d", maxPredictedGoals))
+		return
+	}
+
+	fixture, err := c.fetchFixture(ctx, matchID)
+	if err != nil {
+		if errors.Is(err, errFixtureNotFound) {
+			respondWithError(w, http.StatusNotFound, "Match not found")
+			return
+		}
+		respondWithError(w, http.StatusBadGateway, fmt.Sprintf("Failed to get match: %v", err))
+		return
+	}
+	if fixture.locked(time.Now()) {
+		respondWithError(w, http.StatusConflict, errPredictionsLocked.Error())
+		return
+	}
+
+	prediction, err := c.DB.UpsertScorePrediction(ctx, database.UpsertScorePredictionParams{
+		UserID:    userID,
+		MatchID:   matchID,
+		HomeGoals: *req.HomeGoals,
+		AwayGoals: *req.AwayGoals,
+		LeagueID:  sql.NullInt32{Int32: int32(fixture.LeagueID), Valid: fixture.LeagueID != 0},
+		KickoffAt: fixture.Kickoff,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Nothing is written once kickoff has passed or the prediction has been scored
+			respondWithError(w, http.StatusConflict, errPredictionsLocked.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save prediction: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newScorePredictionResponse(prediction))
+}
+
+// getMyScorePrediction returns the authenticated user's prediction for a fixture
+func (c *Config) getMyScorePrediction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	prediction, err := c.DB.GetUserScorePrediction(ctx, database.GetUserScorePredictionParams{
+		UserID:  userID,
+		MatchID: chi.URLParam(r, "id"),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "No prediction for this match")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get prediction: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newScorePredictionResponse(prediction))
+}
+
+// getScorePredictionDistribution returns how the crowd predicted a fixture
+func (c *Config) getScorePredictionDistribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	matchID := chi.URLParam(r, "id")
+	rows, err := c.DB.GetScorePredictionDistribution(ctx, matchID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get predictions: %v", err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, buildPredictionDistribution(matchID, rows))
+}
+
+// buildPredictionDistribution turns per-scoreline counts into percentages,
+// including the share of fans backing each result
+func buildPredictionDistribution(matchID string, rows []database.GetScorePredictionDistributionRow) ScorePredictionDistribution {
+	distribution := ScorePredictionDistribution{
+		MatchID:    matchID,
+		Scorelines: make([]ScorelineShare, 0, len(rows)),
+	}
+	for _, row := range rows {
+		distribution.Total += row.Predictions
+	}
+	if distribution.Total == 0 {
+		return distribution
+	}
+
+	var homeWins, draws, awayWins int64
+	for _, row := range rows {
+		switch (predictions.Score{Home: row.HomeGoals, Away: row.AwayGoals}).Result() {
+		case 1:
+			homeWins += row.Predictions
+		case -1:
+			awayWins += row.Predictions
+		default:
+			draws += row.Predictions
+		}
+		distribution.Scorelines = append(distribution.Scorelines, ScorelineShare{
+			HomeGoals:   row.HomeGoals,
+			AwayGoals:   row.AwayGoals,
+			Predictions: row.Predictions,
+			Percentage:  percentage(row.Predictions, distribution.Total),
+		})
+	}
+	distribution.HomeWin = percentage(homeWins, distribution.Total)
+	distribution.Draw = percentage(draws, distribution.Total)
+	distribution.AwayWin = percentage(awayWins, distribution.Total)
+	return distribution
+}
+
+func percentage(part, total int64) float64 {
+	return math.Round(float64(part)/float64(total)*1000) / 10
+}
+
+// scorePredictionRules returns the configured scoring rules, or the defaults
+func (c *Config) scorePredictionRules() predictions.Rules {
+	if c.ScorePredictionRules.IsZero() {
+		return predictions.DefaultRules
+	}
+	return c.ScorePredictionRules
+}
+
+// scoreMatchPredictions scores every prediction for a finished fixture and awards
+// leaderboard points. Points are recorded once per prediction, so it's safe to re-run.
+func (c *Config) scoreMatchPredictions(ctx context.Context, matchID string) (scored int, awarded int, err error) {
+	fixture, err := c.fetchFixture(ctx, matchID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !resolvableMatchStatuses[fixture.Status] {
+		return 0, 0, errMatchNotFinished
+	}
+
+	all, err := c.DB.ListScorePredictionsByMatch(ctx, matchID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rules := c.scorePredictionRules()
+	for _, prediction := range all {
+		outcome, points := rules.Evaluate(predictions.Score{Home: prediction.HomeGoals, Away: prediction.AwayGoals}, fixture.FullTime)
+		if err := c.DB.ScoreScorePrediction(ctx, database.ScoreScorePredictionParams{
+			ID:      prediction.ID,
+			Points:  sql.NullInt32{Int32: points, Valid: true},
+			Outcome: sql.NullString{String: outcome, Valid: true},
+		}); err != nil {
+			return scored, awarded, err
+		}
+		scored++
+
+		if points <= 0 {
+			continue
+		}
+		created, err := c.awardPoints(ctx, database.CreatePointAwardParams{
+			UserID:     prediction.UserID,
+			SourceType: pointSourceScorePrediction,
+			SourceID:   strconv.Itoa(int(prediction.ID)),
+			Points:     points,
+			LeagueID:   prediction.LeagueID,
+		})
+		if err != nil {
+			return scored, awarded, err
+		}
+		if created {
+			awarded++
+			c.recordAchievementEvent(ctx, achievements.EventPredictionRewarded, prediction.UserID)
+		}
+	}
+
+	fmt.Printf("Scored %d predictions for match %s (%d-%d), %d awarded\n", scored, matchID, fixture.FullTime.Home, fixture.FullTime.Away, awarded)
+	return scored, awarded, nil
+}
+
+// scoreMatchPredictionsHandler lets an admin score a fixture without waiting for the sweep
+func (c *Config) scoreMatchPredictionsHandler(w http.ResponseWriter, r *http.Request) {
+	scored, awarded, err := c.scoreMatchPredictions(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, errMatchNotFinished):
+			respondWithError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, errFixtureNotFound):
+			respondWithError(w, http.StatusNotFound, "Match not found")
+		default:
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to score predictions: %v", err))
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"scored":  scored,
+		"awarded": awarded,
+	})
+}
+
+// ScorePredictionScorer scores predictions for fixtures that have finished
+type ScorePredictionScorer struct {
+	config *Config
+}
+
+func NewScorePredictionScorer(c Config) *ScorePredictionScorer {
+	return &ScorePredictionScorer{config: &c}
+}
+
+// Run scores finished fixtures every interval until the context is cancelled
+func (s *ScorePredictionScorer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.ScoreOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ScoreOnce scores every fixture with unscored predictions that could have finished
+func (s *ScorePredictionScorer) ScoreOnce(ctx context.Context) {
+	c := s.config
+
+	matchIDs, err := c.DB.ListUnscoredPredictionMatches(ctx, database.ListUnscoredPredictionMatchesParams{
+		Before:     time.Now().Add(-minMatchDuration),
+		MaxResults: scorePredictionBatchSize,
+	})
+	if err != nil {
+		log.Printf("Failed to list unscored prediction matches: %v\n", err)
+		return
+	}
+
+	for _, matchID := range matchIDs {
+		if _, _, err := c.scoreMatchPredictions(ctx, matchID); err != nil && !errors.Is(err, errMatchNotFinished) {
+			log.Printf("Failed to score predictions for match %s: %v\n", matchID, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+)
+
+func TestFixtureSummaryLocked(t *testing.T) {
+	kickoff := time.Date(2025, 10, 25, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		status string
+		now    time.Time
+		want   bool
+	}{
+		{"NS", kickoff.Add(-time.Hour), false},
+		{"TBD", kickoff.Add(-time.Hour), false},
+		{"NS", kickoff, true},
+		{"NS", kickoff.Add(time.Minute), true},
+		{"1H", kickoff.Add(-time.Minute), true},
+		{"PST", kickoff.Add(-time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		fixture := fixtureSummary{Kickoff: kickoff, Status: tt.status}
+		if got := fixture.locked(tt.now); got != tt.want {
+			t.Errorf("locked(%s at %v) = %v, want %v", tt.status, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestBuildPredictionDistribution(t *testing.T) {
+	rows := []database.GetScorePredictionDistributionRow{
+		{HomeGoals: 2, AwayGoals: 1, Predictions: 5},
+		{HomeGoals: 1, AwayGoals: 1, Predictions: 3},
+		{HomeGoals: 0, AwayGoals: 2, Predictions: 1},
+		{HomeGoals: 3, AwayGoals: 0, Predictions: 1},
+	}
+
+	distribution := buildPredictionDistribution("1035", rows)
+
+	if distribution.Total != 10 {
+		t.Errorf("Expected total 10, got %d", distribution.Total)
+	}
+	if distribution.HomeWin != 60 || distribution.Draw != 30 || distribution.AwayWin != 10 {
+		t.Errorf("Expected 60/30/10 split, got %v/%v/%v", distribution.HomeWin, distribution.Draw, distribution.AwayWin)
+	}
+	if len(distribution.Scorelines) != 4 || distribution.Scorelines[0].Percentage != 50 {
+		t.Errorf("Expected 2-1 to have 50%% of predictions, got %+v", distribution.Scorelines)
+	}
+
+	empty := buildPredictionDistribution("1035", nil)
+	if empty.Total != 0 || empty.Scorelines == nil {
+		t.Errorf("Expected an empty distribution, got %+v", empty)
+	}
+}
+
+func TestCreateScorePredictionRequiresAuth(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("POST", "/futbol/matches/1035/predictions", strings.NewReader(`{"home_goals":2,"away_goals":1}`))
+	rec := httptest.NewRecorder()
+
+	config.createScorePrediction(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func scorePredictionRequest(matchID, body string) *http.Request {
+	req := httptest.NewRequest("POST", "/futbol/matches/"+matchID+"/predictions", strings.NewReader(body))
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", matchID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	ctx = context.WithValue(ctx, "user_id", int32(7))
+	return req.WithContext(ctx)
+}
+
+func TestCreateScorePredictionValidatesGoals(t *testing.T) {
+	config := &Config{}
+
+	for _, body := range []string{`{"home_goals":2}`, `{"home_goals":-1,"away_goals":0}`, `{"home_goals":21,"away_goals":0}`} {
+		rec := httptest.NewRecorder()
+		config.createScorePrediction(rec, scorePredictionRequest("1035", body))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestCreateScorePredictionLockedAtKickoff(t *testing.T) {
+	kickoff := time.Now().Add(-10 * time.Minute).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"results":1,"response":[{"fixture":{"id":1035,"timestamp":%d,"status":{"short":"1H"}},"league":{"id":39}}]}`, kickoff)
+	}))
+	defer server.Close()
+
+	config := &Config{APIFootballBaseURL: server.URL}
+	rec := httptest.NewRecorder()
+	config.createScorePrediction(rec, scorePredictionRequest("1035", `{"home_goals":2,"away_goals":1}`))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,692 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type CreateSeasonRequest struct {
+	Name                 string     `json:"name"`       // e.g. 2025/26
+	StartDate            string     `json:"start_date"` // YYYY-MM-DD
+	EndDate              string     `json:"end_date"`   // YYYY-MM-DD
+	RegistrationOpensAt  *time.Time `json:"registration_opens_at"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at"`
+	CopyTeams            *bool      `json:"copy_teams"` // Register the league's teams, defaults to true
+}
+
+// UpdateSeasonRequest changes a season. Omitted fields are left as they are.
+type UpdateSeasonRequest struct {
+	Name                 *string    `json:"name"`
+	StartDate            *string    `json:"start_date"`
+	EndDate              *string    `json:"end_date"`
+	RegistrationOpensAt  *time.Time `json:"registration_opens_at"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at"`
+	Archived             *bool      `json:"archived"`
+}
+
+type RegisterSeasonTeamRequest struct {
+	TeamID uuid.UUID `json:"team_id"`
+}
+
+// RegistrationWindowRequest opens player registration for a period. Players
+// can only join teams inside one of a season's windows, or at any time when
+// it has none.
+type RegistrationWindowRequest struct {
+	OpensAt  time.Time `json:"opens_at"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type RegistrationWindowResponse struct {
+	ID       uuid.UUID `json:"id"`
+	SeasonID uuid.UUID `json:"season_id"`
+	OpensAt  time.Time `json:"opens_at"`
+	ClosesAt time.Time `json:"closes_at"`
+	Open     bool      `json:"open"`
+}
+
+type SeasonTeamResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Logo string    `json:"logo,omitempty"`
+}
+
+type SeasonResponse struct {
+	ID                   uuid.UUID            `json:"id"`
+	LeagueID             uuid.UUID            `json:"league_id"`
+	Name                 string               `json:"name"`
+	StartDate            string               `json:"start_date"`
+	EndDate              string               `json:"end_date"`
+	RegistrationOpensAt  *time.Time           `json:"registration_opens_at,omitempty"`
+	RegistrationClosesAt *time.Time           `json:"registration_closes_at,omitempty"`
+	RegistrationOpen     bool                 `json:"registration_open"`
+	Archived             bool                 `json:"archived"`
+	Teams                []SeasonTeamResponse `json:"teams,omitempty"`
+}
+
+// createSeason adds a season to a local league. By default the league's
+// teams are registered for it.
+func (c *Config) createSeason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+
+	var req CreateSeasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	params := database.CreateSeasonParams{
+		LeagueID:             leagueID,
+		Name:                 strings.TrimSpace(req.Name),
+		RegistrationOpensAt:  nullTime(req.RegistrationOpensAt),
+		RegistrationClosesAt: nullTime(req.RegistrationClosesAt),
+	}
+	if params.StartDate, err = time.Parse(time.DateOnly, req.StartDate); err != nil {
+		respondWithError(w, http.StatusBadRequest, "start_date is required, use YYYY-MM-DD")
+		return
+	}
+	if params.EndDate, err = time.Parse(time.DateOnly, req.EndDate); err != nil {
+		respondWithError(w, http.StatusBadRequest, "end_date is required, use YYYY-MM-DD")
+		return
+	}
+	season := database.Season{
+		Name:                 params.Name,
+		StartDate:            params.StartDate,
+		EndDate:              params.EndDate,
+		RegistrationOpensAt:  params.RegistrationOpensAt,
+		RegistrationClosesAt: params.RegistrationClosesAt,
+	}
+	if err := validateSeason(season); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	league, ok := c.fixtureLeague(w, ctx, leagueID)
+	if !ok {
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can add seasons")
+		return
+	}
+	if c.DBConn == nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Seasons can't be created right now")
+		return
+	}
+
+	season, err = c.saveNewSeason(ctx, userID, params, req.CopyTeams == nil || *req.CopyTeams)
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "League already has a season with that name")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create season: %v", err))
+		return
+	}
+	c.respondWithSeason(w, ctx, http.StatusCreated, season)
+}
+
+func (c *Config) saveNewSeason(ctx context.Context, userID int32, params database.CreateSeasonParams, copyTeams bool) (database.Season, error) {
+	tx, err := c.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Season{}, err
+	}
+	defer tx.Rollback()
+	q := c.DB.WithTx(tx)
+
+	season, err := q.CreateSeason(ctx, params)
+	if err != nil {
+		return database.Season{}, err
+	}
+	if copyTeams {
+		_, err := q.AddLeagueTeamsToSeason(ctx, database.AddLeagueTeamsToSeasonParams{
+			SeasonID:     season.ID,
+			RegisteredBy: sql.NullInt32{Int32: userID, Valid: true},
+			LeagueID:     uuid.NullUUID{UUID: params.LeagueID, Valid: true},
+		})
+		if err != nil {
+			return database.Season{}, err
+		}
+	}
+	return season, tx.Commit()
+}
+
+// listLeagueSeasons returns a league's seasons, newest first. Archived seasons
+// are left out unless ?include_archived=true.
+func (c *Config) listLeagueSeasons(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+	if _, ok := c.fixtureLeague(w, ctx, leagueID); !ok {
+		return
+	}
+
+	seasons, err := c.DB.ListLeagueSeasons(ctx, database.ListLeagueSeasonsParams{
+		LeagueID:        leagueID,
+		IncludeArchived: r.URL.Query().Get("include_archived") == "true",
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list seasons: %v", err))
+		return
+	}
+
+	now := time.Now()
+	response := make([]SeasonResponse, 0, len(seasons))
+	for _, season := range seasons {
+		response = append(response, newSeasonResponse(season, nil, now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// getSeason returns a season with its registered teams
+func (c *Config) getSeason(w http.ResponseWriter, r *http.Request) {
+	season, ok := c.seasonFromRequest(w, r)
+	if !ok {
+		return
+	}
+	c.respondWithSeason(w, r.Context(), http.StatusOK, season)
+}
+
+// updateSeason changes a season's dates or archives it. Archived seasons are
+// read-only until they're unarchived.
+func (c *Config) updateSeason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if _, ok := r.Context().Value("user_id").(int32); !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req UpdateSeasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	season, ok := c.seasonFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if !c.seasonOrganiser(w, r, season) {
+		return
+	}
+
+	var err error
+	if req.Name != nil {
+		season.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.StartDate != nil {
+		if season.StartDate, err = time.Parse(time.DateOnly, *req.StartDate); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid start_date, use YYYY-MM-DD")
+			return
+		}
+	}
+	if req.EndDate != nil {
+		if season.EndDate, err = time.Parse(time.DateOnly, *req.EndDate); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid end_date, use YYYY-MM-DD")
+			return
+		}
+	}
+	if req.RegistrationOpensAt != nil {
+		season.RegistrationOpensAt = nullTime(req.RegistrationOpensAt)
+	}
+	if req.RegistrationClosesAt != nil {
+		season.RegistrationClosesAt = nullTime(req.RegistrationClosesAt)
+	}
+	if req.Archived != nil {
+		season.Archived = *req.Archived
+	}
+	if err := validateSeason(season); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := c.DB.UpdateSeason(ctx, database.UpdateSeasonParams{
+		ID:                   season.ID,
+		Name:                 season.Name,
+		StartDate:            season.StartDate,
+		EndDate:              season.EndDate,
+		RegistrationOpensAt:  season.RegistrationOpensAt,
+		RegistrationClosesAt: season.RegistrationClosesAt,
+		Archived:             season.Archived,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "League already has a season with that name")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update season: %v", err))
+		return
+	}
+	c.respondWithSeason(w, ctx, http.StatusOK, updated)
+}
+
+// registerSeasonTeam enters a league's team into a season. Managers can
+// register their team while registration is open, the league's owner can
+// register any of its teams until the season is archived.
+func (c *Config) registerSeasonTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req RegisterSeasonTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.TeamID == uuid.Nil {
+		respondWithError(w, http.StatusBadRequest, "team_id is required")
+		return
+	}
+
+	season, ok := c.seasonFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if season.Archived {
+		respondWithError(w, http.StatusConflict, "Season is archived")
+		return
+	}
+	team, err := c.DB.GetTeam(ctx, req.TeamID)
+	if err != nil && err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get team: %v", err))
+		return
+	}
+	if err == sql.ErrNoRows || team.LeagueID.UUID != season.LeagueID {
+		respondWithError(w, http.StatusBadRequest, "Team is not in this league")
+		return
+	}
+
+	league, ok := c.fixtureLeague(w, ctx, season.LeagueID)
+	if !ok {
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		manager, err := c.DB.IsTeamManager(ctx, database.IsTeamManagerParams{TeamID: team.ID, UserID: userID, Roles: rolesWith(permissionRoster)})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check permissions: %v", err))
+			return
+		}
+		if !manager {
+			respondWithError(w, http.StatusForbidden, "Only the league's owner or the team's managers can register it")
+			return
+		}
+		if !registrationOpen(season, time.Now()) {
+			respondWithError(w, http.StatusConflict, "Registration for this season is closed")
+			return
+		}
+	}
+
+	_, err = c.DB.AddSeasonTeam(ctx, database.AddSeasonTeamParams{
+		SeasonID:     season.ID,
+		TeamID:       team.ID,
+		RegisteredBy: sql.NullInt32{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to register team: %v", err))
+		return
+	}
+	c.respondWithSeason(w, ctx, http.StatusOK, season)
+}
+
+// removeSeasonTeam withdraws a team from a season. Its fixtures are left for
+// the organiser to cancel.
+func (c *Config) removeSeasonTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if _, ok := r.Context().Value("user_id").(int32); !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	teamID, err := uuid.Parse(chi.URLParam(r, "teamId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	season, ok := c.seasonFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if !c.seasonOrganiser(w, r, season) {
+		return
+	}
+	if season.Archived {
+		respondWithError(w, http.StatusConflict, "Season is archived")
+		return
+	}
+
+	removed, err := c.DB.RemoveSeasonTeam(ctx, database.RemoveSeasonTeamParams{SeasonID: season.ID, TeamID: teamID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to remove team: %v", err))
+		return
+	}
+	if removed == 0 {
+		respondWithError(w, http.StatusNotFound, "Team is not registered for this season")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Config) listRegistrationWindows(w http.ResponseWriter, r *http.Request) {
+	season, ok := c.seasonFromRequest(w, r)
+	if !ok {
+		return
+	}
+	windows, err := c.DB.ListSeasonRegistrationWindows(r.Context(), season.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list registration windows: %v", err))
+		return
+	}
+
+	now := time.Now()
+	response := make([]RegistrationWindowResponse, 0, len(windows))
+	for _, window := range windows {
+		response = append(response, newRegistrationWindowResponse(window, now))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// createRegistrationWindow adds a player registration window to a season.
+// Only the league's owner and admins can add one.
+func (c *Config) createRegistrationWindow(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Value("user_id").(int32); !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req RegistrationWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.OpensAt.IsZero() || req.ClosesAt.IsZero() {
+		respondWithError(w, http.StatusBadRequest, "opens_at and closes_at are required")
+		return
+	}
+	if !req.ClosesAt.After(req.OpensAt) {
+		respondWithError(w, http.StatusBadRequest, "closes_at must be after opens_at")
+		return
+	}
+
+	season, ok := c.seasonFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if !c.seasonOrganiser(w, r, season) {
+		return
+	}
+	if season.Archived {
+		respondWithError(w, http.StatusConflict, "Season is archived")
+		return
+	}
+
+	window, err := c.DB.CreateSeasonRegistrationWindow(r.Context(), database.CreateSeasonRegistrationWindowParams{
+		SeasonID: season.ID,
+		OpensAt:  req.OpensAt,
+		ClosesAt: req.ClosesAt,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create registration window: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, newRegistrationWindowResponse(window, time.Now()))
+}
+
+func (c *Config) deleteRegistrationWindow(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.Context().Value("user_id").(int32); !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	windowID, err := uuid.Parse(chi.URLParam(r, "windowId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid registration window ID")
+		return
+	}
+
+	season, ok := c.seasonFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if !c.seasonOrganiser(w, r, season) {
+		return
+	}
+	if season.Archived {
+		respondWithError(w, http.StatusConflict, "Season is archived")
+		return
+	}
+
+	deleted, err := c.DB.DeleteSeasonRegistrationWindow(r.Context(), database.DeleteSeasonRegistrationWindowParams{ID: windowID, SeasonID: season.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete registration window: %v", err))
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Registration window not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateSeason checks a season's name and that its dates are in order
+func validateSeason(season database.Season) error {
+	if season.Name == "" || len(season.Name) > 100 {
+		return errors.New("name is required and must be at most 100 characters")
+	}
+	if season.EndDate.Before(season.StartDate) {
+		return errors.New("end_date can't be before start_date")
+	}
+	if season.RegistrationOpensAt.Valid && season.RegistrationClosesAt.Valid && !season.RegistrationClosesAt.Time.After(season.RegistrationOpensAt.Time) {
+		return errors.New("registration_closes_at must be after registration_opens_at")
+	}
+	return nil
+}
+
+// registrationOpen reports whether managers can register teams. Without a
+// window registration is open until the season starts.
+func registrationOpen(season database.Season, now time.Time) bool {
+	if season.Archived {
+		return false
+	}
+	if season.RegistrationOpensAt.Valid && now.Before(season.RegistrationOpensAt.Time) {
+		return false
+	}
+	if season.RegistrationClosesAt.Valid {
+		return now.Before(season.RegistrationClosesAt.Time)
+	}
+	return now.Before(season.StartDate)
+}
+
+// seasonFromRequest loads the season in the id URL param. It responds with an
+// error and returns false when there isn't one.
+func (c *Config) seasonFromRequest(w http.ResponseWriter, r *http.Request) (database.Season, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid season ID")
+		return database.Season{}, false
+	}
+	season, err := c.DB.GetSeason(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Season not found")
+			return database.Season{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get season: %v", err))
+		return database.Season{}, false
+	}
+	return season, true
+}
+
+// seasonOrganiser checks the caller is an admin or owns the season's league
+func (c *Config) seasonOrganiser(w http.ResponseWriter, r *http.Request, season database.Season) bool {
+	league, ok := c.fixtureLeague(w, r.Context(), season.LeagueID)
+	if !ok {
+		return false
+	}
+	userID, _ := r.Context().Value("user_id").(int32)
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can change its seasons")
+		return false
+	}
+	return true
+}
+
+// leagueSeason is the season a league request is about: seasonID when given,
+// otherwise the league's current season. It's the zero Season for leagues
+// without seasons, whose fixtures and standings are league-wide. It responds
+// with an error and returns false when seasonID isn't one of the league's.
+func (c *Config) leagueSeason(w http.ResponseWriter, ctx context.Context, leagueID uuid.UUID, seasonID *uuid.UUID) (database.Season, bool) {
+	var season database.Season
+	var err error
+	if seasonID != nil {
+		season, err = c.DB.GetSeason(ctx, *seasonID)
+		if err == nil && season.LeagueID != leagueID {
+			err = sql.ErrNoRows
+		}
+		if err == sql.ErrNoRows {
+			respondWithError(w, http.StatusNotFound, "Season not found")
+			return database.Season{}, false
+		}
+	} else {
+		season, err = c.DB.GetCurrentSeason(ctx, database.GetCurrentSeasonParams{LeagueID: leagueID, Today: time.Now().UTC()})
+		if err == sql.ErrNoRows {
+			return database.Season{}, true
+		}
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get season: %v", err))
+		return database.Season{}, false
+	}
+	return season, true
+}
+
+// seasonIDParam parses the optional ?season_id= query param
+func seasonIDParam(w http.ResponseWriter, r *http.Request) (*uuid.UUID, bool) {
+	seasonStr := r.URL.Query().Get("season_id")
+	if seasonStr == "" {
+		return nil, true
+	}
+	seasonID, err := uuid.Parse(seasonStr)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid season ID")
+		return nil, false
+	}
+	return &seasonID, true
+}
+
+// seasonFilter narrows fixture queries to season, or not at all for the zero Season
+func seasonFilter(season database.Season) uuid.NullUUID {
+	return uuid.NullUUID{UUID: season.ID, Valid: season.ID != uuid.Nil}
+}
+
+// seasonTeams are the teams registered for season, or all the league's teams
+// for the zero Season
+func (c *Config) seasonTeams(ctx context.Context, leagueID uuid.UUID, season database.Season) ([]database.Team, error) {
+	if season.ID == uuid.Nil {
+		return c.DB.GetTeamsByLeague(ctx, uuid.NullUUID{UUID: leagueID, Valid: true})
+	}
+	return c.DB.ListSeasonTeams(ctx, season.ID)
+}
+
+// fixtureSeasonOpen responds with a conflict and returns false when match
+// belongs to an archived season
+func (c *Config) fixtureSeasonOpen(w http.ResponseWriter, ctx context.Context, match database.Match) bool {
+	if !match.SeasonID.Valid {
+		return true
+	}
+	season, err := c.DB.GetSeason(ctx, match.SeasonID.UUID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get season: %v", err))
+		return false
+	}
+	if season.Archived {
+		respondWithError(w, http.StatusConflict, "Season is archived")
+		return false
+	}
+	return true
+}
+
+func (c *Config) respondWithSeason(w http.ResponseWriter, ctx context.Context, status int, season database.Season) {
+	teams, err := c.DB.ListSeasonTeams(ctx, season.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get teams: %v", err))
+		return
+	}
+	respondWithJSON(w, status, newSeasonResponse(season, teams, time.Now()))
+}
+
+func newSeasonResponse(season database.Season, teams []database.Team, now time.Time) SeasonResponse {
+	response := SeasonResponse{
+		ID:               season.ID,
+		LeagueID:         season.LeagueID,
+		Name:             season.Name,
+		StartDate:        season.StartDate.Format(time.DateOnly),
+		EndDate:          season.EndDate.Format(time.DateOnly),
+		RegistrationOpen: registrationOpen(season, now),
+		Archived:         season.Archived,
+	}
+	if season.RegistrationOpensAt.Valid {
+		response.RegistrationOpensAt = &season.RegistrationOpensAt.Time
+	}
+	if season.RegistrationClosesAt.Valid {
+		response.RegistrationClosesAt = &season.RegistrationClosesAt.Time
+	}
+	for _, team := range teams {
+		response.Teams = append(response.Teams, SeasonTeamResponse{ID: team.ID, Name: team.Name, Logo: team.LogoUrl.String})
+	}
+	return response
+}
+
+func newRegistrationWindowResponse(window database.SeasonRegistrationWindow, now time.Time) RegistrationWindowResponse {
+	return RegistrationWindowResponse{
+		ID:       window.ID,
+		SeasonID: window.SeasonID,
+		OpensAt:  window.OpensAt,
+		ClosesAt: window.ClosesAt,
+		Open:     inRegistrationWindow([]database.SeasonRegistrationWindow{window}, now),
+	}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestRegistrationOpen(t *testing.T) {
+	start := time.Date(2025, 9, 6, 0, 0, 0, 0, time.UTC)
+	opens := sql.NullTime{Time: start.AddDate(0, -1, 0), Valid: true}
+	closes := sql.NullTime{Time: start.AddDate(0, 0, -7), Valid: true}
+
+	tests := []struct {
+		name   string
+		season database.Season
+		now    time.Time
+		want   bool
+	}{
+		{"no window before the start", database.Season{StartDate: start}, start.AddDate(0, 0, -1), true},
+		{"no window once started", database.Season{StartDate: start}, start, false},
+		{"before the window", database.Season{StartDate: start, RegistrationOpensAt: opens, RegistrationClosesAt: closes}, opens.Time.Add(-time.Hour), false},
+		{"inside the window", database.Season{StartDate: start, RegistrationOpensAt: opens, RegistrationClosesAt: closes}, opens.Time.Add(time.Hour), true},
+		{"after the window", database.Season{StartDate: start, RegistrationOpensAt: opens, RegistrationClosesAt: closes}, closes.Time, false},
+		{"archived", database.Season{StartDate: start, Archived: true}, start.AddDate(0, 0, -1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registrationOpen(tt.season, tt.now); got != tt.want {
+				t.Errorf("registrationOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateSeasonValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing name", `{"start_date":"2025-09-01","end_date":"2026-05-31"}`},
+		{"missing dates", `{"name":"2025/26"}`},
+		{"ends before it starts", `{"name":"2025/26","start_date":"2026-05-31","end_date":"2025-09-01"}`},
+		{"registration closes before it opens", `{"name":"2025/26","start_date":"2025-09-01","end_date":"2026-05-31","registration_opens_at":"2025-08-01T00:00:00Z","registration_closes_at":"2025-07-01T00:00:00Z"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			req := authedRequest("POST", "/leagues/x/seasons", tt.body)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", uuid.New().String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+			rec := httptest.NewRecorder()
+
+			config.createSeason(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestListLeagueFixturesInvalidSeason(t *testing.T) {
+	config := &Config{}
+	req := httptest.NewRequest("GET", "/leagues/x/fixtures?season_id=abc", nil)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", uuid.New().String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	config.listLeagueFixtures(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/sentiment"
+)
+
+// sentimentScanLimit bounds how many recent comments or votes are scanned per team
+const sentimentScanLimit = 1000
+
+// commentSentimentSource scores comments on debates about the team's matches
+type commentSentimentSource struct {
+	db *database.Queries
+}
+
+func (s *commentSentimentSource) Name() string {
+	return "fan_comments"
+}
+
+func (s *commentSentimentSource) Signals(ctx context.Context, team sentiment.Team, since time.Time) ([]sentiment.Signal, error) {
+	if len(team.MatchIDs) == 0 {
+		return nil, nil
+	}
+
+	comments, err := s.db.ListRecentCommentsForSentiment(ctx, database.ListRecentCommentsForSentimentParams{
+		MatchIds:   team.MatchIDs,
+		Since:      since,
+		MaxResults: sentimentScanLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching comments: %w", err)
+	}
+
+	var signals []sentiment.Signal
+	for _, comment := range comments {
+		signals = append(signals, sentiment.Signal{
+			Text:      comment.Content,
+			Weight:    1,
+			CreatedAt: comment.CreatedAt.Time,
+		})
+	}
+	return signals, nil
+}
+
+// voteSentimentSource scores votes on cards in debates about the team's matches.
+// Emoji reactions are scored directly; an upvote endorses the card's text
+// and a downvote rejects it.
+type voteSentimentSource struct {
+	db *database.Queries
+}
+
+func (s *voteSentimentSource) Name() string {
+	return "fan_votes"
+}
+
+func (s *voteSentimentSource) Signals(ctx context.Context, team sentiment.Team, since time.Time) ([]sentiment.Signal, error) {
+	if len(team.MatchIDs) == 0 {
+		return nil, nil
+	}
+
+	votes, err := s.db.ListRecentCardVotesForSentiment(ctx, database.ListRecentCardVotesForSentimentParams{
+		MatchIds:   team.MatchIDs,
+		Since:      since,
+		MaxResults: sentimentScanLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching votes: %w", err)
+	}
+
+	var signals []sentiment.Signal
+	for _, vote := range votes {
+		cardText := strings.TrimSpace(vote.Title + " " + vote.Description.String)
+		signal := sentiment.Signal{Text: cardText, Weight: 1, CreatedAt: vote.CreatedAt.Time}
+		switch vote.VoteType {
+		case "emoji":
+			if !vote.Emoji.Valid {
+				continue
+			}
+			signal.Text = vote.Emoji.String
+		case "downvote":
+			signal.Weight = -1
+		}
+		signals = append(signals, signal)
+	}
+	return signals, nil
+}
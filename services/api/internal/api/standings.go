@@ -0,0 +1,292 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/standings"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// LocalStandingsResponse is a local league's table in the same shape as
+// GetLeagueStandingsByLeagueIdResponse, so apps can render both the same
+// way. League and team IDs are UUIDs rather than API-Football numbers.
+type LocalStandingsResponse struct {
+	Get        string `json:"get"`
+	Parameters struct {
+		League string `json:"league"`
+		Season string `json:"season"`
+	} `json:"parameters"`
+	Errors  []string `json:"errors"`
+	Results int      `json:"results"`
+	Paging  struct {
+		Current int `json:"current"`
+		Total   int `json:"total"`
+	} `json:"paging"`
+	Response []LocalStandingsLeagueEntry `json:"response"`
+}
+
+type LocalStandingsLeagueEntry struct {
+	League LocalStandingsLeague `json:"league"`
+}
+
+type LocalStandingsLeague struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Country   string            `json:"country"`
+	Logo      string            `json:"logo"`
+	Flag      string            `json:"flag"`
+	Season    int               `json:"season"`              // Year the season starts
+	SeasonID  string            `json:"season_id,omitempty"` // Local season, if the league has them
+	Standings [][]LocalStanding `json:"standings"`
+}
+
+type LocalStanding struct {
+	Rank int `json:"rank"`
+	Team struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Logo string `json:"logo"`
+	} `json:"team"`
+	Points      int            `json:"points"`
+	GoalsDiff   int            `json:"goalsDiff"`
+	Group       string         `json:"group"`
+	Form        string         `json:"form"`
+	Status      string         `json:"status"`
+	Description string         `json:"description"`
+	All         StandingRecord `json:"all"`
+	Home        StandingRecord `json:"home"`
+	Away        StandingRecord `json:"away"`
+	Update      time.Time      `json:"update"`
+}
+
+type StandingRecord struct {
+	Played int `json:"played"`
+	Win    int `json:"win"`
+	Draw   int `json:"draw"`
+	Lose   int `json:"lose"`
+	Goals  struct {
+		For     int `json:"for"`
+		Against int `json:"against"`
+	} `json:"goals"`
+}
+
+type StandingsRules struct {
+	PointsWin   int32    `json:"points_win"`
+	PointsDraw  int32    `json:"points_draw"`
+	PointsLoss  int32    `json:"points_loss"`
+	Tiebreakers []string `json:"tiebreakers"` // head_to_head, goal_difference and goals_for, applied in order after points
+}
+
+// getLeagueStandings works out a local league's table from its confirmed
+// results, for ?season_id= or the current season
+func (c *Config) getLeagueStandings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+	seasonID, ok := seasonIDParam(w, r)
+	if !ok {
+		return
+	}
+	league, ok := c.fixtureLeague(w, ctx, leagueID)
+	if !ok {
+		return
+	}
+	season, ok := c.leagueSeason(w, ctx, leagueID, seasonID)
+	if !ok {
+		return
+	}
+
+	rules, err := c.leagueStandingsRules(ctx, leagueID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get standings rules: %v", err))
+		return
+	}
+	teams, err := c.seasonTeams(ctx, leagueID, season)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get teams: %v", err))
+		return
+	}
+	rows, err := c.DB.ListConfirmedLeagueResults(ctx, database.ListConfirmedLeagueResultsParams{
+		LeagueID: uuid.NullUUID{UUID: leagueID, Valid: true},
+		SeasonID: seasonFilter(season),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get results: %v", err))
+		return
+	}
+
+	teamIDs := make([]uuid.UUID, 0, len(teams))
+	for _, team := range teams {
+		teamIDs = append(teamIDs, team.ID)
+	}
+	results := make([]standings.Result, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, standings.Result{
+			Home:      row.HomeTeamID.UUID,
+			Away:      row.AwayTeamID.UUID,
+			HomeGoals: row.HomeScore.Int32,
+			AwayGoals: row.AwayScore.Int32,
+			Played:    row.MatchDate,
+		})
+	}
+
+	// Leagues without seasons are given the year of their first result
+	year := time.Now().Year()
+	switch {
+	case season.ID != uuid.Nil:
+		year = season.StartDate.Year()
+	case len(rows) > 0:
+		year = rows[0].MatchDate.Year()
+	}
+	table := standings.Compute(teamIDs, results, rules)
+	response := newLocalStandingsResponse(league, teams, table, year, time.Now().UTC())
+	if season.ID != uuid.Nil {
+		response.Response[0].League.SeasonID = season.ID.String()
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func newLocalStandingsResponse(league database.League, teams []database.Team, table []standings.Row, season int, updated time.Time) LocalStandingsResponse {
+	byID := make(map[uuid.UUID]database.Team, len(teams))
+	for _, team := range teams {
+		byID[team.ID] = team
+	}
+
+	entry := LocalStandingsLeagueEntry{League: LocalStandingsLeague{
+		ID:      league.ID.String(),
+		Name:    league.Name,
+		Country: league.Country.String,
+		Logo:    league.LogoUrl.String,
+		Season:  season,
+	}}
+	group := make([]LocalStanding, 0, len(table))
+	for _, row := range table {
+		team := byID[row.Team]
+		standing := LocalStanding{
+			Rank:      row.Rank,
+			Points:    row.Points,
+			GoalsDiff: row.All.GoalDifference(),
+			Group:     league.Name,
+			Form:      row.Form,
+			All:       newStandingRecord(row.All),
+			Home:      newStandingRecord(row.Home),
+			Away:      newStandingRecord(row.Away),
+			Update:    updated,
+		}
+		standing.Team.ID = team.ID.String()
+		standing.Team.Name = team.Name
+		standing.Team.Logo = team.LogoUrl.String
+		group = append(group, standing)
+	}
+	entry.League.Standings = [][]LocalStanding{group}
+
+	response := LocalStandingsResponse{Get: "standings", Errors: []string{}, Results: 1, Response: []LocalStandingsLeagueEntry{entry}}
+	response.Parameters.League = league.ID.String()
+	response.Parameters.Season = fmt.Sprint(season)
+	response.Paging.Current, response.Paging.Total = 1, 1
+	return response
+}
+
+func newStandingRecord(record standings.Record) StandingRecord {
+	r := StandingRecord{Played: record.Played, Win: record.Won, Draw: record.Drawn, Lose: record.Lost}
+	r.Goals.For, r.Goals.Against = record.GoalsFor, record.GoalsAgainst
+	return r
+}
+
+func (c *Config) getLeagueStandingsRules(w http.ResponseWriter, r *http.Request) {
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+	if _, ok := c.fixtureLeague(w, r.Context(), leagueID); !ok {
+		return
+	}
+
+	rules, err := c.leagueStandingsRules(r.Context(), leagueID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get standings rules: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newStandingsRules(rules))
+}
+
+// updateLeagueStandingsRules changes how the league's table is worked out. Only
+// admins and the league's owner can change it.
+func (c *Config) updateLeagueStandingsRules(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid league ID")
+		return
+	}
+
+	var req StandingsRules
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Tiebreakers == nil {
+		req.Tiebreakers = []string{}
+	}
+	rules := standings.Rules{Win: req.PointsWin, Draw: req.PointsDraw, Loss: req.PointsLoss, Tiebreakers: req.Tiebreakers}
+	if err := rules.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	league, ok := c.fixtureLeague(w, r.Context(), leagueID)
+	if !ok {
+		return
+	}
+	if role, _ := r.Context().Value("user_role").(string); role != "admin" && league.OwnerID != userID {
+		respondWithError(w, http.StatusForbidden, "Only the league's owner can change its standings rules")
+		return
+	}
+
+	_, err = c.DB.UpsertLeagueStandingsRules(r.Context(), database.UpsertLeagueStandingsRulesParams{
+		LeagueID:    leagueID,
+		PointsWin:   rules.Win,
+		PointsDraw:  rules.Draw,
+		PointsLoss:  rules.Loss,
+		Tiebreakers: rules.Tiebreakers,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save standings rules: %v", err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newStandingsRules(rules))
+}
+
+// leagueStandingsRules returns the league's rules, or the defaults when it
+// hasn't set any
+func (c *Config) leagueStandingsRules(ctx context.Context, leagueID uuid.UUID) (standings.Rules, error) {
+	row, err := c.DB.GetLeagueStandingsRules(ctx, leagueID)
+	if err == sql.ErrNoRows {
+		return standings.DefaultRules, nil
+	}
+	if err != nil {
+		return standings.Rules{}, err
+	}
+	return standings.Rules{Win: row.PointsWin, Draw: row.PointsDraw, Loss: row.PointsLoss, Tiebreakers: row.Tiebreakers}, nil
+}
+
+func newStandingsRules(rules standings.Rules) StandingsRules {
+	return StandingsRules{PointsWin: rules.Win, PointsDraw: rules.Draw, PointsLoss: rules.Loss, Tiebreakers: rules.Tiebreakers}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/standings"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestNewLocalStandingsResponse(t *testing.T) {
+	league := database.League{ID: uuid.New(), Name: "Sunday League", Country: sql.NullString{String: "England", Valid: true}}
+	home := database.Team{ID: uuid.New(), Name: "Red Lion", LogoUrl: sql.NullString{String: "https://cdn.example.com/red-lion.png", Valid: true}}
+	away := database.Team{ID: uuid.New(), Name: "Kings Arms"}
+	results := []standings.Result{{Home: home.ID, Away: away.ID, HomeGoals: 3, AwayGoals: 1}}
+	table := standings.Compute([]uuid.UUID{home.ID, away.ID}, results, standings.DefaultRules)
+
+	response := newLocalStandingsResponse(league, []database.Team{away, home}, table, 2025, time.Now())
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+	// Decodes the same way as API-Football standings, apart from the IDs
+	var decoded struct {
+		Response []struct {
+			League struct {
+				Name      string `json:"name"`
+				Season    int    `json:"season"`
+				Standings [][]struct {
+					Rank int `json:"rank"`
+					Team struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+						Logo string `json:"logo"`
+					} `json:"team"`
+					Points    int    `json:"points"`
+					GoalsDiff int    `json:"goalsDiff"`
+					Form      string `json:"form"`
+					All       struct {
+						Played int `json:"played"`
+						Win    int `json:"win"`
+						Goals  struct {
+							For     int `json:"for"`
+							Against int `json:"against"`
+						} `json:"goals"`
+					} `json:"all"`
+					Away struct {
+						Lose int `json:"lose"`
+					} `json:"away"`
+				} `json:"standings"`
+			} `json:"league"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	got := decoded.Response[0].League
+	if got.Name != "Sunday League" || got.Season != 2025 || len(got.Standings) != 1 || len(got.Standings[0]) != 2 {
+		t.Fatalf("Unexpected league %+v", got)
+	}
+	top := got.Standings[0][0]
+	if top.Rank != 1 || top.Team.ID != home.ID.String() || top.Team.Name != "Red Lion" || top.Team.Logo == "" {
+		t.Errorf("Unexpected leader %+v", top)
+	}
+	if top.Points != 3 || top.GoalsDiff != 2 || top.Form != "W" || top.All.Played != 1 || top.All.Win != 1 || top.All.Goals.For != 3 || top.All.Goals.Against != 1 {
+		t.Errorf("Unexpected leader's record %+v", top)
+	}
+	if bottom := got.Standings[0][1]; bottom.Away.Lose != 1 || bottom.Points != 0 {
+		t.Errorf("Unexpected bottom team %+v", bottom)
+	}
+}
+
+func TestUpdateLeagueStandingsRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"draw worth more than a win", `{"points_win":1,"points_draw":2}`},
+		{"unknown tiebreaker", `{"points_win":3,"points_draw":1,"tiebreakers":["fair_play"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			req := authedRequest("PUT", "/leagues/x/standings/rules", tt.body)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", uuid.New().String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+			rec := httptest.NewRecorder()
+
+			config.updateLeagueStandingsRules(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", rec.Code)
+			}
+		})
+	}
+}
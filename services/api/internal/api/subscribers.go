@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/achievements"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+	"github.com/google/uuid"
+)
+
+// Subscribe registers the API's reactions to domain events. Handlers are run by
+// the outbox relay after the change that raised the event has committed.
+func (c *Config) Subscribe(bus *events.Bus) {
+	bus.Subscribe(events.TypeVoteCast, "debate_analytics", func(ctx context.Context, event events.Event) error {
+		return c.updateDebateAnalytics(ctx, event.(events.VoteCast).DebateID)
+	})
+	bus.Subscribe(events.TypeVoteCast, "engagement_points", func(ctx context.Context, event events.Event) error {
+		e := event.(events.VoteCast)
+		return c.awardEngagementPoints(ctx, e.UserID, pointSourceVote, e.VoteID, votePoints, e.DebateID)
+	})
+	bus.Subscribe(events.TypeVoteCast, "achievements", func(ctx context.Context, event events.Event) error {
+		return evaluateAchievements(ctx, c.DB, achievements.EventVoteCreated, event.(events.VoteCast).UserID)
+	})
+	bus.Subscribe(events.TypeVoteCast, "inbox", func(ctx context.Context, event events.Event) error {
+		return c.inboxDebateVotes(ctx, event.(events.VoteCast))
+	})
+
+	bus.Subscribe(events.TypeCommentPosted, "debate_analytics", func(ctx context.Context, event events.Event) error {
+		return c.updateDebateAnalytics(ctx, event.(events.CommentPosted).DebateID)
+	})
+	bus.Subscribe(events.TypeCommentPosted, "engagement_points", func(ctx context.Context, event events.Event) error {
+		e := event.(events.CommentPosted)
+		return c.awardEngagementPoints(ctx, e.UserID, pointSourceComment, e.CommentID, commentPoints, e.DebateID)
+	})
+	bus.Subscribe(events.TypeCommentPosted, "achievements", func(ctx context.Context, event events.Event) error {
+		return evaluateAchievements(ctx, c.DB, achievements.EventCommentCreated, event.(events.CommentPosted).UserID)
+	})
+	bus.Subscribe(events.TypeCommentPosted, "inbox", func(ctx context.Context, event events.Event) error {
+		return c.inboxCommentReply(ctx, event.(events.CommentPosted))
+	})
+	bus.Subscribe(events.TypeCommentPosted, "push_notifications", func(ctx context.Context, event events.Event) error {
+		return c.notifyCommentReply(ctx, event.(events.CommentPosted))
+	})
+
+	bus.Subscribe(events.TypeVerificationAdded, "player_verification", func(ctx context.Context, event events.Event) error {
+		e := event.(events.VerificationAdded)
+		profiles := &PlayerProfileService{DB: c.DB}
+		verificationID, err := uuid.Parse(e.VerificationID)
+		if err != nil {
+			return err
+		}
+		return profiles.RecalculateIsVerified(ctx, e.PlayerProfileID, verificationChange{
+			Reason:         verificationReasonAdded,
+			VerificationID: uuid.NullUUID{UUID: verificationID, Valid: true},
+			ActorUserID:    sql.NullInt32{Int32: e.VerifierUserID, Valid: true},
+		})
+	})
+	bus.Subscribe(events.TypeVerificationAdded, "achievements", func(ctx context.Context, event events.Event) error {
+		e := event.(events.VerificationAdded)
+		// The verifier progresses towards scout, the player towards verified_player
+		return errors.Join(
+			evaluateAchievements(ctx, c.DB, achievements.EventVerificationAdded, e.VerifierUserID),
+			evaluateAchievements(ctx, c.DB, achievements.EventVerificationAdded, e.PlayerUserID),
+		)
+	})
+	bus.Subscribe(events.TypeVerificationAdded, "inbox", func(ctx context.Context, event events.Event) error {
+		return c.inboxVerification(ctx, event.(events.VerificationAdded))
+	})
+
+	bus.Subscribe(events.TypeMatchStatusChanged, "score_predictions", func(ctx context.Context, event events.Event) error {
+		e := event.(events.MatchStatusChanged)
+		if !resolvableMatchStatuses[e.ToStatus] {
+			return nil
+		}
+		if _, _, err := c.scoreMatchPredictions(ctx, e.MatchID); err != nil && !errors.Is(err, errMatchNotFinished) {
+			return err
+		}
+		return nil
+	})
+	bus.Subscribe(events.TypeMatchStatusChanged, "push_notifications", func(ctx context.Context, event events.Event) error {
+		return c.notifyKickoff(ctx, event.(events.MatchStatusChanged))
+	})
+
+	bus.Subscribe(events.TypeGoalScored, "push_notifications", func(ctx context.Context, event events.Event) error {
+		return c.notifyGoal(ctx, event.(events.GoalScored))
+	})
+
+	bus.Subscribe(events.TypeDebateGenerated, "push_notifications", func(ctx context.Context, event events.Event) error {
+		return c.notifyNewDebate(ctx, event.(events.DebateGenerated))
+	})
+}
+
+// fixtureStateTTL is how long the last seen state of a fixture is remembered
+const fixtureStateTTL = 7 * 24 * time.Hour
+
+// fixtureState is what's remembered about a fixture between football API reads
+type fixtureState struct {
+	Status    string `json:"status"`
+	HomeGoals int32  `json:"home_goals"`
+	AwayGoals int32  `json:"away_goals"`
+}
+
+// trackFixture compares a fixture with the last time it was read, raising
+// MatchStatusChanged when its status moves and GoalScored when the score goes up.
+// The first sighting of a fixture only records it. current.FromStatus is ignored.
+func (c *Config) trackFixture(ctx context.Context, current events.MatchStatusChanged) {
+	if c.Cache == nil || c.DBConn == nil || current.ToStatus == "" {
+		return
+	}
+
+	state := fixtureState{Status: current.ToStatus, HomeGoals: current.HomeGoals, AwayGoals: current.AwayGoals}
+	cacheKey := fmt.Sprintf("fixture_state:%s", current.MatchID)
+	exists, err := c.Cache.Exists(ctx, cacheKey)
+	if err != nil {
+		log.Printf("Cache check error: %v\n", err)
+		return
+	}
+	if exists {
+		var previous fixtureState
+		if err := c.Cache.Get(ctx, cacheKey, &previous); err != nil {
+			log.Printf("Cache get error: %v\n", err)
+			return
+		}
+		if previous == state {
+			return
+		}
+
+		var changes []events.Event
+		if previous.Status != state.Status {
+			current.FromStatus = previous.Status
+			changes = append(changes, current)
+		}
+		if state.HomeGoals+state.AwayGoals > previous.HomeGoals+previous.AwayGoals {
+			changes = append(changes, events.GoalScored{
+				MatchID:   current.MatchID,
+				LeagueID:  current.LeagueID,
+				HomeTeam:  current.HomeTeam,
+				AwayTeam:  current.AwayTeam,
+				HomeGoals: state.HomeGoals,
+				AwayGoals: state.AwayGoals,
+				Status:    state.Status,
+			})
+		}
+		for _, event := range changes {
+			if err := events.Append(ctx, c.DBConn, event); err != nil {
+				log.Printf("Failed to record %s for match %s: %v\n", event.EventType(), current.MatchID, err)
+				return
+			}
+		}
+	}
+
+	if err := c.Cache.Set(ctx, cacheKey, state, fixtureStateTTL); err != nil {
+		log.Printf("Cache set error: %v\n", err)
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+)
+
+func TestSubscribeRegistersHandlers(t *testing.T) {
+	bus := events.NewBus()
+	(&Config{}).Subscribe(bus)
+
+	got := bus.Types()
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	want := []events.Type{
+		events.TypeCommentPosted,
+		events.TypeDebateGenerated,
+		events.TypeGoalScored,
+		events.TypeMatchStatusChanged,
+		events.TypeVerificationAdded,
+		events.TypeVoteCast,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("subscribed types = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("subscribed types = %v, want %v", got, want)
+			break
+		}
+	}
+}
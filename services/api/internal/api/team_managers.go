@@ -1,7 +1,6 @@
 package api
 
 import (
-	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -14,15 +13,18 @@ import (
 
 // TeamManagersService handles team manager-related operations
 type TeamManagersService struct {
-	db *database.Queries
+	db     *database.Queries
+	dbConn *sql.DB
 }
 
 // NewTeamManagersService creates a new team managers service
-func NewTeamManagersService(db *database.Queries) *TeamManagersService {
-	return &TeamManagersService{db: db}
+func NewTeamManagersService(db *database.Queries, dbConn *sql.DB) *TeamManagersService {
+	return &TeamManagersService{db: db, dbConn: dbConn}
 }
 
-// CreateTeamManager creates a new team manager
+// CreateTeamManager creates a new team manager and promotes their user to
+// the team_manager role. It's for admins; league owners invite managers
+// instead.
 func (s *TeamManagersService) CreateTeamManager(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		UserID     int32  `json:"user_id"`
@@ -31,6 +33,7 @@ func (s *TeamManagersService) CreateTeamManager(w http.ResponseWriter, r *http.R
 		Title      string `json:"title"`
 		Experience int32  `json:"experience"`
 		Bio        string `json:"bio"`
+		Role       string `json:"role"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -42,6 +45,13 @@ func (s *TeamManagersService) CreateTeamManager(w http.ResponseWriter, r *http.R
 		http.Error(w, "league_id is required", http.StatusBadRequest)
 		return
 	}
+	if req.Role == "" {
+		req.Role = managerHeadCoach
+	}
+	if !validManagerRole(req.Role) {
+		http.Error(w, "Invalid role", http.StatusBadRequest)
+		return
+	}
 
 	// Parse UUIDs
 	var leagueIDParsed uuid.UUID
@@ -64,13 +74,6 @@ func (s *TeamManagersService) CreateTeamManager(w http.ResponseWriter, r *http.R
 		teamID = &parsedTeamID
 	}
 
-	// Check if current user has permission to create managers in this league
-	currentUserID := getUserIDFromContext(r)
-	if !s.hasManagerPermission(r.Context(), currentUserID, leagueIDParsed, "create") {
-		http.Error(w, "Insufficient permissions", http.StatusForbidden)
-		return
-	}
-
 	// Check if user exists
 	_, err := s.db.GetUser(r.Context(), req.UserID)
 	if err != nil {
@@ -124,11 +127,16 @@ func (s *TeamManagersService) CreateTeamManager(w http.ResponseWriter, r *http.R
 		Title:      sql.NullString{String: req.Title, Valid: req.Title != ""},
 		Experience: sql.NullInt32{Int32: req.Experience, Valid: req.Experience > 0},
 		Bio:        sql.NullString{String: req.Bio, Valid: req.Bio != ""},
+		Role:       req.Role,
 	})
 	if err != nil {
 		http.Error(w, "Failed to create team manager", http.StatusInternalServerError)
 		return
 	}
+	if err := s.db.PromoteToTeamManager(r.Context(), req.UserID); err != nil {
+		http.Error(w, "Failed to update user role", http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -247,6 +255,12 @@ func (s *TeamManagersService) UpdateTeamManager(w http.ResponseWriter, r *http.R
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// Moving a manager to another team goes through an invitation, so the
+	// team's league and manager limits are checked
+	if req.TeamID != nil {
+		http.Error(w, "team_id can't be changed, invite the manager to the other team instead", http.StatusBadRequest)
+		return
+	}
 
 	// Get current manager
 	manager, err := s.db.GetTeamManager(r.Context(), managerID)
@@ -260,8 +274,12 @@ func (s *TeamManagersService) UpdateTeamManager(w http.ResponseWriter, r *http.R
 	}
 
 	// Check permissions
-	currentUserID := getUserIDFromContext(r)
-	if !s.hasManagerPermission(r.Context(), currentUserID, manager.LeagueID, "update") {
+	allowed, err := s.ownsLeague(r, manager.LeagueID)
+	if err != nil {
+		http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
 		http.Error(w, "Insufficient permissions", http.StatusForbidden)
 		return
 	}
@@ -272,21 +290,6 @@ func (s *TeamManagersService) UpdateTeamManager(w http.ResponseWriter, r *http.R
 	}
 
 	// Update fields if provided
-	if req.TeamID != nil {
-		if *req.TeamID == "" {
-			params.TeamID = uuid.NullUUID{Valid: false}
-		} else {
-			parsedTeamID, err := uuid.Parse(*req.TeamID)
-			if err != nil {
-				http.Error(w, "Invalid team_id format", http.StatusBadRequest)
-				return
-			}
-			params.TeamID = uuid.NullUUID{UUID: parsedTeamID, Valid: true}
-		}
-	} else {
-		params.TeamID = manager.TeamID
-	}
-
 	if req.Title != nil {
 		params.Title = sql.NullString{String: *req.Title, Valid: *req.Title != ""}
 	} else {
@@ -305,19 +308,6 @@ func (s *TeamManagersService) UpdateTeamManager(w http.ResponseWriter, r *http.R
 		params.Bio = manager.Bio
 	}
 
-	// Validate team exists if changing
-	if req.TeamID != nil && params.TeamID.Valid {
-		_, err := s.db.GetTeam(r.Context(), params.TeamID.UUID)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				http.Error(w, "Team not found", http.StatusNotFound)
-				return
-			}
-			http.Error(w, "Failed to get team", http.StatusInternalServerError)
-			return
-		}
-	}
-
 	// Update team manager
 	updatedManager, err := s.db.UpdateTeamManager(r.Context(), params)
 	if err != nil {
@@ -349,14 +339,21 @@ func (s *TeamManagersService) DeleteTeamManager(w http.ResponseWriter, r *http.R
 	}
 
 	// Check permissions
-	currentUserID := getUserIDFromContext(r)
-	if !s.hasManagerPermission(r.Context(), currentUserID, manager.LeagueID, "delete") {
+	allowed, err := s.ownsLeague(r, manager.LeagueID)
+	if err != nil {
+		http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
 		http.Error(w, "Insufficient permissions", http.StatusForbidden)
 		return
 	}
 
-	err = s.db.DeleteTeamManager(r.Context(), managerID)
-	if err != nil {
+	if s.dbConn == nil {
+		http.Error(w, "Managers can't be removed right now", http.StatusServiceUnavailable)
+		return
+	}
+	if err := removeTeamManager(r.Context(), s.dbConn, s.db, manager); err != nil {
 		http.Error(w, "Failed to delete team manager", http.StatusInternalServerError)
 		return
 	}
@@ -435,9 +432,21 @@ func (s *TeamManagersService) GetManagerStats(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(stats)
 }
 
-// hasManagerPermission checks if user has permission for manager operations
-func (s *TeamManagersService) hasManagerPermission(ctx context.Context, userID, leagueID uuid.UUID, operation string) bool {
-	// For now, return true for all operations since we're using placeholder user IDs
-	// In a real implementation, this would check user permissions properly
-	return true
+// ownsLeague reports whether the caller is an admin or the league's owner
+func (s *TeamManagersService) ownsLeague(r *http.Request, leagueID uuid.UUID) (bool, error) {
+	if role, _ := r.Context().Value("user_role").(string); role == "admin" {
+		return true, nil
+	}
+	userID, ok := r.Context().Value("user_id").(int32)
+	if !ok {
+		return false, nil
+	}
+	league, err := s.db.GetLeague(r.Context(), leagueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return league.OwnerID == userID, nil
 }
@@ -12,6 +12,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// TeamResponse is a team with its follower count
+type TeamResponse struct {
+	database.Team
+	FollowStats
+}
+
 // TeamsService handles team-related operations
 type TeamsService struct {
 	db *database.Queries
@@ -141,8 +147,14 @@ func (s *TeamsService) GetTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	responses, err := s.withFollowStats(r, []database.Team{team})
+	if err != nil {
+		http.Error(w, "Failed to count followers", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(team)
+	json.NewEncoder(w).Encode(responses[0])
 }
 
 // ListTeams retrieves all teams with optional filtering
@@ -210,8 +222,33 @@ func (s *TeamsService) ListTeams(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	responses, err := s.withFollowStats(r, teams)
+	if err != nil {
+		http.Error(w, "Failed to count followers", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(teams)
+	json.NewEncoder(w).Encode(responses)
+}
+
+// withFollowStats adds follower counts, and whether the caller follows each team
+func (s *TeamsService) withFollowStats(r *http.Request, teams []database.Team) ([]TeamResponse, error) {
+	ids := make([]uuid.UUID, len(teams))
+	for i, team := range teams {
+		ids[i] = team.ID
+	}
+	userID, _ := r.Context().Value("user_id").(int32)
+	stats, err := followStats(r.Context(), s.db, database.FollowableTypeTeam, ids, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]TeamResponse, len(teams))
+	for i, team := range teams {
+		responses[i] = TeamResponse{Team: team, FollowStats: stats[team.ID]}
+	}
+	return responses, nil
 }
 
 // UpdateTeam updates a team
@@ -424,28 +461,22 @@ func (s *TeamsService) GetTeamStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Calculate stats
-	var totalRating float64
-	var totalVerifications int32
-	playerCount := int32(len(players))
-
-	for _, player := range players {
-		// Calculate average rating from individual stats
-		playerRating := float64(player.Pace+player.Shooting+player.Passing+player.Stamina+player.Dribbling+player.Defending+player.Physical) / 7.0
-		totalRating += playerRating
-		// Note: GetPlayersByTeamRow doesn't have verification count, so skip for now
+	// Average the managers' ratings from confirmed fixtures
+	ratings, err := s.db.GetTeamRatingTotals(r.Context(), teamID)
+	if err != nil {
+		http.Error(w, "Failed to get team ratings", http.StatusInternalServerError)
+		return
 	}
 
-	var avgRating float64
-	if playerCount > 0 {
-		avgRating = totalRating / float64(playerCount)
-	}
+	var totalVerifications int32
+	playerCount := int32(len(players))
+	// Note: GetPlayersByTeamRow doesn't have verification count, so skip for now
 
 	stats := map[string]interface{}{
 		"team_id":             team.ID,
 		"team_name":           team.Name,
 		"player_count":        playerCount,
-		"average_rating":      avgRating,
+		"average_rating":      averageRating(ratings.RatingTotal, ratings.Ratings),
 		"total_verifications": totalVerifications,
 		"players":             players,
 	}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+)
+
+// Trending uses the same idea as Reddit's "hot" ranking: the order of magnitude
+// of a debate's engagement plus a bonus that grows with its creation time.
+// Ordering by it is equivalent to decaying every score by age (Hacker News
+// gravity), but the stored value only changes when engagement changes, so it
+// can be updated incrementally on each vote or comment and indexed.
+const (
+	// trendingGravity is how many seconds newer a debate has to be to outrank
+	// one with ten times its engagement
+	trendingGravity = 45000.0
+
+	trendingVoteWeight    = 1.0
+	trendingCommentWeight = 2.0
+	// Reward debates that draw in many different fans over a few loud ones
+	trendingParticipantWeight = 3.0
+
+	trendingDefaultLimit = 10
+	trendingMaxLimit     = 50
+)
+
+// trendingEpoch keeps the time bonus small enough to stay precise
+var trendingEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// trendingWindows maps the accepted window filters to how far back they look.
+// "all" is also accepted and applies no window.
+var trendingWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+const defaultTrendingWindow = "7d"
+
+// trendingScore computes the hot score of a debate from its engagement and creation time
+func trendingScore(votes, comments, participants int, createdAt time.Time) float64 {
+	points := float64(votes)*trendingVoteWeight +
+		float64(comments)*trendingCommentWeight +
+		float64(participants)*trendingParticipantWeight
+
+	order := math.Log10(math.Max(points, 1))
+	age := createdAt.Sub(trendingEpoch).Seconds()
+	return order + age/trendingGravity
+}
+
+// parseTrendingWindow returns the earliest creation time for the window, or an
+// invalid time for "all"
+func parseTrendingWindow(window string, now time.Time) (sql.NullTime, error) {
+	if window == "" {
+		window = defaultTrendingWindow
+	}
+	if window == "all" {
+		return sql.NullTime{}, nil
+	}
+	duration, ok := trendingWindows[window]
+	if !ok {
+		return sql.NullTime{}, fmt.Errorf("window must be one of '24h', '7d', '30d' or 'all'")
+	}
+	return sql.NullTime{Time: now.Add(-duration), Valid: true}, nil
+}
+
+// getTrendingDebates lists published debates by trending score
+func (c *Config) getTrendingDebates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	since, err := parseTrendingWindow(r.URL.Query().Get("window"), time.Now())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var leagueID sql.NullInt32
+	if leagueStr := r.URL.Query().Get("league"); leagueStr != "" {
+		id, err := strconv.ParseInt(leagueStr, 10, 32)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "league must be a numeric league ID")
+			return
+		}
+		leagueID = sql.NullInt32{Int32: int32(id), Valid: true}
+	}
+
+	limit := int32(trendingDefaultLimit)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.ParseInt(limitStr, 10, 32); err == nil && l > 0 {
+			limit = int32(min(l, trendingMaxLimit))
+		}
+	}
+
+	rows, err := c.DB.GetTrendingDebates(ctx, database.GetTrendingDebatesParams{
+		Since:      since,
+		LeagueID:   leagueID,
+		MaxResults: limit,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get trending debates: %v", err))
+		return
+	}
+
+	response := make([]DebateResponse, len(rows))
+	responsePtrs := make([]*DebateResponse, len(rows))
+	debates := make([]database.Debate, len(rows))
+	for i, row := range rows {
+		debates[i] = database.Debate{
+			ID:          row.ID,
+			MatchID:     row.MatchID,
+			DebateType:  row.DebateType,
+			Headline:    row.Headline,
+			Description: row.Description,
+			AiGenerated: row.AiGenerated,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+			AuthorID:    row.AuthorID,
+			Status:      row.Status,
+			PromotedAt:  row.PromotedAt,
+			LeagueID:    row.LeagueID,
+		}
+
+		engagementScore := 0.0
+		if row.EngagementScore.Valid {
+			if score, err := strconv.ParseFloat(row.EngagementScore.String, 64); err == nil {
+				engagementScore = score
+			}
+		}
+
+		response[i] = newDebateResponse(debates[i])
+		response[i].Analytics = &DebateAnalyticsResponse{
+			ID:                 row.ID,
+			DebateID:           row.ID,
+			TotalVotes:         int(row.TotalVotes.Int32),
+			TotalComments:      int(row.TotalComments.Int32),
+			UniqueParticipants: int(row.UniqueParticipants),
+			EngagementScore:    engagementScore,
+			TrendingScore:      row.TrendingScore,
+			CreatedAt:          row.CreatedAt.Time,
+			UpdatedAt:          row.UpdatedAt.Time,
+		}
+		responsePtrs[i] = &response[i]
+	}
+	c.attachDebateAuthors(ctx, responsePtrs, debates)
+
+	respondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTrendingScore(t *testing.T) {
+	now := time.Date(2025, 10, 20, 20, 0, 0, 0, time.UTC)
+	lastSeason := now.AddDate(-1, 0, 0)
+
+	if trendingScore(5, 2, 4, now) <= trendingScore(500, 200, 150, lastSeason) {
+		t.Error("Expected tonight's debate to outrank a busier debate from last season")
+	}
+
+	if trendingScore(10, 0, 0, now) <= trendingScore(5, 0, 0, now) {
+		t.Error("Expected more engagement to rank higher at the same age")
+	}
+
+	// Ten times the engagement offsets exactly one gravity period of age
+	older := now.Add(-time.Duration(trendingGravity) * time.Second)
+	diff := trendingScore(100, 0, 0, older) - trendingScore(10, 0, 0, now)
+	if math.Abs(diff) > 1e-9 {
+		t.Errorf("Expected equal scores, got difference %v", diff)
+	}
+
+	// Scores past the old DECIMAL(5,2) limit stay finite and ordered
+	if trendingScore(5000, 3000, 2000, now) <= trendingScore(999, 0, 0, now) {
+		t.Error("Expected large engagement to keep ranking higher")
+	}
+}
+
+func TestParseTrendingWindow(t *testing.T) {
+	now := time.Date(2025, 10, 20, 20, 0, 0, 0, time.UTC)
+
+	since, err := parseTrendingWindow("", now)
+	if err != nil || !since.Valid || !since.Time.Equal(now.Add(-7*24*time.Hour)) {
+		t.Errorf("Expected default 7d window, got %+v (err %v)", since, err)
+	}
+
+	since, err = parseTrendingWindow("24h", now)
+	if err != nil || !since.Time.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("Expected 24h window, got %+v (err %v)", since, err)
+	}
+
+	since, err = parseTrendingWindow("all", now)
+	if err != nil || since.Valid {
+		t.Errorf("Expected no window for 'all', got %+v (err %v)", since, err)
+	}
+
+	if _, err := parseTrendingWindow("1y", now); err == nil {
+		t.Error("Expected error for unsupported window")
+	}
+}
@@ -177,31 +177,35 @@ type GetLeaguesResponse struct {
 			Code any    `json:"code"`
 			Flag any    `json:"flag"`
 		} `json:"country"`
-		Seasons []struct {
-			Year     int    `json:"year"`
-			Start    string `json:"start"`
-			End      string `json:"end"`
-			Current  bool   `json:"current"`
-			Coverage struct {
-				Fixtures struct {
-					Events             bool `json:"events"`
-					Lineups            bool `json:"lineups"`
-					StatisticsFixtures bool `json:"statistics_fixtures"`
-					StatisticsPlayers  bool `json:"statistics_players"`
-				} `json:"fixtures"`
-				Standings   bool `json:"standings"`
-				Players     bool `json:"players"`
-				TopScorers  bool `json:"top_scorers"`
-				TopAssists  bool `json:"top_assists"`
-				TopCards    bool `json:"top_cards"`
-				Injuries    bool `json:"injuries"`
-				Predictions bool `json:"predictions"`
-				Odds        bool `json:"odds"`
-			} `json:"coverage"`
-		} `json:"seasons"`
+		Seasons []LeagueSeason `json:"seasons"`
 	} `json:"response"`
 }
 
+// LeagueSeason is a season of an API-Football league. Start and End are
+// YYYY-MM-DD.
+type LeagueSeason struct {
+	Year     int    `json:"year"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Current  bool   `json:"current"`
+	Coverage struct {
+		Fixtures struct {
+			Events             bool `json:"events"`
+			Lineups            bool `json:"lineups"`
+			StatisticsFixtures bool `json:"statistics_fixtures"`
+			StatisticsPlayers  bool `json:"statistics_players"`
+		} `json:"fixtures"`
+		Standings   bool `json:"standings"`
+		Players     bool `json:"players"`
+		TopScorers  bool `json:"top_scorers"`
+		TopAssists  bool `json:"top_assists"`
+		TopCards    bool `json:"top_cards"`
+		Injuries    bool `json:"injuries"`
+		Predictions bool `json:"predictions"`
+		Odds        bool `json:"odds"`
+	} `json:"coverage"`
+}
+
 type GetLeagueStandingsByLeagueIdResponse struct {
 	Get        string `json:"get"`
 	Parameters struct {
@@ -66,6 +66,7 @@ func (config *Config) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error creating user: %s", err))
 		return
 	}
+	config.sendSignUpVerification(r.Context(), user.ID)
 	respondWithJSON(w, http.StatusCreated, user)
 }
 
@@ -1,23 +1,103 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/pkg/blobstore"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+	"github.com/ArronJLinton/fucci-api/pkg/media"
+	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 )
 
+// verificationThreshold is how many qualifying verifiers make a player verified
+const verificationThreshold = 3
+
+// Who a verification came from, stored in verifications.verifier_role
+const (
+	verifierManager = "manager" // A manager in the player's league, or its owner
+	verifierPlayer  = "player"  // A verified player in the player's league
+)
+
+// Why a player's is_verified changed, stored in verification_audit_log.reason
+const (
+	verificationReasonAdded   = "verification_added"
+	verificationReasonRemoved = "verification_removed"
+	verificationReasonExpired = "expired"
+)
+
 type VerificationService struct {
 	DB               *database.Queries
+	DBConn           *sql.DB
+	Blobs            blobstore.BlobStore // Optional, evidence uploads are disabled when nil
 	PlayerProfileSvc *PlayerProfileService
 }
 
-// Handler: Add verification
+type VerificationResponse struct {
+	ID              uuid.UUID                      `json:"id"`
+	PlayerProfileID uuid.UUID                      `json:"player_profile_id"`
+	VerifierUserID  int32                          `json:"verifier_user_id"`
+	VerifierRole    *string                        `json:"verifier_role"` // null for verifications from before roles, which don't count
+	TeamID          *uuid.UUID                     `json:"team_id"`
+	LeagueID        *uuid.UUID                     `json:"league_id"`
+	SeasonID        *uuid.UUID                     `json:"season_id"`
+	Note            string                         `json:"note,omitempty"`
+	ExpiresAt       *time.Time                     `json:"expires_at"`
+	Counts          bool                           `json:"counts"` // Whether it counts towards the player being verified
+	Evidence        []VerificationEvidenceResponse `json:"evidence"`
+	CreatedAt       time.Time                      `json:"created_at"`
+}
+
+type VerificationEvidenceResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Type        string    `json:"type"` // image or video
+	ContentType string    `json:"content_type"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	UploadedBy  *int32    `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type VerificationAuditResponse struct {
+	WasVerified    bool       `json:"was_verified"`
+	IsVerified     bool       `json:"is_verified"`
+	Reason         string     `json:"reason"`
+	VerificationID *uuid.UUID `json:"verification_id,omitempty"`
+	ActorUserID    *int32     `json:"actor_user_id,omitempty"`
+	Verifications  int32      `json:"verifications"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// verificationChange is what prompted a player's verification to be recalculated
+type verificationChange struct {
+	Reason         string
+	VerificationID uuid.NullUUID
+	ActorUserID    sql.NullInt32
+}
+
+// Handler: Add verification. The caller vouches for a player in their
+// league, as a manager there or a verified player on one of its teams. It
+// lapses when the league's current season ends.
 func (svc *VerificationService) AddVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value("user_id").(int32)
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
 	var req struct {
 		PlayerProfileID string `json:"player_profile_id"`
-		VerifierUserID  int64  `json:"verifier_user_id"`
+		Note            string `json:"note"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request", http.StatusBadRequest)
@@ -28,47 +108,188 @@ func (svc *VerificationService) AddVerification(w http.ResponseWriter, r *http.R
 		http.Error(w, "invalid profile id", http.StatusBadRequest)
 		return
 	}
+	profile, err := svc.DB.GetPlayerProfile(ctx, profileID)
+	if err != nil {
+		http.Error(w, "player profile not found", http.StatusNotFound)
+		return
+	}
+	if profile.UserID == userID {
+		http.Error(w, "you can't verify your own profile", http.StatusForbidden)
+		return
+	}
+
+	// Verifiers have to know the player through their league
+	if !profile.TeamID.Valid {
+		http.Error(w, "players can only be verified once they're on a team", http.StatusConflict)
+		return
+	}
+	team, err := svc.DB.GetTeam(ctx, profile.TeamID.UUID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !team.LeagueID.Valid {
+		http.Error(w, "players can only be verified on a league team", http.StatusConflict)
+		return
+	}
+	eligibility, err := svc.DB.GetVerifierEligibility(ctx, database.GetVerifierEligibilityParams{VerifierID: userID, LeagueID: team.LeagueID.UUID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	role, ok := verifierRole(eligibility)
+	if !ok {
+		http.Error(w, "only managers and verified players in the player's league can verify them", http.StatusForbidden)
+		return
+	}
+
 	params := database.CreateVerificationParams{
 		PlayerProfileID: profileID,
-		VerifierUserID:  int32(req.VerifierUserID),
+		VerifierUserID:  userID,
+		// The team the player was on when they were vouched for
+		TeamID:       profile.TeamID,
+		LeagueID:     team.LeagueID,
+		VerifierRole: sql.NullString{String: role, Valid: true},
+		Note:         sql.NullString{String: req.Note, Valid: req.Note != ""},
+	}
+	// Verifications last until the season running today ends, or the next one
+	// when the league is between seasons
+	season, err := svc.DB.GetVerificationSeason(ctx, database.GetVerificationSeasonParams{LeagueID: team.LeagueID.UUID, Today: time.Now().UTC()})
+	switch {
+	case err == nil:
+		params.SeasonID = uuid.NullUUID{UUID: season.ID, Valid: true}
+		params.ExpiresAt = sql.NullTime{Time: verificationExpiry(season), Valid: true}
+	case err == sql.ErrNoRows:
+		// Leagues without seasons have verifications that don't lapse, but one
+		// whose seasons have all ended would only create expired verifications
+		seasons, err := svc.DB.ListLeagueSeasons(ctx, database.ListLeagueSeasonsParams{LeagueID: team.LeagueID.UUID, IncludeArchived: true})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(seasons) > 0 {
+			http.Error(w, "the player's league has no current or upcoming season", http.StatusConflict)
+			return
+		}
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if svc.DBConn == nil {
+		http.Error(w, "verifications can't be added right now", http.StatusServiceUnavailable)
+		return
+	}
+	tx, err := svc.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	verification, err := svc.DB.CreateVerification(r.Context(), params)
+	defer tx.Rollback()
+
+	verification, err := svc.DB.WithTx(tx).CreateVerification(ctx, params)
 	if err != nil {
+		if isUniqueViolation(err) {
+			http.Error(w, "you've already verified this player this season", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Verification status and achievements are recalculated by the VerificationAdded subscribers
+	if err := events.Append(ctx, tx, events.VerificationAdded{
+		VerificationID:  verification.ID.String(),
+		PlayerProfileID: profileID.String(),
+		PlayerUserID:    profile.UserID,
+		VerifierUserID:  verification.VerifierUserID,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	// Recalculate verification status
-	svc.PlayerProfileSvc.RecalculateIsVerified(r.Context(), req.PlayerProfileID)
-	json.NewEncoder(w).Encode(verification)
+	json.NewEncoder(w).Encode(newVerificationResponse(verification, nil, time.Now().UTC()))
 }
 
-// Handler: Remove verification
+// Handler: Remove verification. Only the verifier or an admin can remove it.
 func (svc *VerificationService) RemoveVerification(w http.ResponseWriter, r *http.Request) {
-	idStr := r.URL.Query().Get("id")
-	id, err := uuid.Parse(idStr)
+	ctx := r.Context()
+
+	userID, ok := ctx.Value("user_id").(int32)
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
-	// Get verification to find player_profile_id
-	verification, err := svc.DB.GetVerification(r.Context(), id)
+	verification, err := svc.DB.GetVerification(ctx, id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		if err == sql.ErrNoRows {
+			http.Error(w, "verification not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if err := svc.DB.DeleteVerification(r.Context(), id); err != nil {
+	if role, _ := ctx.Value("user_role").(string); role != "admin" && verification.VerifierUserID != userID {
+		http.Error(w, "only the verifier can remove their verification", http.StatusForbidden)
+		return
+	}
+
+	evidence, err := svc.DB.ListVerificationEvidence(ctx, id)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	// Recalculate verification status
-	svc.PlayerProfileSvc.RecalculateIsVerified(r.Context(), verification.PlayerProfileID.String())
+	if svc.DBConn == nil {
+		http.Error(w, "verifications can't be removed right now", http.StatusServiceUnavailable)
+		return
+	}
+	tx, err := svc.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// The player's status is recalculated with the delete, so they're never
+	// left verified by a verification that's gone
+	qtx := svc.DB.WithTx(tx)
+	if err := qtx.DeleteVerification(ctx, id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := recalculateIsVerified(ctx, qtx, verification.PlayerProfileID, verificationChange{
+		Reason:         verificationReasonRemoved,
+		VerificationID: uuid.NullUUID{UUID: id, Valid: true},
+		ActorUserID:    sql.NullInt32{Int32: userID, Valid: true},
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if svc.Blobs != nil {
+		for _, item := range evidence {
+			if err := svc.Blobs.Delete(ctx, item.StorageKey); err != nil {
+				log.Printf("Failed to delete evidence %s: %v\n", item.StorageKey, err)
+			}
+		}
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// Handler: List verifications for a player
+// Handler: List verifications for a player, newest first, with their evidence
 func (svc *VerificationService) ListVerifications(w http.ResponseWriter, r *http.Request) {
-	profileIDStr := r.URL.Query().Get("player_profile_id")
-	profileID, err := uuid.Parse(profileIDStr)
+	profileID, err := uuid.Parse(chi.URLParam(r, "playerId"))
 	if err != nil {
 		http.Error(w, "invalid profile id", http.StatusBadRequest)
 		return
@@ -78,5 +299,265 @@ func (svc *VerificationService) ListVerifications(w http.ResponseWriter, r *http
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	json.NewEncoder(w).Encode(verifications)
+	evidence, err := svc.DB.ListPlayerVerificationEvidence(r.Context(), profileID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	byVerification := make(map[uuid.UUID][]database.VerificationEvidence)
+	for _, item := range evidence {
+		byVerification[item.VerificationID] = append(byVerification[item.VerificationID], item)
+	}
+
+	now := time.Now().UTC()
+	response := make([]VerificationResponse, 0, len(verifications))
+	for _, verification := range verifications {
+		response = append(response, newVerificationResponse(verification, byVerification[verification.ID], now))
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// Handler: List the changes to a player's verified status, newest first
+func (svc *VerificationService) ListVerificationHistory(w http.ResponseWriter, r *http.Request) {
+	profileID, err := uuid.Parse(chi.URLParam(r, "playerId"))
+	if err != nil {
+		http.Error(w, "invalid profile id", http.StatusBadRequest)
+		return
+	}
+	entries, err := svc.DB.ListVerificationAuditLog(r.Context(), profileID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := make([]VerificationAuditResponse, 0, len(entries))
+	for _, entry := range entries {
+		item := VerificationAuditResponse{
+			WasVerified:   entry.WasVerified,
+			IsVerified:    entry.IsVerified,
+			Reason:        entry.Reason,
+			Verifications: entry.Verifications,
+			CreatedAt:     entry.CreatedAt,
+		}
+		if entry.VerificationID.Valid {
+			item.VerificationID = &entry.VerificationID.UUID
+		}
+		if entry.ActorUserID.Valid {
+			item.ActorUserID = &entry.ActorUserID.Int32
+		}
+		response = append(response, item)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// Handler: Attach a photo or video to a verification, as multipart form data
+// in the "file" field with an optional "description". The verifier and the
+// player can add evidence.
+func (svc *VerificationService) AddEvidence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value("user_id").(int32)
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if svc.Blobs == nil {
+		http.Error(w, "evidence uploads are not available", http.StatusServiceUnavailable)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	verification, err := svc.DB.GetVerification(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "verification not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if verification.VerifierUserID != userID {
+		profile, err := svc.DB.GetPlayerProfile(ctx, verification.PlayerProfileID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if profile.UserID != userID {
+			http.Error(w, "only the verifier and the player can add evidence", http.StatusForbidden)
+			return
+		}
+	}
+
+	file, header, ok := formFile(w, r, media.MaxVideoSize)
+	if !ok {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		http.Error(w, "failed to read upload", http.StatusBadRequest)
+		return
+	}
+	contentType, mediaType, extension, err := media.Detect(head[:n])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unsupported media type %s; upload a JPEG, PNG, GIF, MP4 or WebM", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+	if header.Size > media.MaxSize(mediaType) {
+		http.Error(w, fmt.Sprintf("%s uploads are limited to %d MB", mediaType, media.MaxSize(mediaType)>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, "failed to read upload", http.StatusInternalServerError)
+		return
+	}
+
+	key := fmt.Sprintf("evidence/verifications/%s/%s%s", id, uuid.New(), extension)
+	if err := svc.Blobs.Put(ctx, key, file, header.Size, contentType); err != nil {
+		http.Error(w, fmt.Sprintf("failed to store upload: %v", err), http.StatusInternalServerError)
+		return
+	}
+	description := r.FormValue("description")
+	item, err := svc.DB.CreateVerificationEvidence(ctx, database.CreateVerificationEvidenceParams{
+		VerificationID: id,
+		UploadedBy:     sql.NullInt32{Int32: userID, Valid: true},
+		MediaType:      mediaType,
+		ContentType:    contentType,
+		Url:            svc.Blobs.URL(key),
+		StorageKey:     key,
+		Description:    sql.NullString{String: description, Valid: description != ""},
+	})
+	if err != nil {
+		if err := svc.Blobs.Delete(ctx, key); err != nil {
+			log.Printf("Failed to remove orphaned upload %s: %v\n", key, err)
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newVerificationEvidenceResponse(item))
+}
+
+// verifierRole is the role a verifier vouches with, preferring manager. ok is
+// false for users who can't verify players in the league.
+func verifierRole(eligibility database.GetVerifierEligibilityRow) (role string, ok bool) {
+	switch {
+	case eligibility.Manager:
+		return verifierManager, true
+	case eligibility.VerifiedPlayer:
+		return verifierPlayer, true
+	}
+	return "", false
+}
+
+// verificationExpiry is when verifications given during season lapse, the
+// end of its last day
+func verificationExpiry(season database.Season) time.Time {
+	end := season.EndDate.UTC()
+	return time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+}
+
+// verificationCounts reports whether a verification counts towards the
+// player being verified at now
+func verificationCounts(verification database.Verification, now time.Time) bool {
+	return verification.VerifierRole.Valid && (!verification.ExpiresAt.Valid || verification.ExpiresAt.Time.After(now))
+}
+
+func newVerificationResponse(verification database.Verification, evidence []database.VerificationEvidence, now time.Time) VerificationResponse {
+	response := VerificationResponse{
+		ID:              verification.ID,
+		PlayerProfileID: verification.PlayerProfileID,
+		VerifierUserID:  verification.VerifierUserID,
+		Note:            verification.Note.String,
+		Counts:          verificationCounts(verification, now),
+		Evidence:        make([]VerificationEvidenceResponse, 0, len(evidence)),
+		CreatedAt:       verification.CreatedAt,
+	}
+	if verification.VerifierRole.Valid {
+		response.VerifierRole = &verification.VerifierRole.String
+	}
+	if verification.TeamID.Valid {
+		response.TeamID = &verification.TeamID.UUID
+	}
+	if verification.LeagueID.Valid {
+		response.LeagueID = &verification.LeagueID.UUID
+	}
+	if verification.SeasonID.Valid {
+		response.SeasonID = &verification.SeasonID.UUID
+	}
+	if verification.ExpiresAt.Valid {
+		response.ExpiresAt = &verification.ExpiresAt.Time
+	}
+	for _, item := range evidence {
+		response.Evidence = append(response.Evidence, newVerificationEvidenceResponse(item))
+	}
+	return response
+}
+
+func newVerificationEvidenceResponse(item database.VerificationEvidence) VerificationEvidenceResponse {
+	response := VerificationEvidenceResponse{
+		ID:          item.ID,
+		Type:        item.MediaType,
+		ContentType: item.ContentType,
+		URL:         item.Url,
+		Description: item.Description.String,
+		CreatedAt:   item.CreatedAt,
+	}
+	if item.UploadedBy.Valid {
+		response.UploadedBy = &item.UploadedBy.Int32
+	}
+	return response
+}
+
+// VerificationExpirer unverifies players whose verifications have lapsed
+// with the end of a season
+type VerificationExpirer struct {
+	config *Config
+}
+
+func NewVerificationExpirer(c Config) *VerificationExpirer {
+	return &VerificationExpirer{config: &c}
+}
+
+// Run checks for lapsed verifications every interval until the context is cancelled
+func (ve *VerificationExpirer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ve.ExpireOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireOnce recalculates every verified player who no longer has enough
+// qualifying verifications
+func (ve *VerificationExpirer) ExpireOnce(ctx context.Context) {
+	profiles := &PlayerProfileService{DB: ve.config.DB}
+	ids, err := ve.config.DB.ListLapsedVerifiedProfiles(ctx, database.ListLapsedVerifiedProfilesParams{
+		Now:       time.Now().UTC(),
+		Threshold: verificationThreshold,
+	})
+	if err != nil {
+		log.Printf("Failed to find lapsed verifications: %v\n", err)
+		return
+	}
+	for _, id := range ids {
+		if err := profiles.RecalculateIsVerified(ctx, id.String(), verificationChange{Reason: verificationReasonExpired}); err != nil {
+			log.Printf("Failed to expire verification of %s: %v\n", id, err)
+		}
+	}
+	if len(ids) > 0 {
+		log.Printf("Unverified %d players whose verifications lapsed\n", len(ids))
+	}
 }
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+func TestVerifierRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		eligibility database.GetVerifierEligibilityRow
+		wantRole    string
+		wantOK      bool
+	}{
+		{"neither", database.GetVerifierEligibilityRow{}, "", false},
+		{"verified player", database.GetVerifierEligibilityRow{VerifiedPlayer: true}, verifierPlayer, true},
+		{"manager", database.GetVerifierEligibilityRow{Manager: true}, verifierManager, true},
+		{"both", database.GetVerifierEligibilityRow{Manager: true, VerifiedPlayer: true}, verifierManager, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, ok := verifierRole(tt.eligibility)
+			if role != tt.wantRole || ok != tt.wantOK {
+				t.Errorf("verifierRole() = %q, %v, want %q, %v", role, ok, tt.wantRole, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestVerificationExpiry(t *testing.T) {
+	season := database.Season{EndDate: time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)}
+	want := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	if got := verificationExpiry(season); !got.Equal(want) {
+		t.Errorf("verificationExpiry() = %v, want %v", got, want)
+	}
+}
+
+func TestVerificationCounts(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	role := sql.NullString{String: verifierManager, Valid: true}
+
+	tests := []struct {
+		name         string
+		verification database.Verification
+		want         bool
+	}{
+		{"no season", database.Verification{VerifierRole: role}, true},
+		{"current season", database.Verification{VerifierRole: role, ExpiresAt: sql.NullTime{Time: now.Add(time.Hour), Valid: true}}, true},
+		{"lapsed", database.Verification{VerifierRole: role, ExpiresAt: sql.NullTime{Time: now, Valid: true}}, false},
+		{"from before roles", database.Verification{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verificationCounts(tt.verification, now); got != tt.want {
+				t.Errorf("verificationCounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddVerificationRequiresAuth(t *testing.T) {
+	svc := &VerificationService{}
+	rec := httptest.NewRecorder()
+
+	svc.AddVerification(rec, httptest.NewRequest("POST", "/verifications", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestAddVerificationValidation(t *testing.T) {
+	for _, body := range []string{`not json`, `{"player_profile_id":"abc"}`} {
+		svc := &VerificationService{}
+		rec := httptest.NewRecorder()
+
+		svc.AddVerification(rec, authedRequest("POST", "/verifications", body))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestAddEvidenceWithoutBlobStore(t *testing.T) {
+	svc := &VerificationService{}
+	req := authedRequest("POST", "/verifications/x/evidence", "")
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", uuid.New().String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	svc.AddEvidence(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+}
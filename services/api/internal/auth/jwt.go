@@ -103,17 +103,32 @@ func RequireAuth(next http.Handler) http.Handler {
 			return
 		}
 
-		// Add claims to request context for later use
-		ctx := r.Context()
-		ctx = context.WithValue(ctx, "user_id", claims.UserID)
-		ctx = context.WithValue(ctx, "user_email", claims.Email)
-		ctx = context.WithValue(ctx, "user_role", claims.Role)
-		r = r.WithContext(ctx)
+		next.ServeHTTP(w, withClaims(r, claims))
+	})
+}
 
+// OptionalAuth adds the caller's claims to the context when a valid token is
+// sent, for routes that are public but personalised for signed in users
+func OptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tokenString, err := ExtractToken(r); err == nil {
+			if claims, err := ValidateToken(tokenString); err == nil {
+				r = withClaims(r, claims)
+			}
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// withClaims adds claims to the request context for later use
+func withClaims(r *http.Request, claims *JWTClaims) *http.Request {
+	ctx := r.Context()
+	ctx = context.WithValue(ctx, "user_id", claims.UserID)
+	ctx = context.WithValue(ctx, "user_email", claims.Email)
+	ctx = context.WithValue(ctx, "user_role", claims.Role)
+	return r.WithContext(ctx)
+}
+
 // RequireRole is a middleware that validates user role
 func RequireRole(allowedRoles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -11,6 +11,7 @@ const (
 	LineupTTL      = 12 * time.Hour
 	StandingsTTL   = 6 * time.Hour
 	NewsTTL        = 30 * time.Minute
+	FeedTTL        = 5 * time.Minute
 	DefaultTTL     = 1 * time.Hour
 )
 
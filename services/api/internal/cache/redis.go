@@ -50,6 +50,12 @@ func NewCache(redisURL string) (*Cache, error) {
 	}, nil
 }
 
+// Client exposes the underlying Redis client for data structures the cache
+// interface doesn't cover, such as sorted sets
+func (c *Cache) Client() *redis.Client {
+	return c.client
+}
+
 // Set stores a value in the cache with the given key and expiration time
 func (c *Cache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
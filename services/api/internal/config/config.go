@@ -44,6 +44,22 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("openai_base_url", "https://api.openai.com/v1")
 	viper.SetDefault("port", "8080")
 	viper.SetDefault("environment", "development")
+	viper.SetDefault("news_feed_urls", strings.Join(defaultNewsFeedURLs, ","))
+	viper.SetDefault("news_poll_interval", "15m")
+	viper.SetDefault("debate_resolution_interval", "15m")
+	viper.SetDefault("score_prediction_interval", "15m")
+	viper.SetDefault("score_prediction_exact_points", 5)
+	viper.SetDefault("score_prediction_goal_difference_points", 3)
+	viper.SetDefault("score_prediction_result_points", 2)
+	viper.SetDefault("event_relay_interval", "2s")
+	viper.SetDefault("event_purge_interval", "1h")
+	viper.SetDefault("notification_cleanup_interval", "24h")
+	viper.SetDefault("blob_store", "local")
+	viper.SetDefault("blob_local_dir", "./uploads")
+	viper.SetDefault("blob_public_url", "http://localhost:8080/uploads")
+	viper.SetDefault("s3_region", "us-east-1")
+	viper.SetDefault("image_asset_gc_interval", "1h")
+	viper.SetDefault("verification_expiry_interval", "1h")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -65,5 +81,55 @@ func InitConfig(logger *otelzap.Logger) Config {
 		PORT:             viper.GetString("port"),
 		ENVIRONMENT:      viper.GetString("environment"),
 		JWT_SECRET:       viper.GetString("jwt_secret"),
+
+		NEWS_FEED_URLS:     splitList(viper.GetString("news_feed_urls")),
+		NEWS_POLL_INTERVAL: viper.GetDuration("news_poll_interval"),
+
+		DEBATE_RESOLUTION_INTERVAL: viper.GetDuration("debate_resolution_interval"),
+
+		SCORE_PREDICTION_INTERVAL:               viper.GetDuration("score_prediction_interval"),
+		SCORE_PREDICTION_EXACT_POINTS:           viper.GetInt32("score_prediction_exact_points"),
+		SCORE_PREDICTION_GOAL_DIFFERENCE_POINTS: viper.GetInt32("score_prediction_goal_difference_points"),
+		SCORE_PREDICTION_RESULT_POINTS:          viper.GetInt32("score_prediction_result_points"),
+
+		EVENT_RELAY_INTERVAL: viper.GetDuration("event_relay_interval"),
+		EVENT_PURGE_INTERVAL: viper.GetDuration("event_purge_interval"),
+
+		NOTIFICATION_CLEANUP_INTERVAL: viper.GetDuration("notification_cleanup_interval"),
+
+		BLOB_STORE:           viper.GetString("blob_store"),
+		BLOB_LOCAL_DIR:       viper.GetString("blob_local_dir"),
+		BLOB_PUBLIC_URL:      viper.GetString("blob_public_url"),
+		S3_ENDPOINT:          viper.GetString("s3_endpoint"),
+		S3_REGION:            viper.GetString("s3_region"),
+		S3_BUCKET:            viper.GetString("s3_bucket"),
+		S3_ACCESS_KEY_ID:     viper.GetString("s3_access_key_id"),
+		S3_SECRET_ACCESS_KEY: viper.GetString("s3_secret_access_key"),
+
+		IMAGE_ASSET_GC_INTERVAL: viper.GetDuration("image_asset_gc_interval"),
+
+		VERIFICATION_EXPIRY_INTERVAL: viper.GetDuration("verification_expiry_interval"),
+
+		MAIL_SMTP_URL:          viper.GetString("mail_smtp_url"),
+		MAIL_FROM:              viper.GetString("mail_from"),
+		EMAIL_VERIFICATION_URL: viper.GetString("email_verification_url"),
+	}
+}
+
+// defaultNewsFeedURLs are the football RSS feeds polled when NEWS_FEED_URLS is not set
+var defaultNewsFeedURLs = []string{
+	"https://feeds.bbci.co.uk/sport/football/rss.xml",
+	"https://www.theguardian.com/football/rss",
+	"https://www.espn.com/espn/rss/soccer/news",
+}
+
+// splitList parses a comma separated config value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
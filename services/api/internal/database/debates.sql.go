@@ -12,6 +12,42 @@ import (
 	"github.com/lib/pq"
 )
 
+const countDebateParticipants = `-- name: CountDebateParticipants :one
+SELECT COUNT(DISTINCT participants.user_id) FROM (
+    SELECT v.user_id FROM votes v
+    JOIN debate_cards dc ON v.debate_card_id = dc.id
+    WHERE dc.debate_id = $1
+    UNION
+    SELECT c.user_id FROM comments c
+    WHERE c.debate_id = $1
+) participants
+WHERE participants.user_id IS NOT NULL
+`
+
+func (q *Queries) CountDebateParticipants(ctx context.Context, debateID sql.NullInt32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDebateParticipants, debateID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countDebatesByAuthorForMatch = `-- name: CountDebatesByAuthorForMatch :one
+SELECT COUNT(*) FROM debates
+WHERE match_id = $1 AND author_id = $2 AND deleted_at IS NULL
+`
+
+type CountDebatesByAuthorForMatchParams struct {
+	MatchID  string
+	AuthorID sql.NullInt32
+}
+
+func (q *Queries) CountDebatesByAuthorForMatch(ctx context.Context, arg CountDebatesByAuthorForMatchParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDebatesByAuthorForMatch, arg.MatchID, arg.AuthorID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createComment = `-- name: CreateComment :one
 INSERT INTO comments (debate_id, parent_comment_id, user_id, content)
 VALUES ($1, $2, $3, $4)
@@ -46,9 +82,9 @@ func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (C
 }
 
 const createDebate = `-- name: CreateDebate :one
-INSERT INTO debates (match_id, debate_type, headline, description, ai_generated)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at
+INSERT INTO debates (match_id, debate_type, headline, description, ai_generated, author_id, status, league_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id
 `
 
 type CreateDebateParams struct {
@@ -57,6 +93,9 @@ type CreateDebateParams struct {
 	Headline    string
 	Description sql.NullString
 	AiGenerated sql.NullBool
+	AuthorID    sql.NullInt32
+	Status      string
+	LeagueID    sql.NullInt32
 }
 
 func (q *Queries) CreateDebate(ctx context.Context, arg CreateDebateParams) (Debate, error) {
@@ -66,6 +105,9 @@ func (q *Queries) CreateDebate(ctx context.Context, arg CreateDebateParams) (Deb
 		arg.Headline,
 		arg.Description,
 		arg.AiGenerated,
+		arg.AuthorID,
+		arg.Status,
+		arg.LeagueID,
 	)
 	var i Debate
 	err := row.Scan(
@@ -78,6 +120,11 @@ func (q *Queries) CreateDebate(ctx context.Context, arg CreateDebateParams) (Deb
 		&i.DeletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.PromotedBy,
+		&i.LeagueID,
 	)
 	return i, err
 }
@@ -91,7 +138,7 @@ DO UPDATE SET
     total_comments = $3,
     engagement_score = $4,
     updated_at = CURRENT_TIMESTAMP
-RETURNING id, debate_id, total_votes, total_comments, engagement_score, created_at, updated_at
+RETURNING id, debate_id, total_votes, total_comments, engagement_score, created_at, updated_at, unique_participants, trending_score
 `
 
 type CreateDebateAnalyticsParams struct {
@@ -117,6 +164,8 @@ func (q *Queries) CreateDebateAnalytics(ctx context.Context, arg CreateDebateAna
 		&i.EngagementScore,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UniqueParticipants,
+		&i.TrendingScore,
 	)
 	return i, err
 }
@@ -340,7 +389,7 @@ func (q *Queries) GetComments(ctx context.Context, debateID sql.NullInt32) ([]Ge
 }
 
 const getDebate = `-- name: GetDebate :one
-SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at FROM debates WHERE id = $1 AND deleted_at IS NULL
+SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id FROM debates WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetDebate(ctx context.Context, id int32) (Debate, error) {
@@ -356,12 +405,17 @@ func (q *Queries) GetDebate(ctx context.Context, id int32) (Debate, error) {
 		&i.DeletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.PromotedBy,
+		&i.LeagueID,
 	)
 	return i, err
 }
 
 const getDebateAnalytics = `-- name: GetDebateAnalytics :one
-SELECT id, debate_id, total_votes, total_comments, engagement_score, created_at, updated_at FROM debate_analytics WHERE debate_id = $1
+SELECT id, debate_id, total_votes, total_comments, engagement_score, created_at, updated_at, unique_participants, trending_score FROM debate_analytics WHERE debate_id = $1
 `
 
 func (q *Queries) GetDebateAnalytics(ctx context.Context, debateID sql.NullInt32) (DebateAnalytic, error) {
@@ -375,10 +429,54 @@ func (q *Queries) GetDebateAnalytics(ctx context.Context, debateID sql.NullInt32
 		&i.EngagementScore,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UniqueParticipants,
+		&i.TrendingScore,
 	)
 	return i, err
 }
 
+const getDebateAuthors = `-- name: GetDebateAuthors :many
+SELECT id, firstname, lastname, display_name, avatar_url FROM users
+WHERE id = ANY($1::int[])
+`
+
+type GetDebateAuthorsRow struct {
+	ID          int32
+	Firstname   string
+	Lastname    string
+	DisplayName sql.NullString
+	AvatarUrl   sql.NullString
+}
+
+func (q *Queries) GetDebateAuthors(ctx context.Context, authorIds []int32) ([]GetDebateAuthorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDebateAuthors, pq.Array(authorIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDebateAuthorsRow
+	for rows.Next() {
+		var i GetDebateAuthorsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Firstname,
+			&i.Lastname,
+			&i.DisplayName,
+			&i.AvatarUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getDebateCard = `-- name: GetDebateCard :one
 SELECT id, debate_id, stance, title, description, ai_generated, created_at, updated_at FROM debate_cards WHERE id = $1
 `
@@ -436,7 +534,7 @@ func (q *Queries) GetDebateCards(ctx context.Context, debateID sql.NullInt32) ([
 }
 
 const getDebatesByMatch = `-- name: GetDebatesByMatch :many
-SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at FROM debates 
+SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id FROM debates 
 WHERE match_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -460,6 +558,11 @@ func (q *Queries) GetDebatesByMatch(ctx context.Context, matchID string) ([]Deba
 			&i.DeletedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.PromotedBy,
+			&i.LeagueID,
 		); err != nil {
 			return nil, err
 		}
@@ -475,7 +578,7 @@ func (q *Queries) GetDebatesByMatch(ctx context.Context, matchID string) ([]Deba
 }
 
 const getDebatesByType = `-- name: GetDebatesByType :many
-SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at FROM debates 
+SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id FROM debates 
 WHERE debate_type = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -499,6 +602,11 @@ func (q *Queries) GetDebatesByType(ctx context.Context, debateType string) ([]De
 			&i.DeletedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.PromotedBy,
+			&i.LeagueID,
 		); err != nil {
 			return nil, err
 		}
@@ -515,17 +623,23 @@ func (q *Queries) GetDebatesByType(ctx context.Context, debateType string) ([]De
 
 const getTopDebates = `-- name: GetTopDebates :many
 SELECT 
-    d.id, d.match_id, d.debate_type, d.headline, d.description, d.ai_generated, d.deleted_at, d.created_at, d.updated_at,
+    d.id, d.match_id, d.debate_type, d.headline, d.description, d.ai_generated, d.deleted_at, d.created_at, d.updated_at, d.author_id, d.status, d.promoted_at, d.promoted_by, d.league_id,
     da.total_votes,
     da.total_comments,
     da.engagement_score
 FROM debates d
 LEFT JOIN debate_analytics da ON d.id = da.debate_id
-WHERE d.deleted_at IS NULL
+WHERE d.deleted_at IS NULL AND d.status = 'published'
+  AND ($1::boolean IS NULL OR d.ai_generated = $1)
 ORDER BY da.engagement_score DESC NULLS LAST
-LIMIT $1
+LIMIT $2
 `
 
+type GetTopDebatesParams struct {
+	AiGenerated sql.NullBool
+	MaxResults  int32
+}
+
 type GetTopDebatesRow struct {
 	ID              int32
 	MatchID         string
@@ -536,13 +650,18 @@ type GetTopDebatesRow struct {
 	DeletedAt       sql.NullTime
 	CreatedAt       sql.NullTime
 	UpdatedAt       sql.NullTime
+	AuthorID        sql.NullInt32
+	Status          string
+	PromotedAt      sql.NullTime
+	PromotedBy      sql.NullInt32
+	LeagueID        sql.NullInt32
 	TotalVotes      sql.NullInt32
 	TotalComments   sql.NullInt32
 	EngagementScore sql.NullString
 }
 
-func (q *Queries) GetTopDebates(ctx context.Context, limit int32) ([]GetTopDebatesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTopDebates, limit)
+func (q *Queries) GetTopDebates(ctx context.Context, arg GetTopDebatesParams) ([]GetTopDebatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTopDebates, arg.AiGenerated, arg.MaxResults)
 	if err != nil {
 		return nil, err
 	}
@@ -560,9 +679,102 @@ func (q *Queries) GetTopDebates(ctx context.Context, limit int32) ([]GetTopDebat
 			&i.DeletedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.PromotedBy,
+			&i.LeagueID,
+			&i.TotalVotes,
+			&i.TotalComments,
+			&i.EngagementScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrendingDebates = `-- name: GetTrendingDebates :many
+SELECT
+    d.id, d.match_id, d.debate_type, d.headline, d.description, d.ai_generated, d.deleted_at, d.created_at, d.updated_at, d.author_id, d.status, d.promoted_at, d.promoted_by, d.league_id,
+    da.total_votes,
+    da.total_comments,
+    da.engagement_score,
+    da.unique_participants,
+    da.trending_score
+FROM debates d
+JOIN debate_analytics da ON d.id = da.debate_id
+WHERE d.deleted_at IS NULL AND d.status = 'published'
+  AND ($1::timestamp IS NULL OR d.created_at >= $1)
+  AND ($2::int IS NULL OR d.league_id = $2)
+ORDER BY da.trending_score DESC, d.id DESC
+LIMIT $3
+`
+
+type GetTrendingDebatesParams struct {
+	Since      sql.NullTime
+	LeagueID   sql.NullInt32
+	MaxResults int32
+}
+
+type GetTrendingDebatesRow struct {
+	ID                 int32
+	MatchID            string
+	DebateType         string
+	Headline           string
+	Description        sql.NullString
+	AiGenerated        sql.NullBool
+	DeletedAt          sql.NullTime
+	CreatedAt          sql.NullTime
+	UpdatedAt          sql.NullTime
+	AuthorID           sql.NullInt32
+	Status             string
+	PromotedAt         sql.NullTime
+	PromotedBy         sql.NullInt32
+	LeagueID           sql.NullInt32
+	TotalVotes         sql.NullInt32
+	TotalComments      sql.NullInt32
+	EngagementScore    sql.NullString
+	UniqueParticipants int32
+	TrendingScore      float64
+}
+
+func (q *Queries) GetTrendingDebates(ctx context.Context, arg GetTrendingDebatesParams) ([]GetTrendingDebatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrendingDebates, arg.Since, arg.LeagueID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTrendingDebatesRow
+	for rows.Next() {
+		var i GetTrendingDebatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MatchID,
+			&i.DebateType,
+			&i.Headline,
+			&i.Description,
+			&i.AiGenerated,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.PromotedBy,
+			&i.LeagueID,
 			&i.TotalVotes,
 			&i.TotalComments,
 			&i.EngagementScore,
+			&i.UniqueParticipants,
+			&i.TrendingScore,
 		); err != nil {
 			return nil, err
 		}
@@ -681,6 +893,167 @@ func (q *Queries) GetVotesByCard(ctx context.Context, debateCardID sql.NullInt32
 	return items, nil
 }
 
+const listDebatesByMatch = `-- name: ListDebatesByMatch :many
+SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id FROM debates
+WHERE match_id = $1 AND deleted_at IS NULL
+  AND ($2::text IS NULL OR status = $2)
+  AND ($3::boolean IS NULL OR ai_generated = $3)
+ORDER BY created_at DESC
+`
+
+type ListDebatesByMatchParams struct {
+	MatchID     string
+	Status      sql.NullString
+	AiGenerated sql.NullBool
+}
+
+func (q *Queries) ListDebatesByMatch(ctx context.Context, arg ListDebatesByMatchParams) ([]Debate, error) {
+	rows, err := q.db.QueryContext(ctx, listDebatesByMatch, arg.MatchID, arg.Status, arg.AiGenerated)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Debate
+	for rows.Next() {
+		var i Debate
+		if err := rows.Scan(
+			&i.ID,
+			&i.MatchID,
+			&i.DebateType,
+			&i.Headline,
+			&i.Description,
+			&i.AiGenerated,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.PromotedBy,
+			&i.LeagueID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProposedDebates = `-- name: ListProposedDebates :many
+SELECT id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id FROM debates
+WHERE status = 'proposed' AND deleted_at IS NULL
+ORDER BY created_at ASC
+LIMIT $1
+`
+
+func (q *Queries) ListProposedDebates(ctx context.Context, limit int32) ([]Debate, error) {
+	rows, err := q.db.QueryContext(ctx, listProposedDebates, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Debate
+	for rows.Next() {
+		var i Debate
+		if err := rows.Scan(
+			&i.ID,
+			&i.MatchID,
+			&i.DebateType,
+			&i.Headline,
+			&i.Description,
+			&i.AiGenerated,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.PromotedBy,
+			&i.LeagueID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const promoteDebate = `-- name: PromoteDebate :one
+UPDATE debates
+SET status = 'published', promoted_at = CURRENT_TIMESTAMP, promoted_by = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'proposed' AND deleted_at IS NULL
+RETURNING id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id
+`
+
+type PromoteDebateParams struct {
+	ID         int32
+	PromotedBy sql.NullInt32
+	LeagueID   sql.NullInt32
+}
+
+func (q *Queries) PromoteDebate(ctx context.Context, arg PromoteDebateParams) (Debate, error) {
+	row := q.db.QueryRowContext(ctx, promoteDebate, arg.ID, arg.PromotedBy)
+	var i Debate
+	err := row.Scan(
+		&i.ID,
+		&i.MatchID,
+		&i.DebateType,
+		&i.Headline,
+		&i.Description,
+		&i.AiGenerated,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.PromotedBy,
+		&i.LeagueID,
+	)
+	return i, err
+}
+
+const rejectDebate = `-- name: RejectDebate :one
+UPDATE debates
+SET status = 'rejected', updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'proposed' AND deleted_at IS NULL
+RETURNING id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id
+`
+
+func (q *Queries) RejectDebate(ctx context.Context, id int32) (Debate, error) {
+	row := q.db.QueryRowContext(ctx, rejectDebate, id)
+	var i Debate
+	err := row.Scan(
+		&i.ID,
+		&i.MatchID,
+		&i.DebateType,
+		&i.Headline,
+		&i.Description,
+		&i.AiGenerated,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.PromotedBy,
+		&i.LeagueID,
+	)
+	return i, err
+}
+
 const restoreDebate = `-- name: RestoreDebate :exec
 UPDATE debates SET deleted_at = NULL WHERE id = $1
 `
@@ -730,7 +1103,7 @@ const updateDebate = `-- name: UpdateDebate :one
 UPDATE debates 
 SET headline = $2, description = $3, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at
+RETURNING id, match_id, debate_type, headline, description, ai_generated, deleted_at, created_at, updated_at, author_id, status, promoted_at, promoted_by, league_id
 `
 
 type UpdateDebateParams struct {
@@ -752,22 +1125,36 @@ func (q *Queries) UpdateDebate(ctx context.Context, arg UpdateDebateParams) (Deb
 		&i.DeletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.PromotedBy,
+		&i.LeagueID,
 	)
 	return i, err
 }
 
 const updateDebateAnalytics = `-- name: UpdateDebateAnalytics :one
-UPDATE debate_analytics 
-SET total_votes = $2, total_comments = $3, engagement_score = $4, updated_at = CURRENT_TIMESTAMP
-WHERE debate_id = $1
-RETURNING id, debate_id, total_votes, total_comments, engagement_score, created_at, updated_at
+INSERT INTO debate_analytics (debate_id, total_votes, total_comments, engagement_score, unique_participants, trending_score)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (debate_id)
+DO UPDATE SET
+    total_votes = EXCLUDED.total_votes,
+    total_comments = EXCLUDED.total_comments,
+    engagement_score = EXCLUDED.engagement_score,
+    unique_participants = EXCLUDED.unique_participants,
+    trending_score = EXCLUDED.trending_score,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id, debate_id, total_votes, total_comments, engagement_score, created_at, updated_at, unique_participants, trending_score
 `
 
 type UpdateDebateAnalyticsParams struct {
-	DebateID        sql.NullInt32
-	TotalVotes      sql.NullInt32
-	TotalComments   sql.NullInt32
-	EngagementScore sql.NullString
+	DebateID           sql.NullInt32
+	TotalVotes         sql.NullInt32
+	TotalComments      sql.NullInt32
+	EngagementScore    sql.NullString
+	UniqueParticipants int32
+	TrendingScore      float64
 }
 
 func (q *Queries) UpdateDebateAnalytics(ctx context.Context, arg UpdateDebateAnalyticsParams) (DebateAnalytic, error) {
@@ -776,6 +1163,8 @@ func (q *Queries) UpdateDebateAnalytics(ctx context.Context, arg UpdateDebateAna
 		arg.TotalVotes,
 		arg.TotalComments,
 		arg.EngagementScore,
+		arg.UniqueParticipants,
+		arg.TrendingScore,
 	)
 	var i DebateAnalytic
 	err := row.Scan(
@@ -786,6 +1175,8 @@ func (q *Queries) UpdateDebateAnalytics(ctx context.Context, arg UpdateDebateAna
 		&i.EngagementScore,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UniqueParticipants,
+		&i.TrendingScore,
 	)
 	return i, err
 }
@@ -193,3 +193,17 @@ func (q *Queries) UpdateLeague(ctx context.Context, arg UpdateLeagueParams) (Lea
 	)
 	return i, err
 }
+
+const updateLeagueLogo = `-- name: UpdateLeagueLogo :exec
+UPDATE leagues SET logo_url = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+type UpdateLeagueLogoParams struct {
+	ID      uuid.UUID
+	LogoUrl sql.NullString
+}
+
+func (q *Queries) UpdateLeagueLogo(ctx context.Context, arg UpdateLeagueLogoParams) error {
+	_, err := q.db.ExecContext(ctx, updateLeagueLogo, arg.ID, arg.LogoUrl)
+	return err
+}
@@ -7,21 +7,37 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createMedia = `-- name: CreateMedia :one
-INSERT INTO media (match_id, media_url)
-VALUES ($1, $2)
-RETURNING id, match_id, media_url, created_at, updated_at
+INSERT INTO media (match_id, media_url, user_id, media_type, content_type, size_bytes, storage_key, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, match_id, media_url, created_at, updated_at, user_id, media_type, content_type, size_bytes, storage_key, thumbnail_key, thumbnail_url, status, attempts, last_error
 `
 
 type CreateMediaParams struct {
-	MatchID  string
-	MediaUrl string
+	MatchID     string
+	MediaUrl    string
+	UserID      sql.NullInt32
+	MediaType   string
+	ContentType string
+	SizeBytes   int64
+	StorageKey  sql.NullString
+	Status      string
 }
 
 func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (Medium, error) {
-	row := q.db.QueryRowContext(ctx, createMedia, arg.MatchID, arg.MediaUrl)
+	row := q.db.QueryRowContext(ctx, createMedia,
+		arg.MatchID,
+		arg.MediaUrl,
+		arg.UserID,
+		arg.MediaType,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.StorageKey,
+		arg.Status,
+	)
 	var i Medium
 	err := row.Scan(
 		&i.ID,
@@ -29,6 +45,16 @@ func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (Mediu
 		&i.MediaUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UserID,
+		&i.MediaType,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.ThumbnailUrl,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
 	)
 	return i, err
 }
@@ -42,8 +68,35 @@ func (q *Queries) DeleteMediaById(ctx context.Context, id int32) error {
 	return err
 }
 
+const getMedia = `-- name: GetMedia :one
+SELECT id, match_id, media_url, created_at, updated_at, user_id, media_type, content_type, size_bytes, storage_key, thumbnail_key, thumbnail_url, status, attempts, last_error FROM media WHERE id = $1
+`
+
+func (q *Queries) GetMedia(ctx context.Context, id int32) (Medium, error) {
+	row := q.db.QueryRowContext(ctx, getMedia, id)
+	var i Medium
+	err := row.Scan(
+		&i.ID,
+		&i.MatchID,
+		&i.MediaUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.MediaType,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.ThumbnailUrl,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+	)
+	return i, err
+}
+
 const getMediaByMatchId = `-- name: GetMediaByMatchId :many
-SELECT id, match_id, media_url, created_at, updated_at FROM media WHERE match_id = $1 ORDER BY created_at DESC
+SELECT id, match_id, media_url, created_at, updated_at, user_id, media_type, content_type, size_bytes, storage_key, thumbnail_key, thumbnail_url, status, attempts, last_error FROM media WHERE match_id = $1 AND status <> 'failed' ORDER BY created_at DESC
 `
 
 func (q *Queries) GetMediaByMatchId(ctx context.Context, matchID string) ([]Medium, error) {
@@ -61,6 +114,16 @@ func (q *Queries) GetMediaByMatchId(ctx context.Context, matchID string) ([]Medi
 			&i.MediaUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UserID,
+			&i.MediaType,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.ThumbnailKey,
+			&i.ThumbnailUrl,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
 		); err != nil {
 			return nil, err
 		}
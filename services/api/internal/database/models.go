@@ -6,11 +6,119 @@ package database
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type FollowableType string
+
+const (
+	FollowableTypeTeam   FollowableType = "team"
+	FollowableTypePlayer FollowableType = "player"
+	FollowableTypeMatch  FollowableType = "match"
+)
+
+func (e *FollowableType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FollowableType(s)
+	case string:
+		*e = FollowableType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FollowableType: %T", src)
+	}
+	return nil
+}
+
+type NullFollowableType struct {
+	FollowableType FollowableType
+	Valid          bool // Valid is true if FollowableType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFollowableType) Scan(value interface{}) error {
+	if value == nil {
+		ns.FollowableType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FollowableType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFollowableType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FollowableType), nil
+}
+
+type MatchStatus string
+
+const (
+	MatchStatusScheduled MatchStatus = "scheduled"
+	MatchStatusLive      MatchStatus = "live"
+	MatchStatusFinished  MatchStatus = "finished"
+	MatchStatusPostponed MatchStatus = "postponed"
+	MatchStatusCancelled MatchStatus = "cancelled"
+)
+
+func (e *MatchStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MatchStatus(s)
+	case string:
+		*e = MatchStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MatchStatus: %T", src)
+	}
+	return nil
+}
+
+type NullMatchStatus struct {
+	MatchStatus MatchStatus
+	Valid       bool // Valid is true if MatchStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMatchStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.MatchStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MatchStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMatchStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MatchStatus), nil
+}
+
+type AttributeRating struct {
+	ID              int64
+	PlayerProfileID uuid.UUID
+	RaterUserID     int32
+	Relationship    string
+	Weight          int32
+	Pace            int32
+	Shooting        int32
+	Passing         int32
+	Stamina         int32
+	Dribbling       int32
+	Defending       int32
+	Physical        int32
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
 type Comment struct {
 	ID              int32
 	DebateID        sql.NullInt32
@@ -31,16 +139,23 @@ type Debate struct {
 	DeletedAt   sql.NullTime
 	CreatedAt   sql.NullTime
 	UpdatedAt   sql.NullTime
+	AuthorID    sql.NullInt32
+	Status      string
+	PromotedAt  sql.NullTime
+	PromotedBy  sql.NullInt32
+	LeagueID    sql.NullInt32
 }
 
 type DebateAnalytic struct {
-	ID              int32
-	DebateID        sql.NullInt32
-	TotalVotes      sql.NullInt32
-	TotalComments   sql.NullInt32
-	EngagementScore sql.NullString
-	CreatedAt       sql.NullTime
-	UpdatedAt       sql.NullTime
+	ID                 int32
+	DebateID           sql.NullInt32
+	TotalVotes         sql.NullInt32
+	TotalComments      sql.NullInt32
+	EngagementScore    sql.NullString
+	CreatedAt          sql.NullTime
+	UpdatedAt          sql.NullTime
+	UniqueParticipants int32
+	TrendingScore      float64
 }
 
 type DebateCard struct {
@@ -54,6 +169,57 @@ type DebateCard struct {
 	UpdatedAt   sql.NullTime
 }
 
+type DebateResolution struct {
+	ID            int32
+	DebateID      int32
+	WinningCardID int32
+	Method        string
+	ResolvedBy    sql.NullInt32
+	Evidence      string
+	Confidence    sql.NullFloat64
+	FinalStatus   string
+	HomeScore     int32
+	AwayScore     int32
+	FinalStats    json.RawMessage
+	CreatedAt     time.Time
+}
+
+type DeviceToken struct {
+	ID        int32
+	UserID    int32
+	Token     string
+	Platform  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type EmailVerification struct {
+	ID        uuid.UUID
+	UserID    int32
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type FixtureTeam struct {
+	MatchID   string
+	HomeTeam  string
+	AwayTeam  string
+	UpdatedAt time.Time
+}
+
+type ImageAsset struct {
+	ID          int64
+	OwnerType   string
+	OwnerID     string
+	Url         string
+	StorageKeys []string
+	ReplacedAt  sql.NullTime
+	CreatedAt   time.Time
+}
+
 type League struct {
 	ID          uuid.UUID
 	Name        string
@@ -68,12 +234,181 @@ type League struct {
 	UpdatedAt   time.Time
 }
 
+type LeagueRosterRule struct {
+	LeagueID   uuid.UUID
+	MaxPlayers int32
+	UpdatedAt  time.Time
+}
+
+type LeagueStandingsRule struct {
+	LeagueID    uuid.UUID
+	PointsWin   int32
+	PointsDraw  int32
+	PointsLoss  int32
+	Tiebreakers []string
+	UpdatedAt   time.Time
+}
+
+type ManagerInvitation struct {
+	ID          uuid.UUID
+	LeagueID    uuid.UUID
+	TeamID      uuid.UUID
+	Email       string
+	InviteeID   sql.NullInt32
+	Role        string
+	InvitedBy   sql.NullInt32
+	Message     sql.NullString
+	Status      string
+	AcceptedBy  sql.NullInt32
+	ExpiresAt   time.Time
+	RespondedAt sql.NullTime
+	CreatedAt   time.Time
+}
+
+type Match struct {
+	ID                uuid.UUID
+	ExternalMatchID   sql.NullString
+	HomeTeamID        uuid.NullUUID
+	AwayTeamID        uuid.NullUUID
+	LeagueID          uuid.NullUUID
+	MatchDate         time.Time
+	Venue             sql.NullString
+	Status            MatchStatus
+	HomeScore         sql.NullInt32
+	AwayScore         sql.NullInt32
+	MatchMinute       sql.NullInt32
+	Referee           sql.NullString
+	Attendance        sql.NullInt32
+	WeatherConditions sql.NullString
+	CreatedAt         sql.NullTime
+	UpdatedAt         sql.NullTime
+	CreatedBy         sql.NullInt32
+	ResultEnteredBy   sql.NullInt32
+	HomeConfirmedAt   sql.NullTime
+	AwayConfirmedAt   sql.NullTime
+	Round             sql.NullInt32
+	SeasonID          uuid.NullUUID
+}
+
+type MatchAppearance struct {
+	ID              int64
+	MatchID         uuid.UUID
+	TeamID          uuid.UUID
+	PlayerProfileID uuid.UUID
+	Minutes         int32
+	Assists         int32
+	YellowCards     int32
+	RedCard         bool
+	Rating          sql.NullInt32
+	RecordedBy      sql.NullInt32
+	CreatedAt       time.Time
+}
+
+type MatchGoal struct {
+	ID              int64
+	MatchID         uuid.UUID
+	TeamID          uuid.UUID
+	PlayerProfileID uuid.NullUUID
+	ScorerName      sql.NullString
+	Minute          sql.NullInt32
+	OwnGoal         bool
+	Penalty         bool
+	CreatedAt       time.Time
+}
+
 type Medium struct {
-	ID        int32
-	MatchID   string
-	MediaUrl  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID           int32
+	MatchID      string
+	MediaUrl     string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       sql.NullInt32
+	MediaType    string
+	ContentType  string
+	SizeBytes    int64
+	StorageKey   sql.NullString
+	ThumbnailKey sql.NullString
+	ThumbnailUrl sql.NullString
+	Status       string
+	Attempts     int32
+	LastError    sql.NullString
+}
+
+type NewsArticle struct {
+	ID          int32
+	FeedID      sql.NullInt32
+	Guid        sql.NullString
+	Url         string
+	Title       string
+	Summary     sql.NullString
+	Publisher   sql.NullString
+	ImageUrl    sql.NullString
+	ContentHash string
+	PublishedAt time.Time
+	CreatedAt   time.Time
+}
+
+type NewsArticleTeam struct {
+	ArticleID int32
+	TeamName  string
+}
+
+type NewsFeed struct {
+	ID           int32
+	Name         string
+	Url          string
+	IsActive     bool
+	LastPolledAt sql.NullTime
+	LastError    sql.NullString
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Notification struct {
+	ID               int64
+	UserID           int32
+	NotificationType string
+	Title            string
+	Body             string
+	Data             json.RawMessage
+	DedupeKey        string
+	ReadAt           sql.NullTime
+	CreatedAt        time.Time
+}
+
+type NotificationPreference struct {
+	UserID           int32
+	NotificationType string
+	Enabled          bool
+	UpdatedAt        time.Time
+}
+
+type PlayerAttributeHistory struct {
+	ID              int64
+	PlayerProfileID uuid.UUID
+	Source          string
+	Pace            int32
+	Shooting        int32
+	Passing         int32
+	Stamina         int32
+	Dribbling       int32
+	Defending       int32
+	Physical        int32
+	Raters          sql.NullInt32
+	RecordedAt      time.Time
+}
+
+type PlayerCommunityAttribute struct {
+	PlayerProfileID uuid.UUID
+	Pace            int32
+	Shooting        int32
+	Passing         int32
+	Stamina         int32
+	Dribbling       int32
+	Defending       int32
+	Physical        int32
+	Raters          int32
+	UpdatedAt       time.Time
 }
 
 type PlayerProfile struct {
@@ -96,6 +431,115 @@ type PlayerProfile struct {
 	UpdatedAt  time.Time
 }
 
+type PlayerShortlist struct {
+	TeamID          uuid.UUID
+	PlayerProfileID uuid.UUID
+	AddedBy         sql.NullInt32
+	Note            sql.NullString
+	CreatedAt       time.Time
+}
+
+type PlayerTransfer struct {
+	ID              uuid.UUID
+	PlayerProfileID uuid.UUID
+	FromTeamID      uuid.NullUUID
+	ToTeamID        uuid.NullUUID
+	SeasonID        uuid.NullUUID
+	InvitationID    uuid.NullUUID
+	RecordedBy      sql.NullInt32
+	CreatedAt       time.Time
+}
+
+type PointAward struct {
+	ID         int64
+	UserID     int32
+	SourceType string
+	SourceID   string
+	Points     int32
+	LeagueID   sql.NullInt32
+	AwardedAt  time.Time
+	TeamIds    []uuid.UUID
+}
+
+type PredictionCredit struct {
+	ID           int32
+	UserID       int32
+	DebateID     int32
+	DebateCardID int32
+	Points       int32
+	CreatedAt    time.Time
+}
+
+type PushNotification struct {
+	ID               int64
+	UserID           int32
+	NotificationType string
+	Title            string
+	Body             string
+	Data             json.RawMessage
+	DedupeKey        string
+	Attempts         int32
+	LastError        sql.NullString
+	SentAt           sql.NullTime
+	CreatedAt        time.Time
+	LockedUntil      sql.NullTime
+}
+
+type RosterInvitation struct {
+	ID              uuid.UUID
+	TeamID          uuid.UUID
+	PlayerProfileID uuid.UUID
+	InvitedBy       sql.NullInt32
+	Message         sql.NullString
+	Status          string
+	ExpiresAt       time.Time
+	RespondedAt     sql.NullTime
+	CreatedAt       time.Time
+}
+
+type ScorePrediction struct {
+	ID        int32
+	UserID    int32
+	MatchID   string
+	HomeGoals int32
+	AwayGoals int32
+	LeagueID  sql.NullInt32
+	KickoffAt time.Time
+	Points    sql.NullInt32
+	Outcome   sql.NullString
+	ScoredAt  sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Season struct {
+	ID                   uuid.UUID
+	LeagueID             uuid.UUID
+	Name                 string
+	StartDate            time.Time
+	EndDate              time.Time
+	RegistrationOpensAt  sql.NullTime
+	RegistrationClosesAt sql.NullTime
+	Archived             bool
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+type SeasonRegistrationWindow struct {
+	ID        uuid.UUID
+	SeasonID  uuid.UUID
+	OpensAt   time.Time
+	ClosesAt  time.Time
+	CreatedAt time.Time
+}
+
+type SeasonTeam struct {
+	SeasonID     uuid.UUID
+	TeamID       uuid.UUID
+	RegisteredBy sql.NullInt32
+	CreatedAt    time.Time
+}
+
 type Team struct {
 	ID          uuid.UUID
 	Name        string
@@ -113,6 +557,13 @@ type Team struct {
 	Capacity    sql.NullInt32
 }
 
+type TeamAlias struct {
+	ID        int32
+	TeamName  string
+	Alias     string
+	CreatedAt time.Time
+}
+
 type TeamManager struct {
 	ID         uuid.UUID
 	UserID     int32
@@ -123,6 +574,20 @@ type TeamManager struct {
 	Bio        sql.NullString
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+	Role       string
+}
+
+type TrialInvitation struct {
+	ID              uuid.UUID
+	TeamID          uuid.UUID
+	PlayerProfileID uuid.UUID
+	InvitedBy       sql.NullInt32
+	TrialAt         time.Time
+	Location        sql.NullString
+	Message         sql.NullString
+	Status          string
+	RespondedAt     sql.NullTime
+	CreatedAt       time.Time
 }
 
 type User struct {
@@ -135,11 +600,56 @@ type User struct {
 	IsAdmin   bool
 }
 
+type UserAchievement struct {
+	ID             int32
+	UserID         int32
+	AchievementKey string
+	UnlockedAt     time.Time
+}
+
+type UserFollow struct {
+	ID             uuid.UUID
+	UserID         int32
+	FollowableType FollowableType
+	FollowableID   uuid.UUID
+	CreatedAt      sql.NullTime
+}
+
 type Verification struct {
 	ID              uuid.UUID
 	PlayerProfileID uuid.UUID
 	VerifierUserID  int32
 	CreatedAt       time.Time
+	TeamID          uuid.NullUUID
+	LeagueID        uuid.NullUUID
+	SeasonID        uuid.NullUUID
+	VerifierRole    sql.NullString
+	ExpiresAt       sql.NullTime
+	Note            sql.NullString
+}
+
+type VerificationAuditLog struct {
+	ID              int64
+	PlayerProfileID uuid.UUID
+	WasVerified     bool
+	IsVerified      bool
+	Reason          string
+	VerificationID  uuid.NullUUID
+	ActorUserID     sql.NullInt32
+	Verifications   int32
+	CreatedAt       time.Time
+}
+
+type VerificationEvidence struct {
+	ID             uuid.UUID
+	VerificationID uuid.UUID
+	UploadedBy     sql.NullInt32
+	MediaType      string
+	ContentType    string
+	Url            string
+	StorageKey     string
+	Description    sql.NullString
+	CreatedAt      time.Time
 }
 
 type Vote struct {
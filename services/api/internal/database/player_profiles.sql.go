@@ -486,16 +486,15 @@ func (q *Queries) ListPlayerProfilesByTeam(ctx context.Context, teamID uuid.Null
 const updatePlayerProfile = `-- name: UpdatePlayerProfile :one
 UPDATE player_profiles 
 SET 
-  team_id = $2, position = $3, age = $4, country = $5, height_cm = $6,
-  pace = $7, shooting = $8, passing = $9, stamina = $10, dribbling = $11, 
-  defending = $12, physical = $13, updated_at = CURRENT_TIMESTAMP
+  position = $2, age = $3, country = $4, height_cm = $5,
+  pace = $6, shooting = $7, passing = $8, stamina = $9, dribbling = $10, 
+  defending = $11, physical = $12, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING id, user_id, team_id, position, age, country, height_cm, pace, shooting, passing, stamina, dribbling, defending, physical, is_verified, created_at, updated_at
 `
 
 type UpdatePlayerProfileParams struct {
 	ID        uuid.UUID
-	TeamID    uuid.NullUUID
 	Position  string
 	Age       int32
 	Country   string
@@ -509,10 +508,10 @@ type UpdatePlayerProfileParams struct {
 	Physical  int32
 }
 
+// Team changes go through rosters, see UpdatePlayerTeam
 func (q *Queries) UpdatePlayerProfile(ctx context.Context, arg UpdatePlayerProfileParams) (PlayerProfile, error) {
 	row := q.db.QueryRowContext(ctx, updatePlayerProfile,
 		arg.ID,
-		arg.TeamID,
 		arg.Position,
 		arg.Age,
 		arg.Country,
@@ -560,6 +559,7 @@ type UpdatePlayerTeamParams struct {
 	TeamID uuid.NullUUID
 }
 
+// Record a player_transfers row alongside every change
 func (q *Queries) UpdatePlayerTeam(ctx context.Context, arg UpdatePlayerTeamParams) (PlayerProfile, error) {
 	row := q.db.QueryRowContext(ctx, updatePlayerTeam, arg.ID, arg.TeamID)
 	var i PlayerProfile
@@ -8,14 +8,47 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const assignTeamManager = `-- name: AssignTeamManager :one
+UPDATE team_managers
+SET team_id = $2, role = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at, role
+`
+
+type AssignTeamManagerParams struct {
+	ID     uuid.UUID
+	TeamID uuid.NullUUID
+	Role   string
+}
+
+// Moves a manager onto a team, or changes their role on it
+func (q *Queries) AssignTeamManager(ctx context.Context, arg AssignTeamManagerParams) (TeamManager, error) {
+	row := q.db.QueryRowContext(ctx, assignTeamManager, arg.ID, arg.TeamID, arg.Role)
+	var i TeamManager
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.LeagueID,
+		&i.TeamID,
+		&i.Title,
+		&i.Experience,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
 const createTeamManager = `-- name: CreateTeamManager :one
-INSERT INTO team_managers (user_id, league_id, team_id, title, experience, bio)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at
+INSERT INTO team_managers (user_id, league_id, team_id, title, experience, bio, role)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at, role
 `
 
 type CreateTeamManagerParams struct {
@@ -25,6 +58,7 @@ type CreateTeamManagerParams struct {
 	Title      sql.NullString
 	Experience sql.NullInt32
 	Bio        sql.NullString
+	Role       string
 }
 
 func (q *Queries) CreateTeamManager(ctx context.Context, arg CreateTeamManagerParams) (TeamManager, error) {
@@ -35,6 +69,7 @@ func (q *Queries) CreateTeamManager(ctx context.Context, arg CreateTeamManagerPa
 		arg.Title,
 		arg.Experience,
 		arg.Bio,
+		arg.Role,
 	)
 	var i TeamManager
 	err := row.Scan(
@@ -47,6 +82,7 @@ func (q *Queries) CreateTeamManager(ctx context.Context, arg CreateTeamManagerPa
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -60,8 +96,38 @@ func (q *Queries) DeleteTeamManager(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getLeagueManagerByUser = `-- name: GetLeagueManagerByUser :one
+SELECT id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at, role FROM team_managers WHERE league_id = $1 AND user_id = $2
+ORDER BY created_at
+LIMIT 1
+`
+
+type GetLeagueManagerByUserParams struct {
+	LeagueID uuid.UUID
+	UserID   int32
+}
+
+// A user manages at most one team per league
+func (q *Queries) GetLeagueManagerByUser(ctx context.Context, arg GetLeagueManagerByUserParams) (TeamManager, error) {
+	row := q.db.QueryRowContext(ctx, getLeagueManagerByUser, arg.LeagueID, arg.UserID)
+	var i TeamManager
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.LeagueID,
+		&i.TeamID,
+		&i.Title,
+		&i.Experience,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
 const getTeamManager = `-- name: GetTeamManager :one
-SELECT id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at FROM team_managers WHERE id = $1
+SELECT id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at, role FROM team_managers WHERE id = $1
 `
 
 func (q *Queries) GetTeamManager(ctx context.Context, id uuid.UUID) (TeamManager, error) {
@@ -77,12 +143,13 @@ func (q *Queries) GetTeamManager(ctx context.Context, id uuid.UUID) (TeamManager
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getTeamManagersByLeague = `-- name: GetTeamManagersByLeague :many
-SELECT id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at FROM team_managers WHERE league_id = $1 ORDER BY created_at DESC
+SELECT id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at, role FROM team_managers WHERE league_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetTeamManagersByLeague(ctx context.Context, leagueID uuid.UUID) ([]TeamManager, error) {
@@ -104,6 +171,7 @@ func (q *Queries) GetTeamManagersByLeague(ctx context.Context, leagueID uuid.UUI
 			&i.Bio,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -119,7 +187,7 @@ func (q *Queries) GetTeamManagersByLeague(ctx context.Context, leagueID uuid.UUI
 }
 
 const listTeamManagers = `-- name: ListTeamManagers :many
-SELECT id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at FROM team_managers 
+SELECT id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at, role FROM team_managers 
 WHERE ($1::uuid IS NULL OR league_id = $1)
   AND ($2::uuid IS NULL OR team_id = $2)
   AND ($3::uuid IS NULL OR user_id = $3)
@@ -160,6 +228,68 @@ func (q *Queries) ListTeamManagers(ctx context.Context, arg ListTeamManagersPara
 			&i.Bio,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamManagersByTeam = `-- name: ListTeamManagersByTeam :many
+SELECT tm.id, tm.user_id, tm.league_id, tm.team_id, tm.title, tm.experience, tm.bio, tm.created_at, tm.updated_at, tm.role, u.firstname, u.lastname, u.email
+FROM team_managers tm
+JOIN users u ON u.id = tm.user_id
+WHERE tm.team_id = $1
+ORDER BY tm.created_at
+`
+
+type ListTeamManagersByTeamRow struct {
+	ID         uuid.UUID
+	UserID     int32
+	LeagueID   uuid.UUID
+	TeamID     uuid.NullUUID
+	Title      sql.NullString
+	Experience sql.NullInt32
+	Bio        sql.NullString
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Role       string
+	Firstname  string
+	Lastname   string
+	Email      string
+}
+
+func (q *Queries) ListTeamManagersByTeam(ctx context.Context, teamID uuid.NullUUID) ([]ListTeamManagersByTeamRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamManagersByTeam, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTeamManagersByTeamRow
+	for rows.Next() {
+		var i ListTeamManagersByTeamRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.LeagueID,
+			&i.TeamID,
+			&i.Title,
+			&i.Experience,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Role,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Email,
 		); err != nil {
 			return nil, err
 		}
@@ -220,14 +350,13 @@ func (q *Queries) ListTeamsByManager(ctx context.Context, userID int32) ([]Team,
 
 const updateTeamManager = `-- name: UpdateTeamManager :one
 UPDATE team_managers 
-SET team_id = $2, title = $3, experience = $4, bio = $5, updated_at = CURRENT_TIMESTAMP
+SET title = $2, experience = $3, bio = $4, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at
+RETURNING id, user_id, league_id, team_id, title, experience, bio, created_at, updated_at, role
 `
 
 type UpdateTeamManagerParams struct {
 	ID         uuid.UUID
-	TeamID     uuid.NullUUID
 	Title      sql.NullString
 	Experience sql.NullInt32
 	Bio        sql.NullString
@@ -236,7 +365,6 @@ type UpdateTeamManagerParams struct {
 func (q *Queries) UpdateTeamManager(ctx context.Context, arg UpdateTeamManagerParams) (TeamManager, error) {
 	row := q.db.QueryRowContext(ctx, updateTeamManager,
 		arg.ID,
-		arg.TeamID,
 		arg.Title,
 		arg.Experience,
 		arg.Bio,
@@ -252,6 +380,7 @@ func (q *Queries) UpdateTeamManager(ctx context.Context, arg UpdateTeamManagerPa
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -10,8 +10,41 @@ import (
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const canManageTeam = `-- name: CanManageTeam :one
+SELECT (
+    EXISTS (
+        SELECT 1 FROM teams t JOIN team_managers tm ON tm.id = t.manager_id
+        WHERE t.id = $1 AND tm.user_id = $2 AND tm.role = ANY($3::text[])
+    )
+    OR EXISTS (
+        SELECT 1 FROM team_managers tm
+        WHERE tm.team_id = $1 AND tm.user_id = $2 AND tm.role = ANY($3::text[])
+    )
+    OR EXISTS (
+        SELECT 1 FROM teams t JOIN leagues l ON l.id = t.league_id
+        WHERE t.id = $1 AND l.owner_id = $2
+    )
+)::boolean AS can_manage
+`
+
+type CanManageTeamParams struct {
+	TeamID uuid.UUID
+	UserID int32
+	Roles  []string
+}
+
+// Managers of the team with one of roles, and the owner of its league, can
+// manage it
+func (q *Queries) CanManageTeam(ctx context.Context, arg CanManageTeamParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, canManageTeam, arg.TeamID, arg.UserID, pq.Array(arg.Roles))
+	var can_manage bool
+	err := row.Scan(&can_manage)
+	return can_manage, err
+}
+
 const createTeam = `-- name: CreateTeam :one
 INSERT INTO teams (name, description, league_id, manager_id, logo_url, city, country, founded, stadium, capacity)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
@@ -241,6 +274,16 @@ func (q *Queries) ListTeamsByLeague(ctx context.Context, leagueID uuid.NullUUID)
 	return items, nil
 }
 
+const unsetTeamManager = `-- name: UnsetTeamManager :exec
+UPDATE teams SET manager_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE manager_id = $1
+`
+
+// Clears a manager from any team naming them as its manager_id, before they're removed
+func (q *Queries) UnsetTeamManager(ctx context.Context, managerID uuid.NullUUID) error {
+	_, err := q.db.ExecContext(ctx, unsetTeamManager, managerID)
+	return err
+}
+
 const updateTeam = `-- name: UpdateTeam :one
 UPDATE teams 
 SET name = $2, description = $3, league_id = $4, manager_id = $5, 
@@ -297,3 +340,17 @@ func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, e
 	)
 	return i, err
 }
+
+const updateTeamLogo = `-- name: UpdateTeamLogo :exec
+UPDATE teams SET logo_url = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+type UpdateTeamLogoParams struct {
+	ID      uuid.UUID
+	LogoUrl sql.NullString
+}
+
+func (q *Queries) UpdateTeamLogo(ctx context.Context, arg UpdateTeamLogoParams) error {
+	_, err := q.db.ExecContext(ctx, updateTeamLogo, arg.ID, arg.LogoUrl)
+	return err
+}
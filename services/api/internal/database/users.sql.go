@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -51,6 +52,41 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 	return err
 }
 
+const demoteTeamManager = `-- name: DemoteTeamManager :execrows
+UPDATE users SET role = 'fan', updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND role = 'team_manager'
+  AND NOT EXISTS (SELECT 1 FROM team_managers tm WHERE tm.user_id = $1)
+`
+
+// Only once the user no longer manages any team
+func (q *Queries) DemoteTeamManager(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, demoteTeamManager, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const findUserByEmail = `-- name: FindUserByEmail :one
+SELECT id, firstname, lastname, email, created_at, updated_at, is_admin FROM users WHERE LOWER(email) = LOWER($1) ORDER BY id LIMIT 1
+`
+
+// Case-insensitive, for addresses typed in by someone else
+func (q *Queries) FindUserByEmail(ctx context.Context, lower string) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserByEmail, lower)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+	)
+	return i, err
+}
+
 const getUser = `-- name: GetUser :one
 SELECT id, firstname, lastname, email, created_at, updated_at, is_admin FROM users WHERE id = $1
 `
@@ -124,6 +160,17 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const promoteToTeamManager = `-- name: PromoteToTeamManager :exec
+UPDATE users SET role = 'team_manager', updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND role = 'fan'
+`
+
+// Admins keep their role
+func (q *Queries) PromoteToTeamManager(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, promoteToTeamManager, id)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users 
 SET firstname = $2, lastname = $3, email = $4, is_admin = $5, updated_at = CURRENT_TIMESTAMP
@@ -159,3 +206,17 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 	)
 	return i, err
 }
+
+const updateUserAvatar = `-- name: UpdateUserAvatar :exec
+UPDATE users SET avatar_url = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+type UpdateUserAvatarParams struct {
+	ID        int32
+	AvatarUrl sql.NullString
+}
+
+func (q *Queries) UpdateUserAvatar(ctx context.Context, arg UpdateUserAvatarParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserAvatar, arg.ID, arg.AvatarUrl)
+	return err
+}
@@ -7,40 +7,141 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-const countVerificationsByPlayer = `-- name: CountVerificationsByPlayer :one
-SELECT COUNT(*) FROM verifications WHERE player_profile_id = $1
+const countQualifyingVerifications = `-- name: CountQualifyingVerifications :one
+SELECT COUNT(DISTINCT verifier_user_id) FROM verifications
+WHERE player_profile_id = $1
+  AND verifier_role IS NOT NULL
+  AND (expires_at IS NULL OR expires_at > $2::timestamp)
 `
 
-func (q *Queries) CountVerificationsByPlayer(ctx context.Context, playerProfileID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countVerificationsByPlayer, playerProfileID)
+type CountQualifyingVerificationsParams struct {
+	PlayerProfileID uuid.UUID
+	Now             time.Time
+}
+
+// Verifiers with a role whose verification hasn't lapsed
+func (q *Queries) CountQualifyingVerifications(ctx context.Context, arg CountQualifyingVerificationsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countQualifyingVerifications, arg.PlayerProfileID, arg.Now)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const createVerification = `-- name: CreateVerification :one
-INSERT INTO verifications (player_profile_id, verifier_user_id)
-VALUES ($1, $2)
-RETURNING id, player_profile_id, verifier_user_id, created_at
+INSERT INTO verifications (player_profile_id, verifier_user_id, team_id, league_id, season_id, verifier_role, expires_at, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, player_profile_id, verifier_user_id, created_at, team_id, league_id, season_id, verifier_role, expires_at, note
 `
 
 type CreateVerificationParams struct {
 	PlayerProfileID uuid.UUID
 	VerifierUserID  int32
+	TeamID          uuid.NullUUID
+	LeagueID        uuid.NullUUID
+	SeasonID        uuid.NullUUID
+	VerifierRole    sql.NullString
+	ExpiresAt       sql.NullTime
+	Note            sql.NullString
 }
 
 func (q *Queries) CreateVerification(ctx context.Context, arg CreateVerificationParams) (Verification, error) {
-	row := q.db.QueryRowContext(ctx, createVerification, arg.PlayerProfileID, arg.VerifierUserID)
+	row := q.db.QueryRowContext(ctx, createVerification,
+		arg.PlayerProfileID,
+		arg.VerifierUserID,
+		arg.TeamID,
+		arg.LeagueID,
+		arg.SeasonID,
+		arg.VerifierRole,
+		arg.ExpiresAt,
+		arg.Note,
+	)
 	var i Verification
 	err := row.Scan(
 		&i.ID,
 		&i.PlayerProfileID,
 		&i.VerifierUserID,
 		&i.CreatedAt,
+		&i.TeamID,
+		&i.LeagueID,
+		&i.SeasonID,
+		&i.VerifierRole,
+		&i.ExpiresAt,
+		&i.Note,
+	)
+	return i, err
+}
+
+const createVerificationAuditEntry = `-- name: CreateVerificationAuditEntry :exec
+INSERT INTO verification_audit_log (player_profile_id, was_verified, is_verified, reason, verification_id, actor_user_id, verifications)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateVerificationAuditEntryParams struct {
+	PlayerProfileID uuid.UUID
+	WasVerified     bool
+	IsVerified      bool
+	Reason          string
+	VerificationID  uuid.NullUUID
+	ActorUserID     sql.NullInt32
+	Verifications   int32
+}
+
+func (q *Queries) CreateVerificationAuditEntry(ctx context.Context, arg CreateVerificationAuditEntryParams) error {
+	_, err := q.db.ExecContext(ctx, createVerificationAuditEntry,
+		arg.PlayerProfileID,
+		arg.WasVerified,
+		arg.IsVerified,
+		arg.Reason,
+		arg.VerificationID,
+		arg.ActorUserID,
+		arg.Verifications,
+	)
+	return err
+}
+
+const createVerificationEvidence = `-- name: CreateVerificationEvidence :one
+INSERT INTO verification_evidence (verification_id, uploaded_by, media_type, content_type, url, storage_key, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, verification_id, uploaded_by, media_type, content_type, url, storage_key, description, created_at
+`
+
+type CreateVerificationEvidenceParams struct {
+	VerificationID uuid.UUID
+	UploadedBy     sql.NullInt32
+	MediaType      string
+	ContentType    string
+	Url            string
+	StorageKey     string
+	Description    sql.NullString
+}
+
+func (q *Queries) CreateVerificationEvidence(ctx context.Context, arg CreateVerificationEvidenceParams) (VerificationEvidence, error) {
+	row := q.db.QueryRowContext(ctx, createVerificationEvidence,
+		arg.VerificationID,
+		arg.UploadedBy,
+		arg.MediaType,
+		arg.ContentType,
+		arg.Url,
+		arg.StorageKey,
+		arg.Description,
+	)
+	var i VerificationEvidence
+	err := row.Scan(
+		&i.ID,
+		&i.VerificationID,
+		&i.UploadedBy,
+		&i.MediaType,
+		&i.ContentType,
+		&i.Url,
+		&i.StorageKey,
+		&i.Description,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -55,7 +156,7 @@ func (q *Queries) DeleteVerification(ctx context.Context, id uuid.UUID) error {
 }
 
 const getVerification = `-- name: GetVerification :one
-SELECT id, player_profile_id, verifier_user_id, created_at FROM verifications WHERE id = $1
+SELECT id, player_profile_id, verifier_user_id, created_at, team_id, league_id, season_id, verifier_role, expires_at, note FROM verifications WHERE id = $1
 `
 
 func (q *Queries) GetVerification(ctx context.Context, id uuid.UUID) (Verification, error) {
@@ -66,12 +167,205 @@ func (q *Queries) GetVerification(ctx context.Context, id uuid.UUID) (Verificati
 		&i.PlayerProfileID,
 		&i.VerifierUserID,
 		&i.CreatedAt,
+		&i.TeamID,
+		&i.LeagueID,
+		&i.SeasonID,
+		&i.VerifierRole,
+		&i.ExpiresAt,
+		&i.Note,
 	)
 	return i, err
 }
 
+const getVerifierEligibility = `-- name: GetVerifierEligibility :one
+SELECT (
+    EXISTS (SELECT 1 FROM team_managers tm WHERE tm.user_id = $1 AND tm.league_id = $2)
+    OR EXISTS (SELECT 1 FROM leagues l WHERE l.id = $2 AND l.owner_id = $1)
+)::boolean AS manager,
+EXISTS (
+    SELECT 1 FROM player_profiles pp JOIN teams t ON t.id = pp.team_id
+    WHERE pp.user_id = $1 AND pp.is_verified AND t.league_id = $2
+) AS verified_player
+`
+
+type GetVerifierEligibilityParams struct {
+	VerifierID int32
+	LeagueID   uuid.UUID
+}
+
+type GetVerifierEligibilityRow struct {
+	Manager        bool
+	VerifiedPlayer bool
+}
+
+// Managers in the league, including its owner, and verified players on its
+// teams can verify the league's players
+func (q *Queries) GetVerifierEligibility(ctx context.Context, arg GetVerifierEligibilityParams) (GetVerifierEligibilityRow, error) {
+	row := q.db.QueryRowContext(ctx, getVerifierEligibility, arg.VerifierID, arg.LeagueID)
+	var i GetVerifierEligibilityRow
+	err := row.Scan(
+		&i.Manager,
+		&i.VerifiedPlayer,
+	)
+	return i, err
+}
+
+const listLapsedVerifiedProfiles = `-- name: ListLapsedVerifiedProfiles :many
+SELECT pp.id FROM player_profiles pp
+WHERE pp.is_verified
+  AND (
+      SELECT COUNT(DISTINCT v.verifier_user_id) FROM verifications v
+      WHERE v.player_profile_id = pp.id
+        AND v.verifier_role IS NOT NULL
+        AND (v.expires_at IS NULL OR v.expires_at > $1::timestamp)
+  ) < $2::int
+`
+
+type ListLapsedVerifiedProfilesParams struct {
+	Now       time.Time
+	Threshold int32
+}
+
+// Verified players who no longer have enough qualifying verifications
+func (q *Queries) ListLapsedVerifiedProfiles(ctx context.Context, arg ListLapsedVerifiedProfilesParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listLapsedVerifiedProfiles, arg.Now, arg.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlayerVerificationEvidence = `-- name: ListPlayerVerificationEvidence :many
+SELECT ve.id, ve.verification_id, ve.uploaded_by, ve.media_type, ve.content_type, ve.url, ve.storage_key, ve.description, ve.created_at FROM verification_evidence ve
+JOIN verifications v ON v.id = ve.verification_id
+WHERE v.player_profile_id = $1
+ORDER BY ve.created_at
+`
+
+func (q *Queries) ListPlayerVerificationEvidence(ctx context.Context, playerProfileID uuid.UUID) ([]VerificationEvidence, error) {
+	rows, err := q.db.QueryContext(ctx, listPlayerVerificationEvidence, playerProfileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VerificationEvidence
+	for rows.Next() {
+		var i VerificationEvidence
+		if err := rows.Scan(
+			&i.ID,
+			&i.VerificationID,
+			&i.UploadedBy,
+			&i.MediaType,
+			&i.ContentType,
+			&i.Url,
+			&i.StorageKey,
+			&i.Description,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVerificationAuditLog = `-- name: ListVerificationAuditLog :many
+SELECT id, player_profile_id, was_verified, is_verified, reason, verification_id, actor_user_id, verifications, created_at FROM verification_audit_log WHERE player_profile_id = $1 ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListVerificationAuditLog(ctx context.Context, playerProfileID uuid.UUID) ([]VerificationAuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, listVerificationAuditLog, playerProfileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VerificationAuditLog
+	for rows.Next() {
+		var i VerificationAuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlayerProfileID,
+			&i.WasVerified,
+			&i.IsVerified,
+			&i.Reason,
+			&i.VerificationID,
+			&i.ActorUserID,
+			&i.Verifications,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVerificationEvidence = `-- name: ListVerificationEvidence :many
+SELECT id, verification_id, uploaded_by, media_type, content_type, url, storage_key, description, created_at FROM verification_evidence WHERE verification_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) ListVerificationEvidence(ctx context.Context, verificationID uuid.UUID) ([]VerificationEvidence, error) {
+	rows, err := q.db.QueryContext(ctx, listVerificationEvidence, verificationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VerificationEvidence
+	for rows.Next() {
+		var i VerificationEvidence
+		if err := rows.Scan(
+			&i.ID,
+			&i.VerificationID,
+			&i.UploadedBy,
+			&i.MediaType,
+			&i.ContentType,
+			&i.Url,
+			&i.StorageKey,
+			&i.Description,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVerificationsByPlayer = `-- name: ListVerificationsByPlayer :many
-SELECT id, player_profile_id, verifier_user_id, created_at FROM verifications WHERE player_profile_id = $1
+SELECT id, player_profile_id, verifier_user_id, created_at, team_id, league_id, season_id, verifier_role, expires_at, note FROM verifications WHERE player_profile_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) ListVerificationsByPlayer(ctx context.Context, playerProfileID uuid.UUID) ([]Verification, error) {
@@ -88,6 +382,12 @@ func (q *Queries) ListVerificationsByPlayer(ctx context.Context, playerProfileID
 			&i.PlayerProfileID,
 			&i.VerifierUserID,
 			&i.CreatedAt,
+			&i.TeamID,
+			&i.LeagueID,
+			&i.SeasonID,
+			&i.VerifierRole,
+			&i.ExpiresAt,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,150 @@
+// Package leaderboard keeps fan point rankings in sorted sets. Postgres holds
+// the ledger of awards; boards are a fast, rebuildable view of it.
+package leaderboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scope is who a board ranks
+type Scope string
+
+const (
+	ScopeGlobal Scope = "global"
+	ScopeLeague Scope = "league" // Fans who earned points in an API-Football league
+	ScopeTeam   Scope = "team"   // Fans who follow a team
+)
+
+// Period is the time window a board covers
+type Period string
+
+const (
+	PeriodAllTime Period = "alltime"
+	PeriodWeekly  Period = "weekly"
+)
+
+// weeklyRetention is how long a weekly board is kept after it's last written to
+const weeklyRetention = 5 * 7 * 24 * time.Hour
+
+// Board identifies a single leaderboard
+type Board struct {
+	Scope   Scope
+	ScopeID string // League ID or team UUID; empty for the global board
+	Period  Period
+	Week    string // ISO week, e.g. "2025-W43"; only for weekly boards
+}
+
+// Key is the sorted set key of the board
+func (b Board) Key() string {
+	scope := string(b.Scope)
+	if b.ScopeID != "" {
+		scope += ":" + b.ScopeID
+	}
+	if b.Period == PeriodWeekly {
+		return fmt.Sprintf("leaderboard:%s:week:%s", scope, b.Week)
+	}
+	return fmt.Sprintf("leaderboard:%s:%s", scope, PeriodAllTime)
+}
+
+// TTL is how long the board is kept; zero means forever
+func (b Board) TTL() time.Duration {
+	if b.Period == PeriodWeekly {
+		return weeklyRetention
+	}
+	return 0
+}
+
+// Window is the award time range the board covers. Both are zero for all-time boards.
+func (b Board) Window() (since, until time.Time, err error) {
+	if b.Period != PeriodWeekly {
+		return time.Time{}, time.Time{}, nil
+	}
+	since, err = WeekStart(b.Week)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return since, since.AddDate(0, 0, 7), nil
+}
+
+// Validate checks the board is well formed
+func (b Board) Validate() error {
+	switch b.Scope {
+	case ScopeGlobal:
+		if b.ScopeID != "" {
+			return fmt.Errorf("the global board has no id")
+		}
+	case ScopeLeague, ScopeTeam:
+		if b.ScopeID == "" {
+			return fmt.Errorf("id is required for %s boards", b.Scope)
+		}
+	default:
+		return fmt.Errorf("scope must be 'global', 'league' or 'team'")
+	}
+
+	switch b.Period {
+	case PeriodAllTime:
+	case PeriodWeekly:
+		if _, err := WeekStart(b.Week); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("period must be 'alltime' or 'weekly'")
+	}
+	return nil
+}
+
+// Week returns the ISO week containing t, e.g. "2025-W43"
+func Week(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// WeekStart returns midnight UTC on the Monday that starts an ISO week
+func WeekStart(week string) (time.Time, error) {
+	yearStr, weekStr, found := strings.Cut(week, "-W")
+	if !found {
+		return time.Time{}, fmt.Errorf("week must look like 2025-W43")
+	}
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("week must look like 2025-W43")
+	}
+	number, err := strconv.Atoi(weekStr)
+	if err != nil || number < 1 || number > 53 {
+		return time.Time{}, fmt.Errorf("week must look like 2025-W43")
+	}
+
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7 // days since Monday
+	start := jan4.AddDate(0, 0, -offset+(number-1)*7)
+	if Week(start) != fmt.Sprintf("%d-W%02d", year, number) {
+		return time.Time{}, fmt.Errorf("%s is not a valid week", week)
+	}
+	return start, nil
+}
+
+// BoardsFor lists every board an award counts towards
+func BoardsFor(awardedAt time.Time, leagueID string, teamIDs []string) []Board {
+	week := Week(awardedAt)
+	scopes := []Board{{Scope: ScopeGlobal}}
+	if leagueID != "" {
+		scopes = append(scopes, Board{Scope: ScopeLeague, ScopeID: leagueID})
+	}
+	for _, teamID := range teamIDs {
+		scopes = append(scopes, Board{Scope: ScopeTeam, ScopeID: teamID})
+	}
+
+	boards := make([]Board, 0, len(scopes)*2)
+	for _, scope := range scopes {
+		allTime, weekly := scope, scope
+		allTime.Period = PeriodAllTime
+		weekly.Period = PeriodWeekly
+		weekly.Week = week
+		boards = append(boards, allTime, weekly)
+	}
+	return boards
+}
@@ -0,0 +1,112 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeek(t *testing.T) {
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC), "2025-W43"},
+		{time.Date(2025, 10, 26, 23, 59, 0, 0, time.UTC), "2025-W43"},
+		{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), "2026-W01"},
+		{time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), "2026-W53"},
+	}
+
+	for _, tt := range tests {
+		if got := Week(tt.at); got != tt.want {
+			t.Errorf("Week(%v) = %s, want %s", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	start, err := WeekStart("2025-W43")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("Expected week to start %v, got %v", want, start)
+	}
+
+	start, err = WeekStart("2026-W01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("Expected week to start %v, got %v", want, start)
+	}
+
+	for _, week := range []string{"", "2025-43", "2025-W00", "2025-W53", "abcd-W10"} {
+		if _, err := WeekStart(week); err == nil {
+			t.Errorf("Expected an error for week %q", week)
+		}
+	}
+}
+
+func TestBoardKey(t *testing.T) {
+	tests := []struct {
+		board Board
+		want  string
+	}{
+		{Board{Scope: ScopeGlobal, Period: PeriodAllTime}, "leaderboard:global:alltime"},
+		{Board{Scope: ScopeLeague, ScopeID: "39", Period: PeriodWeekly, Week: "2025-W43"}, "leaderboard:league:39:week:2025-W43"},
+		{Board{Scope: ScopeTeam, ScopeID: "abc", Period: PeriodAllTime}, "leaderboard:team:abc:alltime"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.board.Key(); got != tt.want {
+			t.Errorf("Key() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestBoardValidate(t *testing.T) {
+	valid := []Board{
+		{Scope: ScopeGlobal, Period: PeriodAllTime},
+		{Scope: ScopeLeague, ScopeID: "39", Period: PeriodWeekly, Week: "2025-W43"},
+	}
+	for _, board := range valid {
+		if err := board.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", board, err)
+		}
+	}
+
+	invalid := []Board{
+		{Scope: ScopeGlobal, ScopeID: "1", Period: PeriodAllTime},
+		{Scope: ScopeTeam, Period: PeriodAllTime},
+		{Scope: "country", Period: PeriodAllTime},
+		{Scope: ScopeGlobal, Period: "monthly"},
+		{Scope: ScopeGlobal, Period: PeriodWeekly},
+	}
+	for _, board := range invalid {
+		if err := board.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", board)
+		}
+	}
+}
+
+func TestBoardsFor(t *testing.T) {
+	awardedAt := time.Date(2025, 10, 22, 18, 0, 0, 0, time.UTC)
+
+	boards := BoardsFor(awardedAt, "", nil)
+	if len(boards) != 2 {
+		t.Fatalf("Expected global all-time and weekly boards, got %d", len(boards))
+	}
+
+	boards = BoardsFor(awardedAt, "39", []string{"team-a", "team-b"})
+	if len(boards) != 8 {
+		t.Fatalf("Expected 8 boards, got %d", len(boards))
+	}
+	for _, board := range boards {
+		if err := board.Validate(); err != nil {
+			t.Errorf("Board %s is invalid: %v", board.Key(), err)
+		}
+		if board.Period == PeriodWeekly && board.Week != "2025-W43" {
+			t.Errorf("Expected weekly board for 2025-W43, got %s", board.Week)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package leaderboard
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Entry is a ranked user on a board
+type Entry struct {
+	UserID int32
+	Points int64
+	Rank   int64 // 1-based
+}
+
+// Store holds boards as sorted sets of user ID to points
+type Store interface {
+	// Incr adds points to a user on the board
+	Incr(ctx context.Context, board Board, userID int32, points int64) error
+	// Replace atomically swaps the board's contents, used when rebuilding from Postgres
+	Replace(ctx context.Context, board Board, points map[int32]int64) error
+	// Exists reports whether the board has been built
+	Exists(ctx context.Context, board Board) (bool, error)
+	// Page returns entries by rank, highest points first
+	Page(ctx context.Context, board Board, offset, limit int64) ([]Entry, error)
+	// Rank returns the user's entry, or nil when they aren't on the board
+	Rank(ctx context.Context, board Board, userID int32) (*Entry, error)
+	// Count returns the number of users on the board
+	Count(ctx context.Context, board Board) (int64, error)
+}
+
+// RedisStore keeps boards in Redis sorted sets
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Incr(ctx context.Context, board Board, userID int32, points int64) error {
+	key := board.Key()
+	pipe := s.client.TxPipeline()
+	pipe.ZIncrBy(ctx, key, float64(points), member(userID))
+	if ttl := board.TTL(); ttl > 0 {
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisStore) Replace(ctx context.Context, board Board, points map[int32]int64) error {
+	key := board.Key()
+	// Build aside and rename so readers never see a half built board
+	tmpKey := key + ":rebuild:" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	members := make([]redis.Z, 0, len(points))
+	for userID, total := range points {
+		members = append(members, redis.Z{Score: float64(total), Member: member(userID)})
+	}
+
+	pipe := s.client.TxPipeline()
+	if len(members) == 0 {
+		pipe.Del(ctx, key)
+	} else {
+		pipe.ZAdd(ctx, tmpKey, members...)
+		pipe.Rename(ctx, tmpKey, key)
+		if ttl := board.TTL(); ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisStore) Exists(ctx context.Context, board Board) (bool, error) {
+	n, err := s.client.Exists(ctx, board.Key()).Result()
+	return n > 0, err
+}
+
+func (s *RedisStore) Page(ctx context.Context, board Board, offset, limit int64) ([]Entry, error) {
+	results, err := s.client.ZRevRangeWithScores(ctx, board.Key(), offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(results))
+	for i, result := range results {
+		userID, err := parseMember(result.Member)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			UserID: userID,
+			Points: int64(result.Score),
+			Rank:   offset + int64(i) + 1,
+		})
+	}
+	return entries, nil
+}
+
+func (s *RedisStore) Rank(ctx context.Context, board Board, userID int32) (*Entry, error) {
+	key := board.Key()
+	rank, err := s.client.ZRevRank(ctx, key, member(userID)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	score, err := s.client.ZScore(ctx, key, member(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{UserID: userID, Points: int64(score), Rank: rank + 1}, nil
+}
+
+func (s *RedisStore) Count(ctx context.Context, board Board) (int64, error) {
+	return s.client.ZCard(ctx, board.Key()).Result()
+}
+
+func member(userID int32) string {
+	return strconv.FormatInt(int64(userID), 10)
+}
+
+func parseMember(m interface{}) (int32, error) {
+	s, _ := m.(string)
+	id, err := strconv.ParseInt(s, 10, 32)
+	return int32(id), err
+}
@@ -0,0 +1,231 @@
+package news
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Item is a single entry parsed from an RSS or Atom feed, before normalization
+type Item struct {
+	GUID        string
+	Link        string
+	Title       string
+	Summary     string
+	ImageURL    string
+	Publisher   string
+	PublishedAt time.Time
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	GUID        string `xml:"guid"`
+	Link        string `xml:"link"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Source      string `xml:"source"`
+	Enclosure   struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	MediaContent []struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnail []struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type atomDocument struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Links     []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	Author struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	MediaThumbnail []struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+// dateLayouts covers the date formats seen in the wild across RSS and Atom feeds
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	time.RFC3339Nano,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// ParseFeed parses an RSS 2.0 or Atom document into feed items
+func ParseFeed(r io.Reader) ([]Item, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading feed: %w", err)
+	}
+
+	root, err := rootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss", "RDF":
+		return parseRSS(body)
+	case "feed":
+		return parseAtom(body)
+	default:
+		return nil, fmt.Errorf("unsupported feed format: <%s>", root)
+	}
+}
+
+func rootElement(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("error parsing feed: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func newDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// Feeds routinely contain HTML entities such as &nbsp; that are not valid XML
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	return decoder
+}
+
+func parseRSS(body []byte) ([]Item, error) {
+	var doc rssDocument
+	if err := newDecoder(body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error parsing RSS feed: %w", err)
+	}
+
+	items := make([]Item, 0, len(doc.Channel.Items))
+	for _, entry := range doc.Channel.Items {
+		item := Item{
+			GUID:      strings.TrimSpace(entry.GUID),
+			Link:      strings.TrimSpace(entry.Link),
+			Title:     entry.Title,
+			Summary:   entry.Description,
+			Publisher: strings.TrimSpace(entry.Source),
+		}
+		if item.Publisher == "" {
+			item.Publisher = strings.TrimSpace(doc.Channel.Title)
+		}
+
+		switch {
+		case len(entry.MediaThumbnail) > 0:
+			item.ImageURL = entry.MediaThumbnail[0].URL
+		case len(entry.MediaContent) > 0:
+			item.ImageURL = entry.MediaContent[0].URL
+		case strings.HasPrefix(entry.Enclosure.Type, "image/"):
+			item.ImageURL = entry.Enclosure.URL
+		}
+
+		date := entry.PubDate
+		if date == "" {
+			date = entry.Date
+		}
+		item.PublishedAt = parseDate(date)
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parseAtom(body []byte) ([]Item, error) {
+	var doc atomDocument
+	if err := newDecoder(body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error parsing Atom feed: %w", err)
+	}
+
+	items := make([]Item, 0, len(doc.Entries))
+	for _, entry := range doc.Entries {
+		item := Item{
+			GUID:      strings.TrimSpace(entry.ID),
+			Title:     entry.Title,
+			Summary:   entry.Summary,
+			Publisher: strings.TrimSpace(doc.Title),
+		}
+		if item.Summary == "" {
+			item.Summary = entry.Content
+		}
+		if item.Publisher == "" {
+			item.Publisher = strings.TrimSpace(entry.Author.Name)
+		}
+
+		// Prefer the alternate link; fall back to the first link without a rel
+		for _, link := range entry.Links {
+			if link.Rel == "alternate" || (link.Rel == "" && item.Link == "") {
+				item.Link = strings.TrimSpace(link.Href)
+			}
+			if link.Rel == "enclosure" && strings.HasPrefix(link.Type, "image/") {
+				item.ImageURL = link.Href
+			}
+		}
+		if item.ImageURL == "" && len(entry.MediaThumbnail) > 0 {
+			item.ImageURL = entry.MediaThumbnail[0].URL
+		}
+
+		date := entry.Published
+		if date == "" {
+			date = entry.Updated
+		}
+		item.PublishedAt = parseDate(date)
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// parseDate tries each known layout and returns the zero time when none match
+func parseDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
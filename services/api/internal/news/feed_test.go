@@ -0,0 +1,180 @@
+package news
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFeedRSS(t *testing.T) {
+	f, err := os.Open("testdata/rss.xml")
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	items, err := ParseFeed(f)
+	if err != nil {
+		t.Fatalf("ParseFeed returned error: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("Expected 4 items, got %d", len(items))
+	}
+
+	first := items[0]
+	if first.Title != "Arsenal edge Chelsea in London derby thriller" {
+		t.Errorf("Unexpected title: %q", first.Title)
+	}
+	if first.GUID != "sample-1001" {
+		t.Errorf("Unexpected guid: %q", first.GUID)
+	}
+	if first.Publisher != "Sample Football News" {
+		t.Errorf("Expected channel title as publisher, got %q", first.Publisher)
+	}
+	if first.ImageURL != "https://news.example.com/images/arsenal-chelsea.jpg" {
+		t.Errorf("Expected media thumbnail, got %q", first.ImageURL)
+	}
+	expected := time.Date(2025, 10, 18, 17, 30, 0, 0, time.UTC)
+	if !first.PublishedAt.Equal(expected) {
+		t.Errorf("Expected published at %v, got %v", expected, first.PublishedAt)
+	}
+
+	if items[1].ImageURL != "https://news.example.com/images/spurs.jpg" {
+		t.Errorf("Expected image enclosure, got %q", items[1].ImageURL)
+	}
+	if items[1].PublishedAt.IsZero() {
+		t.Error("Expected GMT pubDate to be parsed")
+	}
+	if !items[3].PublishedAt.Equal(time.Date(2025, 10, 16, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected dc:date fallback, got %v", items[3].PublishedAt)
+	}
+}
+
+func TestParseFeedAtom(t *testing.T) {
+	f, err := os.Open("testdata/atom.xml")
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	items, err := ParseFeed(f)
+	if err != nil {
+		t.Fatalf("ParseFeed returned error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	if items[0].Link != "https://wire.example.org/stories/arsenal-chelsea-derby" {
+		t.Errorf("Expected alternate link, got %q", items[0].Link)
+	}
+	if items[0].Publisher != "Example Sport Wire" {
+		t.Errorf("Expected feed title as publisher, got %q", items[0].Publisher)
+	}
+	if !items[0].PublishedAt.Equal(time.Date(2025, 10, 18, 17, 45, 0, 0, time.UTC)) {
+		t.Errorf("Expected published date to win over updated, got %v", items[0].PublishedAt)
+	}
+
+	second := items[1]
+	if second.Link != "https://wire.example.org/stories/man-utd-injury" {
+		t.Errorf("Expected link without rel, got %q", second.Link)
+	}
+	if second.ImageURL != "https://wire.example.org/img/man-utd.png" {
+		t.Errorf("Expected image enclosure link, got %q", second.ImageURL)
+	}
+	if second.Summary == "" {
+		t.Error("Expected content to be used when summary is missing")
+	}
+	if !second.PublishedAt.Equal(time.Date(2025, 10, 18, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected updated date converted to UTC, got %v", second.PublishedAt)
+	}
+}
+
+func TestParseFeedUnsupported(t *testing.T) {
+	_, err := ParseFeed(strings.NewReader(`<html><body>Not a feed</body></html>`))
+	if err == nil {
+		t.Error("Expected error for non-feed document")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	now := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Cleans text and URL", func(t *testing.T) {
+		article, err := Normalize(Item{
+			Title:   "  Arsenal &amp; Chelsea\n share points ",
+			Link:    "https://News.Example.com/story/?utm_source=rss&id=7&fbclid=abc#top",
+			Summary: "<p>Late <b>drama</b>&nbsp;at the Emirates</p>",
+		}, now)
+		if err != nil {
+			t.Fatalf("Normalize returned error: %v", err)
+		}
+		if article.Title != "Arsenal & Chelsea share points" {
+			t.Errorf("Unexpected title: %q", article.Title)
+		}
+		if article.URL != "https://news.example.com/story?id=7" {
+			t.Errorf("Unexpected URL: %q", article.URL)
+		}
+		if article.Summary != "Late drama at the Emirates" {
+			t.Errorf("Unexpected summary: %q", article.Summary)
+		}
+		if !article.PublishedAt.Equal(now) {
+			t.Errorf("Expected missing date to default to now, got %v", article.PublishedAt)
+		}
+	})
+
+	t.Run("Same title hashes the same", func(t *testing.T) {
+		if ContentHash("Arsenal edge Chelsea") != ContentHash("  ARSENAL edge   chelsea ") {
+			t.Error("Expected content hash to ignore case and whitespace")
+		}
+	})
+
+	t.Run("Rejects items without title or link", func(t *testing.T) {
+		if _, err := Normalize(Item{Title: " ", Link: "https://example.com/a"}, now); err == nil {
+			t.Error("Expected error for missing title")
+		}
+		if _, err := Normalize(Item{Title: "Headline"}, now); err == nil {
+			t.Error("Expected error for missing link")
+		}
+		if _, err := Normalize(Item{Title: "Headline", Link: "javascript:alert(1)"}, now); err == nil {
+			t.Error("Expected error for non-http link")
+		}
+	})
+
+	t.Run("Falls back to permalink guid", func(t *testing.T) {
+		article, err := Normalize(Item{Title: "Headline", GUID: "https://example.com/a"}, now)
+		if err != nil {
+			t.Fatalf("Normalize returned error: %v", err)
+		}
+		if article.URL != "https://example.com/a" {
+			t.Errorf("Unexpected URL: %q", article.URL)
+		}
+	})
+}
+
+func TestTagger(t *testing.T) {
+	tagger := NewTagger(map[string][]string{
+		"Manchester United": {"Man Utd", "Man United"},
+		"Inter":             {"Internazionale"},
+		"Atlético Madrid":   {"Atleti"},
+	})
+
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"Man Utd confirm injury blow", []string{"Manchester United"}},
+		{"manchester united and Atleti in talks", []string{"Atlético Madrid", "Manchester United"}},
+		{"International break: who has been called up?", nil},
+		{"Inter beat Milan in the derby", []string{"Inter"}},
+		{"Atlético Madrid, top of the table", []string{"Atlético Madrid"}},
+	}
+
+	for _, tt := range tests {
+		got := tagger.Tag(tt.text)
+		if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("Tag(%q) = %v, expected %v", tt.text, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package news
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Feed is an RSS/Atom source to poll
+type Feed struct {
+	ID   int32
+	Name string
+	URL  string
+}
+
+// Fetcher retrieves the raw body of a feed
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// Store persists feeds and articles for the ingester
+type Store interface {
+	ListActiveFeeds(ctx context.Context) ([]Feed, error)
+	ListTeamAliases(ctx context.Context) (map[string][]string, error)
+	// SaveArticle stores the article and its team tags. It returns false when the
+	// article was already stored (same URL or content hash).
+	SaveArticle(ctx context.Context, article Article) (bool, error)
+	MarkFeedPolled(ctx context.Context, feedID int32, pollErr error) error
+}
+
+// HTTPFetcher fetches feeds over HTTP
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+// NewHTTPFetcher creates a fetcher with a sensible timeout
+func NewHTTPFetcher() *HTTPFetcher {
+	return &HTTPFetcher{Client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Fetch performs a GET request against the feed URL
+func (f *HTTPFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating feed request: %w", err)
+	}
+	req.Header.Set("User-Agent", "fucci-news-ingester/1.0")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching feed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// PollResult summarizes a single polling pass
+type PollResult struct {
+	Feeds      int
+	Items      int
+	Saved      int
+	Duplicates int
+	Skipped    int
+	FeedErrors int
+}
+
+// Ingester polls feeds, normalizes and tags their items, and stores new articles
+type Ingester struct {
+	store   Store
+	fetcher Fetcher
+	now     func() time.Time
+}
+
+// NewIngester creates a new news ingester
+func NewIngester(store Store, fetcher Fetcher) *Ingester {
+	return &Ingester{
+		store:   store,
+		fetcher: fetcher,
+		now:     time.Now,
+	}
+}
+
+// PollOnce polls every active feed a single time. A failing feed is recorded
+// against that feed and does not stop the others from being processed.
+func (i *Ingester) PollOnce(ctx context.Context) (PollResult, error) {
+	var result PollResult
+
+	feeds, err := i.store.ListActiveFeeds(ctx)
+	if err != nil {
+		return result, fmt.Errorf("error listing feeds: %w", err)
+	}
+	aliases, err := i.store.ListTeamAliases(ctx)
+	if err != nil {
+		return result, fmt.Errorf("error listing team aliases: %w", err)
+	}
+	tagger := NewTagger(aliases)
+
+	// Feeds commonly carry the same wire story, so dedupe across the whole pass
+	seenURLs := make(map[string]bool)
+	seenHashes := make(map[string]bool)
+
+	for _, feed := range feeds {
+		result.Feeds++
+
+		items, pollErr := i.fetchFeed(ctx, feed)
+		if pollErr == nil {
+			for _, item := range items {
+				result.Items++
+
+				article, err := Normalize(item, i.now().UTC())
+				if err != nil {
+					result.Skipped++
+					continue
+				}
+				if seenURLs[article.URL] || seenHashes[article.ContentHash] {
+					result.Duplicates++
+					continue
+				}
+				seenURLs[article.URL] = true
+				seenHashes[article.ContentHash] = true
+
+				article.FeedID = feed.ID
+				article.Teams = tagger.Tag(article.Title, article.Summary)
+
+				saved, err := i.store.SaveArticle(ctx, article)
+				if err != nil {
+					pollErr = fmt.Errorf("error saving article %q: %w", article.URL, err)
+					break
+				}
+				if saved {
+					result.Saved++
+				} else {
+					result.Duplicates++
+				}
+			}
+		}
+
+		if pollErr != nil {
+			result.FeedErrors++
+			log.Printf("News feed %s (%s) failed: %v", feed.Name, feed.URL, pollErr)
+		}
+		if err := i.store.MarkFeedPolled(ctx, feed.ID, pollErr); err != nil {
+			log.Printf("Failed to mark news feed %d as polled: %v", feed.ID, err)
+		}
+	}
+
+	return result, nil
+}
+
+func (i *Ingester) fetchFeed(ctx context.Context, feed Feed) ([]Item, error) {
+	body, err := i.fetcher.Fetch(ctx, feed.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return ParseFeed(body)
+}
+
+// Run polls immediately and then on every interval until the context is cancelled
+func (i *Ingester) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := i.PollOnce(ctx)
+		if err != nil {
+			log.Printf("News ingestion failed: %v", err)
+		} else {
+			log.Printf("News ingestion: %d feeds, %d items, %d saved, %d duplicates, %d skipped, %d feed errors",
+				result.Feeds, result.Items, result.Saved, result.Duplicates, result.Skipped, result.FeedErrors)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+type fixtureFetcher struct {
+	files map[string]string
+}
+
+func (f *fixtureFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	path, ok := f.files[url]
+	if !ok {
+		return nil, fmt.Errorf("feed returned status 404")
+	}
+	return os.Open(path)
+}
+
+type memoryStore struct {
+	feeds    []Feed
+	aliases  map[string][]string
+	articles map[string]Article
+	hashes   map[string]bool
+	polled   map[int32]error
+}
+
+func newMemoryStore(feeds ...Feed) *memoryStore {
+	return &memoryStore{
+		feeds: feeds,
+		aliases: map[string][]string{
+			"Arsenal":           {"Gunners"},
+			"Chelsea":           nil,
+			"Manchester United": {"Man Utd", "United"},
+		},
+		articles: make(map[string]Article),
+		hashes:   make(map[string]bool),
+		polled:   make(map[int32]error),
+	}
+}
+
+func (s *memoryStore) ListActiveFeeds(ctx context.Context) ([]Feed, error) {
+	return s.feeds, nil
+}
+
+func (s *memoryStore) ListTeamAliases(ctx context.Context) (map[string][]string, error) {
+	return s.aliases, nil
+}
+
+func (s *memoryStore) SaveArticle(ctx context.Context, article Article) (bool, error) {
+	if _, ok := s.articles[article.URL]; ok || s.hashes[article.ContentHash] {
+		return false, nil
+	}
+	s.articles[article.URL] = article
+	s.hashes[article.ContentHash] = true
+	return true, nil
+}
+
+func (s *memoryStore) MarkFeedPolled(ctx context.Context, feedID int32, pollErr error) error {
+	s.polled[feedID] = pollErr
+	return nil
+}
+
+func TestIngesterPollOnce(t *testing.T) {
+	store := newMemoryStore(
+		Feed{ID: 1, Name: "rss", URL: "https://news.example.com/rss"},
+		Feed{ID: 2, Name: "atom", URL: "https://wire.example.org/atom"},
+		Feed{ID: 3, Name: "broken", URL: "https://broken.example.com/rss"},
+	)
+	fetcher := &fixtureFetcher{files: map[string]string{
+		"https://news.example.com/rss":  "testdata/rss.xml",
+		"https://wire.example.org/atom": "testdata/atom.xml",
+	}}
+
+	ingester := NewIngester(store, fetcher)
+	ingester.now = func() time.Time { return time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC) }
+
+	result, err := ingester.PollOnce(context.Background())
+	if err != nil {
+		t.Fatalf("PollOnce returned error: %v", err)
+	}
+
+	if result.Feeds != 3 || result.Items != 6 {
+		t.Errorf("Expected 3 feeds and 6 items, got %+v", result)
+	}
+	// The Atom feed syndicates the Arsenal story under a different URL
+	if result.Saved != 4 || result.Duplicates != 1 || result.Skipped != 1 {
+		t.Errorf("Expected 4 saved, 1 duplicate and 1 skipped, got %+v", result)
+	}
+	if result.FeedErrors != 1 {
+		t.Errorf("Expected 1 feed error, got %d", result.FeedErrors)
+	}
+
+	article, ok := store.articles["https://news.example.com/football/arsenal-chelsea"]
+	if !ok {
+		t.Fatalf("Expected normalized Arsenal article to be stored, got %v", store.articles)
+	}
+	if article.FeedID != 1 {
+		t.Errorf("Expected feed ID 1, got %d", article.FeedID)
+	}
+	if len(article.Teams) != 2 || article.Teams[0] != "Arsenal" || article.Teams[1] != "Chelsea" {
+		t.Errorf("Expected article tagged to Arsenal and Chelsea, got %v", article.Teams)
+	}
+
+	united := store.articles["https://wire.example.org/stories/man-utd-injury"]
+	if len(united.Teams) != 1 || united.Teams[0] != "Manchester United" {
+		t.Errorf("Expected article tagged to Manchester United, got %v", united.Teams)
+	}
+
+	if store.polled[1] != nil || store.polled[2] != nil {
+		t.Errorf("Expected healthy feeds to be marked without error, got %v", store.polled)
+	}
+	if store.polled[3] == nil {
+		t.Error("Expected broken feed to record its error")
+	}
+
+	// A second pass stores nothing new
+	result, err = ingester.PollOnce(context.Background())
+	if err != nil {
+		t.Fatalf("PollOnce returned error: %v", err)
+	}
+	if result.Saved != 0 || result.Duplicates != 5 {
+		t.Errorf("Expected second pass to only find duplicates, got %+v", result)
+	}
+}
+
+func TestIngesterPollOnceStoreError(t *testing.T) {
+	store := &failingStore{}
+	ingester := NewIngester(store, &fixtureFetcher{})
+	if _, err := ingester.PollOnce(context.Background()); err == nil {
+		t.Error("Expected error when feeds cannot be listed")
+	}
+}
+
+type failingStore struct {
+	memoryStore
+}
+
+func (s *failingStore) ListActiveFeeds(ctx context.Context) ([]Feed, error) {
+	return nil, errors.New("connection refused")
+}
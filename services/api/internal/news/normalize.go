@@ -0,0 +1,140 @@
+package news
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxSummaryLength caps stored summaries; feeds sometimes ship the full article body
+const maxSummaryLength = 500
+
+var (
+	tagPattern        = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`[\s\p{Zs}]+`)
+
+	// trackingParams are query parameters stripped from article URLs before deduplication
+	trackingParams = map[string]bool{
+		"fbclid":      true,
+		"gclid":       true,
+		"ocid":        true,
+		"cmpid":       true,
+		"ref":         true,
+		"at_medium":   true,
+		"at_campaign": true,
+	}
+)
+
+// Article is a normalized feed item ready to be stored
+type Article struct {
+	FeedID      int32
+	GUID        string
+	URL         string
+	Title       string
+	Summary     string
+	Publisher   string
+	ImageURL    string
+	ContentHash string
+	PublishedAt time.Time
+	Teams       []string
+}
+
+// Normalize cleans up a parsed feed item and computes its dedupe keys.
+// Items without a title or a usable link are rejected.
+func Normalize(item Item, now time.Time) (Article, error) {
+	title := CleanText(item.Title)
+	if title == "" {
+		return Article{}, errors.New("item has no title")
+	}
+
+	link := item.Link
+	// Some RSS feeds only expose the permalink through the guid
+	if link == "" && strings.HasPrefix(item.GUID, "http") {
+		link = item.GUID
+	}
+	normalizedURL, err := NormalizeURL(link)
+	if err != nil {
+		return Article{}, err
+	}
+
+	summary := CleanText(item.Summary)
+	if len([]rune(summary)) > maxSummaryLength {
+		summary = strings.TrimSpace(string([]rune(summary)[:maxSummaryLength])) + "..."
+	}
+
+	publishedAt := item.PublishedAt
+	// Missing or future-dated entries are pinned to the poll time
+	if publishedAt.IsZero() || publishedAt.After(now) {
+		publishedAt = now
+	}
+
+	return Article{
+		GUID:        item.GUID,
+		URL:         normalizedURL,
+		Title:       title,
+		Summary:     summary,
+		Publisher:   CleanText(item.Publisher),
+		ImageURL:    strings.TrimSpace(item.ImageURL),
+		ContentHash: ContentHash(title),
+		PublishedAt: publishedAt.UTC(),
+	}, nil
+}
+
+// NormalizeURL lowercases the scheme and host, drops the fragment and strips tracking
+// parameters so that the same story linked from different places dedupes to one row
+func NormalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("item has no link")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid link %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid link %q: unsupported scheme", raw)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		u.RawPath = ""
+	}
+
+	return u.String(), nil
+}
+
+// CleanText strips HTML tags, unescapes entities and collapses whitespace
+func CleanText(s string) string {
+	s = tagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	s = whitespacePattern.ReplaceAllString(s, " ")
+	return strings.TrimSpace(s)
+}
+
+// ContentHash identifies an article by its title so that syndicated copies of the same
+// story published under different URLs are only stored once
+func ContentHash(title string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(CleanText(title))))
+	return hex.EncodeToString(sum[:])
+}
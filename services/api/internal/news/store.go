@@ -0,0 +1,146 @@
+package news
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ArronJLinton/fucci-api/internal/database"
+)
+
+// DBStore is the Postgres-backed Store
+type DBStore struct {
+	conn *sql.DB
+	db   *database.Queries
+}
+
+// NewDBStore creates a new Postgres-backed news store
+func NewDBStore(conn *sql.DB) *DBStore {
+	return &DBStore{conn: conn, db: database.New(conn)}
+}
+
+// EnsureFeeds registers the configured feed URLs, reactivating any that were disabled
+func (s *DBStore) EnsureFeeds(ctx context.Context, feedURLs []string) error {
+	for _, feedURL := range feedURLs {
+		feedURL = strings.TrimSpace(feedURL)
+		if feedURL == "" {
+			continue
+		}
+		name := feedURL
+		if u, err := url.Parse(feedURL); err == nil && u.Host != "" {
+			name = strings.TrimPrefix(u.Host, "www.")
+		}
+		if _, err := s.db.UpsertNewsFeed(ctx, database.UpsertNewsFeedParams{
+			Name: truncate(name, 100),
+			Url:  feedURL,
+		}); err != nil {
+			return fmt.Errorf("error registering feed %s: %w", feedURL, err)
+		}
+	}
+	return nil
+}
+
+func (s *DBStore) ListActiveFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := s.db.ListActiveNewsFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeds := make([]Feed, 0, len(rows))
+	for _, row := range rows {
+		feeds = append(feeds, Feed{ID: row.ID, Name: row.Name, URL: row.Url})
+	}
+	return feeds, nil
+}
+
+// ListTeamAliases returns the seeded aliases plus every team in the teams
+// table, which is tagged by its name alone when it has no aliases
+func (s *DBStore) ListTeamAliases(ctx context.Context) (map[string][]string, error) {
+	rows, err := s.db.ListTeamAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases := make(map[string][]string)
+	for _, row := range rows {
+		aliases[row.TeamName] = append(aliases[row.TeamName], row.Alias)
+	}
+
+	names, err := s.db.ListTeamNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if _, ok := aliases[name]; !ok {
+			aliases[name] = nil
+		}
+	}
+	return aliases, nil
+}
+
+// SaveArticle stores the article and its tags together, so an article is
+// never left with some of its teams missing
+func (s *DBStore) SaveArticle(ctx context.Context, article Article) (bool, error) {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	q := s.db.WithTx(tx)
+
+	row, err := q.CreateNewsArticle(ctx, database.CreateNewsArticleParams{
+		FeedID:      sql.NullInt32{Int32: article.FeedID, Valid: article.FeedID != 0},
+		Guid:        nullString(article.GUID),
+		Url:         article.URL,
+		Title:       article.Title,
+		Summary:     nullString(article.Summary),
+		Publisher:   nullString(truncate(article.Publisher, 100)),
+		ImageUrl:    nullString(article.ImageURL),
+		ContentHash: article.ContentHash,
+		PublishedAt: article.PublishedAt,
+	})
+	if err != nil {
+		// ON CONFLICT DO NOTHING returns no row for duplicates
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, team := range article.Teams {
+		if err := q.TagNewsArticle(ctx, database.TagNewsArticleParams{
+			ArticleID: row.ID,
+			TeamName:  team,
+		}); err != nil {
+			return false, fmt.Errorf("error tagging article %d: %w", row.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *DBStore) MarkFeedPolled(ctx context.Context, feedID int32, pollErr error) error {
+	var lastError sql.NullString
+	if pollErr != nil {
+		lastError = sql.NullString{String: pollErr.Error(), Valid: true}
+	}
+	return s.db.MarkNewsFeedPolled(ctx, database.MarkNewsFeedPolledParams{
+		ID:        feedID,
+		LastError: lastError,
+	})
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,50 @@
+package news
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Tagger matches article text against team names and their aliases
+type Tagger struct {
+	patterns map[string][]*regexp.Regexp
+}
+
+// NewTagger builds a tagger from a map of team name to aliases.
+// The team name itself is always treated as an alias.
+func NewTagger(aliases map[string][]string) *Tagger {
+	t := &Tagger{patterns: make(map[string][]*regexp.Regexp, len(aliases))}
+	for team, teamAliases := range aliases {
+		seen := make(map[string]bool)
+		for _, alias := range append([]string{team}, teamAliases...) {
+			alias = strings.TrimSpace(alias)
+			key := strings.ToLower(alias)
+			if alias == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			// Match on letter/number boundaries so "Inter" doesn't tag "international"
+			// and accented names still match
+			pattern := `(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(alias) + `($|[^\pL\pN])`
+			t.patterns[team] = append(t.patterns[team], regexp.MustCompile(pattern))
+		}
+	}
+	return t
+}
+
+// Tag returns the sorted list of teams mentioned in any of the given texts
+func (t *Tagger) Tag(texts ...string) []string {
+	text := strings.Join(texts, "\n")
+	var teams []string
+	for team, patterns := range t.patterns {
+		for _, pattern := range patterns {
+			if pattern.MatchString(text) {
+				teams = append(teams, team)
+				break
+			}
+		}
+	}
+	sort.Strings(teams)
+	return teams
+}
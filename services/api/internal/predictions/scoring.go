@@ -0,0 +1,57 @@
+// Package predictions scores fans' scoreline predictions against final results.
+package predictions
+
+// Outcomes of a scored prediction, best first
+const (
+	OutcomeExact          = "exact"
+	OutcomeGoalDifference = "goal_difference"
+	OutcomeResult         = "result"
+	OutcomeMiss           = "miss"
+)
+
+// Rules are the points awarded per outcome. Only the best matching outcome counts.
+type Rules struct {
+	Exact          int32 // Exact scoreline
+	GoalDifference int32 // Right winner (or draw) and margin, wrong scoreline
+	Result         int32 // Right winner or draw only
+}
+
+// DefaultRules reward an exact score most and a correct result least
+var DefaultRules = Rules{Exact: 5, GoalDifference: 3, Result: 2}
+
+// Score is a scoreline, home team first
+type Score struct {
+	Home int32
+	Away int32
+}
+
+// Result is 1 for a home win, -1 for an away win and 0 for a draw
+func (s Score) Result() int {
+	switch {
+	case s.Home > s.Away:
+		return 1
+	case s.Home < s.Away:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// Evaluate returns the outcome and points of a prediction against the final score
+func (r Rules) Evaluate(predicted, final Score) (string, int32) {
+	switch {
+	case predicted == final:
+		return OutcomeExact, r.Exact
+	case predicted.Result() != final.Result():
+		return OutcomeMiss, 0
+	case predicted.Home-predicted.Away == final.Home-final.Away:
+		return OutcomeGoalDifference, r.GoalDifference
+	default:
+		return OutcomeResult, r.Result
+	}
+}
+
+// IsZero reports whether no rules have been configured
+func (r Rules) IsZero() bool {
+	return r == Rules{}
+}
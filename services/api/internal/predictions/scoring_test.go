@@ -0,0 +1,39 @@
+package predictions
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	rules := Rules{Exact: 5, GoalDifference: 3, Result: 2}
+
+	tests := []struct {
+		predicted   Score
+		final       Score
+		wantOutcome string
+		wantPoints  int32
+	}{
+		{Score{2, 1}, Score{2, 1}, OutcomeExact, 5},
+		{Score{0, 0}, Score{0, 0}, OutcomeExact, 5},
+		{Score{3, 2}, Score{2, 1}, OutcomeGoalDifference, 3},
+		{Score{1, 1}, Score{2, 2}, OutcomeGoalDifference, 3},
+		{Score{3, 0}, Score{2, 1}, OutcomeResult, 2},
+		{Score{0, 1}, Score{0, 3}, OutcomeResult, 2},
+		{Score{2, 1}, Score{1, 1}, OutcomeMiss, 0},
+		{Score{0, 2}, Score{2, 0}, OutcomeMiss, 0},
+	}
+
+	for _, tt := range tests {
+		outcome, points := rules.Evaluate(tt.predicted, tt.final)
+		if outcome != tt.wantOutcome || points != tt.wantPoints {
+			t.Errorf("Evaluate(%v, %v) = %s/%d, want %s/%d", tt.predicted, tt.final, outcome, points, tt.wantOutcome, tt.wantPoints)
+		}
+	}
+}
+
+func TestRulesIsZero(t *testing.T) {
+	if !(Rules{}).IsZero() {
+		t.Error("Expected empty rules to be zero")
+	}
+	if DefaultRules.IsZero() {
+		t.Error("Expected default rules to not be zero")
+	}
+}
@@ -0,0 +1,103 @@
+// Package ratings aggregates the attribute ratings players give each other.
+package ratings
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Ratings are out of 99, like the attributes players report themselves
+const (
+	MinValue = 1
+	MaxValue = 99
+)
+
+// MinRaters is how many ratings a player needs before community values are
+// published, so a single teammate can't set them
+const MinRaters = 3
+
+// trimFraction of the ratings is dropped from each end of every attribute
+// once there are enough of them, so a grudge or a favour doesn't move it
+const trimFraction = 0.1
+
+// Attributes are a player's seven rated attributes
+type Attributes struct {
+	Pace      int32 `json:"pace"`
+	Shooting  int32 `json:"shooting"`
+	Passing   int32 `json:"passing"`
+	Stamina   int32 `json:"stamina"`
+	Dribbling int32 `json:"dribbling"`
+	Defending int32 `json:"defending"`
+	Physical  int32 `json:"physical"`
+}
+
+// fields lists the attributes in a fixed order
+func (a *Attributes) fields() []*int32 {
+	return []*int32{&a.Pace, &a.Shooting, &a.Passing, &a.Stamina, &a.Dribbling, &a.Defending, &a.Physical}
+}
+
+// names matches the order of fields
+var names = []string{"pace", "shooting", "passing", "stamina", "dribbling", "defending", "physical"}
+
+// Validate checks every attribute is between min and MaxValue
+func (a Attributes) Validate(min int32) error {
+	for i, value := range a.fields() {
+		if *value < min || *value > MaxValue {
+			return fmt.Errorf("%s must be between %d and %d", names[i], min, MaxValue)
+		}
+	}
+	return nil
+}
+
+// Rating is one rater's view of a player, counted Weight times
+type Rating struct {
+	Attributes
+	Weight int32
+}
+
+type weighted struct {
+	value  int32
+	weight int32
+}
+
+// Aggregate combines ratings into community attributes. Each attribute
+// drops its highest and lowest ratings, then takes the weighted mean of the
+// rest. It returns false until there are MinRaters ratings.
+func Aggregate(ratings []Rating) (Attributes, bool) {
+	var result Attributes
+	if len(ratings) < MinRaters {
+		return result, false
+	}
+
+	for i, field := range result.fields() {
+		values := make([]weighted, 0, len(ratings))
+		for _, rating := range ratings {
+			values = append(values, weighted{value: *rating.fields()[i], weight: rating.Weight})
+		}
+		*field = trimmedMean(values)
+	}
+	return result, true
+}
+
+// trimmedMean drops trimFraction of the values from each end, at least one
+// once there are five, and averages the rest by weight
+func trimmedMean(values []weighted) int32 {
+	sort.SliceStable(values, func(i, j int) bool { return values[i].value < values[j].value })
+
+	trim := 0
+	if len(values) >= 5 {
+		trim = int(math.Max(1, math.Floor(float64(len(values))*trimFraction)))
+	}
+	values = values[trim : len(values)-trim]
+
+	var total, weights float64
+	for _, v := range values {
+		total += float64(v.value) * float64(v.weight)
+		weights += float64(v.weight)
+	}
+	if weights == 0 {
+		return 0
+	}
+	return int32(math.Round(total / weights))
+}
@@ -0,0 +1,51 @@
+package ratings
+
+import "testing"
+
+func rating(value, weight int32) Rating {
+	return Rating{Attributes: Attributes{value, value, value, value, value, value, value}, Weight: weight}
+}
+
+func TestAggregateNeedsMinRaters(t *testing.T) {
+	if _, ok := Aggregate([]Rating{rating(70, 1), rating(80, 1)}); ok {
+		t.Error("Expected no community values from two ratings")
+	}
+}
+
+func TestAggregateWeightsRaters(t *testing.T) {
+	// A manager counts double, pulling the mean towards their 90
+	got, ok := Aggregate([]Rating{rating(60, 1), rating(60, 1), rating(90, 2)})
+	if !ok {
+		t.Fatal("Expected community values from three ratings")
+	}
+	if got.Pace != 75 || got.Physical != 75 {
+		t.Errorf("Expected 75, got %+v", got)
+	}
+}
+
+func TestAggregateTrimsOutliers(t *testing.T) {
+	got, _ := Aggregate([]Rating{rating(1, 1), rating(70, 1), rating(72, 1), rating(74, 1), rating(99, 1)})
+	if got.Shooting != 72 {
+		t.Errorf("Expected the outliers dropped leaving 72, got %d", got.Shooting)
+	}
+}
+
+func TestAggregateAttributesIndependently(t *testing.T) {
+	fast := Rating{Attributes: Attributes{Pace: 90, Defending: 40}, Weight: 1}
+	got, _ := Aggregate([]Rating{fast, fast, fast})
+	if got.Pace != 90 || got.Defending != 40 || got.Passing != 0 {
+		t.Errorf("Unexpected attributes %+v", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Attributes{70, 70, 70, 70, 70, 70, 70}).Validate(MinValue); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := (Attributes{Pace: 70}).Validate(MinValue); err == nil {
+		t.Error("Expected an error for unrated attributes")
+	}
+	if err := (Attributes{Pace: 100}).Validate(0); err == nil {
+		t.Error("Expected an error above the maximum")
+	}
+}
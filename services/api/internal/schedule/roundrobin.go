@@ -0,0 +1,68 @@
+// Package schedule generates round-robin fixture lists for local leagues and
+// fits them into the weeks, venues and kickoff slots a league has available.
+package schedule
+
+import "github.com/google/uuid"
+
+// Pairing is one match of a round-robin, before it has a date
+type Pairing struct {
+	Round int // 1-based
+	Home  uuid.UUID
+	Away  uuid.UUID
+}
+
+// RoundRobin pairs every team with every other using the circle method. One
+// team stays fixed while the rest rotate around it, and odd numbers of teams
+// get a bye each round. Home and away alternate so every team's counts differ
+// by at most one, with the fewest possible back-to-back home or away games.
+//
+// A double round-robin repeats the rounds with home and away swapped.
+func RoundRobin(teams []uuid.UUID, double bool) []Pairing {
+	if len(teams) < 2 {
+		return nil
+	}
+
+	// uuid.Nil stands in for the bye
+	slots := append([]uuid.UUID(nil), teams...)
+	if len(slots)%2 == 1 {
+		slots = append(slots, uuid.Nil)
+	}
+	n := len(slots)
+	fixed := slots[n-1]
+	rotating := slots[:n-1]
+
+	var pairings []Pairing
+	add := func(round int, home, away uuid.UUID) {
+		if home != uuid.Nil && away != uuid.Nil {
+			pairings = append(pairings, Pairing{Round: round, Home: home, Away: away})
+		}
+	}
+	for r := 0; r < n-1; r++ {
+		round := r + 1
+
+		opponent := rotating[r]
+		if r%2 == 0 {
+			add(round, opponent, fixed)
+		} else {
+			add(round, fixed, opponent)
+		}
+
+		for k := 1; k < n/2; k++ {
+			a := rotating[(r+k)%(n-1)]
+			b := rotating[(r-k+n-1)%(n-1)]
+			if k%2 == 1 {
+				add(round, b, a)
+			} else {
+				add(round, a, b)
+			}
+		}
+	}
+
+	if double {
+		rounds := n - 1
+		for _, p := range pairings {
+			pairings = append(pairings, Pairing{Round: p.Round + rounds, Home: p.Away, Away: p.Home})
+		}
+	}
+	return pairings
+}
@@ -0,0 +1,199 @@
+package schedule
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTooFewTeams = errors.New("at least two teams are needed")
+	ErrNoSlots     = errors.New("no venue has a kickoff slot")
+	ErrDoesNotFit  = errors.New("fixtures don't fit before the end of the season")
+)
+
+// DefaultMaxWeeks bounds a schedule without an end date
+const DefaultMaxWeeks = 104
+
+// Slot is a weekly kickoff time
+type Slot struct {
+	Weekday time.Weekday
+	Kickoff time.Duration // Time of day
+}
+
+// Venue is somewhere matches can be played and when it's available
+type Venue struct {
+	Name        string
+	Slots       []Slot
+	Pitches     int         // Matches it can host at once, 1 when unset
+	Unavailable []time.Time // Dates it can't be used
+}
+
+// Fixture is a pairing with a kickoff and venue
+type Fixture struct {
+	Pairing
+	Kickoff time.Time
+	Venue   string
+}
+
+type Options struct {
+	Teams     []uuid.UUID
+	Double    bool        // Everyone plays everyone home and away
+	Start     time.Time   // Nothing kicks off before this. Slots are in its location.
+	End       time.Time   // Optional, nothing kicks off after this
+	MaxWeeks  int         // Used when End is unset, DefaultMaxWeeks when zero
+	Venues    []Venue     // At least one, with slots
+	Blackouts []time.Time // Dates nobody plays
+	Played    []Fixture   // Already played, so not scheduled again
+}
+
+// Generate builds a round-robin and gives each match a slot. Rounds are
+// played in order, a week at a time, earliest slot first. No team plays
+// twice in a week (Monday to Sunday), counting matches already played, and
+// a round that doesn't fit in a week runs into the next.
+func Generate(opts Options) ([]Fixture, error) {
+	if len(opts.Teams) < 2 {
+		return nil, ErrTooFewTeams
+	}
+	hasSlots := false
+	for _, venue := range opts.Venues {
+		hasSlots = hasSlots || len(venue.Slots) > 0
+	}
+	if !hasSlots {
+		return nil, ErrNoSlots
+	}
+
+	loc := opts.Start.Location()
+	pending := unplayed(RoundRobin(opts.Teams, opts.Double), opts.Played)
+
+	busy := make(map[string]map[uuid.UUID]bool)
+	for _, played := range opts.Played {
+		week := weekOf(played.Kickoff.In(loc)).Format(time.DateOnly)
+		if busy[week] == nil {
+			busy[week] = make(map[uuid.UUID]bool)
+		}
+		busy[week][played.Home] = true
+		busy[week][played.Away] = true
+	}
+
+	blackouts := make(map[string]bool, len(opts.Blackouts))
+	for _, date := range opts.Blackouts {
+		blackouts[date.Format(time.DateOnly)] = true
+	}
+
+	maxWeeks := opts.MaxWeeks
+	if maxWeeks <= 0 {
+		maxWeeks = DefaultMaxWeeks
+	}
+
+	fixtures := make([]Fixture, 0, len(pending))
+	monday := weekOf(opts.Start.In(loc))
+	for week := 0; len(pending) > 0; week++ {
+		weekStart := monday.AddDate(0, 0, 7*week)
+		if opts.End.IsZero() && week >= maxWeeks || !opts.End.IsZero() && weekStart.After(opts.End) {
+			return nil, ErrDoesNotFit
+		}
+
+		playing := make(map[uuid.UUID]bool)
+		for team := range busy[weekStart.Format(time.DateOnly)] {
+			playing[team] = true
+		}
+
+		slots := weekSlots(opts, weekStart, blackouts)
+		remaining := pending[:0]
+		for _, pairing := range pending {
+			if playing[pairing.Home] || playing[pairing.Away] {
+				remaining = append(remaining, pairing)
+				continue
+			}
+			slot := firstFree(slots)
+			if slot == nil {
+				remaining = append(remaining, pairing)
+				continue
+			}
+			slot.free--
+			playing[pairing.Home] = true
+			playing[pairing.Away] = true
+			fixtures = append(fixtures, Fixture{Pairing: pairing, Kickoff: slot.kickoff, Venue: slot.venue})
+		}
+		pending = remaining
+	}
+
+	return fixtures, nil
+}
+
+// unplayed drops a pairing for each played match between the same teams,
+// preferring the one with the same home team
+func unplayed(pairings []Pairing, played []Fixture) []Pairing {
+	remaining := append([]Pairing(nil), pairings...)
+	for _, match := range played {
+		i := indexOf(remaining, match.Home, match.Away)
+		if i < 0 {
+			i = indexOf(remaining, match.Away, match.Home)
+		}
+		if i >= 0 {
+			remaining = append(remaining[:i], remaining[i+1:]...)
+		}
+	}
+	return remaining
+}
+
+func indexOf(pairings []Pairing, home, away uuid.UUID) int {
+	for i, p := range pairings {
+		if p.Home == home && p.Away == away {
+			return i
+		}
+	}
+	return -1
+}
+
+type weekSlot struct {
+	kickoff time.Time
+	venue   string
+	free    int
+}
+
+// weekSlots lists the week's usable kickoffs, earliest first
+func weekSlots(opts Options, weekStart time.Time, blackouts map[string]bool) []*weekSlot {
+	var slots []*weekSlot
+	for _, venue := range opts.Venues {
+		unavailable := make(map[string]bool, len(venue.Unavailable))
+		for _, date := range venue.Unavailable {
+			unavailable[date.Format(time.DateOnly)] = true
+		}
+		pitches := max(venue.Pitches, 1)
+
+		for _, slot := range venue.Slots {
+			day := weekStart.AddDate(0, 0, (int(slot.Weekday)+6)%7)
+			date := day.Format(time.DateOnly)
+			if blackouts[date] || unavailable[date] {
+				continue
+			}
+			hour, minute := int(slot.Kickoff/time.Hour), int(slot.Kickoff%time.Hour/time.Minute)
+			kickoff := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+			if kickoff.Before(opts.Start) || !opts.End.IsZero() && kickoff.After(opts.End) {
+				continue
+			}
+			slots = append(slots, &weekSlot{kickoff: kickoff, venue: venue.Name, free: pitches})
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].kickoff.Before(slots[j].kickoff) })
+	return slots
+}
+
+func firstFree(slots []*weekSlot) *weekSlot {
+	for _, slot := range slots {
+		if slot.free > 0 {
+			return slot
+		}
+	}
+	return nil
+}
+
+// weekOf returns midnight on the Monday of t's week, in t's location
+func weekOf(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
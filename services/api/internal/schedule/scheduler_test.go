@@ -0,0 +1,211 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func teams(n int) []uuid.UUID {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	return ids
+}
+
+func TestRoundRobin(t *testing.T) {
+	for n := 2; n <= 12; n++ {
+		for _, double := range []bool{false, true} {
+			ids := teams(n)
+			pairings := RoundRobin(ids, double)
+
+			legs := 1
+			if double {
+				legs = 2
+			}
+			if want := legs * n * (n - 1) / 2; len(pairings) != want {
+				t.Fatalf("%d teams, double=%v: got %d pairings, want %d", n, double, len(pairings), want)
+			}
+
+			met := make(map[[2]uuid.UUID]int)
+			home := make(map[uuid.UUID]int)
+			away := make(map[uuid.UUID]int)
+			rounds := make(map[int]map[uuid.UUID]bool)
+			for _, p := range pairings {
+				if p.Home == p.Away {
+					t.Fatalf("%d teams: a team plays itself", n)
+				}
+				met[[2]uuid.UUID{p.Home, p.Away}]++
+				home[p.Home]++
+				away[p.Away]++
+				if rounds[p.Round] == nil {
+					rounds[p.Round] = make(map[uuid.UUID]bool)
+				}
+				if rounds[p.Round][p.Home] || rounds[p.Round][p.Away] {
+					t.Fatalf("%d teams: a team plays twice in round %d", n, p.Round)
+				}
+				rounds[p.Round][p.Home] = true
+				rounds[p.Round][p.Away] = true
+			}
+
+			for i, a := range ids {
+				for _, b := range ids[i+1:] {
+					if got := met[[2]uuid.UUID{a, b}] + met[[2]uuid.UUID{b, a}]; got != legs {
+						t.Fatalf("%d teams, double=%v: pair met %d times, want %d", n, double, got, legs)
+					}
+					if double && (met[[2]uuid.UUID{a, b}] != 1 || met[[2]uuid.UUID{b, a}] != 1) {
+						t.Fatalf("%d teams: pair doesn't meet once at each ground", n)
+					}
+				}
+				diff := home[a] - away[a]
+				if diff < -1 || diff > 1 || double && diff != 0 {
+					t.Fatalf("%d teams, double=%v: %d home and %d away games", n, double, home[a], away[a])
+				}
+			}
+		}
+	}
+}
+
+func TestRoundRobinTooFewTeams(t *testing.T) {
+	if pairings := RoundRobin(teams(1), true); pairings != nil {
+		t.Errorf("Expected no pairings for one team, got %v", pairings)
+	}
+}
+
+var saturdays = Venue{Name: "Park", Slots: []Slot{{Weekday: time.Saturday, Kickoff: 10 * time.Hour}}, Pitches: 2}
+
+func TestGenerate(t *testing.T) {
+	ids := teams(4)
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC) // Monday
+
+	fixtures, err := Generate(Options{Teams: ids, Start: start, Venues: []Venue{saturdays}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(fixtures) != 6 {
+		t.Fatalf("Expected 6 fixtures, got %d", len(fixtures))
+	}
+	for i, f := range fixtures {
+		want := time.Date(2025, 9, 6+7*(f.Round-1), 10, 0, 0, 0, time.UTC)
+		if !f.Kickoff.Equal(want) {
+			t.Errorf("Fixture %d in round %d kicks off %v, want %v", i, f.Round, f.Kickoff, want)
+		}
+		if f.Venue != "Park" {
+			t.Errorf("Fixture %d at %q, want Park", i, f.Venue)
+		}
+	}
+}
+
+func TestGenerateSkipsBlackoutsAndUnavailableVenues(t *testing.T) {
+	ids := teams(2)
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	venue := saturdays
+	venue.Unavailable = []time.Time{time.Date(2025, 9, 13, 0, 0, 0, 0, time.UTC)}
+
+	fixtures, err := Generate(Options{
+		Teams:     ids,
+		Double:    true,
+		Start:     start,
+		Venues:    []Venue{venue},
+		Blackouts: []time.Time{time.Date(2025, 9, 6, 0, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(fixtures) != 2 {
+		t.Fatalf("Expected 2 fixtures, got %d", len(fixtures))
+	}
+	if want := time.Date(2025, 9, 20, 10, 0, 0, 0, time.UTC); !fixtures[0].Kickoff.Equal(want) {
+		t.Errorf("First fixture kicks off %v, want %v", fixtures[0].Kickoff, want)
+	}
+	if want := time.Date(2025, 9, 27, 10, 0, 0, 0, time.UTC); !fixtures[1].Kickoff.Equal(want) {
+		t.Errorf("Second fixture kicks off %v, want %v", fixtures[1].Kickoff, want)
+	}
+}
+
+func TestGenerateOneMatchPerWeek(t *testing.T) {
+	ids := teams(6)
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	// Two kickoffs a week on one pitch can't fit a round of three matches
+	venue := Venue{Name: "Rec", Slots: []Slot{
+		{Weekday: time.Saturday, Kickoff: 10 * time.Hour},
+		{Weekday: time.Sunday, Kickoff: 14 * time.Hour},
+	}}
+
+	fixtures, err := Generate(Options{Teams: ids, Start: start, Venues: []Venue{venue}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(fixtures) != 15 {
+		t.Fatalf("Expected 15 fixtures, got %d", len(fixtures))
+	}
+
+	weeks := make(map[time.Time]map[uuid.UUID]bool)
+	slots := make(map[time.Time]int)
+	for _, f := range fixtures {
+		week := weekOf(f.Kickoff)
+		if weeks[week] == nil {
+			weeks[week] = make(map[uuid.UUID]bool)
+		}
+		if weeks[week][f.Home] || weeks[week][f.Away] {
+			t.Fatalf("A team plays twice in the week of %v", week)
+		}
+		weeks[week][f.Home] = true
+		weeks[week][f.Away] = true
+		slots[f.Kickoff]++
+		if slots[f.Kickoff] > 1 {
+			t.Fatalf("Two matches on one pitch at %v", f.Kickoff)
+		}
+	}
+}
+
+func TestGenerateKeepsPlayedMatches(t *testing.T) {
+	ids := teams(4)
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	played := Fixture{
+		Pairing: Pairing{Home: ids[1], Away: ids[0]},
+		Kickoff: time.Date(2025, 9, 3, 19, 0, 0, 0, time.UTC), // Wednesday of the first week
+	}
+
+	fixtures, err := Generate(Options{Teams: ids, Start: start, Venues: []Venue{saturdays}, Played: []Fixture{played}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(fixtures) != 5 {
+		t.Fatalf("Expected 5 fixtures, got %d", len(fixtures))
+	}
+	firstWeek := weekOf(start)
+	for _, f := range fixtures {
+		if f.Home == ids[0] && f.Away == ids[1] || f.Home == ids[1] && f.Away == ids[0] {
+			t.Error("Played pairing was scheduled again")
+		}
+		if weekOf(f.Kickoff).Equal(firstWeek) && (f.Home == ids[0] || f.Away == ids[0] || f.Home == ids[1] || f.Away == ids[1]) {
+			t.Error("A team that already played this week was scheduled again")
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{"one team", Options{Teams: teams(1), Start: start, Venues: []Venue{saturdays}}, ErrTooFewTeams},
+		{"no slots", Options{Teams: teams(4), Start: start, Venues: []Venue{{Name: "Park"}}}, ErrNoSlots},
+		{"past the end", Options{Teams: teams(4), Start: start, End: start.AddDate(0, 0, 14), Venues: []Venue{saturdays}}, ErrDoesNotFit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("Generate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package sentiment
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signal is a single piece of fan reaction to score
+type Signal struct {
+	Text string
+	// Weight scales the signal's contribution. A negative weight inverts the
+	// polarity, e.g. a downvote on a card praising the team.
+	Weight    float64
+	CreatedAt time.Time
+}
+
+// Team identifies whose fans to listen to. MatchIDs are the team's fixtures;
+// sources that only see our own debates use them to decide what is about the team.
+type Team struct {
+	Name     string
+	MatchIDs []string
+}
+
+// Source provides signals about a team. Our own comments and votes are
+// sources; external feeds (Twitter, Reddit, ...) plug in the same way.
+type Source interface {
+	Name() string
+	Signals(ctx context.Context, team Team, since time.Time) ([]Signal, error)
+}
+
+// TeamSentiment is the aggregated view of fan sentiment for a team
+type TeamSentiment struct {
+	Team       string
+	Score      float64            // -1 to 1
+	SampleSize int                // Number of signals scored
+	BySource   map[string]float64 // Per-source score, -1 to 1
+	Topics     []string
+	// Controversial holds the most negative texts that mention refereeing or VAR incidents
+	Controversial []string
+}
+
+// Analyzer combines sources and a scorer into team sentiment
+type Analyzer struct {
+	scorer  Scorer
+	sources []Source
+}
+
+// NewAnalyzer creates an analyzer. A nil scorer falls back to the lexicon scorer.
+func NewAnalyzer(scorer Scorer, sources ...Source) *Analyzer {
+	if scorer == nil {
+		scorer = NewLexiconScorer()
+	}
+	return &Analyzer{scorer: scorer, sources: sources}
+}
+
+// maxControversialMoments caps how many incidents are reported per team
+const maxControversialMoments = 3
+
+// maxMomentLength truncates quoted fan comments
+const maxMomentLength = 160
+
+// Analyze scores every signal from every source for the team since the given time.
+// A failing source is skipped so that one broken integration doesn't hide the rest.
+func (a *Analyzer) Analyze(ctx context.Context, team Team, since time.Time, topicLimit int) (TeamSentiment, error) {
+	result := TeamSentiment{Team: team.Name, BySource: make(map[string]float64)}
+
+	var (
+		weighted    float64
+		totalWeight float64
+		texts       []string
+		seenTexts   = make(map[string]bool)
+		moments     []scoredText
+		failures    []string
+	)
+
+	for _, source := range a.sources {
+		signals, err := source.Signals(ctx, team, since)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", source.Name(), err))
+			continue
+		}
+		if len(signals) == 0 {
+			continue
+		}
+
+		var sourceWeighted, sourceWeight float64
+		for _, signal := range signals {
+			weight := signal.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			score := a.scorer.Score(signal.Text) * weight
+			sourceWeighted += score
+			sourceWeight += math.Abs(weight)
+
+			// Votes repeat their card's text, so only count each text once for topics
+			if !seenTexts[signal.Text] {
+				seenTexts[signal.Text] = true
+				texts = append(texts, signal.Text)
+			}
+			if isControversial(signal.Text) {
+				moments = append(moments, scoredText{text: signal.Text, score: score})
+			}
+		}
+
+		result.BySource[source.Name()] = round(sourceWeighted / sourceWeight)
+		result.SampleSize += len(signals)
+		weighted += sourceWeighted
+		totalWeight += sourceWeight
+	}
+
+	if len(failures) > 0 && result.SampleSize == 0 {
+		return result, fmt.Errorf("all sentiment sources failed: %s", strings.Join(failures, "; "))
+	}
+
+	if totalWeight > 0 {
+		result.Score = round(weighted / totalWeight)
+	}
+	result.Topics = ExtractTopics(texts, topicLimit, team.Name)
+	result.Controversial = topMoments(moments)
+
+	return result, nil
+}
+
+type scoredText struct {
+	text  string
+	score float64
+}
+
+var controversyTerms = []string{"var", "referee", "ref", "penalty", "offside", "red card", "handball", "dive", "robbed"}
+
+func isControversial(text string) bool {
+	tokens := " " + strings.Join(Tokenize(text), " ") + " "
+	for _, term := range controversyTerms {
+		if strings.Contains(tokens, " "+term+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func topMoments(moments []scoredText) []string {
+	sort.SliceStable(moments, func(i, j int) bool {
+		return moments[i].score < moments[j].score
+	})
+	var result []string
+	seen := make(map[string]bool)
+	for _, moment := range moments {
+		text := strings.TrimSpace(moment.text)
+		if runes := []rune(text); len(runes) > maxMomentLength {
+			text = string(runes[:maxMomentLength]) + "..."
+		}
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		result = append(result, text)
+		if len(result) == maxControversialMoments {
+			break
+		}
+	}
+	return result
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
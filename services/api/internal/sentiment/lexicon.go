@@ -0,0 +1,163 @@
+package sentiment
+
+// wordLexicon maps words to a valence between -4 and 4. It mixes general
+// sentiment words with football-specific vocabulary.
+var wordLexicon = map[string]float64{
+	// Positive
+	"amazing":     3.0,
+	"awesome":     3.0,
+	"beautiful":   2.5,
+	"best":        2.5,
+	"brilliant":   3.0,
+	"class":       2.0,
+	"classy":      2.0,
+	"clinical":    2.0,
+	"comeback":    2.0,
+	"confident":   1.5,
+	"dominant":    2.0,
+	"dominated":   1.5,
+	"excellent":   3.0,
+	"fantastic":   3.0,
+	"good":        1.5,
+	"great":       2.5,
+	"happy":       2.0,
+	"hero":        2.5,
+	"legend":      3.0,
+	"love":        3.0,
+	"loved":       2.5,
+	"magic":       2.5,
+	"masterclass": 3.5,
+	"outstanding": 3.0,
+	"perfect":     3.0,
+	"proud":       2.5,
+	"quality":     1.5,
+	"solid":       1.5,
+	"strong":      1.5,
+	"superb":      3.0,
+	"unbeatable":  3.0,
+	"unstoppable": 3.0,
+	"win":         2.0,
+	"winning":     2.0,
+	"wins":        2.0,
+	"won":         2.0,
+	"worldie":     3.0,
+	"goat":        3.5,
+	"champions":   2.5,
+	"deserved":    1.5,
+	"excited":     2.0,
+	"hope":        1.0,
+	"believe":     1.5,
+
+	// Negative
+	"awful":        -3.0,
+	"bad":          -2.0,
+	"bottle":       -2.0,
+	"bottled":      -2.5,
+	"clueless":     -2.5,
+	"collapse":     -2.5,
+	"disaster":     -3.0,
+	"disgrace":     -3.5,
+	"disgraceful":  -3.5,
+	"dive":         -1.5,
+	"embarrassing": -3.0,
+	"fraud":        -3.0,
+	"hate":         -3.0,
+	"hopeless":     -3.0,
+	"horrible":     -3.0,
+	"lazy":         -2.5,
+	"lose":         -2.0,
+	"losing":       -2.0,
+	"lost":         -2.0,
+	"mess":         -2.5,
+	"overrated":    -2.5,
+	"pathetic":     -3.5,
+	"poor":         -2.0,
+	"relegation":   -2.0,
+	"robbed":       -2.5,
+	"rubbish":      -3.0,
+	"sack":         -2.5,
+	"sacked":       -2.0,
+	"shambles":     -3.5,
+	"shocking":     -2.5,
+	"sloppy":       -2.0,
+	"terrible":     -3.0,
+	"trash":        -3.0,
+	"useless":      -3.0,
+	"weak":         -2.0,
+	"worst":        -3.5,
+	"angry":        -2.0,
+	"boring":       -2.0,
+	"cheat":        -3.0,
+	"cheated":      -3.0,
+	"corrupt":      -3.0,
+	"worried":      -1.5,
+	"injury":       -1.0,
+	"injured":      -1.0,
+}
+
+// emojiLexicon covers the reactions fans use on debate cards
+var emojiLexicon = map[string]float64{
+	"🔥": 2.5,
+	"⚽": 1.0,
+	"🏆": 3.0,
+	"👏": 2.0,
+	"💪": 2.0,
+	"😍": 3.0,
+	"❤": 3.0,
+	"😂": 1.0,
+	"🤣": 1.0,
+	"🙌": 2.5,
+	"🐐": 3.5,
+	"👍": 2.0,
+	"🎉": 2.5,
+	"😎": 1.5,
+	"😡": -3.0,
+	"🤬": -3.5,
+	"😢": -2.0,
+	"😭": -2.5,
+	"💩": -3.0,
+	"🤡": -3.0,
+	"👎": -2.0,
+	"🤦": -2.0,
+	"😤": -1.5,
+	"😴": -2.0,
+	"🤮": -3.0,
+	"💀": -1.0,
+}
+
+var negators = map[string]bool{
+	"not":     true,
+	"no":      true,
+	"never":   true,
+	"nothing": true,
+	"isn't":   true,
+	"isn’t":   true,
+	"wasn't":  true,
+	"wasn’t":  true,
+	"aren't":  true,
+	"aren’t":  true,
+	"don't":   true,
+	"don’t":   true,
+	"didn't":  true,
+	"didn’t":  true,
+	"can't":   true,
+	"can’t":   true,
+	"won't":   true,
+	"won’t":   true,
+	"hardly":  true,
+}
+
+var intensifiers = map[string]float64{
+	"very":       1.3,
+	"so":         1.3,
+	"really":     1.3,
+	"absolutely": 1.5,
+	"totally":    1.4,
+	"completely": 1.4,
+	"utterly":    1.5,
+	"extremely":  1.5,
+	"proper":     1.2,
+	"slightly":   0.6,
+	"somewhat":   0.7,
+	"bit":        0.7,
+}
@@ -0,0 +1,107 @@
+package sentiment
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Scorer scores a piece of text from -1 (negative) to 1 (positive).
+// The lexicon scorer is the default; a hosted model can be plugged in by
+// implementing this interface.
+type Scorer interface {
+	Score(text string) float64
+}
+
+// normalizationAlpha controls how quickly raw lexicon sums approach +/-1
+const normalizationAlpha = 15.0
+
+// negationWindow is how many tokens after a negator have their polarity flipped
+const negationWindow = 3
+
+// LexiconScorer is a rule-based scorer tuned for football fan language
+type LexiconScorer struct {
+	words        map[string]float64
+	emojis       map[string]float64
+	negators     map[string]bool
+	intensifiers map[string]float64
+}
+
+// NewLexiconScorer creates a scorer using the built-in football lexicon
+func NewLexiconScorer() *LexiconScorer {
+	return &LexiconScorer{
+		words:        wordLexicon,
+		emojis:       emojiLexicon,
+		negators:     negators,
+		intensifiers: intensifiers,
+	}
+}
+
+// Score sums the valence of known words and emojis, applying negation and
+// intensifiers, and squashes the result into [-1, 1]
+func (s *LexiconScorer) Score(text string) float64 {
+	var total float64
+	for _, r := range text {
+		if v, ok := s.emojis[string(r)]; ok {
+			total += v
+		}
+	}
+
+	tokens := Tokenize(text)
+	negateUntil := -1
+	boost := 1.0
+	for i, token := range tokens {
+		if s.negators[token] {
+			negateUntil = i + negationWindow
+			continue
+		}
+		if m, ok := s.intensifiers[token]; ok {
+			boost *= m
+			continue
+		}
+
+		v, ok := s.words[token]
+		if !ok {
+			boost = 1.0
+			continue
+		}
+		v *= boost
+		if i <= negateUntil {
+			// Negation dampens as well as flips: "not bad" is mildly positive
+			v *= -0.75
+		}
+		total += v
+		boost = 1.0
+	}
+
+	// Shouting adds emphasis in the direction of the sentiment
+	if total != 0 && isShouting(text) {
+		total *= 1.25
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return total / math.Sqrt(total*total+normalizationAlpha)
+}
+
+// Tokenize lowercases text and splits it into word tokens, keeping apostrophes
+// so contractions like "isn't" survive
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\'' && r != '’'
+	})
+}
+
+func isShouting(text string) bool {
+	var letters, upper int
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 8 && float64(upper)/float64(letters) > 0.7
+}
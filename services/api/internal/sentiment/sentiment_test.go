@@ -0,0 +1,152 @@
+package sentiment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLexiconScorer(t *testing.T) {
+	scorer := NewLexiconScorer()
+
+	tests := []struct {
+		name string
+		text string
+		want func(float64) bool
+	}{
+		{"positive", "What a brilliant performance, absolutely class", func(s float64) bool { return s > 0.5 }},
+		{"negative", "Useless defending, a total shambles", func(s float64) bool { return s < -0.5 }},
+		{"neutral", "Kick off is at three", func(s float64) bool { return s == 0 }},
+		{"negated positive", "That was not good at all", func(s float64) bool { return s < 0 }},
+		{"negated negative", "Honestly not bad", func(s float64) bool { return s > 0 }},
+		{"emoji", "🔥🔥🐐", func(s float64) bool { return s > 0.5 }},
+		{"negative emoji", "🤡", func(s float64) bool { return s < 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := scorer.Score(tt.text)
+			if !tt.want(score) {
+				t.Errorf("Score(%q) = %.2f", tt.text, score)
+			}
+			if score < -1 || score > 1 {
+				t.Errorf("Score(%q) = %.2f is out of range", tt.text, score)
+			}
+		})
+	}
+
+	t.Run("intensifiers and shouting amplify", func(t *testing.T) {
+		plain := scorer.Score("great win")
+		if scorer.Score("really great win") <= plain {
+			t.Error("Expected intensifier to increase the score")
+		}
+		if scorer.Score("GREAT WIN TODAY") <= scorer.Score("great win today") {
+			t.Error("Expected shouting to increase the score")
+		}
+	})
+}
+
+func TestExtractTopics(t *testing.T) {
+	texts := []string{
+		"The high press was brilliant again",
+		"Their high press is suffocating",
+		"Saka on the wing, high press everywhere",
+		"Saka is the best winger in the league",
+		"Arsenal looked sharp",
+		"Arsenal will win this",
+	}
+
+	topics := ExtractTopics(texts, 3, "Arsenal")
+	if len(topics) == 0 || topics[0] != "high press" {
+		t.Fatalf("Expected 'high press' to be the top topic, got %v", topics)
+	}
+	for _, topic := range topics {
+		if topic == "high" || topic == "press" {
+			t.Errorf("Expected words covered by a phrase to be dropped, got %v", topics)
+		}
+		if strings.Contains(topic, "arsenal") {
+			t.Errorf("Expected excluded team name to be ignored, got %v", topics)
+		}
+	}
+	if !contains(topics, "saka") {
+		t.Errorf("Expected 'saka' in topics, got %v", topics)
+	}
+
+	if got := ExtractTopics(texts, 0); got != nil {
+		t.Errorf("Expected no topics for zero limit, got %v", got)
+	}
+}
+
+type staticSource struct {
+	name    string
+	signals []Signal
+	err     error
+}
+
+func (s *staticSource) Name() string { return s.name }
+
+func (s *staticSource) Signals(ctx context.Context, team Team, since time.Time) ([]Signal, error) {
+	return s.signals, s.err
+}
+
+type fixedScorer map[string]float64
+
+func (f fixedScorer) Score(text string) float64 { return f[text] }
+
+func TestAnalyzer(t *testing.T) {
+	scorer := fixedScorer{
+		"great card":                   0.8,
+		"🔥":                            0.6,
+		"The referee robbed us, VAR!!": -0.9,
+	}
+	comments := &staticSource{name: "comments", signals: []Signal{
+		{Text: "The referee robbed us, VAR!!", Weight: 1},
+	}}
+	votes := &staticSource{name: "votes", signals: []Signal{
+		{Text: "great card", Weight: 1},
+		{Text: "great card", Weight: -1},
+		{Text: "🔥", Weight: 1},
+	}}
+	broken := &staticSource{name: "twitter", err: errors.New("rate limited")}
+
+	analyzer := NewAnalyzer(scorer, comments, votes, broken)
+	result, err := analyzer.Analyze(context.Background(), Team{Name: "Arsenal"}, time.Now().Add(-time.Hour), 5)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	if result.SampleSize != 4 {
+		t.Errorf("Expected 4 signals, got %d", result.SampleSize)
+	}
+	// (-0.9 + 0.8 - 0.8 + 0.6) / 4
+	if result.Score != -0.08 {
+		t.Errorf("Expected score -0.08, got %.2f", result.Score)
+	}
+	if result.BySource["comments"] != -0.9 || result.BySource["votes"] != 0.2 {
+		t.Errorf("Unexpected per-source scores: %v", result.BySource)
+	}
+	if _, ok := result.BySource["twitter"]; ok {
+		t.Error("Expected failing source to be skipped")
+	}
+	if len(result.Controversial) != 1 || result.Controversial[0] != "The referee robbed us, VAR!!" {
+		t.Errorf("Expected referee comment as controversial moment, got %v", result.Controversial)
+	}
+
+	t.Run("all sources failing is an error", func(t *testing.T) {
+		_, err := NewAnalyzer(nil, broken).Analyze(context.Background(), Team{Name: "Arsenal"}, time.Now(), 5)
+		if err == nil {
+			t.Error("Expected error when every source fails")
+		}
+	})
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package sentiment
+
+import (
+	"sort"
+	"strings"
+)
+
+// ExtractTopics returns up to limit trending keywords and phrases across the
+// given texts. Terms are ranked by how many texts mention them, so one
+// long rant can't dominate. Bigrams are preferred over their component words
+// when they are about as common. Words in exclude (e.g. team names) are ignored.
+func ExtractTopics(texts []string, limit int, exclude ...string) []string {
+	if limit <= 0 {
+		return nil
+	}
+
+	excluded := make(map[string]bool)
+	for _, phrase := range exclude {
+		for _, token := range Tokenize(phrase) {
+			excluded[token] = true
+		}
+	}
+
+	unigrams := make(map[string]int)
+	bigrams := make(map[string]int)
+	for _, text := range texts {
+		seen := make(map[string]bool)
+		var prev string
+		for _, token := range Tokenize(text) {
+			if !isKeyword(token) || excluded[token] {
+				prev = ""
+				continue
+			}
+			if !seen[token] {
+				seen[token] = true
+				unigrams[token]++
+			}
+			if prev != "" {
+				bigram := prev + " " + token
+				if !seen[bigram] {
+					seen[bigram] = true
+					bigrams[bigram]++
+				}
+			}
+			prev = token
+		}
+	}
+
+	type term struct {
+		text  string
+		count int
+	}
+	var terms []term
+	covered := make(map[string]bool)
+	for bigram, count := range bigrams {
+		if count < 2 {
+			continue
+		}
+		terms = append(terms, term{bigram, count})
+		for _, word := range strings.Fields(bigram) {
+			// Drop a word when the phrase accounts for most of its mentions
+			if float64(count) >= 0.6*float64(unigrams[word]) {
+				covered[word] = true
+			}
+		}
+	}
+	for word, count := range unigrams {
+		if count < 2 || covered[word] {
+			continue
+		}
+		terms = append(terms, term{word, count})
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].count != terms[j].count {
+			return terms[i].count > terms[j].count
+		}
+		// Phrases are more descriptive than single words
+		iWords, jWords := strings.Count(terms[i].text, " "), strings.Count(terms[j].text, " ")
+		if iWords != jWords {
+			return iWords > jWords
+		}
+		return terms[i].text < terms[j].text
+	})
+
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+	topics := make([]string, 0, len(terms))
+	for _, t := range terms {
+		topics = append(topics, t.text)
+	}
+	return topics
+}
+
+func isKeyword(token string) bool {
+	if len([]rune(token)) < 3 || stopwords[token] {
+		return false
+	}
+	// Pure numbers (scores, minutes) are noise on their own
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return true
+		}
+	}
+	return false
+}
+
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "had": true, "her": true,
+	"was": true, "one": true, "our": true, "out": true, "has": true, "have": true,
+	"his": true, "how": true, "its": true, "may": true, "new": true, "now": true,
+	"old": true, "see": true, "two": true, "way": true, "who": true, "did": true,
+	"get": true, "got": true, "him": true, "let": true, "put": true, "say": true,
+	"she": true, "too": true, "use": true, "that": true, "with": true, "this": true,
+	"they": true, "them": true, "then": true, "than": true, "what": true, "when": true,
+	"will": true, "would": true, "could": true, "should": true, "just": true,
+	"from": true, "been": true, "were": true, "their": true, "there": true,
+	"about": true, "into": true, "more": true, "some": true, "very": true,
+	"really": true, "much": true, "also": true, "only": true, "even": true,
+	"still": true, "like": true, "think": true, "know": true, "going": true,
+	"because": true, "which": true, "your": true, "over": true, "after": true,
+	"before": true, "being": true, "does": true, "doing": true, "here": true,
+	"where": true, "why": true, "these": true, "those": true, "every": true,
+	"isn't": true, "don't": true, "didn't": true, "can't": true, "won't": true,
+	"it's": true, "i'm": true, "that's": true, "team": true, "match": true,
+	"game": true, "today": true, "tonight": true, "yes": true, "lol": true,
+}
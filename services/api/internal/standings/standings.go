@@ -0,0 +1,235 @@
+// Package standings builds league tables from match results.
+package standings
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tiebreakers separate teams level on points, in the order a league lists them
+const (
+	TiebreakHeadToHead     = "head_to_head"    // Points, then goal difference, in matches between the tied teams
+	TiebreakGoalDifference = "goal_difference" // Overall goal difference
+	TiebreakGoalsFor       = "goals_for"       // Overall goals scored
+)
+
+// formLength is how many recent results make up a team's form
+const formLength = 5
+
+// Rules are the points per result and the tiebreakers used after points
+type Rules struct {
+	Win         int32
+	Draw        int32
+	Loss        int32
+	Tiebreakers []string
+}
+
+// DefaultRules are three points for a win and one for a draw, separated by
+// goal difference, goals scored, then head-to-head
+var DefaultRules = Rules{
+	Win:         3,
+	Draw:        1,
+	Loss:        0,
+	Tiebreakers: []string{TiebreakGoalDifference, TiebreakGoalsFor, TiebreakHeadToHead},
+}
+
+// Validate checks a win is worth more than a draw, which is worth at least a
+// loss, and that tiebreakers are known and listed once
+func (r Rules) Validate() error {
+	if r.Loss < 0 || r.Draw < r.Loss || r.Win <= r.Draw {
+		return errors.New("points must be win > draw >= loss >= 0")
+	}
+	seen := make(map[string]bool, len(r.Tiebreakers))
+	for _, tiebreaker := range r.Tiebreakers {
+		switch tiebreaker {
+		case TiebreakHeadToHead, TiebreakGoalDifference, TiebreakGoalsFor:
+		default:
+			return fmt.Errorf("unknown tiebreaker %q", tiebreaker)
+		}
+		if seen[tiebreaker] {
+			return fmt.Errorf("tiebreaker %q is listed twice", tiebreaker)
+		}
+		seen[tiebreaker] = true
+	}
+	return nil
+}
+
+// Result is a played match
+type Result struct {
+	Home      uuid.UUID
+	Away      uuid.UUID
+	HomeGoals int32
+	AwayGoals int32
+	Played    time.Time
+}
+
+// Record is a team's results, overall or at home or away
+type Record struct {
+	Played       int
+	Won          int
+	Drawn        int
+	Lost         int
+	GoalsFor     int
+	GoalsAgainst int
+}
+
+func (r Record) GoalDifference() int {
+	return r.GoalsFor - r.GoalsAgainst
+}
+
+func (r *Record) add(scored, conceded int32) {
+	r.Played++
+	r.GoalsFor += int(scored)
+	r.GoalsAgainst += int(conceded)
+	switch {
+	case scored > conceded:
+		r.Won++
+	case scored < conceded:
+		r.Lost++
+	default:
+		r.Drawn++
+	}
+}
+
+func (r Record) points(rules Rules) int {
+	return r.Won*int(rules.Win) + r.Drawn*int(rules.Draw) + r.Lost*int(rules.Loss)
+}
+
+// Row is a team's line in the table
+type Row struct {
+	Rank   int
+	Team   uuid.UUID
+	Points int
+	All    Record
+	Home   Record
+	Away   Record
+	Form   string // Last five results, oldest first, e.g. "WWDLW"
+}
+
+// Compute builds the table for teams from results. Teams are ranked on
+// points, then the rules' tiebreakers, then their order in teams. Results
+// involving other teams are ignored.
+func Compute(teams []uuid.UUID, results []Result, rules Rules) []Row {
+	rows := make(map[uuid.UUID]*Row, len(teams))
+	order := make([]*Row, 0, len(teams))
+	for _, team := range teams {
+		if rows[team] == nil {
+			rows[team] = &Row{Team: team}
+			order = append(order, rows[team])
+		}
+	}
+
+	sorted := append([]Result(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Played.Before(sorted[j].Played) })
+
+	var counted []Result
+	for _, result := range sorted {
+		home, away := rows[result.Home], rows[result.Away]
+		if home == nil || away == nil || home == away {
+			continue
+		}
+		counted = append(counted, result)
+
+		home.All.add(result.HomeGoals, result.AwayGoals)
+		home.Home.add(result.HomeGoals, result.AwayGoals)
+		away.All.add(result.AwayGoals, result.HomeGoals)
+		away.Away.add(result.AwayGoals, result.HomeGoals)
+		home.Form = appendForm(home.Form, result.HomeGoals, result.AwayGoals)
+		away.Form = appendForm(away.Form, result.AwayGoals, result.HomeGoals)
+	}
+	for _, row := range order {
+		row.Points = row.All.points(rules)
+	}
+
+	ranked := rank(order, counted, rules, append([]string{"points"}, rules.Tiebreakers...))
+	table := make([]Row, len(ranked))
+	for i, row := range ranked {
+		row.Rank = i + 1
+		table[i] = *row
+	}
+	return table
+}
+
+// rank orders rows by the first criterion, then ranks each group still level
+// by the rest. Head-to-head is worked out within each group.
+func rank(rows []*Row, results []Result, rules Rules, criteria []string) []*Row {
+	if len(rows) < 2 || len(criteria) == 0 {
+		return rows
+	}
+
+	keys := make(map[uuid.UUID][2]int, len(rows))
+	switch criteria[0] {
+	case "points":
+		for _, row := range rows {
+			keys[row.Team] = [2]int{row.Points}
+		}
+	case TiebreakGoalDifference:
+		for _, row := range rows {
+			keys[row.Team] = [2]int{row.All.GoalDifference()}
+		}
+	case TiebreakGoalsFor:
+		for _, row := range rows {
+			keys[row.Team] = [2]int{row.All.GoalsFor}
+		}
+	case TiebreakHeadToHead:
+		keys = headToHead(rows, results, rules)
+	}
+
+	sorted := append([]*Row(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := keys[sorted[i].Team], keys[sorted[j].Team]
+		return a[0] > b[0] || a[0] == b[0] && a[1] > b[1]
+	})
+
+	ranked := make([]*Row, 0, len(sorted))
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && keys[sorted[end].Team] == keys[sorted[start].Team] {
+			end++
+		}
+		ranked = append(ranked, rank(sorted[start:end], results, rules, criteria[1:])...)
+		start = end
+	}
+	return ranked
+}
+
+// headToHead is each team's points and goal difference in matches between rows
+func headToHead(rows []*Row, results []Result, rules Rules) map[uuid.UUID][2]int {
+	records := make(map[uuid.UUID]*Record, len(rows))
+	for _, row := range rows {
+		records[row.Team] = &Record{}
+	}
+	for _, result := range results {
+		home, away := records[result.Home], records[result.Away]
+		if home == nil || away == nil {
+			continue
+		}
+		home.add(result.HomeGoals, result.AwayGoals)
+		away.add(result.AwayGoals, result.HomeGoals)
+	}
+
+	keys := make(map[uuid.UUID][2]int, len(rows))
+	for team, record := range records {
+		keys[team] = [2]int{record.points(rules), record.GoalDifference()}
+	}
+	return keys
+}
+
+func appendForm(form string, scored, conceded int32) string {
+	switch {
+	case scored > conceded:
+		form += "W"
+	case scored < conceded:
+		form += "L"
+	default:
+		form += "D"
+	}
+	if len(form) > formLength {
+		form = form[len(form)-formLength:]
+	}
+	return form
+}
@@ -0,0 +1,158 @@
+package standings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var day = time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
+
+func result(home, away uuid.UUID, homeGoals, awayGoals int32, week int) Result {
+	return Result{Home: home, Away: away, HomeGoals: homeGoals, AwayGoals: awayGoals, Played: day.AddDate(0, 0, 7*week)}
+}
+
+func order(table []Row) []uuid.UUID {
+	teams := make([]uuid.UUID, len(table))
+	for i, row := range table {
+		teams[i] = row.Team
+	}
+	return teams
+}
+
+func TestCompute(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	results := []Result{
+		result(a, b, 2, 0, 0),
+		result(c, a, 1, 1, 1),
+		result(b, c, 3, 1, 2),
+		result(a, uuid.New(), 9, 0, 3), // Not in the league
+	}
+
+	table := Compute([]uuid.UUID{a, b, c}, results, DefaultRules)
+
+	if len(table) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(table))
+	}
+	top := table[0]
+	if top.Team != a || top.Rank != 1 || top.Points != 4 {
+		t.Fatalf("Expected a top on 4 points, got %+v", top)
+	}
+	if top.All != (Record{Played: 2, Won: 1, Drawn: 1, GoalsFor: 3, GoalsAgainst: 1}) {
+		t.Errorf("Unexpected overall record %+v", top.All)
+	}
+	if top.Home != (Record{Played: 1, Won: 1, GoalsFor: 2}) || top.Away != (Record{Played: 1, Drawn: 1, GoalsFor: 1, GoalsAgainst: 1}) {
+		t.Errorf("Unexpected home/away records %+v %+v", top.Home, top.Away)
+	}
+	if top.Form != "WD" {
+		t.Errorf("Expected form WD, got %q", top.Form)
+	}
+	if table[1].Team != b || table[2].Team != c || table[2].Points != 1 {
+		t.Errorf("Unexpected order %v", order(table))
+	}
+}
+
+func TestComputeCustomPoints(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	rules := Rules{Win: 2, Draw: 1, Loss: 1}
+
+	table := Compute([]uuid.UUID{a, b}, []Result{result(a, b, 1, 0, 0), result(b, a, 2, 2, 1)}, rules)
+
+	if table[0].Points != 3 || table[1].Points != 2 {
+		t.Errorf("Expected 3 and 2 points, got %d and %d", table[0].Points, table[1].Points)
+	}
+}
+
+func TestComputeTiebreakers(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	// a and b both win two and lose one. a beat b, but b has the better
+	// goal difference and a scored more.
+	results := []Result{
+		result(a, b, 1, 0, 0),
+		result(a, c, 5, 0, 1),
+		result(d, a, 4, 0, 2),
+		result(b, c, 6, 0, 3),
+		result(b, d, 2, 0, 4),
+		result(c, d, 0, 0, 5),
+	}
+	teams := []uuid.UUID{c, d, b, a}
+
+	tests := []struct {
+		name        string
+		tiebreakers []string
+		want        uuid.UUID
+	}{
+		{"head to head", []string{TiebreakHeadToHead}, a},
+		{"goal difference", []string{TiebreakGoalDifference, TiebreakHeadToHead}, b},
+		{"goals for", []string{TiebreakGoalsFor}, b},
+		{"none keeps team order", nil, b},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultRules
+			rules.Tiebreakers = tt.tiebreakers
+			table := Compute(teams, results, rules)
+			if table[0].Team != tt.want {
+				t.Errorf("Expected %v top, got order %v", tt.want, order(table))
+			}
+		})
+	}
+}
+
+func TestComputeHeadToHeadWithinGroup(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	// Everyone beats someone once: head-to-head points are level, so
+	// head-to-head goal difference decides
+	results := []Result{
+		result(a, b, 3, 0, 0),
+		result(b, c, 1, 0, 1),
+		result(c, a, 1, 0, 2),
+	}
+
+	table := Compute([]uuid.UUID{c, b, a}, results, Rules{Win: 3, Draw: 1, Tiebreakers: []string{TiebreakHeadToHead}})
+
+	want := []uuid.UUID{a, c, b}
+	for i, team := range order(table) {
+		if team != want[i] {
+			t.Fatalf("Expected order %v, got %v", want, order(table))
+		}
+	}
+}
+
+func TestComputeFormKeepsLastFive(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	var results []Result
+	for week := 0; week < 7; week++ {
+		results = append(results, result(a, b, int32(week%3), 1, week))
+	}
+
+	table := Compute([]uuid.UUID{a, b}, results, DefaultRules)
+
+	for _, row := range table {
+		if row.Team == a && row.Form != "WLDWL" {
+			t.Errorf("Expected form WLDWL, got %q", row.Form)
+		}
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		rules   Rules
+		wantErr bool
+	}{
+		{DefaultRules, false},
+		{Rules{Win: 2, Draw: 1, Loss: 1}, false},
+		{Rules{Win: 1, Draw: 1}, true},
+		{Rules{Win: 3, Draw: 1, Loss: -1}, true},
+		{Rules{Win: 3, Draw: 1, Tiebreakers: []string{"away_goals"}}, true},
+		{Rules{Win: 3, Draw: 1, Tiebreakers: []string{TiebreakGoalsFor, TiebreakGoalsFor}}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.rules.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%+v.Validate() error = %v, wantErr %v", tt.rules, err, tt.wantErr)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -11,6 +12,12 @@ import (
 	"github.com/ArronJLinton/fucci-api/internal/cache"
 	"github.com/ArronJLinton/fucci-api/internal/config"
 	"github.com/ArronJLinton/fucci-api/internal/database"
+	"github.com/ArronJLinton/fucci-api/internal/leaderboard"
+	"github.com/ArronJLinton/fucci-api/internal/news"
+	"github.com/ArronJLinton/fucci-api/internal/predictions"
+	"github.com/ArronJLinton/fucci-api/pkg/blobstore"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+	"github.com/ArronJLinton/fucci-api/pkg/mail"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/cors"
 	_ "github.com/lib/pq"
@@ -68,6 +75,35 @@ func main() {
 		MaxAge:           300,
 	}))
 
+	// Uploaded media; local files are served by the API itself
+	blobs, err := blobstore.New(blobstore.Config{
+		Backend:           c.BLOB_STORE,
+		PublicURL:         c.BLOB_PUBLIC_URL,
+		LocalDir:          c.BLOB_LOCAL_DIR,
+		S3Endpoint:        c.S3_ENDPOINT,
+		S3Region:          c.S3_REGION,
+		S3Bucket:          c.S3_BUCKET,
+		S3AccessKeyID:     c.S3_ACCESS_KEY_ID,
+		S3SecretAccessKey: c.S3_SECRET_ACCESS_KEY,
+	})
+	if err != nil {
+		log.Printf("Warning: Media uploads are disabled: %v\n", err)
+	}
+	if local, ok := blobs.(*blobstore.LocalStore); ok {
+		router.Handle("/uploads/*", http.StripPrefix("/uploads", local.Handler()))
+	}
+
+	// Verification emails; without them nobody can verify their email
+	var mailer mail.Sender
+	if c.MAIL_SMTP_URL != "" && c.EMAIL_VERIFICATION_URL != "" {
+		smtpSender, err := mail.NewSMTPSender(c.MAIL_SMTP_URL, c.MAIL_FROM)
+		if err != nil {
+			log.Printf("Warning: Email verification is disabled: %v\n", err)
+		} else {
+			mailer = smtpSender
+		}
+	}
+
 	v1Router := chi.NewRouter()
 	dbQueries := database.New(conn)
 	apiCfg := api.Config{
@@ -78,8 +114,63 @@ func main() {
 		Cache:          redisCache,
 		OpenAIKey:      c.OPENAI_API_KEY,
 		OpenAIBaseURL:  c.OPENAI_BASE_URL,
+		Leaderboards:   leaderboard.NewRedisStore(redisCache.Client()),
+		ScorePredictionRules: predictions.Rules{
+			Exact:          c.SCORE_PREDICTION_EXACT_POINTS,
+			GoalDifference: c.SCORE_PREDICTION_GOAL_DIFFERENCE_POINTS,
+			Result:         c.SCORE_PREDICTION_RESULT_POINTS,
+		},
+		Blobs:                blobs,
+		Mailer:               mailer,
+		EmailVerificationURL: c.EMAIL_VERIFICATION_URL,
 	}
 	apiRouter := api.New(apiCfg)
+
+	// Start polling RSS/Atom feeds for news
+	newsStore := news.NewDBStore(conn)
+	if err := newsStore.EnsureFeeds(context.Background(), c.NEWS_FEED_URLS); err != nil {
+		log.Printf("Warning: Failed to register news feeds: %v\n", err)
+	}
+	if c.NEWS_POLL_INTERVAL > 0 {
+		go news.NewIngester(newsStore, news.NewHTTPFetcher()).Run(context.Background(), c.NEWS_POLL_INTERVAL)
+	}
+
+	// Settle pre-match predictions once their fixtures finish
+	if c.OPENAI_API_KEY != "" && c.DEBATE_RESOLUTION_INTERVAL > 0 {
+		go api.NewDebateResolver(apiCfg).Run(context.Background(), c.DEBATE_RESOLUTION_INTERVAL)
+	}
+
+	// Score fans' scoreline predictions once their fixtures finish
+	if c.SCORE_PREDICTION_INTERVAL > 0 {
+		go api.NewScorePredictionScorer(apiCfg).Run(context.Background(), c.SCORE_PREDICTION_INTERVAL)
+	}
+
+	// Expire old inbox notifications
+	if c.NOTIFICATION_CLEANUP_INTERVAL > 0 {
+		go api.NewNotificationCleaner(apiCfg).Run(context.Background(), c.NOTIFICATION_CLEANUP_INTERVAL)
+	}
+
+	// Delete the files of replaced avatars and logos
+	if blobs != nil && c.IMAGE_ASSET_GC_INTERVAL > 0 {
+		go api.NewImageAssetCollector(apiCfg).Run(context.Background(), c.IMAGE_ASSET_GC_INTERVAL)
+	}
+
+	// Unverify players whose verifications lapsed with their season
+	if c.VERIFICATION_EXPIRY_INTERVAL > 0 {
+		go api.NewVerificationExpirer(apiCfg).Run(context.Background(), c.VERIFICATION_EXPIRY_INTERVAL)
+	}
+
+	// Deliver domain events (votes, comments, verifications, match status changes) to subscribers
+	if c.EVENT_RELAY_INTERVAL > 0 {
+		bus := events.NewBus()
+		apiCfg.Subscribe(bus)
+		go events.NewRelay(conn, "api", bus).Run(context.Background(), c.EVENT_RELAY_INTERVAL)
+	}
+
+	// Keep the outbox from growing without bound
+	if c.EVENT_PURGE_INTERVAL > 0 {
+		go events.NewPurger(conn).Run(context.Background(), c.EVENT_PURGE_INTERVAL)
+	}
 	v1Router.Mount("/api", apiRouter)
 	router.Mount("/v1", v1Router)
 
@@ -0,0 +1,76 @@
+// Package blobstore stores uploaded files on local disk or an S3-compatible
+// service. It's shared by the API, which takes uploads, and the workers, which
+// process them.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore saves files by key, e.g. "media/1035045/1f0c….jpg", and gives out
+// public URLs for them
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound when nothing is stored under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete succeeds when nothing is stored under the key
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// Config picks and configures a store. Backend is "local" or "s3".
+type Config struct {
+	Backend   string
+	PublicURL string // Base URL objects are served from
+
+	LocalDir string
+
+	S3Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000 for MinIO
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+}
+
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		if cfg.LocalDir == "" {
+			return nil, errors.New("a directory is required for the local blob store")
+		}
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL), nil
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKeyID == "" || cfg.S3SecretAccessKey == "" {
+			return nil, errors.New("an endpoint, bucket and credentials are required for the S3 blob store")
+		}
+		return NewS3Store(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKeyID, cfg.S3SecretAccessKey, cfg.PublicURL)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.Backend)
+	}
+}
+
+// validateKey rejects keys that could escape the store's root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
@@ -0,0 +1,70 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "http://localhost:8080/uploads/")
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "media/1/a.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	body, err := store.Get(ctx, "media/1/a.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "jpeg" {
+		t.Errorf("Get = %q, want jpeg", data)
+	}
+
+	if got := store.URL("media/1/a.jpg"); got != "http://localhost:8080/uploads/media/1/a.jpg" {
+		t.Errorf("URL = %q", got)
+	}
+
+	if err := store.Delete(ctx, "media/1/a.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "media/1/a.jpg"); err != ErrNotFound {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "media/1/a.jpg"); err != nil {
+		t.Errorf("Delete of a missing key = %v, want nil", err)
+	}
+}
+
+func TestLocalStoreRejectsEscapingKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "")
+	for _, key := range []string{"", "/etc/passwd", "../secret", "media/../../secret", "media//a.jpg"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); err != ErrInvalidKey {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestLocalStoreHandler(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "")
+	store.Put(context.Background(), "media/1/a.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+	handler := store.Handler()
+
+	tests := map[string]int{
+		"/media/1/a.jpg": http.StatusOK,
+		"/media/1/":      http.StatusNotFound,
+		"/media/1/b.jpg": http.StatusNotFound,
+	}
+	for path, want := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Handler reacts to an event. Events are delivered at least once, so handlers
+// must be safe to run twice for the same event.
+type Handler func(ctx context.Context, event Event) error
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+// Bus routes events to the handlers subscribed to their type
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[Type][]subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{subscriptions: make(map[Type][]subscription)}
+}
+
+// Subscribe registers a named handler for an event type. Handlers for the same
+// type run in the order they were subscribed.
+func (b *Bus) Subscribe(eventType Type, name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventType] = append(b.subscriptions[eventType], subscription{name: name, handler: handler})
+}
+
+// Types lists the event types that have subscribers
+func (b *Bus) Types() []Type {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	types := make([]Type, 0, len(b.subscriptions))
+	for eventType := range b.subscriptions {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+// Publish runs every handler for the event. A failing handler doesn't stop the
+// others; their errors are joined and returned.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	subscriptions := b.subscriptions[event.EventType()]
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range subscriptions {
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
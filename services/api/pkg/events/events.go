@@ -0,0 +1,145 @@
+// Package events defines the domain events shared by the API and workers, an
+// in-process bus to subscribe to them, and a transactional outbox so an event
+// is only published if the change that raised it is committed.
+//
+// It lives outside internal/ so that services/workers can import it.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Type names an event in the outbox
+type Type string
+
+const (
+	TypeVoteCast           Type = "vote.cast"
+	TypeCommentPosted      Type = "comment.posted"
+	TypeVerificationAdded  Type = "verification.added"
+	TypeDebateGenerated    Type = "debate.generated"
+	TypeMatchStatusChanged Type = "match.status_changed"
+	TypeGoalScored         Type = "match.goal_scored"
+)
+
+// Event is a typed domain event
+type Event interface {
+	EventType() Type
+}
+
+// VoteCast is raised when a user votes on a debate card
+type VoteCast struct {
+	VoteID       int32  `json:"vote_id"`
+	DebateID     int32  `json:"debate_id"`
+	DebateCardID int32  `json:"debate_card_id"`
+	UserID       int32  `json:"user_id"`
+	VoteType     string `json:"vote_type"`
+}
+
+func (VoteCast) EventType() Type { return TypeVoteCast }
+
+// CommentPosted is raised when a user comments on a debate
+type CommentPosted struct {
+	CommentID       int32  `json:"comment_id"`
+	DebateID        int32  `json:"debate_id"`
+	ParentCommentID *int32 `json:"parent_comment_id,omitempty"`
+	UserID          int32  `json:"user_id"`
+}
+
+func (CommentPosted) EventType() Type { return TypeCommentPosted }
+
+// VerificationAdded is raised when a user vouches for a player profile
+type VerificationAdded struct {
+	VerificationID  string `json:"verification_id"`
+	PlayerProfileID string `json:"player_profile_id"`
+	PlayerUserID    int32  `json:"player_user_id"`
+	VerifierUserID  int32  `json:"verifier_user_id"`
+}
+
+func (VerificationAdded) EventType() Type { return TypeVerificationAdded }
+
+// DebateGenerated is raised when the AI publishes a debate for a match
+type DebateGenerated struct {
+	DebateID   int32  `json:"debate_id"`
+	MatchID    string `json:"match_id"`
+	DebateType string `json:"debate_type"`
+	Headline   string `json:"headline"`
+	LeagueID   int32  `json:"league_id,omitempty"`
+	HomeTeam   string `json:"home_team,omitempty"`
+	AwayTeam   string `json:"away_team,omitempty"`
+}
+
+func (DebateGenerated) EventType() Type { return TypeDebateGenerated }
+
+// MatchStatusChanged is raised when an API-Football fixture's short status changes, e.g. NS to 1H
+type MatchStatusChanged struct {
+	MatchID    string `json:"match_id"`
+	LeagueID   int32  `json:"league_id,omitempty"`
+	HomeTeam   string `json:"home_team,omitempty"`
+	AwayTeam   string `json:"away_team,omitempty"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	HomeGoals  int32  `json:"home_goals"`
+	AwayGoals  int32  `json:"away_goals"`
+}
+
+func (MatchStatusChanged) EventType() Type { return TypeMatchStatusChanged }
+
+// GoalScored is raised when a live fixture's score goes up between two reads
+type GoalScored struct {
+	MatchID   string `json:"match_id"`
+	LeagueID  int32  `json:"league_id,omitempty"`
+	HomeTeam  string `json:"home_team"`
+	AwayTeam  string `json:"away_team"`
+	HomeGoals int32  `json:"home_goals"`
+	AwayGoals int32  `json:"away_goals"`
+	Status    string `json:"status"`
+}
+
+func (GoalScored) EventType() Type { return TypeGoalScored }
+
+// Decode parses an outbox payload back into its typed event
+func Decode(eventType Type, payload []byte) (Event, error) {
+	var event Event
+	switch eventType {
+	case TypeVoteCast:
+		var e VoteCast
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		event = e
+	case TypeCommentPosted:
+		var e CommentPosted
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		event = e
+	case TypeVerificationAdded:
+		var e VerificationAdded
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		event = e
+	case TypeDebateGenerated:
+		var e DebateGenerated
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		event = e
+	case TypeMatchStatusChanged:
+		var e MatchStatusChanged
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		event = e
+	case TypeGoalScored:
+		var e GoalScored
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		event = e
+	default:
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+	return event, nil
+}
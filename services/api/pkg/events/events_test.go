@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeRoundTrip(t *testing.T) {
+	parent := int32(7)
+	tests := []Event{
+		VoteCast{VoteID: 1, DebateID: 2, DebateCardID: 3, UserID: 4, VoteType: "upvote"},
+		CommentPosted{CommentID: 5, DebateID: 2, ParentCommentID: &parent, UserID: 4},
+		VerificationAdded{VerificationID: "v1", PlayerProfileID: "p1", PlayerUserID: 8, VerifierUserID: 4},
+		DebateGenerated{DebateID: 2, MatchID: "1035", DebateType: "pre_match", Headline: "Who wins?", LeagueID: 39},
+		MatchStatusChanged{MatchID: "1035", LeagueID: 39, HomeTeam: "Arsenal", AwayTeam: "Chelsea", FromStatus: "2H", ToStatus: "FT", HomeGoals: 2, AwayGoals: 1},
+		GoalScored{MatchID: "1035", LeagueID: 39, HomeTeam: "Arsenal", AwayTeam: "Chelsea", HomeGoals: 1, AwayGoals: 0, Status: "1H"},
+	}
+
+	for _, event := range tests {
+		t.Run(string(event.EventType()), func(t *testing.T) {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			decoded, err := Decode(event.EventType(), payload)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, event) {
+				t.Errorf("Decode() = %+v, want %+v", decoded, event)
+			}
+		})
+	}
+}
+
+func TestDecodeUnknownType(t *testing.T) {
+	if _, err := Decode("match.abandoned", []byte(`{}`)); err == nil {
+		t.Error("expected an error for an unknown event type")
+	}
+}
+
+func TestBusPublish(t *testing.T) {
+	bus := NewBus()
+	var calls []string
+	bus.Subscribe(TypeVoteCast, "first", func(ctx context.Context, event Event) error {
+		calls = append(calls, "first")
+		return errors.New("boom")
+	})
+	bus.Subscribe(TypeVoteCast, "second", func(ctx context.Context, event Event) error {
+		calls = append(calls, "second")
+		return nil
+	})
+	bus.Subscribe(TypeCommentPosted, "other", func(ctx context.Context, event Event) error {
+		calls = append(calls, "other")
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), VoteCast{VoteID: 1})
+	if err == nil || err.Error() != "first: boom" {
+		t.Errorf("Publish() error = %v, want first: boom", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"first", "second"}) {
+		t.Errorf("handlers ran %v, want [first second]", calls)
+	}
+
+	if err := bus.Publish(context.Background(), MatchStatusChanged{}); err != nil {
+		t.Errorf("Publish() with no subscribers = %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// The outbox is queried with database/sql rather than sqlc because the sqlc
+// package is internal to the API and the workers need the same queries.
+const (
+	appendEventSQL = `INSERT INTO event_outbox (event_type, payload) VALUES ($1, $2)`
+
+	// pendingEventsSQL returns events the consumer hasn't handled yet, skipping
+	// ones it has given up on
+	pendingEventsSQL = `SELECT e.id, e.event_type, e.payload, COALESCE(d.attempts, 0)
+FROM event_outbox e
+LEFT JOIN event_deliveries d ON d.event_id = e.id AND d.consumer = $1
+WHERE e.event_type = ANY($2::text[])
+  AND e.created_at >= $3
+  AND d.delivered_at IS NULL
+  AND COALESCE(d.attempts, 0) < $4
+ORDER BY e.id
+LIMIT $5`
+
+	markDeliveredSQL = `INSERT INTO event_deliveries (event_id, consumer, attempts, delivered_at)
+VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+ON CONFLICT (event_id, consumer) DO UPDATE
+SET attempts = EXCLUDED.attempts, delivered_at = EXCLUDED.delivered_at, last_error = NULL`
+
+	markFailedSQL = `INSERT INTO event_deliveries (event_id, consumer, attempts, last_error)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (event_id, consumer) DO UPDATE
+SET attempts = EXCLUDED.attempts, last_error = EXCLUDED.last_error`
+
+	// consumerLockSQL stops two instances of the same consumer handling a batch at once
+	consumerLockSQL = `SELECT pg_try_advisory_xact_lock(hashtext('event_outbox:' || $1))`
+
+	// The event types a consumer reads, so the purge knows who still has to deliver an event
+	unregisterTypesSQL = `DELETE FROM event_consumers WHERE consumer = $1 AND event_type <> ALL($2::text[])`
+	registerTypesSQL   = `INSERT INTO event_consumers (consumer, event_type)
+SELECT $1, unnest($2::text[])
+ON CONFLICT (consumer, event_type) DO NOTHING`
+
+	// purgeEventsSQL deletes a batch of events from before the retention window
+	// that every consumer of their type has delivered. Deliveries go with them.
+	purgeEventsSQL = `DELETE FROM event_outbox
+WHERE id IN (
+    SELECT e.id FROM event_outbox e
+    WHERE e.created_at < $1
+      AND NOT EXISTS (
+          SELECT 1 FROM event_consumers c
+          WHERE c.event_type = e.event_type
+            AND NOT EXISTS (
+                SELECT 1 FROM event_deliveries d
+                WHERE d.event_id = e.id AND d.consumer = c.consumer AND d.delivered_at IS NOT NULL
+            )
+      )
+    ORDER BY e.id
+    LIMIT $2
+)`
+)
+
+const (
+	// MaxAttempts is how many times an event is retried before a consumer gives up on it
+	MaxAttempts = 5
+	// retention bounds how far back a new consumer looks for events it hasn't handled
+	retention = 7 * 24 * time.Hour
+	batchSize = 100
+	// purgeBatchSize bounds how many events one purge statement deletes
+	purgeBatchSize = 1000
+)
+
+// Execer is satisfied by *sql.DB and *sql.Tx
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Append writes an event to the outbox. Pass the transaction that makes the
+// change the event describes, so the event is only published if it commits.
+func Append(ctx context.Context, db Execer, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding %s event: %w", event.EventType(), err)
+	}
+	if _, err := db.ExecContext(ctx, appendEventSQL, string(event.EventType()), payload); err != nil {
+		return fmt.Errorf("error appending %s event: %w", event.EventType(), err)
+	}
+	return nil
+}
+
+// Relay delivers outbox events to a consumer's bus. Each consumer (e.g. "api",
+// "workers") tracks its own deliveries, so every service sees every event.
+type Relay struct {
+	db       *sql.DB
+	consumer string
+	bus      *Bus
+	// registered is set once the consumer's event types have been recorded
+	registered bool
+}
+
+func NewRelay(db *sql.DB, consumer string, bus *Bus) *Relay {
+	return &Relay{db: db, consumer: consumer, bus: bus}
+}
+
+// Run delivers events every interval until the context is cancelled
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.DeliverOnce(ctx); err != nil {
+			log.Printf("Event relay %s failed: %v\n", r.consumer, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverOnce publishes a batch of pending events and records the outcome of each.
+// It returns how many were delivered.
+func (r *Relay) DeliverOnce(ctx context.Context) (int, error) {
+	types := r.bus.Types()
+	if len(types) == 0 {
+		return 0, nil
+	}
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = string(t)
+	}
+
+	if !r.registered {
+		if err := r.register(ctx, typeNames); err != nil {
+			return 0, err
+		}
+		r.registered = true
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, consumerLockSQL, r.consumer).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("error locking consumer: %w", err)
+	}
+	if !locked {
+		// Another instance is handling this consumer's events
+		return 0, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, pendingEventsSQL, r.consumer, pq.Array(typeNames), time.Now().Add(-retention), MaxAttempts, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching pending events: %w", err)
+	}
+	type pending struct {
+		id        int64
+		eventType Type
+		payload   []byte
+		attempts  int
+	}
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.eventType, &p.payload, &p.attempts); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, p := range batch {
+		attempts := p.attempts + 1
+		event, err := Decode(p.eventType, p.payload)
+		if err == nil {
+			err = r.bus.Publish(ctx, event)
+		}
+		if err != nil {
+			if attempts >= MaxAttempts {
+				log.Printf("Event %d (%s) failed %d times for %s, giving up: %v\n", p.id, p.eventType, attempts, r.consumer, err)
+			}
+			if _, err := tx.ExecContext(ctx, markFailedSQL, p.id, r.consumer, attempts, err.Error()); err != nil {
+				return delivered, fmt.Errorf("error recording failed event %d: %w", p.id, err)
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, markDeliveredSQL, p.id, r.consumer, attempts); err != nil {
+			return delivered, fmt.Errorf("error recording delivered event %d: %w", p.id, err)
+		}
+		delivered++
+	}
+
+	return delivered, tx.Commit()
+}
+
+// register records the event types the consumer reads, replacing the ones it
+// read before, so events it no longer reads don't wait on it to be purged
+func (r *Relay) register(ctx context.Context, typeNames []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, unregisterTypesSQL, r.consumer, pq.Array(typeNames)); err != nil {
+		return fmt.Errorf("error registering consumer %s: %w", r.consumer, err)
+	}
+	if _, err := tx.ExecContext(ctx, registerTypesSQL, r.consumer, pq.Array(typeNames)); err != nil {
+		return fmt.Errorf("error registering consumer %s: %w", r.consumer, err)
+	}
+	return tx.Commit()
+}
+
+// Purger deletes outbox events once they are past the retention window and
+// every consumer of their type has delivered them. Events a consumer gave up
+// on are kept, with their last error, until someone looks into them.
+type Purger struct {
+	db *sql.DB
+}
+
+func NewPurger(db *sql.DB) *Purger {
+	return &Purger{db: db}
+}
+
+// Run purges every interval until the context is cancelled
+func (p *Purger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.PurgeOnce(ctx)
+		if err != nil {
+			log.Printf("Event purge failed: %v\n", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d delivered events\n", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce deletes delivered events from before the retention window in
+// batches, and returns how many were deleted
+func (p *Purger) PurgeOnce(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-retention)
+	var purged int64
+	for {
+		result, err := p.db.ExecContext(ctx, purgeEventsSQL, before, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("error purging events: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return purged, err
+		}
+		purged += n
+		if n < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Package mail sends transactional email, like the links people follow to
+// verify their address.
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is a plain text email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages, through SMTP in production
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// FakeSender records messages instead of sending them. It's used in tests.
+type FakeSender struct {
+	mu   sync.Mutex
+	Sent []Message
+}
+
+func (f *FakeSender) Send(ctx context.Context, message Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Sent = append(f.Sent, message)
+	return nil
+}
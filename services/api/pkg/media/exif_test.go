@@ -0,0 +1,110 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// jpegWithOrientation builds a JPEG with an EXIF block holding only the orientation tag
+func jpegWithOrientation(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	binary.Write(&tiff, binary.BigEndian, uint16(42))
+	binary.Write(&tiff, binary.BigEndian, uint32(8)) // First IFD offset
+	binary.Write(&tiff, binary.BigEndian, uint16(1)) // One entry
+	binary.Write(&tiff, binary.BigEndian, uint16(exifOrientationTag))
+	binary.Write(&tiff, binary.BigEndian, uint16(3)) // SHORT
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, orientation)
+	binary.Write(&tiff, binary.BigEndian, uint16(0))
+	binary.Write(&tiff, binary.BigEndian, uint32(0)) // No next IFD
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(encoded.Bytes()[2:]) // Skip the encoder's own SOI marker
+	return out.Bytes()
+}
+
+func TestOrientation(t *testing.T) {
+	for _, want := range []uint16{1, 3, 6, 8} {
+		if got := Orientation(jpegWithOrientation(t, want)); got != int(want) {
+			t.Errorf("Orientation = %d, want %d", got, want)
+		}
+	}
+	if got := Orientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("Orientation(garbage) = %d, want 1", got)
+	}
+}
+
+func TestDecodeUploadAppliesOrientation(t *testing.T) {
+	img, err := DecodeUpload(jpegWithOrientation(t, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A 4x2 photo taken on its side is 2x4 upright
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 4 {
+		t.Errorf("decoded image is %dx%d, want 2x4", b.Dx(), b.Dy())
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A red pixel in the top left corner of a 3x2 image
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	tests := map[int]image.Point{
+		1: {0, 0},
+		2: {2, 0},
+		3: {2, 1},
+		4: {0, 1},
+		6: {1, 0},
+		8: {0, 2},
+	}
+	for orientation, want := range tests {
+		oriented := toRGBA(Orient(img, orientation))
+		if oriented.RGBAAt(want.X, want.Y).R != 255 {
+			t.Errorf("orientation %d: expected the red pixel at %v", orientation, want)
+		}
+	}
+}
+
+func TestFill(t *testing.T) {
+	got := Fill(image.NewRGBA(image.Rect(0, 0, 800, 600)), 128).Bounds()
+	if got.Dx() != 128 || got.Dy() != 128 {
+		t.Errorf("Fill = %dx%d, want 128x128", got.Dx(), got.Dy())
+	}
+}
+
+func TestStripMetadata(t *testing.T) {
+	clean, err := StripMetadata(jpegWithOrientation(t, 6), "image/jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(clean, []byte("Exif")) {
+		t.Error("re-encoded JPEG still has its EXIF block")
+	}
+	img, err := jpeg.Decode(bytes.NewReader(clean))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 4 {
+		t.Errorf("re-encoded image is %dx%d, want it upright at 2x4", b.Dx(), b.Dy())
+	}
+
+	if _, err := StripMetadata([]byte("\xFF\xD8\xFFnot really"), "image/jpeg"); err == nil {
+		t.Error("expected an error for a corrupt JPEG")
+	}
+}
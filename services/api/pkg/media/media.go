@@ -0,0 +1,56 @@
+// Package media validates uploaded match media and generates thumbnails for it
+package media
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Media types stored in media.media_type
+const (
+	TypeImage = "image"
+	TypeVideo = "video"
+)
+
+// Upload limits per media type
+const (
+	MaxImageSize int64 = 10 << 20
+	MaxVideoSize int64 = 50 << 20
+)
+
+// Statuses of an upload in media.status
+const (
+	StatusProcessing = "processing"
+	StatusReady      = "ready"
+	StatusFailed     = "failed"
+)
+
+var ErrUnsupportedType = errors.New("unsupported media type")
+
+// allowedTypes maps each accepted content type to its media type and file extension
+var allowedTypes = map[string]struct{ mediaType, extension string }{
+	"image/jpeg": {TypeImage, ".jpg"},
+	"image/png":  {TypeImage, ".png"},
+	"image/gif":  {TypeImage, ".gif"},
+	"video/mp4":  {TypeVideo, ".mp4"},
+	"video/webm": {TypeVideo, ".webm"},
+}
+
+// Detect sniffs the content type from the start of a file, ignoring whatever
+// the client claimed. It returns ErrUnsupportedType for anything not allowed.
+func Detect(head []byte) (contentType, mediaType, extension string, err error) {
+	contentType = http.DetectContentType(head)
+	allowed, ok := allowedTypes[contentType]
+	if !ok {
+		return contentType, "", "", ErrUnsupportedType
+	}
+	return contentType, allowed.mediaType, allowed.extension, nil
+}
+
+// MaxSize is the largest upload allowed for the media type
+func MaxSize(mediaType string) int64 {
+	if mediaType == TypeVideo {
+		return MaxVideoSize
+	}
+	return MaxImageSize
+}
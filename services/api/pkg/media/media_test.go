@@ -0,0 +1,47 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 30, B: 30, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	contentType, mediaType, extension, err := Detect(pngBytes(t, 2, 2))
+	if err != nil || contentType != "image/png" || mediaType != TypeImage || extension != ".png" {
+		t.Errorf("Detect(png) = %q, %q, %q, %v", contentType, mediaType, extension, err)
+	}
+
+	mp4 := append([]byte{0, 0, 0, 0x18}, []byte("ftypmp42\x00\x00\x00\x00mp42isom")...)
+	if _, mediaType, _, err := Detect(mp4); err != nil || mediaType != TypeVideo {
+		t.Errorf("Detect(mp4) = %q, %v; want video", mediaType, err)
+	}
+
+	// A script renamed to .jpg is still rejected
+	if _, _, _, err := Detect([]byte("<html><script>alert(1)</script>")); err != ErrUnsupportedType {
+		t.Errorf("Detect(html) = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	if MaxSize(TypeImage) != MaxImageSize || MaxSize(TypeVideo) != MaxVideoSize {
+		t.Error("unexpected size limits")
+	}
+}
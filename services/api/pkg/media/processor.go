@@ -0,0 +1,220 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ArronJLinton/fucci-api/pkg/blobstore"
+)
+
+// Like the push queue, uploads are read with database/sql because the sqlc
+// package is internal to the API
+const (
+	// pendingMediaSQL claims a batch of uploads waiting for a thumbnail
+	pendingMediaSQL = `SELECT id, media_type, storage_key FROM media
+WHERE status = 'processing' AND attempts < $1 AND storage_key IS NOT NULL
+ORDER BY id
+LIMIT $2
+FOR UPDATE SKIP LOCKED`
+
+	markReadySQL = `UPDATE media SET status = 'ready', thumbnail_key = $2, thumbnail_url = $3,
+attempts = attempts + 1, last_error = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1`
+
+	// markFailedSQL gives up on the upload after its last attempt
+	markFailedSQL = `UPDATE media SET attempts = attempts + 1, last_error = $2, updated_at = CURRENT_TIMESTAMP,
+status = CASE WHEN attempts + 1 >= $3 THEN 'failed' ELSE status END
+WHERE id = $1`
+)
+
+const (
+	// MaxAttempts is how many times an upload is processed before it's marked failed
+	MaxAttempts = 3
+	// processBatchSize is small because each upload is downloaded while its row is locked
+	processBatchSize = 10
+	// videoFrameOffset is how far into a video its thumbnail is taken from
+	videoFrameOffset = "00:00:01"
+)
+
+// Processor generates thumbnails for uploaded media and marks it ready
+type Processor struct {
+	db     *sql.DB
+	store  blobstore.BlobStore
+	ffmpeg string
+}
+
+// NewProcessor creates a processor. Video thumbnails are taken with ffmpeg at
+// ffmpegPath; when it's empty, videos are marked ready without one.
+func NewProcessor(db *sql.DB, store blobstore.BlobStore, ffmpegPath string) *Processor {
+	return &Processor{db: db, store: store, ffmpeg: ffmpegPath}
+}
+
+// Run processes uploads every interval until the context is cancelled
+func (p *Processor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.ProcessOnce(ctx); err != nil {
+			log.Printf("Media processing failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessOnce processes a batch of uploads and returns how many are now ready
+func (p *Processor) ProcessOnce(ctx context.Context) (int, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, pendingMediaSQL, MaxAttempts, processBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching uploads: %w", err)
+	}
+	type upload struct {
+		id         int32
+		mediaType  string
+		storageKey string
+	}
+	var pending []upload
+	for rows.Next() {
+		var u upload
+		if err := rows.Scan(&u.id, &u.mediaType, &u.storageKey); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	ready := 0
+	for _, u := range pending {
+		thumbnailKey, err := p.process(ctx, u.mediaType, u.storageKey)
+		if err != nil {
+			log.Printf("Failed to process media %d: %v\n", u.id, err)
+			if _, err := tx.ExecContext(ctx, markFailedSQL, u.id, err.Error(), MaxAttempts); err != nil {
+				return ready, err
+			}
+			continue
+		}
+
+		var thumbnailURL sql.NullString
+		if thumbnailKey.Valid {
+			thumbnailURL = sql.NullString{String: p.store.URL(thumbnailKey.String), Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx, markReadySQL, u.id, thumbnailKey, thumbnailURL); err != nil {
+			return ready, err
+		}
+		ready++
+	}
+
+	return ready, tx.Commit()
+}
+
+// process stores a thumbnail for the upload and returns its key, which is
+// null for videos when ffmpeg isn't available
+func (p *Processor) process(ctx context.Context, mediaType, key string) (sql.NullString, error) {
+	var thumbnail []byte
+	var err error
+	switch mediaType {
+	case TypeImage:
+		thumbnail, err = p.imageThumbnail(ctx, key)
+	case TypeVideo:
+		if p.ffmpeg == "" {
+			return sql.NullString{}, nil
+		}
+		thumbnail, err = p.videoThumbnail(ctx, key)
+	default:
+		return sql.NullString{}, fmt.Errorf("unknown media type %q", mediaType)
+	}
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	thumbnailKey := ThumbnailKey(key)
+	if err := p.store.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+		return sql.NullString{}, fmt.Errorf("error storing thumbnail: %w", err)
+	}
+	return sql.NullString{String: thumbnailKey, Valid: true}, nil
+}
+
+func (p *Processor) imageThumbnail(ctx context.Context, key string) ([]byte, error) {
+	body, err := p.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return Thumbnail(body, ThumbnailSize)
+}
+
+// videoThumbnail copies the video to a temporary file, since ffmpeg needs to
+// seek, and grabs a frame from it
+func (p *Processor) videoThumbnail(ctx context.Context, key string) ([]byte, error) {
+	body, err := p.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "media-*"+path.Ext(key))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	var frame, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.ffmpeg, "-ss", videoFrameOffset, "-i", tmp.Name(),
+		"-frames:v", "1", "-f", "image2", "-c:v", "png", "pipe:1")
+	cmd.Stdout = &frame
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, lastLine(stderr.String()))
+	}
+	if frame.Len() == 0 {
+		// Shorter than the offset; take the first frame instead
+		cmd = exec.CommandContext(ctx, p.ffmpeg, "-i", tmp.Name(), "-frames:v", "1", "-f", "image2", "-c:v", "png", "pipe:1")
+		cmd.Stdout = &frame
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("ffmpeg: %w", err)
+		}
+	}
+	return Thumbnail(&frame, ThumbnailSize)
+}
+
+// ThumbnailKey is where an upload's thumbnail is stored, e.g.
+// media/1/abc.mp4 -> thumbnails/media/1/abc.jpg
+func ThumbnailKey(key string) string {
+	return "thumbnails/" + strings.TrimSuffix(key, path.Ext(key)) + ".jpg"
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,178 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const (
+	// ThumbnailSize is the longest side of a thumbnail in pixels
+	ThumbnailSize = 320
+	// maxPixels guards against decompression bombs, e.g. a tiny PNG claiming to be 100k×100k
+	maxPixels   = 50_000_000
+	jpegQuality = 80
+)
+
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// Decode reads an image after checking its dimensions are reasonable
+func Decode(r io.Reader) (image.Image, error) {
+	var head bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(io.MultiReader(&head, r))
+	return img, err
+}
+
+// DecodeUpload decodes an uploaded image and turns it upright using its EXIF
+// orientation, so it can be re-encoded without its metadata
+func DecodeUpload(data []byte) (image.Image, error) {
+	img, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return Orient(img, Orientation(data)), nil
+}
+
+// StripMetadata re-encodes an uploaded image in its own format so none of the
+// original's metadata, such as EXIF GPS coordinates, is kept. JPEGs are turned
+// upright first since their orientation tag goes too. GIFs keep every frame.
+func StripMetadata(data []byte, contentType string) ([]byte, error) {
+	if contentType == "image/gif" {
+		config, err := gif.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if config.Width*config.Height > maxPixels {
+			return nil, ErrImageTooLarge
+		}
+		// The encoder only writes frames and the loop count, dropping comments and XMP
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := gif.EncodeAll(&buf, animation); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	img, err := DecodeUpload(data)
+	if err != nil {
+		return nil, err
+	}
+	if contentType == "image/png" {
+		return EncodePNG(img)
+	}
+	return EncodeJPEG(img)
+}
+
+// Thumbnail decodes an image and returns it scaled to fit within maxSide as a JPEG
+func Thumbnail(r io.Reader, maxSide int) ([]byte, error) {
+	img, err := Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	return EncodeJPEG(Resize(img, maxSide))
+}
+
+// EncodeJPEG re-encodes an image, which also drops any metadata the original
+// had. Transparent areas become white.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	flat := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodePNG re-encodes an image as PNG, keeping transparency but not metadata
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Fill crops an image to a centred square and scales it down to side
+func Fill(img image.Image, side int) *image.RGBA {
+	src := toRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	size := min(w, h)
+	x0, y0 := (w-size)/2, (h-size)/2
+	return Resize(src.SubImage(image.Rect(x0, y0, x0+size, y0+size)), side)
+}
+
+// Resize scales an image down to fit within maxSide, keeping its aspect ratio.
+// Each output pixel averages the source pixels it covers. Images that already
+// fit are returned as RGBA at their original size.
+func Resize(img image.Image, maxSide int) *image.RGBA {
+	src := toRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= maxSide && h <= maxSide || w == 0 || h == 0 {
+		return src
+	}
+
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// toRGBA copies the image into an RGBA with its origin at 0,0
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
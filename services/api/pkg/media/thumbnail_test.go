@@ -0,0 +1,71 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		w, h, maxSide int
+		wantW, wantH  int
+	}{
+		{1200, 800, 300, 300, 200},
+		{800, 1200, 300, 200, 300},
+		{100, 50, 300, 100, 50},
+		{3000, 2, 300, 300, 1},
+	}
+	for _, tt := range tests {
+		got := Resize(image.NewRGBA(image.Rect(0, 0, tt.w, tt.h)), tt.maxSide).Bounds()
+		if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+			t.Errorf("Resize(%dx%d, %d) = %dx%d, want %dx%d", tt.w, tt.h, tt.maxSide, got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestResizeAveragesPixels(t *testing.T) {
+	// Alternating black and white columns average to grey
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			if x%2 == 0 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	got := Resize(img, 2).RGBAAt(0, 0)
+	if got.R != 127 || got.A != 255 {
+		t.Errorf("averaged pixel = %v, want grey", got)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	thumbnail, err := Thumbnail(bytes.NewReader(pngBytes(t, 640, 480)), ThumbnailSize)
+	if err != nil {
+		t.Fatalf("Thumbnail: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(thumbnail))
+	if err != nil {
+		t.Fatalf("thumbnail is not a JPEG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 320 || b.Dy() != 240 {
+		t.Errorf("thumbnail is %dx%d, want 320x240", b.Dx(), b.Dy())
+	}
+}
+
+func TestThumbnailRejectsGarbage(t *testing.T) {
+	if _, err := Thumbnail(bytes.NewReader([]byte("not an image")), ThumbnailSize); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestThumbnailKey(t *testing.T) {
+	if got := ThumbnailKey("media/1035045/abc.mp4"); got != "thumbnails/media/1035045/abc.jpg" {
+		t.Errorf("ThumbnailKey = %q", got)
+	}
+}
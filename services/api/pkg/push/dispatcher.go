@@ -0,0 +1,268 @@
+package push
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Like the event outbox, the queue is read with database/sql because the sqlc
+// package is internal to the API.
+const (
+	// claimNotificationsSQL leases a batch of unsent notifications and counts the
+	// attempt. The claim commits before anything is sent, so a slow gateway holds
+	// no locks; SKIP LOCKED and the lease stop several workers sending the same rows.
+	claimNotificationsSQL = `UPDATE push_notifications
+SET locked_until = CURRENT_TIMESTAMP + $4 * INTERVAL '1 second', attempts = attempts + 1
+WHERE id IN (
+    SELECT id FROM push_notifications
+    WHERE sent_at IS NULL AND attempts < $1 AND created_at >= $2
+      AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+    ORDER BY id
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, title, body, data`
+
+	deviceTokensSQL = `SELECT user_id, token FROM device_tokens WHERE user_id = ANY($1::int[])`
+
+	markSentSQL = `UPDATE push_notifications SET sent_at = CURRENT_TIMESTAMP, last_error = NULL, locked_until = NULL
+WHERE id = ANY($1::bigint[])`
+
+	// markFailedSQL releases the lease so the next run retries them
+	markFailedSQL = `UPDATE push_notifications SET last_error = $2, locked_until = NULL
+WHERE id = ANY($1::bigint[])`
+
+	deleteTokensSQL = `DELETE FROM device_tokens WHERE token = ANY($1::text[])`
+)
+
+const (
+	// MaxAttempts is how many times a notification is retried before it's dropped
+	MaxAttempts = 3
+	// maxAge stops stale notifications (e.g. a goal from yesterday) from being sent late
+	maxAge    = time.Hour
+	batchSize = 1000
+	// lease is how long claimed notifications are held for one worker. A worker
+	// that dies mid-send leaves them to be claimed again once it expires.
+	lease = 5 * time.Minute
+)
+
+// queued is a notification read from the queue
+type queued struct {
+	id     int64
+	userID int32
+	Notification
+}
+
+// group is one notification going to many devices
+type group struct {
+	notification Notification
+	ids          []int64
+	tokens       []string
+	devices      map[int64][]string // Each notification's tokens
+}
+
+// Dispatcher sends queued push notifications through a provider
+type Dispatcher struct {
+	db       *sql.DB
+	provider Provider
+}
+
+func NewDispatcher(db *sql.DB, provider Provider) *Dispatcher {
+	return &Dispatcher{db: db, provider: provider}
+}
+
+// Run sends queued notifications every interval until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchOnce(ctx); err != nil {
+			log.Printf("Push dispatch failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce sends a batch of queued notifications and returns how many were
+// sent. Each group's results are recorded as soon as it has been sent.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	pending, err := d.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	userIDs := make([]int32, 0, len(pending))
+	for _, n := range pending {
+		userIDs = append(userIDs, n.userID)
+	}
+	tokens, err := deviceTokens(ctx, d.db, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, g := range groupNotifications(pending, tokens) {
+		failed := make(map[string]string)
+		var unregistered []string
+		for start := 0; start < len(g.tokens); start += d.provider.MaxBatch() {
+			chunk := g.tokens[start:min(start+d.provider.MaxBatch(), len(g.tokens))]
+			response, err := d.provider.Send(ctx, g.notification, chunk)
+			if err != nil {
+				for _, token := range chunk {
+					failed[token] = err.Error()
+				}
+				continue
+			}
+			unregistered = append(unregistered, response.Unregistered...)
+			for _, token := range response.Failed {
+				failed[token] = "push gateway could not reach the device"
+			}
+		}
+
+		delivered, failures := g.results(failed, unregistered)
+		if err := d.record(ctx, delivered, failures, unregistered); err != nil {
+			return sent, err
+		}
+		sent += len(delivered)
+	}
+	return sent, nil
+}
+
+func (d *Dispatcher) claim(ctx context.Context) ([]queued, error) {
+	rows, err := d.db.QueryContext(ctx, claimNotificationsSQL, MaxAttempts, time.Now().Add(-maxAge), batchSize, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error claiming queued notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []queued
+	for rows.Next() {
+		var n queued
+		var data []byte
+		if err := rows.Scan(&n.id, &n.userID, &n.Title, &n.Body, &data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &n.Data); err != nil {
+			return nil, fmt.Errorf("error decoding data for notification %d: %w", n.id, err)
+		}
+		pending = append(pending, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING doesn't keep the subquery's order
+	sort.Slice(pending, func(i, j int) bool { return pending[i].id < pending[j].id })
+	return pending, nil
+}
+
+// record marks a group's notifications sent or failed and forgets unregistered devices
+func (d *Dispatcher) record(ctx context.Context, delivered []int64, failures map[string][]int64, unregistered []string) error {
+	if len(delivered) > 0 {
+		if _, err := d.db.ExecContext(ctx, markSentSQL, pq.Array(delivered)); err != nil {
+			return fmt.Errorf("error marking notifications sent: %w", err)
+		}
+	}
+	for message, ids := range failures {
+		if _, err := d.db.ExecContext(ctx, markFailedSQL, pq.Array(ids), message); err != nil {
+			return fmt.Errorf("error marking notifications failed: %w", err)
+		}
+	}
+	if len(unregistered) > 0 {
+		if _, err := d.db.ExecContext(ctx, deleteTokensSQL, pq.Array(unregistered)); err != nil {
+			return fmt.Errorf("error removing unregistered devices: %w", err)
+		}
+	}
+	return nil
+}
+
+func deviceTokens(ctx context.Context, db *sql.DB, userIDs []int32) (map[int32][]string, error) {
+	rows, err := db.QueryContext(ctx, deviceTokensSQL, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching device tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make(map[int32][]string)
+	for rows.Next() {
+		var userID int32
+		var token string
+		if err := rows.Scan(&userID, &token); err != nil {
+			return nil, err
+		}
+		tokens[userID] = append(tokens[userID], token)
+	}
+	return tokens, rows.Err()
+}
+
+// groupNotifications merges notifications with identical content, e.g. a goal
+// queued for every follower, so they go out as one multicast per batch of devices
+func groupNotifications(pending []queued, tokens map[int32][]string) []*group {
+	var groups []*group
+	byContent := make(map[string]*group)
+	for _, n := range pending {
+		key := contentKey(n.Notification)
+		g, ok := byContent[key]
+		if !ok {
+			g = &group{notification: n.Notification, devices: make(map[int64][]string)}
+			byContent[key] = g
+			groups = append(groups, g)
+		}
+		g.ids = append(g.ids, n.id)
+		g.tokens = append(g.tokens, tokens[n.userID]...)
+		g.devices[n.id] = tokens[n.userID]
+	}
+	return groups
+}
+
+// results splits a group's notifications into those delivered and those to retry,
+// keyed by error. A notification counts as delivered once any of its user's devices
+// got it, since retrying would repeat it on the others. It's retried only when a
+// device failed and none got it; one whose devices were all unregistered (or
+// removed after it was queued) has nothing left to send, so it's marked sent too.
+func (g *group) results(failed map[string]string, unregistered []string) ([]int64, map[string][]int64) {
+	gone := make(map[string]bool, len(unregistered))
+	for _, token := range unregistered {
+		gone[token] = true
+	}
+
+	var delivered []int64
+	failures := make(map[string][]int64)
+	for _, id := range g.ids {
+		message, got := "", false
+		for _, token := range g.devices[id] {
+			if err, ok := failed[token]; ok {
+				message = err
+			} else if !gone[token] {
+				got = true
+			}
+		}
+		if !got && message != "" {
+			failures[message] = append(failures[message], id)
+		} else {
+			delivered = append(delivered, id)
+		}
+	}
+	return delivered, failures
+}
+
+func contentKey(n Notification) string {
+	data, _ := json.Marshal(n.Data) // Map keys are sorted, so equal maps encode the same
+	return strings.Join([]string{n.Title, n.Body, string(data)}, "\x00")
+}
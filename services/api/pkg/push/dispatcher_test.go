@@ -0,0 +1,77 @@
+package push
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGroupNotifications(t *testing.T) {
+	goal := Notification{Title: "Goal!", Body: "Arsenal 1-0 Chelsea", Data: map[string]string{"match_id": "1035", "type": "goal"}}
+	reply := Notification{Title: "New reply", Body: "Sam replied to your comment", Data: map[string]string{"debate_id": "4"}}
+	pending := []queued{
+		{id: 1, userID: 10, Notification: goal},
+		{id: 2, userID: 11, Notification: reply},
+		{id: 3, userID: 12, Notification: Notification{Title: goal.Title, Body: goal.Body, Data: map[string]string{"type": "goal", "match_id": "1035"}}},
+		{id: 4, userID: 13, Notification: goal},
+	}
+	tokens := map[int32][]string{
+		10: {"a", "b"},
+		11: {"c"},
+		12: {"d"},
+	}
+
+	groups := groupNotifications(pending, tokens)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	if !reflect.DeepEqual(groups[0].ids, []int64{1, 3, 4}) {
+		t.Errorf("goal ids = %v, want [1 3 4]", groups[0].ids)
+	}
+	if !reflect.DeepEqual(groups[0].tokens, []string{"a", "b", "d"}) {
+		t.Errorf("goal tokens = %v, want [a b d]", groups[0].tokens)
+	}
+	if !reflect.DeepEqual(groups[1].tokens, []string{"c"}) {
+		t.Errorf("reply tokens = %v, want [c]", groups[1].tokens)
+	}
+}
+
+func TestGroupResults(t *testing.T) {
+	g := &group{
+		ids: []int64{1, 2, 3, 4, 5},
+		devices: map[int64][]string{
+			1: {"a", "b"}, // One device failed, the other got it
+			2: {"c"},      // Failed
+			3: {"d", "e"}, // Unregistered and failed
+			4: {"f"},      // Unregistered
+			5: nil,        // Devices removed after it was queued
+		},
+	}
+	failed := map[string]string{"b": "unavailable", "c": "unavailable", "e": "timeout"}
+
+	delivered, failures := g.results(failed, []string{"d", "f"})
+	if !reflect.DeepEqual(delivered, []int64{1, 4, 5}) {
+		t.Errorf("delivered = %v, want [1 4 5]", delivered)
+	}
+	want := map[string][]int64{"unavailable": {2}, "timeout": {3}}
+	if !reflect.DeepEqual(failures, want) {
+		t.Errorf("failures = %v, want %v", failures, want)
+	}
+}
+
+func TestFakeProvider(t *testing.T) {
+	provider := &FakeProvider{Batch: 2, Unregistered: map[string]bool{"stale": true}}
+	response, err := provider.Send(context.Background(), Notification{Title: "Kick off"}, []string{"a", "stale"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if response.Sent != 1 || !reflect.DeepEqual(response.Unregistered, []string{"stale"}) {
+		t.Errorf("Send() = %+v, want 1 sent and stale unregistered", response)
+	}
+	if provider.MaxBatch() != 2 {
+		t.Errorf("MaxBatch() = %d, want 2", provider.MaxBatch())
+	}
+	if batches := provider.Batches(); len(batches) != 1 || len(batches[0].Tokens) != 2 {
+		t.Errorf("Batches() = %+v, want one batch of 2 tokens", batches)
+	}
+}
@@ -0,0 +1,52 @@
+package push
+
+import (
+	"context"
+	"sync"
+)
+
+// Sent is a batch recorded by FakeProvider
+type Sent struct {
+	Notification Notification
+	Tokens       []string
+}
+
+// FakeProvider records what would have been sent instead of contacting a gateway.
+// It's used in tests and for running the workers locally.
+type FakeProvider struct {
+	mu           sync.Mutex
+	Batch        int             // Defaults to 500
+	Unregistered map[string]bool // Tokens to report as unregistered
+	Sent         []Sent
+}
+
+func (f *FakeProvider) Send(ctx context.Context, notification Notification, tokens []string) (Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Sent = append(f.Sent, Sent{Notification: notification, Tokens: append([]string(nil), tokens...)})
+
+	var response Response
+	for _, token := range tokens {
+		if f.Unregistered[token] {
+			response.Unregistered = append(response.Unregistered, token)
+			continue
+		}
+		response.Sent++
+	}
+	return response, nil
+}
+
+func (f *FakeProvider) MaxBatch() int {
+	if f.Batch > 0 {
+		return f.Batch
+	}
+	return 500
+}
+
+// Batches returns the batches sent so far
+func (f *FakeProvider) Batches() []Sent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Sent(nil), f.Sent...)
+}
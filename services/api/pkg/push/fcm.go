@@ -0,0 +1,276 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultFCMEndpoint is the Firebase Cloud Messaging API host. Firebase relays
+// to APNs for iOS devices.
+const DefaultFCMEndpoint = "https://fcm.googleapis.com"
+
+const (
+	// fcmScope is the OAuth scope needed to send messages
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+	// fcmMaxBatch is how many tokens one Send handles. The v1 API takes one
+	// token per message, so this only bounds how long a Send runs.
+	fcmMaxBatch = 500
+	// fcmConcurrency is how many messages are in flight at once
+	fcmConcurrency = 10
+)
+
+// ServiceAccount is the part of a Google service account key file used to
+// authenticate with FCM
+type ServiceAccount struct {
+	ProjectID    string `json:"project_id"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// FCMProvider sends notifications through the FCM HTTP v1 API
+// (projects/{id}/messages:send), authenticating as a service account
+type FCMProvider struct {
+	account  ServiceAccount
+	endpoint string
+	client   *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMProvider reads a service account key file's JSON. The endpoint defaults
+// to DefaultFCMEndpoint.
+func NewFCMProvider(credentials []byte, endpoint string) (*FCMProvider, error) {
+	var account ServiceAccount
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("error parsing service account: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" || account.TokenURI == "" {
+		return nil, errors.New("service account is missing project_id, client_email, private_key or token_uri")
+	}
+	if _, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey)); err != nil {
+		return nil, fmt.Errorf("error parsing service account private key: %w", err)
+	}
+	if endpoint == "" {
+		endpoint = DefaultFCMEndpoint
+	}
+	return &FCMProvider{
+		account:  account,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// unregistered reports whether FCM rejected the token itself rather than the request
+func (e fcmError) unregistered() bool {
+	if e.Error.Status == "NOT_FOUND" {
+		return true
+	}
+	for _, detail := range e.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *FCMProvider) MaxBatch() int {
+	return fcmMaxBatch
+}
+
+// Send sends a message per token. An error means nothing was sent, e.g. the
+// access token couldn't be fetched; per-token failures are reported in the Response.
+func (p *FCMProvider) Send(ctx context.Context, notification Notification, tokens []string) (Response, error) {
+	accessToken, err := p.token(ctx)
+	if err != nil {
+		return Response{}, err
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		response Response
+	)
+	sem := make(chan struct{}, fcmConcurrency)
+	for _, token := range tokens {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(token string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			unregistered, err := p.send(ctx, accessToken, notification, token)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case unregistered:
+				response.Unregistered = append(response.Unregistered, token)
+			case err != nil:
+				response.Failed = append(response.Failed, token)
+			default:
+				response.Sent++
+			}
+		}(token)
+	}
+	wg.Wait()
+	return response, nil
+}
+
+// send delivers one message, reporting whether FCM no longer recognises the token
+func (p *FCMProvider) send(ctx context.Context, accessToken string, notification Notification, token string) (bool, error) {
+	payload, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: notification.Title, Body: notification.Body},
+		Data:         notification.Data,
+	}})
+	if err != nil {
+		return false, err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", p.endpoint, p.account.ProjectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("error sending push notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("error reading push response: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return false, nil
+	}
+
+	var data fcmError
+	if err := json.Unmarshal(body, &data); err == nil && data.unregistered() {
+		return true, nil
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		p.resetToken()
+	}
+	return false, fmt.Errorf("push gateway returned %d: %s", resp.StatusCode, body)
+}
+
+// token returns a cached OAuth access token, exchanging a signed service
+// account assertion for a new one shortly before it expires
+func (p *FCMProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.accessToken != "" && now.Before(p.expiresAt.Add(-time.Minute)) {
+		return p.accessToken, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(p.account.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("error parsing service account private key: %w", err)
+	}
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	assertion.Header["kid"] = p.account.PrivateKeyID
+	signed, err := assertion.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("error signing service account assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading access token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, body)
+	}
+
+	var data struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("error parsing access token response: %w", err)
+	}
+	if data.AccessToken == "" {
+		return "", errors.New("token endpoint returned no access token")
+	}
+
+	p.accessToken = data.AccessToken
+	p.expiresAt = now.Add(time.Duration(data.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
+
+// resetToken forces the next Send to fetch a new access token
+func (p *FCMProvider) resetToken() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.accessToken = ""
+}
@@ -0,0 +1,186 @@
+package push
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fcmServer fakes Google's token endpoint and the FCM v1 send endpoint
+type fcmServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu          sync.Mutex
+	tokenFetch  int
+	messages    []fcmMessage
+	sendStatus  map[string]int
+	sendReplies map[string]string
+}
+
+func newFCMServer(t *testing.T) *fcmServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	s := &fcmServer{key: key, sendStatus: map[string]int{}, sendReplies: map[string]string{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/token":
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("parse form: %v", err)
+			}
+			claims := jwt.MapClaims{}
+			if _, err := jwt.ParseWithClaims(r.PostForm.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) {
+				return &key.PublicKey, nil
+			}); err != nil {
+				t.Errorf("assertion: %v", err)
+			}
+			if claims["iss"] != "push@fucci.iam.gserviceaccount.com" || claims["scope"] != fcmScope {
+				t.Errorf("claims = %v", claims)
+			}
+			s.tokenFetch++
+			w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+		case "/v1/projects/fucci/messages:send":
+			if r.Header.Get("Authorization") != "Bearer access" {
+				t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+			}
+			var req fcmRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("decode request: %v", err)
+			}
+			s.messages = append(s.messages, req.Message)
+			if status, ok := s.sendStatus[req.Message.Token]; ok {
+				w.WriteHeader(status)
+				w.Write([]byte(s.sendReplies[req.Message.Token]))
+				return
+			}
+			w.Write([]byte(`{"name":"projects/fucci/messages/1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fcmServer) credentials() []byte {
+	key := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(s.key)})
+	credentials, _ := json.Marshal(ServiceAccount{
+		ProjectID:   "fucci",
+		ClientEmail: "push@fucci.iam.gserviceaccount.com",
+		PrivateKey:  string(key),
+		TokenURI:    s.URL + "/token",
+	})
+	return credentials
+}
+
+func TestFCMProviderSend(t *testing.T) {
+	server := newFCMServer(t)
+	server.sendStatus["b"] = http.StatusNotFound
+	server.sendReplies["b"] = `{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`
+	server.sendStatus["c"] = http.StatusServiceUnavailable
+	server.sendReplies["c"] = `{"error":{"code":503,"status":"UNAVAILABLE"}}`
+
+	provider, err := NewFCMProvider(server.credentials(), server.URL)
+	if err != nil {
+		t.Fatalf("NewFCMProvider: %v", err)
+	}
+	response, err := provider.Send(context.Background(), Notification{
+		Title: "Goal!",
+		Body:  "Arsenal 1-0 Chelsea",
+		Data:  map[string]string{"match_id": "1035"},
+	}, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if len(server.messages) != 3 {
+		t.Fatalf("sent %d messages, want one per token", len(server.messages))
+	}
+	for _, message := range server.messages {
+		if message.Notification.Title != "Goal!" || message.Data["match_id"] != "1035" {
+			t.Errorf("message = %+v", message)
+		}
+	}
+	if response.Sent != 1 {
+		t.Errorf("Sent = %d, want 1", response.Sent)
+	}
+	if len(response.Unregistered) != 1 || response.Unregistered[0] != "b" {
+		t.Errorf("Unregistered = %v, want [b]", response.Unregistered)
+	}
+	if len(response.Failed) != 1 || response.Failed[0] != "c" {
+		t.Errorf("Failed = %v, want [c]", response.Failed)
+	}
+
+	// The access token is cached between sends
+	if _, err := provider.Send(context.Background(), Notification{}, []string{"d"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if server.tokenFetch != 1 {
+		t.Errorf("fetched %d access tokens, want 1", server.tokenFetch)
+	}
+}
+
+func TestFCMProviderSendsEveryToken(t *testing.T) {
+	server := newFCMServer(t)
+	provider, err := NewFCMProvider(server.credentials(), server.URL)
+	if err != nil {
+		t.Fatalf("NewFCMProvider: %v", err)
+	}
+
+	tokens := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+	response, err := provider.Send(context.Background(), Notification{Title: "Kick off"}, tokens)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if response.Sent != len(tokens) {
+		t.Errorf("Sent = %d, want %d", response.Sent, len(tokens))
+	}
+	var got []string
+	for _, message := range server.messages {
+		got = append(got, message.Token)
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, tokens) {
+		t.Errorf("sent to %v, want %v", got, tokens)
+	}
+}
+
+func TestFCMProviderTokenError(t *testing.T) {
+	server := newFCMServer(t)
+	credentials := server.credentials()
+
+	provider, err := NewFCMProvider(credentials, server.URL)
+	if err != nil {
+		t.Fatalf("NewFCMProvider: %v", err)
+	}
+	provider.account.TokenURI = server.URL + "/missing"
+
+	if _, err := provider.Send(context.Background(), Notification{}, []string{"a"}); err == nil {
+		t.Error("expected an error when no access token can be fetched")
+	}
+	if len(server.messages) != 0 {
+		t.Errorf("sent %d messages without an access token", len(server.messages))
+	}
+}
+
+func TestNewFCMProviderInvalidCredentials(t *testing.T) {
+	if _, err := NewFCMProvider([]byte(`{"project_id":"fucci"}`), ""); err == nil {
+		t.Error("expected an error for incomplete credentials")
+	}
+}
@@ -0,0 +1,28 @@
+// Package push delivers queued push notifications to devices. Notifications are
+// queued in push_notifications by the API and sent by the notification worker in
+// services/workers through a Provider.
+package push
+
+import "context"
+
+// Notification is the content shown on the device
+type Notification struct {
+	Title string
+	Body  string
+	Data  map[string]string // Delivered to the app alongside the notification, e.g. match_id
+}
+
+// Response reports the outcome of sending a notification to a batch of devices
+type Response struct {
+	Sent         int
+	Failed       []string // Tokens that couldn't be reached this time
+	Unregistered []string // Tokens the provider no longer recognises; they should be forgotten
+}
+
+// Provider sends one notification to many devices in a single call, so a goal
+// doesn't mean a request per follower. FCM and APNs gateways both fit this shape.
+type Provider interface {
+	Send(ctx context.Context, notification Notification, tokens []string) (Response, error)
+	// MaxBatch is the most tokens a single Send accepts
+	MaxBatch() int
+}
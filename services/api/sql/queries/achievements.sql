@@ -0,0 +1,33 @@
+-- name: UnlockAchievement :one
+INSERT INTO user_achievements (user_id, achievement_key)
+VALUES ($1, $2)
+ON CONFLICT (user_id, achievement_key) DO NOTHING
+RETURNING *;
+
+-- name: ListUserAchievements :many
+SELECT * FROM user_achievements
+WHERE user_id = $1
+ORDER BY unlocked_at, id;
+
+-- name: CountUserVotes :one
+SELECT COUNT(*) FROM votes WHERE user_id = sqlc.arg(user_id)::int;
+
+-- name: CountUserComments :one
+SELECT COUNT(*) FROM comments WHERE user_id = sqlc.arg(user_id)::int;
+
+-- name: CountCorrectPredictions :one
+SELECT (
+    (SELECT COUNT(*) FROM prediction_credits pc WHERE pc.user_id = sqlc.arg(user_id)::int)
+  + (SELECT COUNT(*) FROM score_predictions sp WHERE sp.user_id = sqlc.arg(user_id)::int AND sp.points > 0)
+)::bigint AS correct_predictions;
+
+-- name: CountExactScorePredictions :one
+SELECT COUNT(*) FROM score_predictions
+WHERE user_id = $1 AND outcome = 'exact';
+
+-- name: CountVerificationsGiven :one
+SELECT COUNT(*) FROM verifications WHERE verifier_user_id = $1;
+
+-- name: CountVerifiedPlayerProfiles :one
+SELECT COUNT(*) FROM player_profiles
+WHERE user_id = $1 AND is_verified = TRUE;
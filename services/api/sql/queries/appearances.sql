@@ -0,0 +1,112 @@
+-- name: CreateMatchAppearance :exec
+INSERT INTO match_appearances (match_id, team_id, player_profile_id, minutes, assists, yellow_cards, red_card, rating, recorded_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: DeleteTeamAppearances :exec
+DELETE FROM match_appearances WHERE match_id = $1 AND team_id = $2;
+
+-- name: ListMatchAppearances :many
+SELECT ma.*, u.firstname, u.lastname, (
+    SELECT COUNT(*) FROM match_goals g
+    WHERE g.match_id = ma.match_id AND g.player_profile_id = ma.player_profile_id AND NOT g.own_goal
+) AS goals
+FROM match_appearances ma
+JOIN player_profiles pp ON pp.id = ma.player_profile_id
+JOIN users u ON u.id = pp.user_id
+WHERE ma.match_id = $1
+ORDER BY ma.team_id, ma.minutes DESC, u.lastname;
+
+-- name: ListTeamPlayersAt :many
+-- The players on a team at a point in time, from their transfers
+SELECT player_profile_id FROM (
+    SELECT DISTINCT ON (player_profile_id) player_profile_id, to_team_id
+    FROM player_transfers
+    WHERE created_at <= sqlc.arg(at)
+    ORDER BY player_profile_id, created_at DESC
+) latest
+WHERE to_team_id = sqlc.arg(team_id);
+
+-- name: ListPlayerSeasonStats :many
+-- A player's totals per season and team from confirmed fixtures. Leagues
+-- without seasons have a single row per team with no season. A clean sheet
+-- needs 60 minutes without the team conceding.
+WITH stats AS (
+    SELECT m.league_id, m.season_id, ma.team_id,
+           1 AS appearances, ma.minutes, 0 AS goals, ma.assists, ma.yellow_cards,
+           CASE WHEN ma.red_card THEN 1 ELSE 0 END AS red_cards,
+           CASE WHEN ma.minutes >= 60 AND (CASE WHEN ma.team_id = m.home_team_id THEN m.away_score ELSE m.home_score END) = 0 THEN 1 ELSE 0 END AS clean_sheets,
+           ma.rating
+    FROM match_appearances ma
+    JOIN matches m ON m.id = ma.match_id
+    WHERE ma.player_profile_id = sqlc.arg(player_profile_id)
+      AND m.status = 'finished' AND m.home_confirmed_at IS NOT NULL AND m.away_confirmed_at IS NOT NULL
+    UNION ALL
+    SELECT m.league_id, m.season_id, g.team_id, 0, 0, 1, 0, 0, 0, 0, NULL
+    FROM match_goals g
+    JOIN matches m ON m.id = g.match_id
+    WHERE g.player_profile_id = sqlc.arg(player_profile_id) AND NOT g.own_goal
+      AND m.status = 'finished' AND m.home_confirmed_at IS NOT NULL AND m.away_confirmed_at IS NOT NULL
+)
+SELECT l.id AS league_id, l.name AS league_name, s.id AS season_id, s.name AS season_name, t.id AS team_id, t.name AS team_name,
+       SUM(st.appearances)::bigint AS appearances,
+       SUM(st.minutes)::bigint AS minutes,
+       SUM(st.goals)::bigint AS goals,
+       SUM(st.assists)::bigint AS assists,
+       SUM(st.yellow_cards)::bigint AS yellow_cards,
+       SUM(st.red_cards)::bigint AS red_cards,
+       SUM(st.clean_sheets)::bigint AS clean_sheets,
+       COALESCE(SUM(st.rating), 0)::bigint AS rating_total,
+       COUNT(st.rating) AS ratings
+FROM stats st
+JOIN leagues l ON l.id = st.league_id
+LEFT JOIN seasons s ON s.id = st.season_id
+JOIN teams t ON t.id = st.team_id
+GROUP BY l.id, l.name, s.id, s.name, s.start_date, t.id, t.name
+ORDER BY s.start_date DESC NULLS LAST, l.name, t.name;
+
+-- name: ListTopScorers :many
+-- Goals in confirmed fixtures, excluding own goals. team_id is the team the
+-- player last scored for.
+SELECT pp.id AS player_profile_id, u.firstname, u.lastname,
+       (array_agg(g.team_id ORDER BY m.match_date DESC))[1]::uuid AS team_id,
+       COUNT(*) AS goals
+FROM match_goals g
+JOIN matches m ON m.id = g.match_id
+JOIN player_profiles pp ON pp.id = g.player_profile_id
+JOIN users u ON u.id = pp.user_id
+WHERE m.league_id = sqlc.arg(league_id)
+  AND m.status = 'finished' AND m.home_confirmed_at IS NOT NULL AND m.away_confirmed_at IS NOT NULL
+  AND NOT g.own_goal
+  AND (sqlc.narg(season_id)::uuid IS NULL OR m.season_id = sqlc.narg(season_id))
+  AND (sqlc.narg(team_id)::uuid IS NULL OR g.team_id = sqlc.narg(team_id))
+GROUP BY pp.id, u.firstname, u.lastname
+ORDER BY goals DESC, u.lastname, u.firstname
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListTopAssists :many
+-- Assists in confirmed fixtures. team_id is the team the player last
+-- appeared for.
+SELECT pp.id AS player_profile_id, u.firstname, u.lastname,
+       (array_agg(ma.team_id ORDER BY m.match_date DESC))[1]::uuid AS team_id,
+       SUM(ma.assists)::bigint AS assists,
+       COUNT(*) AS appearances
+FROM match_appearances ma
+JOIN matches m ON m.id = ma.match_id
+JOIN player_profiles pp ON pp.id = ma.player_profile_id
+JOIN users u ON u.id = pp.user_id
+WHERE m.league_id = sqlc.arg(league_id)
+  AND m.status = 'finished' AND m.home_confirmed_at IS NOT NULL AND m.away_confirmed_at IS NOT NULL
+  AND (sqlc.narg(season_id)::uuid IS NULL OR m.season_id = sqlc.narg(season_id))
+  AND (sqlc.narg(team_id)::uuid IS NULL OR ma.team_id = sqlc.narg(team_id))
+GROUP BY pp.id, u.firstname, u.lastname
+HAVING SUM(ma.assists) > 0
+ORDER BY assists DESC, appearances, u.lastname, u.firstname
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetTeamRatingTotals :one
+-- Manager ratings of the team's players in confirmed fixtures
+SELECT COALESCE(SUM(ma.rating), 0)::bigint AS rating_total, COUNT(ma.rating) AS ratings
+FROM match_appearances ma
+JOIN matches m ON m.id = ma.match_id
+WHERE ma.team_id = $1
+  AND m.status = 'finished' AND m.home_confirmed_at IS NOT NULL AND m.away_confirmed_at IS NOT NULL;
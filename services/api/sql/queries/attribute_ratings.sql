@@ -0,0 +1,91 @@
+-- name: CreateAttributeHistory :exec
+INSERT INTO player_attribute_history (player_profile_id, source, pace, shooting, passing, stamina, dribbling, defending, physical, raters)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetCommunityAttributes :one
+SELECT * FROM player_community_attributes WHERE player_profile_id = $1;
+
+-- name: GetRaterRelationship :one
+-- How a user knows a player: a teammate on their current team, an opponent
+-- whose team has played theirs, or a manager in their league or of a team
+-- they've had a trial with. Appointed managers own the player's league or
+-- were given the player's own team to manage.
+SELECT
+    EXISTS (
+        SELECT 1 FROM player_profiles rp
+        WHERE rp.user_id = sqlc.arg(rater_id) AND rp.team_id = pp.team_id
+    ) AS teammate,
+    EXISTS (
+        SELECT 1 FROM player_profiles rp
+        JOIN matches m ON m.status = 'finished'
+            AND ((m.home_team_id = rp.team_id AND m.away_team_id = pp.team_id)
+              OR (m.away_team_id = rp.team_id AND m.home_team_id = pp.team_id))
+        WHERE rp.user_id = sqlc.arg(rater_id)
+    ) AS opponent,
+    (
+        EXISTS (
+            SELECT 1 FROM team_managers tm JOIN teams t ON t.league_id = tm.league_id
+            WHERE t.id = pp.team_id AND tm.user_id = sqlc.arg(rater_id)
+        )
+        OR EXISTS (
+            SELECT 1 FROM teams t JOIN leagues l ON l.id = t.league_id
+            WHERE t.id = pp.team_id AND l.owner_id = sqlc.arg(rater_id)
+        )
+        OR EXISTS (
+            SELECT 1 FROM trial_invitations ti JOIN team_managers tm ON tm.team_id = ti.team_id
+            WHERE ti.player_profile_id = pp.id AND ti.status = 'accepted' AND tm.user_id = sqlc.arg(rater_id)
+        )
+    )::boolean AS manager,
+    (
+        EXISTS (
+            SELECT 1 FROM teams t JOIN leagues l ON l.id = t.league_id
+            WHERE t.id = pp.team_id AND l.owner_id = sqlc.arg(rater_id)
+        )
+        OR EXISTS (
+            SELECT 1 FROM team_managers tm
+            WHERE tm.team_id = pp.team_id AND tm.user_id = sqlc.arg(rater_id)
+        )
+    )::boolean AS appointed
+FROM player_profiles pp
+WHERE pp.id = sqlc.arg(player_profile_id);
+
+-- name: ListAttributeHistory :many
+SELECT * FROM player_attribute_history
+WHERE player_profile_id = sqlc.arg(player_profile_id)
+  AND (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source))
+ORDER BY recorded_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAttributeRatings :many
+SELECT * FROM attribute_ratings WHERE player_profile_id = $1;
+
+-- name: UpsertAttributeRating :one
+-- A rater's new rating replaces their last one
+INSERT INTO attribute_ratings (player_profile_id, rater_user_id, relationship, weight, pace, shooting, passing, stamina, dribbling, defending, physical)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (player_profile_id, rater_user_id) DO UPDATE SET
+    relationship = EXCLUDED.relationship,
+    weight = EXCLUDED.weight,
+    pace = EXCLUDED.pace,
+    shooting = EXCLUDED.shooting,
+    passing = EXCLUDED.passing,
+    stamina = EXCLUDED.stamina,
+    dribbling = EXCLUDED.dribbling,
+    defending = EXCLUDED.defending,
+    physical = EXCLUDED.physical,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: UpsertCommunityAttributes :exec
+INSERT INTO player_community_attributes (player_profile_id, pace, shooting, passing, stamina, dribbling, defending, physical, raters)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (player_profile_id) DO UPDATE SET
+    pace = EXCLUDED.pace,
+    shooting = EXCLUDED.shooting,
+    passing = EXCLUDED.passing,
+    stamina = EXCLUDED.stamina,
+    dribbling = EXCLUDED.dribbling,
+    defending = EXCLUDED.defending,
+    physical = EXCLUDED.physical,
+    raters = EXCLUDED.raters,
+    updated_at = CURRENT_TIMESTAMP;
@@ -1,6 +1,6 @@
 -- name: CreateDebate :one
-INSERT INTO debates (match_id, debate_type, headline, description, ai_generated)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO debates (match_id, debate_type, headline, description, ai_generated, author_id, status, league_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: GetDebate :one
@@ -11,6 +11,39 @@ SELECT * FROM debates
 WHERE match_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
+-- name: ListDebatesByMatch :many
+SELECT * FROM debates
+WHERE match_id = sqlc.arg(match_id) AND deleted_at IS NULL
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(ai_generated)::boolean IS NULL OR ai_generated = sqlc.narg(ai_generated))
+ORDER BY created_at DESC;
+
+-- name: CountDebatesByAuthorForMatch :one
+SELECT COUNT(*) FROM debates
+WHERE match_id = $1 AND author_id = $2 AND deleted_at IS NULL;
+
+-- name: ListProposedDebates :many
+SELECT * FROM debates
+WHERE status = 'proposed' AND deleted_at IS NULL
+ORDER BY created_at ASC
+LIMIT $1;
+
+-- name: PromoteDebate :one
+UPDATE debates
+SET status = 'published', promoted_at = CURRENT_TIMESTAMP, promoted_by = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'proposed' AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RejectDebate :one
+UPDATE debates
+SET status = 'rejected', updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'proposed' AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDebateAuthors :many
+SELECT id, firstname, lastname, display_name, avatar_url FROM users
+WHERE id = ANY(sqlc.arg(author_ids)::int[]);
+
 -- name: GetDebatesByType :many
 SELECT * FROM debates 
 WHERE debate_type = $1 AND deleted_at IS NULL
@@ -113,6 +146,17 @@ DELETE FROM comments WHERE id = $1;
 -- name: GetCommentCount :one
 SELECT COUNT(*) FROM comments WHERE debate_id = $1;
 
+-- name: CountDebateParticipants :one
+SELECT COUNT(DISTINCT participants.user_id) FROM (
+    SELECT v.user_id FROM votes v
+    JOIN debate_cards dc ON v.debate_card_id = dc.id
+    WHERE dc.debate_id = $1
+    UNION
+    SELECT c.user_id FROM comments c
+    WHERE c.debate_id = $1
+) participants
+WHERE participants.user_id IS NOT NULL;
+
 -- name: CreateDebateAnalytics :one
 INSERT INTO debate_analytics (debate_id, total_votes, total_comments, engagement_score)
 VALUES ($1, $2, $3, $4)
@@ -128,9 +172,16 @@ RETURNING *;
 SELECT * FROM debate_analytics WHERE debate_id = $1;
 
 -- name: UpdateDebateAnalytics :one
-UPDATE debate_analytics 
-SET total_votes = $2, total_comments = $3, engagement_score = $4, updated_at = CURRENT_TIMESTAMP
-WHERE debate_id = $1
+INSERT INTO debate_analytics (debate_id, total_votes, total_comments, engagement_score, unique_participants, trending_score)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (debate_id)
+DO UPDATE SET
+    total_votes = EXCLUDED.total_votes,
+    total_comments = EXCLUDED.total_comments,
+    engagement_score = EXCLUDED.engagement_score,
+    unique_participants = EXCLUDED.unique_participants,
+    trending_score = EXCLUDED.trending_score,
+    updated_at = CURRENT_TIMESTAMP
 RETURNING *;
 
 -- name: GetTopDebates :many
@@ -141,6 +192,23 @@ SELECT
     da.engagement_score
 FROM debates d
 LEFT JOIN debate_analytics da ON d.id = da.debate_id
-WHERE d.deleted_at IS NULL
+WHERE d.deleted_at IS NULL AND d.status = 'published'
+  AND (sqlc.narg(ai_generated)::boolean IS NULL OR d.ai_generated = sqlc.narg(ai_generated))
 ORDER BY da.engagement_score DESC NULLS LAST
-LIMIT $1; 
\ No newline at end of file
+LIMIT sqlc.arg(max_results); 
+
+-- name: GetTrendingDebates :many
+SELECT
+    d.*,
+    da.total_votes,
+    da.total_comments,
+    da.engagement_score,
+    da.unique_participants,
+    da.trending_score
+FROM debates d
+JOIN debate_analytics da ON d.id = da.debate_id
+WHERE d.deleted_at IS NULL AND d.status = 'published'
+  AND (sqlc.narg(since)::timestamp IS NULL OR d.created_at >= sqlc.narg(since))
+  AND (sqlc.narg(league_id)::int IS NULL OR d.league_id = sqlc.narg(league_id))
+ORDER BY da.trending_score DESC, d.id DESC
+LIMIT sqlc.arg(max_results);
@@ -0,0 +1,18 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (user_id, email, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UseEmailVerification :one
+-- Each link works once, and only until it expires
+UPDATE email_verifications
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING *;
+
+-- name: GetVerifiedEmail :one
+-- The user's email, lower cased, if they've verified it
+SELECT ev.email FROM email_verifications ev
+JOIN users u ON u.id = ev.user_id
+WHERE ev.user_id = $1 AND ev.used_at IS NOT NULL AND ev.email = LOWER(u.email)
+LIMIT 1;
@@ -0,0 +1,48 @@
+-- name: ListDebateFeedCandidates :many
+SELECT
+    d.*,
+    COALESCE(da.total_votes, 0)::int AS total_votes,
+    COALESCE(da.total_comments, 0)::int AS total_comments,
+    EXISTS (
+        SELECT 1 FROM user_follows uf
+        WHERE uf.user_id = sqlc.arg(user_id)
+          AND (
+            (uf.followable_type = 'match' AND uf.followable_id IN (
+                SELECT m.id FROM matches m WHERE m.external_match_id = d.match_id
+            ))
+            -- Teams playing in the fixture, matched on name since API-Football
+            -- fixtures have no local team IDs
+            OR (uf.followable_type = 'team' AND uf.followable_id IN (
+                SELECT t.id FROM fixture_teams ft
+                JOIN teams t ON LOWER(t.name) IN (LOWER(ft.home_team), LOWER(ft.away_team))
+                WHERE ft.match_id = d.match_id
+            ))
+            OR (uf.followable_type = 'player' AND uf.followable_id IN (
+                SELECT pp.id FROM fixture_teams ft
+                JOIN teams t ON LOWER(t.name) IN (LOWER(ft.home_team), LOWER(ft.away_team))
+                JOIN player_profiles pp ON pp.team_id = t.id
+                WHERE ft.match_id = d.match_id
+            ))
+          )
+    ) AS is_followed,
+    EXISTS (
+        SELECT 1 FROM votes v
+        JOIN debate_cards dc ON v.debate_card_id = dc.id
+        WHERE dc.debate_id = d.id AND v.user_id = sqlc.arg(user_id)
+    ) AS has_voted,
+    (
+        SELECT COUNT(*) FROM votes v
+        JOIN debate_cards dc ON v.debate_card_id = dc.id
+        WHERE dc.debate_id = d.id AND v.created_at >= sqlc.arg(velocity_since)::timestamp
+    ) AS recent_votes,
+    (
+        SELECT COUNT(*) FROM comments c
+        WHERE c.debate_id = d.id AND c.created_at >= sqlc.arg(velocity_since)::timestamp
+    ) AS recent_comments
+FROM debates d
+LEFT JOIN debate_analytics da ON d.id = da.debate_id
+WHERE d.deleted_at IS NULL
+  AND d.status = 'published'
+  AND d.created_at >= sqlc.arg(since)::timestamp
+ORDER BY d.created_at DESC
+LIMIT sqlc.arg(max_results);
@@ -0,0 +1,5 @@
+-- name: UpsertFixtureTeams :exec
+INSERT INTO fixture_teams (match_id, home_team, away_team)
+VALUES (sqlc.arg(match_id), sqlc.arg(home_team), sqlc.arg(away_team))
+ON CONFLICT (match_id) DO UPDATE
+SET home_team = EXCLUDED.home_team, away_team = EXCLUDED.away_team, updated_at = CURRENT_TIMESTAMP;
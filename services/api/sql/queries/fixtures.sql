@@ -0,0 +1,73 @@
+-- name: CreateFixture :one
+INSERT INTO matches (league_id, home_team_id, away_team_id, match_date, venue, created_by, round, season_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: DeleteUnplayedLeagueFixtures :execrows
+DELETE FROM matches
+WHERE league_id = sqlc.arg(league_id)
+  AND external_match_id IS NULL
+  AND (sqlc.narg(season_id)::uuid IS NULL OR season_id = sqlc.narg(season_id))
+  AND status NOT IN ('live', 'finished');
+
+-- name: GetFixture :one
+SELECT * FROM matches WHERE id = $1 AND external_match_id IS NULL;
+
+-- name: ListLeagueFixtures :many
+SELECT * FROM matches
+WHERE league_id = sqlc.arg(league_id)
+  AND external_match_id IS NULL
+  AND (sqlc.narg(status)::match_status IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(team_id)::uuid IS NULL OR home_team_id = sqlc.narg(team_id) OR away_team_id = sqlc.narg(team_id))
+  AND (sqlc.narg(season_id)::uuid IS NULL OR season_id = sqlc.narg(season_id))
+ORDER BY match_date, id;
+
+-- name: UpdateFixtureSchedule :one
+UPDATE matches
+SET match_date = $2, venue = $3, status = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: RecordFixtureResult :one
+-- Entering a result clears confirmations except from the side entering it
+UPDATE matches
+SET status = 'finished',
+    home_score = sqlc.arg(home_score),
+    away_score = sqlc.arg(away_score),
+    result_entered_by = sqlc.arg(entered_by),
+    home_confirmed_at = CASE WHEN sqlc.arg(home_confirmed)::boolean THEN NOW() END,
+    away_confirmed_at = CASE WHEN sqlc.arg(away_confirmed)::boolean THEN NOW() END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ConfirmFixtureResult :one
+UPDATE matches
+SET home_confirmed_at = CASE WHEN sqlc.arg(home)::boolean THEN COALESCE(home_confirmed_at, NOW()) ELSE home_confirmed_at END,
+    away_confirmed_at = CASE WHEN sqlc.arg(away)::boolean THEN COALESCE(away_confirmed_at, NOW()) ELSE away_confirmed_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = 'finished'
+RETURNING *;
+
+-- name: CreateMatchGoal :exec
+INSERT INTO match_goals (match_id, team_id, player_profile_id, scorer_name, minute, own_goal, penalty)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: DeleteMatchGoals :exec
+DELETE FROM match_goals WHERE match_id = $1;
+
+-- name: ListMatchGoals :many
+SELECT * FROM match_goals WHERE match_id = $1 ORDER BY minute NULLS LAST, id;
+
+-- name: IsTeamManager :one
+-- Whether the user manages the team in one of roles
+SELECT (
+    EXISTS (
+        SELECT 1 FROM teams t JOIN team_managers tm ON tm.id = t.manager_id
+        WHERE t.id = sqlc.arg(team_id) AND tm.user_id = sqlc.arg(user_id) AND tm.role = ANY(sqlc.arg(roles)::text[])
+    )
+    OR EXISTS (
+        SELECT 1 FROM team_managers tm
+        WHERE tm.team_id = sqlc.arg(team_id) AND tm.user_id = sqlc.arg(user_id) AND tm.role = ANY(sqlc.arg(roles)::text[])
+    )
+)::boolean AS is_manager;
@@ -0,0 +1,41 @@
+-- name: FollowEntity :execrows
+INSERT INTO user_follows (user_id, followable_type, followable_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, followable_type, followable_id) DO NOTHING;
+
+-- name: UnfollowEntity :execrows
+DELETE FROM user_follows
+WHERE user_id = $1 AND followable_type = $2 AND followable_id = $3;
+
+-- name: ListUserFollows :many
+SELECT uf.followable_type, uf.followable_id, m.external_match_id, uf.created_at
+FROM user_follows uf
+LEFT JOIN matches m ON uf.followable_type = 'match' AND m.id = uf.followable_id
+WHERE uf.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(followable_type)::followable_type IS NULL OR uf.followable_type = sqlc.narg(followable_type))
+ORDER BY uf.created_at DESC, uf.id;
+
+-- name: ListFollowers :many
+SELECT user_id FROM user_follows
+WHERE followable_type = $1 AND followable_id = $2
+ORDER BY created_at, id;
+
+-- name: CountFollowers :many
+SELECT followable_id, COUNT(*) AS followers FROM user_follows
+WHERE followable_type = sqlc.arg(followable_type) AND followable_id = ANY(sqlc.arg(followable_ids)::uuid[])
+GROUP BY followable_id;
+
+-- name: ListFollowedAmong :many
+SELECT followable_id FROM user_follows
+WHERE user_id = sqlc.arg(user_id)
+  AND followable_type = sqlc.arg(followable_type)
+  AND followable_id = ANY(sqlc.arg(followable_ids)::uuid[]);
+
+-- name: GetMatchIDByExternalID :one
+SELECT id FROM matches WHERE external_match_id = $1;
+
+-- name: UpsertExternalMatch :one
+INSERT INTO matches (external_match_id, match_date)
+VALUES ($1, $2)
+ON CONFLICT (external_match_id) DO UPDATE SET match_date = EXCLUDED.match_date, updated_at = NOW()
+RETURNING id;
@@ -0,0 +1,25 @@
+-- name: CreateImageAsset :one
+INSERT INTO image_assets (owner_type, owner_id, url, storage_keys)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ReplaceImageAssets :execrows
+UPDATE image_assets SET replaced_at = CURRENT_TIMESTAMP
+WHERE owner_type = $1 AND owner_id = $2 AND replaced_at IS NULL;
+
+-- name: MarkDetachedImageAssets :execrows
+-- Assets whose owner was deleted or whose URL was overwritten by hand
+UPDATE image_assets a SET replaced_at = CURRENT_TIMESTAMP
+WHERE a.replaced_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM users u WHERE a.owner_type = 'user_avatar' AND u.id::text = a.owner_id AND u.avatar_url = a.url)
+  AND NOT EXISTS (SELECT 1 FROM teams t WHERE a.owner_type = 'team_logo' AND t.id::text = a.owner_id AND t.logo_url = a.url)
+  AND NOT EXISTS (SELECT 1 FROM leagues l WHERE a.owner_type = 'league_logo' AND l.id::text = a.owner_id AND l.logo_url = a.url);
+
+-- name: ListReplacedImageAssets :many
+SELECT * FROM image_assets
+WHERE replaced_at < sqlc.arg(replaced_before)
+ORDER BY replaced_at
+LIMIT sqlc.arg(max_results);
+
+-- name: DeleteImageAsset :exec
+DELETE FROM image_assets WHERE id = $1;
@@ -0,0 +1,48 @@
+-- name: CreatePointAward :one
+INSERT INTO point_awards (user_id, source_type, source_id, points, league_id, team_ids)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, source_type, source_id) DO NOTHING
+RETURNING *;
+
+-- name: LockUserPoints :exec
+-- Holds the user's point awards until the transaction ends, so the daily
+-- engagement cap is checked and spent one award at a time
+SELECT pg_advisory_xact_lock(hashtext('point_awards'), $1);
+
+-- name: GetEngagementPointsSince :one
+SELECT COALESCE(SUM(points), 0)::bigint FROM point_awards
+WHERE user_id = $1
+  AND source_type IN ('vote', 'comment')
+  AND awarded_at >= sqlc.arg(since)::timestamp;
+
+-- name: ListPredictionCredits :many
+SELECT * FROM prediction_credits WHERE debate_id = $1;
+
+-- name: ListResolvedDebatesByMatch :many
+SELECT d.* FROM debates d
+JOIN debate_resolutions dr ON dr.debate_id = d.id
+WHERE d.match_id = $1;
+
+-- name: SumPointsByUser :many
+SELECT pa.user_id, SUM(pa.points)::bigint AS points
+FROM point_awards pa
+WHERE (sqlc.narg(since)::timestamp IS NULL OR pa.awarded_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamp IS NULL OR pa.awarded_at < sqlc.narg(until))
+  AND (sqlc.narg(league_id)::int IS NULL OR pa.league_id = sqlc.narg(league_id))
+  AND (sqlc.narg(team_id)::uuid IS NULL OR sqlc.narg(team_id) = ANY(pa.team_ids))
+GROUP BY pa.user_id;
+
+-- name: ListAwardedLeagues :many
+SELECT DISTINCT league_id FROM point_awards
+WHERE league_id IS NOT NULL;
+
+-- name: ListFollowedTeamIDs :many
+SELECT followable_id FROM user_follows
+WHERE user_id = $1 AND followable_type = 'team';
+
+-- name: ListAwardedTeams :many
+SELECT DISTINCT unnest(team_ids)::uuid AS team_id FROM point_awards;
+
+-- name: GetUserSummaries :many
+SELECT id, firstname, lastname, display_name, avatar_url FROM users
+WHERE id = ANY($1::int[]);
@@ -22,4 +22,7 @@ WHERE id = $1
 RETURNING *;
 
 -- name: DeleteLeague :exec
-DELETE FROM leagues WHERE id = $1; 
\ No newline at end of file
+DELETE FROM leagues WHERE id = $1; 
+
+-- name: UpdateLeagueLogo :exec
+UPDATE leagues SET logo_url = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
@@ -0,0 +1,34 @@
+-- name: CreateManagerInvitation :one
+INSERT INTO manager_invitations (league_id, team_id, email, invitee_id, role, invited_by, message, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetManagerInvitation :one
+SELECT * FROM manager_invitations WHERE id = $1;
+
+-- name: ListTeamManagerInvitations :many
+SELECT * FROM manager_invitations
+WHERE team_id = $1 AND status = 'pending'
+ORDER BY created_at DESC;
+
+-- name: ListManagerInvitationsByInvitee :many
+SELECT mi.*, t.name AS team_name, l.name AS league_name
+FROM manager_invitations mi
+JOIN teams t ON t.id = mi.team_id
+JOIN leagues l ON l.id = mi.league_id
+WHERE mi.invitee_id = $1 AND mi.status = 'pending'
+ORDER BY mi.created_at DESC;
+
+-- name: BindManagerInvitations :exec
+-- Hands the pending invitations sent to an email to the account that just
+-- verified it
+UPDATE manager_invitations
+SET invitee_id = sqlc.arg(invitee_id)
+WHERE email = sqlc.arg(email) AND invitee_id IS NULL AND status = 'pending';
+
+-- name: RespondToManagerInvitation :one
+-- Only pending invitations can be accepted, declined or cancelled
+UPDATE manager_invitations
+SET status = sqlc.arg(status), accepted_by = sqlc.narg(accepted_by), responded_at = NOW()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
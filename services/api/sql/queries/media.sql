@@ -1,10 +1,13 @@
 -- name: CreateMedia :one
-INSERT INTO media (match_id, media_url)
-VALUES ($1, $2)
+INSERT INTO media (match_id, media_url, user_id, media_type, content_type, size_bytes, storage_key, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
+-- name: GetMedia :one
+SELECT * FROM media WHERE id = $1;
+
 -- name: GetMediaByMatchId :many
-SELECT * FROM media WHERE match_id = $1 ORDER BY created_at DESC;
+SELECT * FROM media WHERE match_id = $1 AND status <> 'failed' ORDER BY created_at DESC;
 
 -- name: DeleteMediaById :exec
 DELETE FROM media WHERE id = $1;
@@ -0,0 +1,59 @@
+-- name: UpsertNewsFeed :one
+INSERT INTO news_feeds (name, url)
+VALUES ($1, $2)
+ON CONFLICT (url)
+DO UPDATE SET name = $1, is_active = TRUE, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListActiveNewsFeeds :many
+SELECT * FROM news_feeds WHERE is_active = TRUE ORDER BY id;
+
+-- name: MarkNewsFeedPolled :exec
+UPDATE news_feeds
+SET last_polled_at = CURRENT_TIMESTAMP, last_error = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: CreateNewsArticle :one
+INSERT INTO news_articles (feed_id, guid, url, title, summary, publisher, image_url, content_hash, published_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: TagNewsArticle :exec
+INSERT INTO news_article_teams (article_id, team_name)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListTeamAliases :many
+SELECT * FROM team_aliases ORDER BY team_name, alias;
+
+-- name: ListTeamNames :many
+-- Every team is tagged by its own name, aliases or not
+SELECT DISTINCT name FROM teams ORDER BY name;
+
+-- name: CreateTeamAlias :exec
+INSERT INTO team_aliases (team_name, alias)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListNewsArticlesByTeam :many
+SELECT a.* FROM news_articles a
+JOIN news_article_teams nat ON a.id = nat.article_id
+WHERE nat.team_name = $1 AND a.published_at >= $2
+ORDER BY a.published_at DESC
+LIMIT $3;
+
+-- name: ListNewsArticlesByTeams :many
+SELECT a.* FROM news_articles a
+WHERE a.published_at >= sqlc.arg(since)
+  AND EXISTS (SELECT 1 FROM news_article_teams t1 WHERE t1.article_id = a.id AND t1.team_name = sqlc.arg(home_team))
+  AND EXISTS (SELECT 1 FROM news_article_teams t2 WHERE t2.article_id = a.id AND t2.team_name = sqlc.arg(away_team))
+ORDER BY a.published_at DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: SearchNewsArticles :many
+SELECT a.* FROM news_articles a
+WHERE a.title ILIKE sqlc.arg(pattern) OR a.summary ILIKE sqlc.arg(pattern)
+   OR EXISTS (SELECT 1 FROM news_article_teams nat WHERE nat.article_id = a.id AND nat.team_name ILIKE sqlc.arg(pattern))
+ORDER BY a.published_at DESC
+LIMIT sqlc.arg(max_results);
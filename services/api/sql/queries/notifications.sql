@@ -0,0 +1,34 @@
+-- name: CreateNotification :execrows
+INSERT INTO notifications (user_id, notification_type, title, body, data, dedupe_key)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, dedupe_key) DO NOTHING;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+  AND (NOT sqlc.arg(unread_only)::bool OR read_at IS NULL)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: DeleteExpiredNotifications :execrows
+DELETE FROM notifications
+WHERE created_at < sqlc.arg(created_before)
+   OR (read_at IS NOT NULL AND read_at < sqlc.arg(read_before));
+
+-- name: CountDebateVotes :one
+SELECT COUNT(*) FROM votes v
+JOIN debate_cards dc ON v.debate_card_id = dc.id
+WHERE dc.debate_id = $1::int;
@@ -45,15 +45,17 @@ WHERE pp.team_id IS NULL
 ORDER BY pp.created_at DESC;
 
 -- name: UpdatePlayerProfile :one
+-- Team changes go through rosters, see UpdatePlayerTeam
 UPDATE player_profiles 
 SET 
-  team_id = $2, position = $3, age = $4, country = $5, height_cm = $6,
-  pace = $7, shooting = $8, passing = $9, stamina = $10, dribbling = $11, 
-  defending = $12, physical = $13, updated_at = CURRENT_TIMESTAMP
+  position = $2, age = $3, country = $4, height_cm = $5,
+  pace = $6, shooting = $7, passing = $8, stamina = $9, dribbling = $10, 
+  defending = $11, physical = $12, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
 -- name: UpdatePlayerTeam :one
+-- Record a player_transfers row alongside every change
 UPDATE player_profiles 
 SET team_id = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
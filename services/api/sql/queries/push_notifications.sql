@@ -0,0 +1,45 @@
+-- name: UpsertDeviceToken :one
+INSERT INTO device_tokens (user_id, token, platform)
+VALUES ($1, $2, $3)
+ON CONFLICT (token) DO UPDATE
+SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteDeviceToken :execrows
+DELETE FROM device_tokens WHERE user_id = $1 AND token = $2;
+
+-- name: ListNotificationPreferences :many
+SELECT notification_type, enabled FROM notification_preferences
+WHERE user_id = $1;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, notification_type, enabled)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, notification_type) DO UPDATE
+SET enabled = EXCLUDED.enabled, updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListMatchFollowers :many
+-- Users following the fixture or either team in it. API-Football fixtures have
+-- no local team IDs, so teams are matched on the names the event carries.
+SELECT DISTINCT uf.user_id FROM user_follows uf
+WHERE (uf.followable_type = 'match' AND uf.followable_id IN (
+        SELECT m.id FROM matches m WHERE m.external_match_id = sqlc.arg(match_id)
+    ))
+   OR (uf.followable_type = 'team' AND uf.followable_id IN (
+        SELECT t.id FROM teams t
+        WHERE LOWER(t.name) IN (LOWER(sqlc.arg(home_team)::text), LOWER(sqlc.arg(away_team)::text))
+    ));
+
+-- name: EnqueuePushNotifications :execrows
+-- Queues a notification for each user who has a device and hasn't opted out of the type
+INSERT INTO push_notifications (user_id, notification_type, title, body, data, dedupe_key)
+SELECT u.user_id, sqlc.arg(notification_type)::text, sqlc.arg(title)::text, sqlc.arg(body)::text, sqlc.arg(data)::jsonb, sqlc.arg(dedupe_key)::text
+FROM unnest(sqlc.arg(user_ids)::int[]) AS u(user_id)
+WHERE EXISTS (SELECT 1 FROM device_tokens dt WHERE dt.user_id = u.user_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM notification_preferences np
+    WHERE np.user_id = u.user_id
+      AND np.notification_type = sqlc.arg(notification_type)
+      AND NOT np.enabled
+  )
+ON CONFLICT (user_id, dedupe_key) DO NOTHING;
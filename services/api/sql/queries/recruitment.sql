@@ -0,0 +1,99 @@
+-- name: SearchPlayerProfiles :many
+-- Unset filters match everyone. sort is age, height, overall or an
+-- attribute, and anything else lists the newest profiles first. Attributes
+-- are community ratings where a player has them, see attribute_ratings.sql.
+SELECT pp.*, u.firstname, u.lastname, COUNT(*) OVER () AS total_count
+FROM (
+    -- Community ratings stand in for self-reported attributes once published
+    SELECT p.id, p.user_id, p.team_id, p.position, p.age, p.country, p.height_cm,
+           COALESCE(ca.pace, p.pace) AS pace,
+           COALESCE(ca.shooting, p.shooting) AS shooting,
+           COALESCE(ca.passing, p.passing) AS passing,
+           COALESCE(ca.stamina, p.stamina) AS stamina,
+           COALESCE(ca.dribbling, p.dribbling) AS dribbling,
+           COALESCE(ca.defending, p.defending) AS defending,
+           COALESCE(ca.physical, p.physical) AS physical,
+           p.is_verified, p.created_at, p.updated_at, ca.raters AS community_raters
+    FROM player_profiles p
+    LEFT JOIN player_community_attributes ca ON ca.player_profile_id = p.id
+) pp
+JOIN users u ON u.id = pp.user_id
+WHERE (NOT sqlc.arg(free_agents_only)::bool OR pp.team_id IS NULL)
+  AND (sqlc.narg(position)::text IS NULL OR LOWER(pp.position) = LOWER(sqlc.narg(position)))
+  AND (sqlc.narg(country)::text IS NULL OR LOWER(pp.country) = LOWER(sqlc.narg(country)))
+  AND (sqlc.narg(min_age)::int IS NULL OR pp.age >= sqlc.narg(min_age))
+  AND (sqlc.narg(max_age)::int IS NULL OR pp.age <= sqlc.narg(max_age))
+  AND (sqlc.narg(min_height)::int IS NULL OR pp.height_cm >= sqlc.narg(min_height))
+  AND (sqlc.narg(max_height)::int IS NULL OR pp.height_cm <= sqlc.narg(max_height))
+  AND pp.pace >= sqlc.arg(min_pace)
+  AND pp.shooting >= sqlc.arg(min_shooting)
+  AND pp.passing >= sqlc.arg(min_passing)
+  AND pp.stamina >= sqlc.arg(min_stamina)
+  AND pp.dribbling >= sqlc.arg(min_dribbling)
+  AND pp.defending >= sqlc.arg(min_defending)
+  AND pp.physical >= sqlc.arg(min_physical)
+  AND (NOT sqlc.arg(verified_only)::bool OR pp.is_verified)
+ORDER BY
+  CASE sqlc.arg(sort)::text
+    WHEN 'age' THEN pp.age
+    WHEN 'height' THEN pp.height_cm
+    WHEN 'pace' THEN pp.pace
+    WHEN 'shooting' THEN pp.shooting
+    WHEN 'passing' THEN pp.passing
+    WHEN 'stamina' THEN pp.stamina
+    WHEN 'dribbling' THEN pp.dribbling
+    WHEN 'defending' THEN pp.defending
+    WHEN 'physical' THEN pp.physical
+    WHEN 'overall' THEN pp.pace + pp.shooting + pp.passing + pp.stamina + pp.dribbling + pp.defending + pp.physical
+  END * CASE WHEN sqlc.arg(descending)::bool THEN -1 ELSE 1 END,
+  pp.created_at DESC,
+  pp.id
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: AddShortlistedPlayer :execrows
+INSERT INTO player_shortlists (team_id, player_profile_id, added_by, note)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (team_id, player_profile_id) DO NOTHING;
+
+-- name: RemoveShortlistedPlayer :execrows
+DELETE FROM player_shortlists WHERE team_id = $1 AND player_profile_id = $2;
+
+-- name: ListTeamShortlist :many
+SELECT pp.*, u.firstname, u.lastname, ps.note, ps.created_at AS shortlisted_at
+FROM player_shortlists ps
+JOIN player_profiles pp ON pp.id = ps.player_profile_id
+JOIN users u ON u.id = pp.user_id
+WHERE ps.team_id = $1
+ORDER BY ps.created_at DESC;
+
+-- name: CreateTrialInvitation :one
+INSERT INTO trial_invitations (team_id, player_profile_id, invited_by, trial_at, location, message)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetTrialInvitation :one
+SELECT * FROM trial_invitations WHERE id = $1;
+
+-- name: ListTeamTrialInvitations :many
+-- Upcoming trials that haven't been cancelled
+SELECT ti.*, u.firstname, u.lastname
+FROM trial_invitations ti
+JOIN player_profiles pp ON pp.id = ti.player_profile_id
+JOIN users u ON u.id = pp.user_id
+WHERE ti.team_id = $1 AND ti.status <> 'cancelled' AND ti.trial_at >= NOW()
+ORDER BY ti.trial_at;
+
+-- name: ListPlayerTrialInvitations :many
+-- Upcoming trials the player hasn't turned down
+SELECT ti.*, t.name AS team_name
+FROM trial_invitations ti
+JOIN teams t ON t.id = ti.team_id
+WHERE ti.player_profile_id = $1 AND ti.status IN ('pending', 'accepted') AND ti.trial_at >= NOW()
+ORDER BY ti.trial_at;
+
+-- name: RespondToTrialInvitation :one
+-- Only pending invitations can be accepted, declined or cancelled
+UPDATE trial_invitations
+SET status = $2, responded_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,45 @@
+-- name: CreateDebateResolution :one
+INSERT INTO debate_resolutions (
+    debate_id, winning_card_id, method, resolved_by, evidence, confidence,
+    final_status, home_score, away_score, final_stats
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (debate_id) DO NOTHING
+RETURNING *;
+
+-- name: GetDebateResolution :one
+SELECT * FROM debate_resolutions WHERE debate_id = $1;
+
+-- name: CreditPredictionVoters :execrows
+INSERT INTO prediction_credits (user_id, debate_id, debate_card_id, points)
+SELECT DISTINCT v.user_id, dc.debate_id, dc.id, sqlc.arg(points)::int
+FROM votes v
+JOIN debate_cards dc ON v.debate_card_id = dc.id
+WHERE dc.id = sqlc.arg(debate_card_id)
+  AND v.vote_type = 'upvote'
+  AND v.user_id IS NOT NULL
+  AND v.created_at < sqlc.arg(kickoff)::timestamp
+ON CONFLICT (user_id, debate_id) DO NOTHING;
+
+-- name: LockDebate :one
+-- Holds the debate's row until the transaction ends, so a user's votes on it
+-- are checked one at a time
+SELECT debate_type FROM debates WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- name: GetPredictionUpvote :one
+-- The card the user has already backed in a prediction debate
+SELECT v.debate_card_id FROM votes v
+JOIN debate_cards dc ON v.debate_card_id = dc.id
+WHERE dc.debate_id = sqlc.arg(debate_id) AND v.user_id = sqlc.arg(user_id) AND v.vote_type = 'upvote'
+LIMIT 1;
+
+-- name: ListUnresolvedPredictionDebates :many
+SELECT d.* FROM debates d
+LEFT JOIN debate_resolutions dr ON dr.debate_id = d.id
+WHERE d.debate_type = 'pre_match'
+  AND d.status = 'published'
+  AND d.deleted_at IS NULL
+  AND dr.id IS NULL
+  AND d.created_at >= sqlc.arg(since)::timestamp
+ORDER BY d.created_at ASC
+LIMIT sqlc.arg(max_results);
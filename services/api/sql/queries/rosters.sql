@@ -0,0 +1,68 @@
+-- name: CountTeamPlayers :one
+SELECT COUNT(*) FROM player_profiles WHERE team_id = $1;
+
+-- name: LockTeam :one
+-- Holds the team's row until the transaction ends, so changes to its roster
+-- happen one at a time
+SELECT league_id FROM teams WHERE id = $1 FOR UPDATE;
+
+-- name: LockPlayerTeam :one
+-- The player's current team, with their profile held until the transaction ends
+SELECT team_id FROM player_profiles WHERE id = $1 FOR UPDATE;
+
+-- name: CreateRosterInvitation :one
+INSERT INTO roster_invitations (team_id, player_profile_id, invited_by, message, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetRosterInvitation :one
+SELECT * FROM roster_invitations WHERE id = $1;
+
+-- name: ListTeamRosterInvitations :many
+SELECT ri.*, u.firstname, u.lastname
+FROM roster_invitations ri
+JOIN player_profiles pp ON pp.id = ri.player_profile_id
+JOIN users u ON u.id = pp.user_id
+WHERE ri.team_id = $1 AND ri.status = 'pending'
+ORDER BY ri.created_at DESC;
+
+-- name: ListPlayerRosterInvitations :many
+SELECT ri.*, t.name AS team_name
+FROM roster_invitations ri
+JOIN teams t ON t.id = ri.team_id
+WHERE ri.player_profile_id = $1 AND ri.status = 'pending'
+ORDER BY ri.created_at DESC;
+
+-- name: RespondToRosterInvitation :one
+-- Only pending invitations can be accepted, declined or cancelled
+UPDATE roster_invitations
+SET status = $2, responded_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: CreatePlayerTransfer :one
+INSERT INTO player_transfers (player_profile_id, from_team_id, to_team_id, season_id, invitation_id, recorded_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListPlayerTransfers :many
+SELECT pt.*, ft.name AS from_team_name, tt.name AS to_team_name
+FROM player_transfers pt
+LEFT JOIN teams ft ON ft.id = pt.from_team_id
+LEFT JOIN teams tt ON tt.id = pt.to_team_id
+WHERE pt.player_profile_id = $1
+ORDER BY pt.created_at DESC;
+
+-- name: GetLeagueRosterRules :one
+SELECT * FROM league_roster_rules WHERE league_id = $1;
+
+-- name: UpsertLeagueRosterRules :one
+INSERT INTO league_roster_rules (league_id, max_players)
+VALUES ($1, $2)
+ON CONFLICT (league_id) DO UPDATE
+SET max_players = EXCLUDED.max_players,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteLeagueRosterRules :exec
+DELETE FROM league_roster_rules WHERE league_id = $1;
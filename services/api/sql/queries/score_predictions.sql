@@ -0,0 +1,42 @@
+-- name: UpsertScorePrediction :one
+-- Writes nothing once kickoff has passed, by either the fixture's current
+-- kickoff or the one stored with the prediction. Kickoffs are stored in UTC.
+INSERT INTO score_predictions (user_id, match_id, home_goals, away_goals, league_id, kickoff_at)
+SELECT $1, $2, $3, $4, $5, $6::timestamp
+WHERE $6::timestamp > (NOW() AT TIME ZONE 'UTC')
+ON CONFLICT (user_id, match_id) DO UPDATE
+SET home_goals = EXCLUDED.home_goals,
+    away_goals = EXCLUDED.away_goals,
+    kickoff_at = EXCLUDED.kickoff_at,
+    updated_at = CURRENT_TIMESTAMP
+WHERE score_predictions.scored_at IS NULL
+  AND score_predictions.kickoff_at > (NOW() AT TIME ZONE 'UTC')
+RETURNING *;
+
+-- name: GetUserScorePrediction :one
+SELECT * FROM score_predictions
+WHERE user_id = $1 AND match_id = $2;
+
+-- name: GetScorePredictionDistribution :many
+SELECT home_goals, away_goals, COUNT(*) AS predictions
+FROM score_predictions
+WHERE match_id = $1
+GROUP BY home_goals, away_goals
+ORDER BY predictions DESC, home_goals, away_goals;
+
+-- name: ListScorePredictionsByMatch :many
+SELECT * FROM score_predictions
+WHERE match_id = $1
+ORDER BY id;
+
+-- name: ScoreScorePrediction :exec
+UPDATE score_predictions
+SET points = $2, outcome = $3, scored_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListUnscoredPredictionMatches :many
+SELECT match_id FROM score_predictions
+WHERE scored_at IS NULL AND kickoff_at < sqlc.arg(before)::timestamp
+GROUP BY match_id
+ORDER BY MIN(kickoff_at)
+LIMIT sqlc.arg(max_results);
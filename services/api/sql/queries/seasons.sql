@@ -0,0 +1,76 @@
+-- name: CreateSeason :one
+INSERT INTO seasons (league_id, name, start_date, end_date, registration_opens_at, registration_closes_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetSeason :one
+SELECT * FROM seasons WHERE id = $1;
+
+-- name: ListLeagueSeasons :many
+SELECT * FROM seasons
+WHERE league_id = sqlc.arg(league_id)
+  AND (sqlc.arg(include_archived)::boolean OR NOT archived)
+ORDER BY start_date DESC;
+
+-- name: GetCurrentSeason :one
+-- The latest season to have started by today, or the next to start if none
+-- has. Archived seasons are only picked when every season is archived.
+SELECT * FROM seasons
+WHERE league_id = sqlc.arg(league_id)
+ORDER BY archived,
+    start_date <= sqlc.arg(today)::date DESC,
+    CASE WHEN start_date <= sqlc.arg(today)::date THEN start_date END DESC NULLS LAST,
+    start_date
+LIMIT 1;
+
+-- name: GetVerificationSeason :one
+-- The season running today, or the next to start when the league is between
+-- seasons. Seasons that have ended or are archived are never picked.
+SELECT * FROM seasons
+WHERE league_id = sqlc.arg(league_id)
+  AND NOT archived
+  AND end_date >= sqlc.arg(today)::date
+ORDER BY start_date
+LIMIT 1;
+
+-- name: UpdateSeason :one
+UPDATE seasons
+SET name = $2,
+    start_date = $3,
+    end_date = $4,
+    registration_opens_at = $5,
+    registration_closes_at = $6,
+    archived = $7,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: AddSeasonTeam :execrows
+INSERT INTO season_teams (season_id, team_id, registered_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (season_id, team_id) DO NOTHING;
+
+-- name: AddLeagueTeamsToSeason :execrows
+INSERT INTO season_teams (season_id, team_id, registered_by)
+SELECT sqlc.arg(season_id), id, sqlc.arg(registered_by) FROM teams WHERE league_id = sqlc.arg(league_id)
+ON CONFLICT (season_id, team_id) DO NOTHING;
+
+-- name: RemoveSeasonTeam :execrows
+DELETE FROM season_teams WHERE season_id = $1 AND team_id = $2;
+
+-- name: ListSeasonTeams :many
+SELECT t.* FROM teams t
+JOIN season_teams st ON st.team_id = t.id
+WHERE st.season_id = $1
+ORDER BY t.name;
+
+-- name: CreateSeasonRegistrationWindow :one
+INSERT INTO season_registration_windows (season_id, opens_at, closes_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListSeasonRegistrationWindows :many
+SELECT * FROM season_registration_windows WHERE season_id = $1 ORDER BY opens_at;
+
+-- name: DeleteSeasonRegistrationWindow :execrows
+DELETE FROM season_registration_windows WHERE id = $1 AND season_id = $2;
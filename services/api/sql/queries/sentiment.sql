@@ -0,0 +1,20 @@
+-- name: ListRecentCommentsForSentiment :many
+SELECT c.id, c.content, c.created_at
+FROM comments c
+JOIN debates d ON c.debate_id = d.id
+WHERE d.deleted_at IS NULL
+  AND d.match_id = ANY(sqlc.arg(match_ids)::text[])
+  AND c.created_at >= sqlc.arg(since)::timestamp
+ORDER BY c.created_at DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListRecentCardVotesForSentiment :many
+SELECT v.vote_type, v.emoji, dc.title, dc.description, v.created_at
+FROM votes v
+JOIN debate_cards dc ON v.debate_card_id = dc.id
+JOIN debates d ON dc.debate_id = d.id
+WHERE d.deleted_at IS NULL
+  AND d.match_id = ANY(sqlc.arg(match_ids)::text[])
+  AND v.created_at >= sqlc.arg(since)::timestamp
+ORDER BY v.created_at DESC
+LIMIT sqlc.arg(max_results);
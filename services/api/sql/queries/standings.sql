@@ -0,0 +1,25 @@
+-- name: GetLeagueStandingsRules :one
+SELECT * FROM league_standings_rules WHERE league_id = $1;
+
+-- name: UpsertLeagueStandingsRules :one
+INSERT INTO league_standings_rules (league_id, points_win, points_draw, points_loss, tiebreakers)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (league_id) DO UPDATE
+SET points_win = EXCLUDED.points_win,
+    points_draw = EXCLUDED.points_draw,
+    points_loss = EXCLUDED.points_loss,
+    tiebreakers = EXCLUDED.tiebreakers,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListConfirmedLeagueResults :many
+-- Results only count towards the table once both sides have confirmed them
+SELECT home_team_id, away_team_id, home_score, away_score, match_date
+FROM matches
+WHERE league_id = sqlc.arg(league_id)
+  AND external_match_id IS NULL
+  AND status = 'finished'
+  AND home_confirmed_at IS NOT NULL
+  AND away_confirmed_at IS NOT NULL
+  AND (sqlc.narg(season_id)::uuid IS NULL OR season_id = sqlc.narg(season_id))
+ORDER BY match_date, id;
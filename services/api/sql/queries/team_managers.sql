@@ -1,6 +1,6 @@
 -- name: CreateTeamManager :one
-INSERT INTO team_managers (user_id, league_id, team_id, title, experience, bio)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO team_managers (user_id, league_id, team_id, title, experience, bio, role)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: GetTeamManager :one
@@ -16,7 +16,7 @@ LIMIT $4 OFFSET $5;
 
 -- name: UpdateTeamManager :one
 UPDATE team_managers 
-SET team_id = $2, title = $3, experience = $4, bio = $5, updated_at = CURRENT_TIMESTAMP
+SET title = $2, experience = $3, bio = $4, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
@@ -29,4 +29,24 @@ SELECT * FROM team_managers WHERE league_id = $1 ORDER BY created_at DESC;
 -- name: ListTeamsByManager :many
 SELECT t.* FROM teams t
 JOIN team_managers tm ON t.id = tm.team_id
-WHERE tm.user_id = $1; 
\ No newline at end of file
+WHERE tm.user_id = $1;
+
+-- name: ListTeamManagersByTeam :many
+SELECT tm.*, u.firstname, u.lastname, u.email
+FROM team_managers tm
+JOIN users u ON u.id = tm.user_id
+WHERE tm.team_id = $1
+ORDER BY tm.created_at;
+
+-- name: GetLeagueManagerByUser :one
+-- A user manages at most one team per league
+SELECT * FROM team_managers WHERE league_id = $1 AND user_id = $2
+ORDER BY created_at
+LIMIT 1;
+
+-- name: AssignTeamManager :one
+-- Moves a manager onto a team, or changes their role on it
+UPDATE team_managers
+SET team_id = $2, role = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
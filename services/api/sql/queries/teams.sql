@@ -28,4 +28,29 @@ RETURNING *;
 DELETE FROM teams WHERE id = $1;
 
 -- name: GetTeamsByLeague :many
-SELECT * FROM teams WHERE league_id = $1 ORDER BY name;
\ No newline at end of file
+SELECT * FROM teams WHERE league_id = $1 ORDER BY name;
+
+-- name: UpdateTeamLogo :exec
+UPDATE teams SET logo_url = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: UnsetTeamManager :exec
+-- Clears a manager from any team naming them as its manager_id, before they're removed
+UPDATE teams SET manager_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE manager_id = $1;
+
+-- name: CanManageTeam :one
+-- Managers of the team with one of roles, and the owner of its league, can
+-- manage it
+SELECT (
+    EXISTS (
+        SELECT 1 FROM teams t JOIN team_managers tm ON tm.id = t.manager_id
+        WHERE t.id = sqlc.arg(team_id) AND tm.user_id = sqlc.arg(user_id) AND tm.role = ANY(sqlc.arg(roles)::text[])
+    )
+    OR EXISTS (
+        SELECT 1 FROM team_managers tm
+        WHERE tm.team_id = sqlc.arg(team_id) AND tm.user_id = sqlc.arg(user_id) AND tm.role = ANY(sqlc.arg(roles)::text[])
+    )
+    OR EXISTS (
+        SELECT 1 FROM teams t JOIN leagues l ON l.id = t.league_id
+        WHERE t.id = sqlc.arg(team_id) AND l.owner_id = sqlc.arg(user_id)
+    )
+)::boolean AS can_manage;
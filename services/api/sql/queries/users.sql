@@ -19,4 +19,22 @@ WHERE id = $1
 RETURNING *;
 
 -- name: DeleteUser :exec
-DELETE FROM users WHERE id = $1;
\ No newline at end of file
+DELETE FROM users WHERE id = $1;
+
+-- name: UpdateUserAvatar :exec
+UPDATE users SET avatar_url = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: PromoteToTeamManager :exec
+-- Admins keep their role
+UPDATE users SET role = 'team_manager', updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND role = 'fan';
+
+-- name: DemoteTeamManager :execrows
+-- Only once the user no longer manages any team
+UPDATE users SET role = 'fan', updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND role = 'team_manager'
+  AND NOT EXISTS (SELECT 1 FROM team_managers tm WHERE tm.user_id = $1);
+
+-- name: FindUserByEmail :one
+-- Case-insensitive, for addresses typed in by someone else
+SELECT * FROM users WHERE LOWER(email) = LOWER($1) ORDER BY id LIMIT 1;
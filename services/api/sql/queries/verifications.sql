@@ -1,16 +1,43 @@
 -- name: CreateVerification :one
-INSERT INTO verifications (player_profile_id, verifier_user_id)
-VALUES ($1, $2)
+INSERT INTO verifications (player_profile_id, verifier_user_id, team_id, league_id, season_id, verifier_role, expires_at, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: GetVerification :one
 SELECT * FROM verifications WHERE id = $1;
 
 -- name: ListVerificationsByPlayer :many
-SELECT * FROM verifications WHERE player_profile_id = $1;
+SELECT * FROM verifications WHERE player_profile_id = $1 ORDER BY created_at DESC;
 
--- name: CountVerificationsByPlayer :one
-SELECT COUNT(*) FROM verifications WHERE player_profile_id = $1;
+-- name: CountQualifyingVerifications :one
+-- Verifiers with a role whose verification hasn't lapsed
+SELECT COUNT(DISTINCT verifier_user_id) FROM verifications
+WHERE player_profile_id = sqlc.arg(player_profile_id)
+  AND verifier_role IS NOT NULL
+  AND (expires_at IS NULL OR expires_at > sqlc.arg(now)::timestamp);
+
+-- name: GetVerifierEligibility :one
+-- Managers in the league, including its owner, and verified players on its
+-- teams can verify the league's players
+SELECT (
+    EXISTS (SELECT 1 FROM team_managers tm WHERE tm.user_id = sqlc.arg(verifier_id) AND tm.league_id = sqlc.arg(league_id))
+    OR EXISTS (SELECT 1 FROM leagues l WHERE l.id = sqlc.arg(league_id) AND l.owner_id = sqlc.arg(verifier_id))
+)::boolean AS manager,
+EXISTS (
+    SELECT 1 FROM player_profiles pp JOIN teams t ON t.id = pp.team_id
+    WHERE pp.user_id = sqlc.arg(verifier_id) AND pp.is_verified AND t.league_id = sqlc.arg(league_id)
+) AS verified_player;
+
+-- name: ListLapsedVerifiedProfiles :many
+-- Verified players who no longer have enough qualifying verifications
+SELECT pp.id FROM player_profiles pp
+WHERE pp.is_verified
+  AND (
+      SELECT COUNT(DISTINCT v.verifier_user_id) FROM verifications v
+      WHERE v.player_profile_id = pp.id
+        AND v.verifier_role IS NOT NULL
+        AND (v.expires_at IS NULL OR v.expires_at > sqlc.arg(now)::timestamp)
+  ) < sqlc.arg(threshold)::int;
 
 -- name: DeleteVerification :exec
 DELETE FROM verifications WHERE id = $1;
@@ -18,4 +45,25 @@ DELETE FROM verifications WHERE id = $1;
 -- name: UpdatePlayerVerificationStatus :exec
 UPDATE player_profiles 
 SET is_verified = $2, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1; 
\ No newline at end of file
+WHERE id = $1;
+
+-- name: CreateVerificationEvidence :one
+INSERT INTO verification_evidence (verification_id, uploaded_by, media_type, content_type, url, storage_key, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListVerificationEvidence :many
+SELECT * FROM verification_evidence WHERE verification_id = $1 ORDER BY created_at;
+
+-- name: ListPlayerVerificationEvidence :many
+SELECT ve.* FROM verification_evidence ve
+JOIN verifications v ON v.id = ve.verification_id
+WHERE v.player_profile_id = $1
+ORDER BY ve.created_at;
+
+-- name: CreateVerificationAuditEntry :exec
+INSERT INTO verification_audit_log (player_profile_id, was_verified, is_verified, reason, verification_id, actor_user_id, verifications)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListVerificationAuditLog :many
+SELECT * FROM verification_audit_log WHERE player_profile_id = $1 ORDER BY created_at DESC, id DESC;
@@ -0,0 +1,87 @@
+-- +goose Up
+-- Create news_feeds table for RSS/Atom sources polled by the news ingester
+CREATE TABLE IF NOT EXISTS news_feeds (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_polled_at TIMESTAMP NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create news_articles table for normalized, deduplicated articles
+CREATE TABLE IF NOT EXISTS news_articles (
+    id SERIAL PRIMARY KEY,
+    feed_id INTEGER REFERENCES news_feeds(id) ON DELETE SET NULL,
+    guid TEXT,
+    url TEXT NOT NULL UNIQUE, -- Normalized URL (tracking params stripped)
+    title TEXT NOT NULL,
+    summary TEXT,
+    publisher VARCHAR(100),
+    image_url TEXT,
+    content_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the normalized title, catches syndicated copies
+    published_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create team_aliases table used to tag articles to teams
+CREATE TABLE IF NOT EXISTS team_aliases (
+    id SERIAL PRIMARY KEY,
+    team_name VARCHAR(100) NOT NULL,
+    alias VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(team_name, alias)
+);
+
+-- Create news_article_teams join table
+CREATE TABLE IF NOT EXISTS news_article_teams (
+    article_id INTEGER NOT NULL REFERENCES news_articles(id) ON DELETE CASCADE,
+    team_name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (article_id, team_name)
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_news_articles_published_at ON news_articles(published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_feed_id ON news_articles(feed_id);
+CREATE INDEX IF NOT EXISTS idx_news_article_teams_team_name ON news_article_teams(team_name);
+CREATE INDEX IF NOT EXISTS idx_team_aliases_team_name ON team_aliases(team_name);
+
+-- Seed common aliases used by headline writers
+INSERT INTO team_aliases (team_name, alias) VALUES
+    ('Arsenal', 'Gunners'),
+    ('Manchester United', 'Man Utd'),
+    ('Manchester United', 'Man United'),
+    ('Manchester City', 'Man City'),
+    ('Tottenham', 'Spurs'),
+    ('Tottenham', 'Tottenham Hotspur'),
+    ('Newcastle', 'Newcastle United'),
+    ('Newcastle', 'Magpies'),
+    ('West Ham', 'Hammers'),
+    ('Wolves', 'Wolverhampton'),
+    ('Real Madrid', 'Los Blancos'),
+    ('Barcelona', 'Barca'),
+    ('Barcelona', 'Barça'),
+    ('Atletico Madrid', 'Atleti'),
+    ('Atletico Madrid', 'Atlético Madrid'),
+    ('Bayern Munich', 'Bayern'),
+    ('Bayern Munich', 'Bayern München'),
+    ('Borussia Dortmund', 'Dortmund'),
+    ('Paris Saint Germain', 'PSG'),
+    ('Paris Saint Germain', 'Paris Saint-Germain'),
+    ('Inter', 'Inter Milan'),
+    ('Inter', 'Internazionale'),
+    ('AC Milan', 'Rossoneri'),
+    ('Juventus', 'Juve')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_team_aliases_team_name;
+DROP INDEX IF EXISTS idx_news_article_teams_team_name;
+DROP INDEX IF EXISTS idx_news_articles_feed_id;
+DROP INDEX IF EXISTS idx_news_articles_published_at;
+DROP TABLE IF EXISTS news_article_teams;
+DROP TABLE IF EXISTS team_aliases;
+DROP TABLE IF EXISTS news_articles;
+DROP TABLE IF EXISTS news_feeds;
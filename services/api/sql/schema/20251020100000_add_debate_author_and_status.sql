@@ -0,0 +1,19 @@
+-- +goose Up
+-- Track who proposed a debate and whether it has been promoted to the main feed.
+-- Existing (AI generated) debates are already live, so they default to 'published'.
+ALTER TABLE debates ADD COLUMN IF NOT EXISTS author_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE debates ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('proposed', 'published', 'rejected'));
+ALTER TABLE debates ADD COLUMN IF NOT EXISTS promoted_at TIMESTAMP NULL;
+ALTER TABLE debates ADD COLUMN IF NOT EXISTS promoted_by INTEGER REFERENCES users(id) ON DELETE SET NULL; -- NULL when promoted by engagement
+
+CREATE INDEX IF NOT EXISTS idx_debates_status ON debates(status);
+CREATE INDEX IF NOT EXISTS idx_debates_match_author ON debates(match_id, author_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_debates_match_author;
+DROP INDEX IF EXISTS idx_debates_status;
+ALTER TABLE debates DROP COLUMN IF EXISTS promoted_by;
+ALTER TABLE debates DROP COLUMN IF EXISTS promoted_at;
+ALTER TABLE debates DROP COLUMN IF EXISTS status;
+ALTER TABLE debates DROP COLUMN IF EXISTS author_id;
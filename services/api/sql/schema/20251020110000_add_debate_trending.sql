@@ -0,0 +1,30 @@
+-- +goose Up
+-- UpdateDebateAnalytics (and CreateDebateAnalytics) upsert on debate_id, which needs a unique constraint.
+-- Keep the most recent row for any debate that was double counted.
+DELETE FROM debate_analytics older
+USING debate_analytics newer
+WHERE older.debate_id = newer.debate_id AND older.id < newer.id;
+
+DROP INDEX IF EXISTS idx_debate_analytics_debate_id;
+ALTER TABLE debate_analytics ADD CONSTRAINT debate_analytics_debate_id_key UNIQUE (debate_id);
+
+-- DECIMAL(5,2) overflows once a debate passes 999.99 engagement
+ALTER TABLE debate_analytics ALTER COLUMN engagement_score TYPE NUMERIC(12,2);
+
+ALTER TABLE debate_analytics ADD COLUMN IF NOT EXISTS unique_participants INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE debate_analytics ADD COLUMN IF NOT EXISTS trending_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_debate_analytics_trending_score ON debate_analytics(trending_score DESC);
+
+-- API-Football league ID of the debated match, used to filter trending debates
+ALTER TABLE debates ADD COLUMN IF NOT EXISTS league_id INTEGER NULL;
+CREATE INDEX IF NOT EXISTS idx_debates_league_created ON debates(league_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_debates_league_created;
+ALTER TABLE debates DROP COLUMN IF EXISTS league_id;
+DROP INDEX IF EXISTS idx_debate_analytics_trending_score;
+ALTER TABLE debate_analytics DROP COLUMN IF EXISTS trending_score;
+ALTER TABLE debate_analytics DROP COLUMN IF EXISTS unique_participants;
+ALTER TABLE debate_analytics ALTER COLUMN engagement_score TYPE DECIMAL(5,2);
+ALTER TABLE debate_analytics DROP CONSTRAINT IF EXISTS debate_analytics_debate_id_key;
+CREATE INDEX IF NOT EXISTS idx_debate_analytics_debate_id ON debate_analytics(debate_id);
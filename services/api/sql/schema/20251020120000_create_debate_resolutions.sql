@@ -0,0 +1,34 @@
+-- +goose Up
+-- Outcome of a pre-match prediction debate, decided by the AI or an admin after full time
+CREATE TABLE IF NOT EXISTS debate_resolutions (
+    id SERIAL PRIMARY KEY,
+    debate_id INTEGER NOT NULL UNIQUE REFERENCES debates(id) ON DELETE CASCADE,
+    winning_card_id INTEGER NOT NULL REFERENCES debate_cards(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL CHECK (method IN ('ai', 'admin')),
+    resolved_by INTEGER REFERENCES users(id) ON DELETE SET NULL, -- NULL when resolved by the AI
+    evidence TEXT NOT NULL,
+    confidence DOUBLE PRECISION, -- Only set for AI resolutions, 0 to 1
+    final_status VARCHAR(10) NOT NULL, -- FT, AET or PEN
+    home_score INTEGER NOT NULL,
+    away_score INTEGER NOT NULL,
+    final_stats JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Points awarded to users who voted for the stance that came true
+CREATE TABLE IF NOT EXISTS prediction_credits (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    debate_id INTEGER NOT NULL REFERENCES debates(id) ON DELETE CASCADE,
+    debate_card_id INTEGER NOT NULL REFERENCES debate_cards(id) ON DELETE CASCADE,
+    points INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, debate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prediction_credits_user_id ON prediction_credits(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_prediction_credits_user_id;
+DROP TABLE IF EXISTS prediction_credits;
+DROP TABLE IF EXISTS debate_resolutions;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Ledger of every point a fan has earned. Leaderboards in Redis are rebuilt from here.
+CREATE TABLE IF NOT EXISTS point_awards (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_type VARCHAR(30) NOT NULL
+        CHECK (source_type IN ('prediction', 'score_prediction', 'vote', 'comment')),
+    source_id VARCHAR(64) NOT NULL, -- e.g. the debate, prediction, vote or comment ID
+    points INTEGER NOT NULL,
+    league_id INTEGER NULL, -- API-Football league the points were earned in
+    awarded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Re-running a matchday must not award the same thing twice
+    UNIQUE (user_id, source_type, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_awards_user_awarded ON point_awards(user_id, awarded_at);
+CREATE INDEX IF NOT EXISTS idx_point_awards_league_awarded ON point_awards(league_id, awarded_at);
+CREATE INDEX IF NOT EXISTS idx_point_awards_awarded_at ON point_awards(awarded_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_point_awards_awarded_at;
+DROP INDEX IF EXISTS idx_point_awards_league_awarded;
+DROP INDEX IF EXISTS idx_point_awards_user_awarded;
+DROP TABLE IF EXISTS point_awards;
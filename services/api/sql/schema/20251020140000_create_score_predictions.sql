@@ -0,0 +1,25 @@
+-- +goose Up
+-- Fans' exact scoreline predictions for API-Football fixtures
+CREATE TABLE IF NOT EXISTS score_predictions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    match_id VARCHAR(50) NOT NULL, -- API-Football fixture ID
+    home_goals INTEGER NOT NULL CHECK (home_goals >= 0),
+    away_goals INTEGER NOT NULL CHECK (away_goals >= 0),
+    league_id INTEGER NULL,
+    kickoff_at TIMESTAMP NOT NULL, -- Predictions are locked from here on
+    points INTEGER NULL, -- Set once the match has been scored
+    outcome VARCHAR(20) NULL CHECK (outcome IN ('exact', 'goal_difference', 'result', 'miss')),
+    scored_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, match_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_score_predictions_match_id ON score_predictions(match_id);
+CREATE INDEX IF NOT EXISTS idx_score_predictions_unscored ON score_predictions(kickoff_at) WHERE scored_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_score_predictions_unscored;
+DROP INDEX IF EXISTS idx_score_predictions_match_id;
+DROP TABLE IF EXISTS score_predictions;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Achievements unlocked by each user. The rules themselves live in code (internal/achievements).
+CREATE TABLE IF NOT EXISTS user_achievements (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_key VARCHAR(50) NOT NULL,
+    unlocked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, achievement_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_achievements_user_id ON user_achievements(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_achievements_user_id;
+DROP TABLE IF EXISTS user_achievements;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Transactional outbox: events are written in the same transaction as the change
+-- they describe and relayed to subscribers afterwards (see pkg/events)
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_type_created ON event_outbox(event_type, created_at);
+
+-- Each consumer (api, workers) records which events it has handled
+CREATE TABLE IF NOT EXISTS event_deliveries (
+    event_id BIGINT NOT NULL REFERENCES event_outbox(id) ON DELETE CASCADE,
+    consumer VARCHAR(50) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    delivered_at TIMESTAMP NULL,
+    PRIMARY KEY (event_id, consumer)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS event_deliveries;
+DROP INDEX IF EXISTS idx_event_outbox_type_created;
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- Devices registered for push notifications. A token belongs to the last user who registered it.
+CREATE TABLE IF NOT EXISTS device_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE,
+    platform VARCHAR(10) NOT NULL CHECK (platform IN ('ios', 'android', 'web')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user_id ON device_tokens(user_id);
+
+-- Per-type opt outs. A missing row means the notification type is enabled.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notification_type VARCHAR(30) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, notification_type)
+);
+
+-- Push notifications waiting to be delivered by the notification worker (services/workers).
+-- dedupe_key stops a re-delivered event from notifying the same user twice.
+CREATE TABLE IF NOT EXISTS push_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notification_type VARCHAR(30) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    dedupe_key VARCHAR(100) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    sent_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, dedupe_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_notifications_pending ON push_notifications(created_at) WHERE sent_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_push_notifications_pending;
+DROP TABLE IF EXISTS push_notifications;
+DROP TABLE IF EXISTS notification_preferences;
+DROP INDEX IF EXISTS idx_device_tokens_user_id;
+DROP TABLE IF EXISTS device_tokens;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Uploaded match media. Files live in the blob store under storage_key; the
+-- workers generate a thumbnail and move uploads from processing to ready.
+ALTER TABLE media
+    ALTER COLUMN media_url TYPE TEXT,
+    ADD COLUMN user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN media_type VARCHAR(10) NOT NULL DEFAULT 'image' CHECK (media_type IN ('image', 'video')),
+    ADD COLUMN content_type VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN size_bytes BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN storage_key TEXT,
+    ADD COLUMN thumbnail_key TEXT,
+    ADD COLUMN thumbnail_url TEXT,
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ready' CHECK (status IN ('processing', 'ready', 'failed')),
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_media_processing ON media(id) WHERE status = 'processing';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_media_processing;
+ALTER TABLE media
+    DROP COLUMN last_error,
+    DROP COLUMN attempts,
+    DROP COLUMN status,
+    DROP COLUMN thumbnail_url,
+    DROP COLUMN thumbnail_key,
+    DROP COLUMN storage_key,
+    DROP COLUMN size_bytes,
+    DROP COLUMN content_type,
+    DROP COLUMN media_type,
+    DROP COLUMN user_id,
+    ALTER COLUMN media_url TYPE VARCHAR(255);
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Uploaded avatars and logos. Each upload is stored at several sizes; once an
+-- asset is replaced (or its URL is overwritten) its files are garbage-collected.
+CREATE TABLE IF NOT EXISTS image_assets (
+    id BIGSERIAL PRIMARY KEY,
+    owner_type VARCHAR(20) NOT NULL CHECK (owner_type IN ('user_avatar', 'team_logo', 'league_logo')),
+    owner_id VARCHAR(50) NOT NULL, -- users.id, teams.id or leagues.id
+    url TEXT NOT NULL,
+    storage_keys TEXT[] NOT NULL,
+    replaced_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_assets_current ON image_assets(owner_type, owner_id) WHERE replaced_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_image_assets_replaced_at ON image_assets(replaced_at) WHERE replaced_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_image_assets_replaced_at;
+DROP INDEX IF EXISTS idx_image_assets_current;
+DROP TABLE IF EXISTS image_assets;
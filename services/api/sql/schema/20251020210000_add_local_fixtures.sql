@@ -0,0 +1,36 @@
+-- +goose Up
+-- Fixtures between local teams live in matches alongside followed API-Football
+-- fixtures, without an external ID. A result counts once both sides confirm it.
+ALTER TABLE matches
+    ALTER COLUMN external_match_id DROP NOT NULL,
+    ADD COLUMN created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN result_entered_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN home_confirmed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN away_confirmed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS match_goals (
+    id BIGSERIAL PRIMARY KEY,
+    match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE, -- The team credited with the goal
+    player_profile_id UUID REFERENCES player_profiles(id) ON DELETE SET NULL,
+    scorer_name VARCHAR(100), -- For scorers without a profile
+    minute INTEGER CHECK (minute >= 0 AND minute <= 130),
+    own_goal BOOLEAN NOT NULL DEFAULT FALSE,
+    penalty BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_goals_match_id ON match_goals(match_id);
+CREATE INDEX IF NOT EXISTS idx_match_goals_player_profile_id ON match_goals(player_profile_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_match_goals_player_profile_id;
+DROP INDEX IF EXISTS idx_match_goals_match_id;
+DROP TABLE IF EXISTS match_goals;
+DELETE FROM matches WHERE external_match_id IS NULL;
+ALTER TABLE matches
+    DROP COLUMN away_confirmed_at,
+    DROP COLUMN home_confirmed_at,
+    DROP COLUMN result_entered_by,
+    DROP COLUMN created_by,
+    ALTER COLUMN external_match_id SET NOT NULL;
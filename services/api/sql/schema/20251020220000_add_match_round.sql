@@ -0,0 +1,6 @@
+-- +goose Up
+-- The round of a generated league schedule a fixture belongs to
+ALTER TABLE matches ADD COLUMN round INTEGER;
+
+-- +goose Down
+ALTER TABLE matches DROP COLUMN round;
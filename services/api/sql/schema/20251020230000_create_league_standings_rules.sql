@@ -0,0 +1,15 @@
+-- +goose Up
+-- How a local league's table is worked out. Leagues without a row use
+-- standings.DefaultRules.
+CREATE TABLE IF NOT EXISTS league_standings_rules (
+    league_id UUID PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+    points_win INTEGER NOT NULL DEFAULT 3,
+    points_draw INTEGER NOT NULL DEFAULT 1,
+    points_loss INTEGER NOT NULL DEFAULT 0,
+    tiebreakers TEXT[] NOT NULL DEFAULT '{goal_difference,goals_for,head_to_head}', -- Applied in order after points
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (points_win > points_draw AND points_draw >= points_loss AND points_loss >= 0)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS league_standings_rules;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- Seasons of a local league. Teams register for a season, and fixtures and
+-- standings belong to one.
+CREATE TABLE IF NOT EXISTS seasons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    league_id UUID NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL, -- e.g. 2025/26
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    registration_opens_at TIMESTAMP WITH TIME ZONE,
+    registration_closes_at TIMESTAMP WITH TIME ZONE,
+    archived BOOLEAN NOT NULL DEFAULT FALSE, -- Archived seasons are read-only
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (league_id, name),
+    CHECK (end_date >= start_date),
+    CHECK (registration_closes_at IS NULL OR registration_opens_at IS NULL OR registration_closes_at > registration_opens_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_seasons_league_id ON seasons(league_id, start_date);
+
+CREATE TABLE IF NOT EXISTS season_teams (
+    season_id UUID NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    registered_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (season_id, team_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_season_teams_team_id ON season_teams(team_id);
+
+-- Fixtures from before seasons existed keep a NULL season
+ALTER TABLE matches ADD COLUMN season_id UUID REFERENCES seasons(id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_matches_season_id ON matches(season_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_matches_season_id;
+ALTER TABLE matches DROP COLUMN season_id;
+DROP INDEX IF EXISTS idx_season_teams_team_id;
+DROP TABLE IF EXISTS season_teams;
+DROP INDEX IF EXISTS idx_seasons_league_id;
+DROP TABLE IF EXISTS seasons;
@@ -0,0 +1,73 @@
+-- +goose Up
+-- How many players each of a league's teams can register. Leagues without a
+-- row have no limit.
+CREATE TABLE IF NOT EXISTS league_roster_rules (
+    league_id UUID PRIMARY KEY REFERENCES leagues(id) ON DELETE CASCADE,
+    max_players INTEGER NOT NULL CHECK (max_players > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- When players can join teams during a season. Seasons without any windows
+-- are open all season.
+CREATE TABLE IF NOT EXISTS season_registration_windows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    season_id UUID NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    opens_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    closes_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_season_registration_windows_season_id ON season_registration_windows(season_id, opens_at);
+
+-- A team manager asking a player to join. Pending invitations past
+-- expires_at can no longer be accepted.
+CREATE TABLE IF NOT EXISTS roster_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    message TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One open invitation per player per team
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roster_invitations_pending ON roster_invitations(team_id, player_profile_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_roster_invitations_player ON roster_invitations(player_profile_id, status);
+
+-- Every change of a player's team. The team a player was on at any time is
+-- to_team_id of their latest transfer before it.
+CREATE TABLE IF NOT EXISTS player_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    from_team_id UUID REFERENCES teams(id) ON DELETE SET NULL, -- NULL when joining as a free agent
+    to_team_id UUID REFERENCES teams(id) ON DELETE SET NULL,   -- NULL when released or leaving
+    season_id UUID REFERENCES seasons(id) ON DELETE SET NULL,
+    invitation_id UUID REFERENCES roster_invitations(id) ON DELETE SET NULL,
+    recorded_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_transfers_player ON player_transfers(player_profile_id, created_at);
+
+-- Players already on a team start their history when their profile was made
+INSERT INTO player_transfers (player_profile_id, to_team_id, created_at)
+SELECT id, team_id, created_at FROM player_profiles WHERE team_id IS NOT NULL;
+
+-- Verifications stay with the team the player was on when they were given
+ALTER TABLE verifications ADD COLUMN team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+UPDATE verifications v SET team_id = pp.team_id FROM player_profiles pp WHERE pp.id = v.player_profile_id;
+
+-- +goose Down
+ALTER TABLE verifications DROP COLUMN team_id;
+DROP INDEX IF EXISTS idx_player_transfers_player;
+DROP TABLE IF EXISTS player_transfers;
+DROP INDEX IF EXISTS idx_roster_invitations_player;
+DROP INDEX IF EXISTS idx_roster_invitations_pending;
+DROP TABLE IF EXISTS roster_invitations;
+DROP INDEX IF EXISTS idx_season_registration_windows_season_id;
+DROP TABLE IF EXISTS season_registration_windows;
+DROP TABLE IF EXISTS league_roster_rules;
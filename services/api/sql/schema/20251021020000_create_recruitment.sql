@@ -0,0 +1,42 @@
+-- +goose Up
+-- Players a team's managers are keeping an eye on
+CREATE TABLE IF NOT EXISTS player_shortlists (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    added_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, player_profile_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_shortlists_player ON player_shortlists(player_profile_id);
+
+-- A team inviting a player to a trial session. Accepting doesn't put the
+-- player on the team, that's a roster invitation.
+CREATE TABLE IF NOT EXISTS trial_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    trial_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    location TEXT,
+    message TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One open trial invitation per player per team
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trial_invitations_pending ON trial_invitations(team_id, player_profile_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_trial_invitations_player ON trial_invitations(player_profile_id, trial_at);
+
+-- Recruitment search mostly looks at free agents
+CREATE INDEX IF NOT EXISTS idx_player_profiles_free_agents ON player_profiles(position, created_at) WHERE team_id IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_player_profiles_free_agents;
+DROP INDEX IF EXISTS idx_trial_invitations_player;
+DROP INDEX IF EXISTS idx_trial_invitations_pending;
+DROP TABLE IF EXISTS trial_invitations;
+DROP INDEX IF EXISTS idx_player_shortlists_player;
+DROP TABLE IF EXISTS player_shortlists;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- A player's appearance in a local fixture, entered by their team's manager.
+-- team_id is the team they played for, so stats stay with it after a
+-- transfer. Goals come from match_goals and clean sheets from the score.
+CREATE TABLE IF NOT EXISTS match_appearances (
+    id BIGSERIAL PRIMARY KEY,
+    match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    minutes INTEGER NOT NULL CHECK (minutes >= 0 AND minutes <= 130),
+    assists INTEGER NOT NULL DEFAULT 0 CHECK (assists >= 0),
+    yellow_cards INTEGER NOT NULL DEFAULT 0 CHECK (yellow_cards >= 0 AND yellow_cards <= 2),
+    red_card BOOLEAN NOT NULL DEFAULT FALSE,
+    rating INTEGER CHECK (rating >= 1 AND rating <= 10), -- The manager's rating, out of 10
+    recorded_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (match_id, player_profile_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_appearances_player_profile_id ON match_appearances(player_profile_id);
+CREATE INDEX IF NOT EXISTS idx_match_appearances_team_id ON match_appearances(team_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_match_appearances_team_id;
+DROP INDEX IF EXISTS idx_match_appearances_player_profile_id;
+DROP TABLE IF EXISTS match_appearances;
@@ -0,0 +1,64 @@
+-- +goose Up
+-- Attribute ratings a player receives from teammates, opponents and
+-- managers, one per rater. relationship and weight are set by the server
+-- when the rating is given, see ratings.go.
+CREATE TABLE IF NOT EXISTS attribute_ratings (
+    id BIGSERIAL PRIMARY KEY,
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    rater_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    relationship VARCHAR(20) NOT NULL CHECK (relationship IN ('teammate', 'opponent', 'manager')),
+    weight INTEGER NOT NULL CHECK (weight > 0),
+    pace INTEGER NOT NULL CHECK (pace BETWEEN 1 AND 99),
+    shooting INTEGER NOT NULL CHECK (shooting BETWEEN 1 AND 99),
+    passing INTEGER NOT NULL CHECK (passing BETWEEN 1 AND 99),
+    stamina INTEGER NOT NULL CHECK (stamina BETWEEN 1 AND 99),
+    dribbling INTEGER NOT NULL CHECK (dribbling BETWEEN 1 AND 99),
+    defending INTEGER NOT NULL CHECK (defending BETWEEN 1 AND 99),
+    physical INTEGER NOT NULL CHECK (physical BETWEEN 1 AND 99),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (player_profile_id, rater_user_id)
+);
+
+-- The aggregated ratings, kept once a player has enough raters
+CREATE TABLE IF NOT EXISTS player_community_attributes (
+    player_profile_id UUID PRIMARY KEY REFERENCES player_profiles(id) ON DELETE CASCADE,
+    pace INTEGER NOT NULL,
+    shooting INTEGER NOT NULL,
+    passing INTEGER NOT NULL,
+    stamina INTEGER NOT NULL,
+    dribbling INTEGER NOT NULL,
+    defending INTEGER NOT NULL,
+    physical INTEGER NOT NULL,
+    raters INTEGER NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A row every time a player's self-reported or community attributes change
+CREATE TABLE IF NOT EXISTS player_attribute_history (
+    id BIGSERIAL PRIMARY KEY,
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('self', 'community')),
+    pace INTEGER NOT NULL,
+    shooting INTEGER NOT NULL,
+    passing INTEGER NOT NULL,
+    stamina INTEGER NOT NULL,
+    dribbling INTEGER NOT NULL,
+    defending INTEGER NOT NULL,
+    physical INTEGER NOT NULL,
+    raters INTEGER, -- Community rows only
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_attribute_history_player ON player_attribute_history(player_profile_id, recorded_at DESC);
+
+-- Start each player's history from what they report today
+INSERT INTO player_attribute_history (player_profile_id, source, pace, shooting, passing, stamina, dribbling, defending, physical, recorded_at)
+SELECT id, 'self', pace, shooting, passing, stamina, dribbling, defending, physical, updated_at
+FROM player_profiles;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_player_attribute_history_player;
+DROP TABLE IF EXISTS player_attribute_history;
+DROP TABLE IF EXISTS player_community_attributes;
+DROP TABLE IF EXISTS attribute_ratings;
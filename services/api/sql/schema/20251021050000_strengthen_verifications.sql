@@ -0,0 +1,60 @@
+-- +goose Up
+-- Verifications are given within the player's league by a manager or a
+-- verified player, recorded as verifier_role, and lapse when the league's
+-- season ends. Rows from before this have no role and no longer count.
+ALTER TABLE verifications ADD COLUMN league_id UUID REFERENCES leagues(id) ON DELETE SET NULL;
+ALTER TABLE verifications ADD COLUMN season_id UUID REFERENCES seasons(id) ON DELETE SET NULL;
+ALTER TABLE verifications ADD COLUMN verifier_role VARCHAR(20) CHECK (verifier_role IN ('manager', 'player'));
+ALTER TABLE verifications ADD COLUMN expires_at TIMESTAMP;
+ALTER TABLE verifications ADD COLUMN note TEXT;
+UPDATE verifications v SET league_id = t.league_id FROM teams t WHERE t.id = v.team_id;
+
+-- A verifier can vouch for a player again in a new season
+ALTER TABLE verifications DROP CONSTRAINT IF EXISTS verifications_player_profile_id_verifier_user_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verifications_season ON verifications(player_profile_id, verifier_user_id, COALESCE(season_id, '00000000-0000-0000-0000-000000000000'));
+
+-- Photos and videos backing up a verification
+CREATE TABLE IF NOT EXISTS verification_evidence (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    verification_id UUID NOT NULL REFERENCES verifications(id) ON DELETE CASCADE,
+    uploaded_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    media_type VARCHAR(10) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_evidence_verification ON verification_evidence(verification_id);
+
+-- Every time a player's is_verified changes, and why. verification_id isn't
+-- a foreign key so the entry outlives a removed verification.
+CREATE TABLE IF NOT EXISTS verification_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    player_profile_id UUID NOT NULL REFERENCES player_profiles(id) ON DELETE CASCADE,
+    was_verified BOOLEAN NOT NULL,
+    is_verified BOOLEAN NOT NULL,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('verification_added', 'verification_removed', 'expired')),
+    verification_id UUID,
+    actor_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    verifications INTEGER NOT NULL, -- How many counted after the change
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_audit_log_player ON verification_audit_log(player_profile_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_verification_audit_log_player;
+DROP TABLE IF EXISTS verification_audit_log;
+DROP INDEX IF EXISTS idx_verification_evidence_verification;
+DROP TABLE IF EXISTS verification_evidence;
+DROP INDEX IF EXISTS idx_verifications_season;
+DELETE FROM verifications v USING verifications newer
+WHERE newer.player_profile_id = v.player_profile_id AND newer.verifier_user_id = v.verifier_user_id AND newer.created_at > v.created_at;
+ALTER TABLE verifications ADD CONSTRAINT verifications_player_profile_id_verifier_user_id_key UNIQUE (player_profile_id, verifier_user_id);
+ALTER TABLE verifications DROP COLUMN note;
+ALTER TABLE verifications DROP COLUMN expires_at;
+ALTER TABLE verifications DROP COLUMN verifier_role;
+ALTER TABLE verifications DROP COLUMN season_id;
+ALTER TABLE verifications DROP COLUMN league_id;
@@ -0,0 +1,49 @@
+-- +goose Up
+-- What a manager can do for their team. Existing managers keep full control
+-- as head coaches.
+ALTER TABLE team_managers ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'head_coach' CHECK (role IN ('head_coach', 'assistant', 'admin'));
+
+-- Single-use links sent to prove someone owns their email. Only a hash of
+-- the token is kept. A user's email counts as verified while a used link
+-- for their current address exists.
+CREATE TABLE IF NOT EXISTS email_verifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL, -- Lower case, the address the link was sent to
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A league's owner asking someone to help manage one of its teams. The
+-- invitee may not have signed up yet, so it's kept against their email and
+-- bound to their account once they've verified that email.
+CREATE TABLE IF NOT EXISTS manager_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    league_id UUID NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL, -- Stored lower case
+    invitee_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- Set once bound to a verified account
+    role VARCHAR(20) NOT NULL CHECK (role IN ('head_coach', 'assistant', 'admin')),
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    message TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    accepted_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One open invitation per person per team
+CREATE UNIQUE INDEX IF NOT EXISTS idx_manager_invitations_pending ON manager_invitations(team_id, email) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_manager_invitations_email ON manager_invitations(email, status);
+CREATE INDEX IF NOT EXISTS idx_manager_invitations_invitee ON manager_invitations(invitee_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_manager_invitations_invitee;
+DROP INDEX IF EXISTS idx_manager_invitations_email;
+DROP INDEX IF EXISTS idx_manager_invitations_pending;
+DROP TABLE IF EXISTS manager_invitations;
+DROP TABLE IF EXISTS email_verifications;
+ALTER TABLE team_managers DROP COLUMN role;
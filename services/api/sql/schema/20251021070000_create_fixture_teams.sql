@@ -0,0 +1,18 @@
+-- +goose Up
+-- The home and away teams of API-Football fixtures, recorded whenever we fetch
+-- a fixture. Debates only know the fixture ID and external fixtures have no
+-- local team IDs, so follows of a team are matched on its name.
+CREATE TABLE IF NOT EXISTS fixture_teams (
+    match_id VARCHAR(50) PRIMARY KEY,
+    home_team VARCHAR(100) NOT NULL,
+    away_team VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_fixture_teams_home ON fixture_teams(LOWER(home_team));
+CREATE INDEX IF NOT EXISTS idx_fixture_teams_away ON fixture_teams(LOWER(away_team));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_fixture_teams_away;
+DROP INDEX IF EXISTS idx_fixture_teams_home;
+DROP TABLE IF EXISTS fixture_teams;
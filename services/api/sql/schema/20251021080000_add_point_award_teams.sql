@@ -0,0 +1,15 @@
+-- +goose Up
+-- Team boards rank fans by the points they earned while following the team,
+-- so each award records the teams the fan followed when it was made.
+-- Existing awards take the fan's current follows.
+ALTER TABLE point_awards ADD COLUMN IF NOT EXISTS team_ids UUID[] NOT NULL DEFAULT '{}';
+UPDATE point_awards pa SET team_ids = ARRAY(
+    SELECT uf.followable_id FROM user_follows uf
+    WHERE uf.user_id = pa.user_id AND uf.followable_type = 'team'
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_awards_team_ids ON point_awards USING GIN (team_ids);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_point_awards_team_ids;
+ALTER TABLE point_awards DROP COLUMN IF EXISTS team_ids;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- The event types each consumer (api, workers) reads. An outbox event can be
+-- purged once every consumer of its type has delivered it.
+CREATE TABLE IF NOT EXISTS event_consumers (
+    consumer VARCHAR(50) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    registered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (consumer, event_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_created ON event_outbox(created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_event_outbox_created;
+DROP TABLE IF EXISTS event_consumers;
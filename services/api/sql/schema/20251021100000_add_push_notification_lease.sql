@@ -0,0 +1,8 @@
+-- +goose Up
+-- The notification worker leases the rows it claims so it can send outside a
+-- transaction. A worker that dies mid-send leaves the lease to expire and another
+-- worker picks the rows up again.
+ALTER TABLE push_notifications ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP NULL;
+
+-- +goose Down
+ALTER TABLE push_notifications DROP COLUMN IF EXISTS locked_until;
@@ -4,10 +4,15 @@ go 1.22
 
 toolchain go1.23.0
 
-require go.uber.org/zap v1.27.0
+require (
+	github.com/ArronJLinton/fucci-api v0.0.0-00010101000000-000000000000
+	github.com/lib/pq v1.10.9
+	go.uber.org/zap v1.27.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/golang-jwt/jwt/v5 v5.3.0 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/stretchr/testify v1.9.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
@@ -2,15 +2,31 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/ArronJLinton/fucci-api/pkg/blobstore"
+	"github.com/ArronJLinton/fucci-api/pkg/events"
+	"github.com/ArronJLinton/fucci-api/pkg/media"
+	"github.com/ArronJLinton/fucci-api/pkg/push"
+	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
+const (
+	// relayInterval is how often the outbox is polled for events
+	relayInterval = 2 * time.Second
+	// pushInterval is how often queued push notifications are sent
+	pushInterval = 5 * time.Second
+	// mediaInterval is how often uploads are checked for thumbnails to generate
+	mediaInterval = 5 * time.Second
+)
+
 func main() {
 	// Initialize logger
 	logger, err := zap.NewProduction()
@@ -28,10 +44,35 @@ func main() {
 	// Start workers
 	logger.Info("Starting background workers...")
 
+	// Domain events are read from the API's outbox under their own consumer
+	// name, so the workers track delivery independently of the API
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		logger.Warn("DB_URL is not set, domain events will not be consumed")
+	} else {
+		conn, err := sql.Open("postgres", dbURL)
+		if err != nil {
+			logger.Fatal("Failed to connect to database", zap.Error(err))
+		}
+		defer conn.Close()
+
+		bus := events.NewBus()
+		subscribe(bus, logger)
+		go events.NewRelay(conn, "workers", bus).Run(ctx, relayInterval)
+
+		// Notification worker: sends push notifications queued by the API
+		if provider := pushProvider(logger); provider != nil {
+			go push.NewDispatcher(conn, provider).Run(ctx, pushInterval)
+		}
+
+		// Media processing worker: generates thumbnails for uploads
+		if store := blobStore(logger); store != nil {
+			go media.NewProcessor(conn, store, ffmpegPath(logger)).Run(ctx, mediaInterval)
+		}
+	}
+
 	// TODO: Implement actual workers
 	// - Debate generation worker
-	// - Media processing worker
-	// - Notification worker
 	// - Data aggregation worker
 
 	// Wait for interrupt signal
@@ -57,3 +98,115 @@ func main() {
 
 	logger.Info("Workers service stopped")
 }
+
+// subscribe registers the workers' event handlers
+func subscribe(bus *events.Bus, logger *zap.Logger) {
+	bus.Subscribe(events.TypeDebateGenerated, "log", func(ctx context.Context, event events.Event) error {
+		e := event.(events.DebateGenerated)
+		logger.Info("Debate generated",
+			zap.Int32("debate_id", e.DebateID),
+			zap.String("match_id", e.MatchID),
+			zap.String("debate_type", e.DebateType),
+		)
+		return nil
+	})
+	bus.Subscribe(events.TypeMatchStatusChanged, "log", func(ctx context.Context, event events.Event) error {
+		e := event.(events.MatchStatusChanged)
+		logger.Info("Match status changed",
+			zap.String("match_id", e.MatchID),
+			zap.String("from", e.FromStatus),
+			zap.String("to", e.ToStatus),
+		)
+		return nil
+	})
+}
+
+// pushProvider picks the push gateway from PUSH_PROVIDER: "fcm" (needs
+// FCM_CREDENTIALS_FILE, a service account key file; FCM_ENDPOINT is optional) or
+// "log" to log them when running locally. Push notifications aren't sent when it's unset.
+func pushProvider(logger *zap.Logger) push.Provider {
+	switch provider := os.Getenv("PUSH_PROVIDER"); provider {
+	case "fcm":
+		path := os.Getenv("FCM_CREDENTIALS_FILE")
+		if path == "" {
+			logger.Warn("FCM_CREDENTIALS_FILE is not set, push notifications will not be sent")
+			return nil
+		}
+		credentials, err := os.ReadFile(path)
+		if err != nil {
+			logger.Warn("Failed to read FCM credentials, push notifications will not be sent", zap.Error(err))
+			return nil
+		}
+		fcm, err := push.NewFCMProvider(credentials, os.Getenv("FCM_ENDPOINT"))
+		if err != nil {
+			logger.Warn("Invalid FCM credentials, push notifications will not be sent", zap.Error(err))
+			return nil
+		}
+		return fcm
+	case "log":
+		return &logProvider{logger: logger}
+	case "":
+		logger.Info("PUSH_PROVIDER is not set, push notifications will not be sent")
+		return nil
+	default:
+		logger.Warn("Unknown PUSH_PROVIDER, push notifications will not be sent", zap.String("provider", provider))
+		return nil
+	}
+}
+
+// logProvider logs notifications instead of sending them
+type logProvider struct {
+	logger *zap.Logger
+}
+
+func (p *logProvider) Send(ctx context.Context, notification push.Notification, tokens []string) (push.Response, error) {
+	p.logger.Info("Push notification",
+		zap.String("title", notification.Title),
+		zap.String("body", notification.Body),
+		zap.Int("devices", len(tokens)),
+	)
+	return push.Response{Sent: len(tokens)}, nil
+}
+
+func (p *logProvider) MaxBatch() int {
+	return 500
+}
+
+// blobStore opens the store uploads are kept in, configured with the same
+// BLOB_* and S3_* settings as the API. Media isn't processed when BLOB_STORE is unset.
+func blobStore(logger *zap.Logger) blobstore.BlobStore {
+	backend := os.Getenv("BLOB_STORE")
+	if backend == "" {
+		logger.Info("BLOB_STORE is not set, uploaded media will not be processed")
+		return nil
+	}
+	store, err := blobstore.New(blobstore.Config{
+		Backend:           backend,
+		PublicURL:         os.Getenv("BLOB_PUBLIC_URL"),
+		LocalDir:          os.Getenv("BLOB_LOCAL_DIR"),
+		S3Endpoint:        os.Getenv("S3_ENDPOINT"),
+		S3Region:          os.Getenv("S3_REGION"),
+		S3Bucket:          os.Getenv("S3_BUCKET"),
+		S3AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+	})
+	if err != nil {
+		logger.Warn("Uploaded media will not be processed", zap.Error(err))
+		return nil
+	}
+	return store
+}
+
+// ffmpegPath finds ffmpeg for video thumbnails, from FFMPEG_PATH or the PATH.
+// Videos are published without a thumbnail when it isn't installed.
+func ffmpegPath(logger *zap.Logger) string {
+	if path := os.Getenv("FFMPEG_PATH"); path != "" {
+		return path
+	}
+	path, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		logger.Info("ffmpeg not found, videos will not get thumbnails")
+		return ""
+	}
+	return path
+}